@@ -0,0 +1,185 @@
+package temple
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+)
+
+var (
+	// ErrAccessibility is returned by AccessibilityAudit when it's
+	// strict and a page has accessibility issues.
+	ErrAccessibility = errors.New("page has accessibility issues")
+)
+
+// AccessibilityIssueKind identifies the kind of problem an
+// AccessibilityIssue describes.
+type AccessibilityIssueKind string
+
+const (
+	// AccessibilityMissingAlt means an <img> element has no alt
+	// attribute. Decorative images should have an empty one.
+	AccessibilityMissingAlt AccessibilityIssueKind = "missing-alt"
+
+	// AccessibilityMissingLang means the <html> element has no lang
+	// attribute, so screen readers can't tell what language the page
+	// is in.
+	AccessibilityMissingLang AccessibilityIssueKind = "missing-lang"
+
+	// AccessibilityDuplicateID means more than one element has the same
+	// id, so labels and ARIA attributes referring to it are ambiguous.
+	AccessibilityDuplicateID AccessibilityIssueKind = "duplicate-id"
+
+	// AccessibilityEmptyLink means an <a> element has no text, image alt
+	// text, or label, so there's nothing to announce for it.
+	AccessibilityEmptyLink AccessibilityIssueKind = "empty-link"
+)
+
+// AccessibilityIssue is a problem found by AuditAccessibility.
+type AccessibilityIssue struct {
+	// Kind is the kind of problem found.
+	Kind AccessibilityIssueKind
+
+	// Element is the start tag of the element with the problem, like
+	// `<img src="/logo.png">`.
+	Element string
+}
+
+// String returns a description of the AccessibilityIssue.
+func (issue AccessibilityIssue) String() string {
+	return fmt.Sprintf("%s: %s", issue.Kind, issue.Element)
+}
+
+// AuditAccessibility runs basic accessibility checks on an HTML document,
+// returning the problems it finds in the order they appear: <img> elements
+// without alt attributes, an <html> element without a lang attribute,
+// duplicate ids, and links with no accessible name. It's no substitute for a
+// real audit, but catches the most common mistakes cheaply.
+//
+// Documents without an <html> element, like HTML fragments, aren't checked for
+// a lang attribute.
+func AuditAccessibility(html []byte) []AccessibilityIssue {
+	var issues []AccessibilityIssue
+	ids := map[string]int{}
+	tokenizer := xhtml.NewTokenizer(bytes.NewReader(html))
+	// the link currently open, and whether anything inside it would
+	// give it a name
+	var link string
+	var linkNamed bool
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == xhtml.ErrorToken {
+			break
+		}
+		token := tokenizer.Token()
+		switch tokenType {
+		case xhtml.TextToken:
+			if link != "" && strings.TrimSpace(token.Data) != "" {
+				linkNamed = true
+			}
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			if id, ok := lookupTokenAttr(token, "id"); ok && id != "" {
+				ids[id]++
+				if ids[id] == 2 {
+					issues = append(issues, AccessibilityIssue{Kind: AccessibilityDuplicateID, Element: token.String()})
+				}
+			}
+			switch token.Data {
+			case "html":
+				if lang, _ := lookupTokenAttr(token, "lang"); strings.TrimSpace(lang) == "" {
+					issues = append(issues, AccessibilityIssue{Kind: AccessibilityMissingLang, Element: token.String()})
+				}
+			case "img":
+				alt, ok := lookupTokenAttr(token, "alt")
+				if !ok {
+					issues = append(issues, AccessibilityIssue{Kind: AccessibilityMissingAlt, Element: token.String()})
+				}
+				if strings.TrimSpace(alt) != "" {
+					linkNamed = true
+				}
+			case "a":
+				if _, ok := lookupTokenAttr(token, "href"); !ok || tokenType == xhtml.SelfClosingTagToken {
+					continue
+				}
+				link, linkNamed = token.String(), hasAccessibleLabel(token)
+			default:
+				if hasAccessibleLabel(token) {
+					linkNamed = true
+				}
+			}
+		case xhtml.EndTagToken:
+			if token.Data != "a" || link == "" {
+				continue
+			}
+			if !linkNamed {
+				issues = append(issues, AccessibilityIssue{Kind: AccessibilityEmptyLink, Element: link})
+			}
+			link = ""
+		}
+	}
+	return issues
+}
+
+// lookupTokenAttr returns the value of the token's attribute with the key, and
+// whether it was set.
+func lookupTokenAttr(token xhtml.Token, key string) (string, bool) {
+	for _, a := range token.Attr {
+		if a.Namespace == "" && a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// hasAccessibleLabel returns true if the element is labelled using
+// aria-label, aria-labelledby, or title.
+func hasAccessibleLabel(token xhtml.Token) bool {
+	for _, key := range []string{"aria-label", "aria-labelledby", "title"} {
+		if val, _ := lookupTokenAttr(token, key); strings.TrimSpace(val) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+var _ OutputFilter = AccessibilityAudit{}
+
+// AccessibilityAudit is an OutputFilter that runs AuditAccessibility on every
+// page rendered with it, leaving the output unchanged. It's meant to be used
+// in development and tests:
+//
+//	temple.Render(ctx, w, site, page, temple.WithOutputFilters(temple.AccessibilityAudit{}))
+type AccessibilityAudit struct {
+	// Report is called with the issues found on each page that has any.
+	// If nil, each issue is logged as a warning using the slog.Logger
+	// from LoggingContext.
+	Report func(ctx context.Context, issues []AccessibilityIssue)
+
+	// Strict makes pages with any issues fail to render, with an error
+	// wrapping ErrAccessibility, after they've been reported.
+	Strict bool
+}
+
+// FilterOutput audits the HTML, reporting any issues found, and returns it
+// unchanged.
+func (audit AccessibilityAudit) FilterOutput(ctx context.Context, html []byte) ([]byte, error) {
+	issues := AuditAccessibility(html)
+	if len(issues) < 1 {
+		return html, nil
+	}
+	if audit.Report != nil {
+		audit.Report(ctx, issues)
+	} else {
+		for _, issue := range issues {
+			logger(ctx).WarnContext(ctx, "accessibility issue", "kind", string(issue.Kind), "element", issue.Element)
+		}
+	}
+	if audit.Strict {
+		return nil, fmt.Errorf("%w: %d issues, first %s", ErrAccessibility, len(issues), issues[0])
+	}
+	return html, nil
+}
@@ -0,0 +1,99 @@
+package temple
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// AccessLogMiddleware returns a middleware that logs every request served by
+// the http.Handler it wraps, including information about any calls to Render
+// the http.Handler made using the request's context.Context.
+//
+// Each request is logged with its method, path, response status, the number
+// of bytes written, and how long it took to serve. If the request rendered a
+// Renderable, the Renderable's Key, how long rendering took, and whether the
+// template was cached are logged, too, along with any error encountered while
+// rendering. If the http.Handler rendered more than once, only the render
+// that finished last is logged.
+//
+// If `log` is nil, the slog.Logger embedded in the request's context.Context
+// using LoggingContext will be used.
+func AccessLogMiddleware(log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ctx := RecordRenderResult(r.Context())
+			rec := &responseRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r.WithContext(ctx))
+
+			attrs := []slog.Attr{
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", rec.statusCode()),
+				slog.Int64("bytes", rec.bytes),
+				slog.Duration("duration", time.Since(start)),
+			}
+			level := slog.LevelInfo
+			if result, ok := RenderResultFromContext(ctx); ok {
+				attrs = append(attrs, slog.Group("render",
+					slog.String("key", result.Key),
+					slog.Duration("duration", result.Duration),
+					slog.Bool("cached_template", result.CachedTemplate),
+				))
+				if result.Err != nil {
+					level = slog.LevelError
+					attrs = append(attrs, slog.String("error", result.Err.Error()))
+				}
+			}
+
+			l := log
+			if l == nil {
+				l = logger(ctx)
+			}
+			l.LogAttrs(ctx, level, "served request", attrs...)
+		})
+	}
+}
+
+// responseRecorder is an http.ResponseWriter that keeps track of the status
+// code and number of bytes written to the http.ResponseWriter it wraps.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+// WriteHeader records the status code and passes it on to the wrapped
+// http.ResponseWriter. Informational status codes, like 103 Early Hints,
+// aren't recorded, as they're not the final status of the response.
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 && (status < 100 || status > 199) {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the number of bytes written and passes them on to the wrapped
+// http.ResponseWriter.
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap returns the wrapped http.ResponseWriter, for use with
+// http.ResponseController.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *responseRecorder) statusCode() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
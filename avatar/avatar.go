@@ -0,0 +1,149 @@
+// Package avatar provides a temple Component for rendering a user's avatar:
+// their Gravatar, if they have one, over a generated image of their initials.
+//
+// The Component supplies its own template and embeds the CSS it needs, so all
+// that's required to use it is to include it in the UseComponents output of a
+// Component and execute its template:
+//
+//	{{ template "avatar/avatar.html.tmpl" .Page.Avatar }}
+package avatar
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"html/template"
+	"io/fs"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"impractical.co/temple"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// defaultSize is the size, in pixels, of an Avatar with no Size set.
+const defaultSize = 48
+
+const css = `
+.temple-avatar { display: inline-block; position: relative; border-radius: 50%; overflow: hidden; vertical-align: middle; }
+.temple-avatar svg, .temple-avatar img { display: block; position: absolute; top: 0; left: 0; width: 100%; height: 100%; }
+`
+
+var (
+	_ temple.Component           = Avatar{}
+	_ temple.TemplateDirProvider = Avatar{}
+	_ temple.CSSEmbedder         = Avatar{}
+)
+
+// Avatar is a Component that renders a circular image representing a user.
+// The user's initials are rendered as an inline SVG, and if the user has an
+// Email, their Gravatar is layered on top. Users without a Gravatar get a
+// transparent image, so their initials show through without any JavaScript.
+type Avatar struct {
+	// Name is the user's name, used for their initials and as the
+	// accessible name of the Avatar.
+	Name string
+
+	// Email is the user's email address, used to look up their Gravatar.
+	// If empty, only the user's initials are rendered.
+	Email string
+
+	// Size is the width and height of the Avatar, in pixels. Defaults to
+	// 48.
+	Size int
+}
+
+// Templates returns the template needed to render the Avatar.
+func (Avatar) Templates(_ context.Context) []string {
+	return []string{"avatar/avatar.html.tmpl"}
+}
+
+// TemplateDir returns the fs.FS containing the Avatar's template.
+func (Avatar) TemplateDir(_ context.Context) fs.FS {
+	return temple.SubFS(templates, "templates")
+}
+
+// EmbedCSS returns the CSS used to style Avatars.
+func (Avatar) EmbedCSS(_ context.Context) template.CSS {
+	return css
+}
+
+// PixelSize returns the width and height of the Avatar, in pixels.
+func (a Avatar) PixelSize() int {
+	if a.Size < 1 {
+		return defaultSize
+	}
+	return a.Size
+}
+
+// Initials returns up to two letters representing the user: the first letter
+// of the first and last words of their Name.
+func (a Avatar) Initials() string {
+	words := strings.FieldsFunc(a.Name, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})
+	if len(words) < 1 {
+		return ""
+	}
+	first, _ := utf8.DecodeRuneInString(words[0])
+	if len(words) == 1 {
+		return string(unicode.ToUpper(first))
+	}
+	last, _ := utf8.DecodeRuneInString(words[len(words)-1])
+	return string(unicode.ToUpper(first)) + string(unicode.ToUpper(last))
+}
+
+// Color returns the background color of the Avatar's initials, derived from
+// the user's Email or Name so the same user always gets the same color.
+func (a Avatar) Color() string {
+	hash := fnv.New32a()
+	if a.Email != "" {
+		_, _ = hash.Write([]byte(normalizeEmail(a.Email)))
+	} else {
+		_, _ = hash.Write([]byte(a.Name))
+	}
+	return fmt.Sprintf("hsl(%d, 45%%, 45%%)", hash.Sum32()%360)
+}
+
+// ImageURL returns the URL of the user's Gravatar, or an empty string if the
+// Avatar has no Email. The image is requested at twice the Avatar's size, so
+// it's sharp on high-density displays.
+func (a Avatar) ImageURL() string {
+	if a.Email == "" {
+		return ""
+	}
+	return GravatarURL(a.Email, a.PixelSize()*2, "blank")
+}
+
+// GravatarURL returns the URL of the Gravatar for the passed email address,
+// at the passed size in pixels. If the email address has no Gravatar, the
+// image named by `fallback` is returned instead; see the Gravatar
+// documentation for the available options, like "mp", "identicon", or
+// "blank".
+func GravatarURL(email string, size int, fallback string) string {
+	hash := sha256.Sum256([]byte(normalizeEmail(email)))
+	query := url.Values{}
+	if size > 0 {
+		query.Set("s", strconv.Itoa(size))
+	}
+	if fallback != "" {
+		query.Set("d", fallback)
+	}
+	result := "https://gravatar.com/avatar/" + hex.EncodeToString(hash[:])
+	if len(query) > 0 {
+		result += "?" + query.Encode()
+	}
+	return result
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
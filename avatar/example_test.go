@@ -0,0 +1,59 @@
+package avatar_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing/fstest"
+
+	"impractical.co/temple"
+	"impractical.co/temple/avatar"
+)
+
+type ProfilePage struct {
+	Avatar avatar.Avatar
+}
+
+func (ProfilePage) Templates(_ context.Context) []string {
+	return []string{"profile.html.tmpl"}
+}
+
+func (p ProfilePage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{p.Avatar}
+}
+
+func (ProfilePage) Key(_ context.Context) string {
+	return "profile.html.tmpl"
+}
+
+func (ProfilePage) ExecutedTemplate(_ context.Context) string {
+	return "profile.html.tmpl"
+}
+
+func Example() {
+	templates := fstest.MapFS{
+		"profile.html.tmpl": {Data: []byte(`{{ template "avatar/avatar.html.tmpl" .Page.Avatar }}`)},
+	}
+	site := temple.NewCachedSite(templates)
+
+	temple.Render(context.Background(), os.Stdout, site, ProfilePage{
+		Avatar: avatar.Avatar{
+			Name:  "Ada Lovelace",
+			Email: " Ada@Example.com ",
+			Size:  32,
+		},
+	})
+
+	//Output:
+	// <span class="temple-avatar" role="img" aria-label="Ada Lovelace" style="width: 32px; height: 32px">
+	// 	<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 100 100" aria-hidden="true"><rect width="100" height="100" fill="hsl(162, 45%, 45%)"/><text x="50" y="50" dy="0.35em" text-anchor="middle" fill="#fff" font-family="sans-serif" font-size="40">AL</text></svg>
+	// 	<img src="https://gravatar.com/avatar/b5fc85e55755f9e0d030a10ab4429b6b2944855f9a0d60077fe832becbc41d72?d=blank&amp;s=64" alt="" width="32" height="32" loading="lazy">
+	// </span>
+}
+
+func ExampleGravatarURL() {
+	fmt.Println(avatar.GravatarURL("ada@example.com", 80, "identicon"))
+
+	//Output:
+	// https://gravatar.com/avatar/b5fc85e55755f9e0d030a10ab4429b6b2944855f9a0d60077fe832becbc41d72?d=identicon&s=80
+}
@@ -0,0 +1,8 @@
+package avatar
+
+import "impractical.co/temple"
+
+// Package returns the temple.ComponentPackage for Avatar.
+func Package() (temple.ComponentPackage, error) {
+	return temple.NewComponentPackage("avatar", templates, "templates/avatar", "", Avatar{})
+}
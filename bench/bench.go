@@ -0,0 +1,306 @@
+// Package bench provides synthetic temple Sites and pages of configurable
+// size, for measuring how temple performs consistently across changes, and
+// for modeling the workloads of Sites built with it.
+//
+// A Config describes the shape of the workload: how many Components a page
+// uses, how deeply they're nested, how many resources each one supplies, and
+// how much output the page renders. NewPage builds a page from a Config, and
+// NewSite, NewUncachedSite, and NewGraphCachedSite wrap them
+// in Sites that cache different amounts of work between renders:
+//
+//	cfg := bench.Config{Components: 50, Depth: 5, Resources: 2, Items: 100}
+//	site := bench.NewSite()
+//	page := bench.NewPage(cfg)
+//	for range b.N {
+//		temple.Render(ctx, io.Discard, site, page)
+//	}
+package bench
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"strings"
+	"testing/fstest"
+
+	"impractical.co/temple"
+)
+
+// Config describes the size of a synthetic page.
+type Config struct {
+	// Components is the number of Components the page uses, not
+	// including the page itself.
+	Components int
+
+	// Depth is how deeply the Components are nested. Components are
+	// arranged in chains of Depth Components, each one using the next;
+	// the first Component of each chain is used by the page. Depth must
+	// be less than 100, and is treated as 1 if it's less than 1.
+	Depth int
+
+	// Resources is the number of stylesheets and scripts each Component
+	// links to. Each Component also embeds some CSS and JavaScript of
+	// its own.
+	Resources int
+
+	// SharedResources is the number of stylesheets and scripts every
+	// Component links to, like a design system's, which are
+	// deduplicated when the page's resources are collected.
+	SharedResources int
+
+	// Items is the number of list items the page renders, to control
+	// the size of its output.
+	Items int
+}
+
+// Realistic is a Config modeled on a typical content page: a layout, a
+// navigation menu, a sidebar of widgets, and a listing, with 60 Components
+// nested up to 4 deep that link to 120 stylesheets and 120 scripts of their
+// own and share 4 more.
+var Realistic = Config{
+	Components:      60,
+	Depth:           4,
+	Resources:       2,
+	SharedResources: 4,
+	Items:           200,
+}
+
+// key returns the page Key for pages built from the Config.
+func (c Config) key() string {
+	return fmt.Sprintf("bench-%d-%d-%d-%d-%d", c.Components, c.depth(), c.Resources, c.SharedResources, c.Items)
+}
+
+func (c Config) depth() int {
+	return max(c.Depth, 1)
+}
+
+// Templates returns an fs.FS containing the templates needed to render pages
+// built by NewPage.
+func Templates() fs.FS {
+	files := fstest.MapFS{}
+	files["page.html.tmpl"] = &fstest.MapFile{Data: []byte(`<!doctype html>
+<html>
+	<head>
+		<title>{{ .Page.Title }}</title>
+		{{- range .LinkedCSS }}
+		<link rel="stylesheet" href="{{ . }}">
+		{{- end }}
+		<style>{{ .EmbeddedCSS }}</style>
+	</head>
+	<body>
+		{{- range .Page.Components }}
+		{{ template "component" . }}
+		{{- end }}
+		<ul>
+		{{- range .Page.Items }}
+			<li>{{ . }}</li>
+		{{- end }}
+		</ul>
+		{{- range .LinkedJS }}
+		<script src="{{ . }}"></script>
+		{{- end }}
+		<script>{{ .EmbeddedJS }}</script>
+	</body>
+</html>`)}
+	files["component.html.tmpl"] = &fstest.MapFile{Data: []byte(`{{ define "component" -}}
+<section class="component-{{ .ID }}">
+	<h2>Component {{ .ID }}</h2>
+	{{- range .Children }}
+	{{ template "component" . }}
+	{{- end }}
+</section>
+{{- end }}`)}
+	return files
+}
+
+var (
+	_ temple.Site           = Site{}
+	_ temple.TemplateCacher = Site{}
+	_ temple.GraphCacher    = GraphCachedSite{}
+)
+
+// Site is a temple.Site that caches parsed templates, like most Sites do.
+type Site struct {
+	*temple.CachedSite
+}
+
+// NewSite returns a Site for rendering pages built by NewPage.
+func NewSite() Site {
+	return Site{CachedSite: temple.NewCachedSite(Templates())}
+}
+
+// UncachedSite is a temple.Site that doesn't cache anything, so every render
+// parses its templates again.
+type UncachedSite struct {
+	templates fs.FS
+}
+
+// NewUncachedSite returns an UncachedSite for rendering pages built by
+// NewPage.
+func NewUncachedSite() UncachedSite {
+	return UncachedSite{templates: Templates()}
+}
+
+// TemplateDir returns the fs.FS containing the templates for pages built by
+// NewPage.
+func (u UncachedSite) TemplateDir(_ context.Context) fs.FS {
+	return u.templates
+}
+
+// GraphCachedSite is a temple.Site that caches both parsed templates and the
+// Components each page uses.
+type GraphCachedSite struct {
+	*temple.CachedSite
+	*temple.GraphCache
+}
+
+// NewGraphCachedSite returns a GraphCachedSite for rendering pages built by
+// NewPage.
+func NewGraphCachedSite() GraphCachedSite {
+	return GraphCachedSite{
+		CachedSite: temple.NewCachedSite(Templates()),
+		GraphCache: temple.NewGraphCache(),
+	}
+}
+
+var (
+	_ temple.Component     = &Component{}
+	_ temple.ComponentUser = &Component{}
+	_ temple.CSSEmbedder   = &Component{}
+	_ temple.CSSLinker     = &Component{}
+	_ temple.JSEmbedder    = &Component{}
+	_ temple.JSLinker      = &Component{}
+)
+
+// Component is a synthetic Component that renders a section containing the
+// Components it uses, and supplies its own CSS and JavaScript.
+type Component struct {
+	// ID identifies the Component within the page.
+	ID int
+
+	// Children are the Components this Component uses.
+	Children []*Component
+
+	// Resources is the number of stylesheets and scripts the Component
+	// links to.
+	Resources int
+
+	// SharedResources is the number of stylesheets and scripts the
+	// Component links to that every other Component links to as well.
+	SharedResources int
+}
+
+// Templates returns the templates needed to render the Component.
+func (*Component) Templates(_ context.Context) []string {
+	return []string{"component.html.tmpl"}
+}
+
+// UseComponents returns the Component's Children.
+func (c *Component) UseComponents(_ context.Context) []temple.Component {
+	results := make([]temple.Component, 0, len(c.Children))
+	for _, child := range c.Children {
+		results = append(results, child)
+	}
+	return results
+}
+
+// EmbedCSS returns a CSS rule for the Component.
+func (c *Component) EmbedCSS(_ context.Context) template.CSS {
+	return template.CSS(fmt.Sprintf(".component-%d { padding: 1em; border: 1px solid #ccc; }", c.ID)) // #nosec G203
+}
+
+// LinkCSS returns a unique stylesheet URL for each of the Component's
+// Resources, and a shared one for each of its SharedResources.
+func (c *Component) LinkCSS(_ context.Context) []string {
+	return c.links("css")
+}
+
+// EmbedJS returns a script for the Component.
+func (c *Component) EmbedJS(_ context.Context) template.JS {
+	return template.JS(fmt.Sprintf("document.querySelector(%q)?.setAttribute(\"data-ready\", \"\");", fmt.Sprintf(".component-%d", c.ID))) // #nosec G203
+}
+
+// LinkJS returns a unique script URL for each of the Component's Resources,
+// and a shared one for each of its SharedResources.
+func (c *Component) LinkJS(_ context.Context) []string {
+	return c.links("js")
+}
+
+func (c *Component) links(ext string) []string {
+	results := make([]string, 0, c.Resources+c.SharedResources)
+	for i := range c.SharedResources {
+		results = append(results, fmt.Sprintf("/static/shared/%d.%s", i, ext))
+	}
+	for i := range c.Resources {
+		results = append(results, fmt.Sprintf("/static/component-%d/%d.%s", c.ID, i, ext))
+	}
+	return results
+}
+
+var (
+	_ temple.Renderable    = Page{}
+	_ temple.ComponentUser = Page{}
+)
+
+// Page is a synthetic page, built from a Config by NewPage.
+type Page struct {
+	// Title is the page's title.
+	Title string
+
+	// Components are the Components the page uses directly, each the
+	// first of a chain of nested Components.
+	Components []*Component
+
+	// Items are the list items the page renders.
+	Items []string
+
+	key string
+}
+
+// NewPage builds a Page from the Config.
+func NewPage(cfg Config) Page {
+	page := Page{
+		Title: "Benchmark page",
+		Items: make([]string, cfg.Items),
+		key:   cfg.key(),
+	}
+	for i := range page.Items {
+		page.Items[i] = fmt.Sprintf("Item %d: %s", i, strings.Repeat("lorem ipsum ", 4))
+	}
+	var parent *Component
+	for id := range cfg.Components {
+		comp := &Component{ID: id, Resources: cfg.Resources, SharedResources: cfg.SharedResources}
+		if id%cfg.depth() == 0 {
+			page.Components = append(page.Components, comp)
+		} else {
+			parent.Children = append(parent.Children, comp)
+		}
+		parent = comp
+	}
+	return page
+}
+
+// Templates returns the templates needed to render the Page.
+func (Page) Templates(_ context.Context) []string {
+	return []string{"page.html.tmpl"}
+}
+
+// UseComponents returns the Components the Page uses directly.
+func (p Page) UseComponents(_ context.Context) []temple.Component {
+	results := make([]temple.Component, 0, len(p.Components))
+	for _, comp := range p.Components {
+		results = append(results, comp)
+	}
+	return results
+}
+
+// Key returns a key unique to the Config the Page was built from.
+func (p Page) Key(_ context.Context) string {
+	return p.key
+}
+
+// ExecutedTemplate returns the template to execute to render the Page.
+func (Page) ExecutedTemplate(_ context.Context) string {
+	return "page.html.tmpl"
+}
@@ -0,0 +1,113 @@
+package bench_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"impractical.co/temple"
+	"impractical.co/temple/bench"
+)
+
+var configs = []struct {
+	name string
+	cfg  bench.Config
+}{
+	{name: "small", cfg: bench.Config{Components: 5, Depth: 2, Resources: 1, Items: 10}},
+	{name: "medium", cfg: bench.Config{Components: 50, Depth: 5, Resources: 2, Items: 100}},
+	{name: "large", cfg: bench.Config{Components: 200, Depth: 10, Resources: 4, Items: 1000}},
+	{name: "deep", cfg: bench.Config{Components: 90, Depth: 90, Resources: 1, Items: 10}},
+	{name: "realistic", cfg: bench.Realistic},
+}
+
+func benchmarkRender[SiteType temple.Site](b *testing.B, site SiteType, page bench.Page) {
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		result := temple.Render(ctx, io.Discard, site, page)
+		if result.Err != nil {
+			b.Fatal(result.Err)
+		}
+	}
+}
+
+func BenchmarkRender_uncached(b *testing.B) {
+	for _, c := range configs {
+		b.Run(c.name, func(b *testing.B) {
+			benchmarkRender(b, bench.NewUncachedSite(), bench.NewPage(c.cfg))
+		})
+	}
+}
+
+func BenchmarkRender_cached(b *testing.B) {
+	for _, c := range configs {
+		b.Run(c.name, func(b *testing.B) {
+			benchmarkRender(b, bench.NewSite(), bench.NewPage(c.cfg))
+		})
+	}
+}
+
+func BenchmarkRender_graphCached(b *testing.B) {
+	for _, c := range configs {
+		b.Run(c.name, func(b *testing.B) {
+			benchmarkRender(b, bench.NewGraphCachedSite(), bench.NewPage(c.cfg))
+		})
+	}
+}
+
+func BenchmarkRender_parallel(b *testing.B) {
+	for _, c := range configs {
+		b.Run(c.name, func(b *testing.B) {
+			ctx := context.Background()
+			site := bench.NewSite()
+			page := bench.NewPage(c.cfg)
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					temple.Render(ctx, io.Discard, site, page)
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkInspect measures resolving a page's Components and collecting
+// their resources, without parsing or executing any templates.
+func BenchmarkInspect(b *testing.B) {
+	for _, c := range configs {
+		b.Run(c.name, func(b *testing.B) {
+			ctx := context.Background()
+			site := bench.NewSite()
+			page := bench.NewPage(c.cfg)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				_, err := temple.Inspect(ctx, site, page)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkEmbeddedCSS measures resolving a page's Components and merging
+// the CSS they embed.
+func BenchmarkEmbeddedCSS(b *testing.B) {
+	for _, c := range configs {
+		b.Run(c.name, func(b *testing.B) {
+			ctx := context.Background()
+			page := bench.NewPage(c.cfg)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				_, err := temple.EmbeddedCSS(ctx, page)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package bench_test
+
+import (
+	"context"
+	"fmt"
+
+	"impractical.co/temple"
+	"impractical.co/temple/bench"
+)
+
+func ExampleNewPage() {
+	cfg := bench.Config{Components: 6, Depth: 3, Resources: 2, Items: 10}
+	report, err := temple.Inspect(context.Background(), bench.NewSite(), bench.NewPage(cfg))
+	if err != nil {
+		panic(err)
+	}
+	for _, comp := range report.Components {
+		fmt.Println(comp.Indent() + comp.Type)
+	}
+	fmt.Println(len(report.LinkedCSS), "stylesheets,", len(report.LinkedJS), "scripts")
+
+	//Output:
+	// bench.Page
+	//   *bench.Component
+	//     *bench.Component
+	//       *bench.Component
+	//   *bench.Component
+	//     *bench.Component
+	//       *bench.Component
+	// 12 stylesheets, 12 scripts
+}
+
+func ExampleRealistic() {
+	report, err := temple.Inspect(context.Background(), bench.NewSite(), bench.NewPage(bench.Realistic))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(len(report.Components), "components,", len(report.LinkedCSS), "stylesheets,", len(report.LinkedJS), "scripts")
+
+	//Output:
+	// 61 components, 124 stylesheets, 124 scripts
+}
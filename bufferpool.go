@@ -0,0 +1,57 @@
+package temple
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+)
+
+// maxPooledBufferSize is the capacity above which render buffers are dropped
+// instead of being returned to the pool, so one huge page doesn't keep a huge
+// buffer alive forever.
+const maxPooledBufferSize = 4 << 20
+
+var (
+	// renderBuffers holds the buffers pages are rendered into, so they
+	// can be reused across renders.
+	renderBuffers = sync.Pool{
+		New: func() any {
+			return new(bytes.Buffer)
+		},
+	}
+
+	// recentRenderSize is a moving average of the size of recently
+	// rendered pages, used to size new buffers.
+	recentRenderSize atomic.Int64
+)
+
+// getRenderBuffer returns an empty buffer to render a page into, with room
+// for at least a recently rendered page's worth of output.
+func getRenderBuffer() *bytes.Buffer {
+	// the pool only ever holds *bytes.Buffer
+	buf := renderBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	if size := int(recentRenderSize.Load()); buf.Cap() < size {
+		buf.Grow(size)
+	}
+	return buf
+}
+
+// putRenderBuffer records how much was rendered into the buffer and returns it
+// to the pool for reuse. The buffer must not be used after it's been returned.
+func putRenderBuffer(buf *bytes.Buffer, rendered int) {
+	// an exponentially weighted moving average, weighting each render at
+	// 1/8, so the size follows the pages being rendered without being
+	// thrown off by a single outlier
+	for {
+		old := recentRenderSize.Load()
+		updated := old + (int64(rendered)-old)/8
+		if recentRenderSize.CompareAndSwap(old, updated) {
+			break
+		}
+	}
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	renderBuffers.Put(buf)
+}
@@ -0,0 +1,176 @@
+package temple
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"path"
+	"strings"
+	"sync"
+)
+
+// BuildStep generates static assets, like compiling CSS with Tailwind or
+// PostCSS, before the Site serves requests.
+type BuildStep interface {
+	// Build generates the assets, returning the paths of the files it
+	// wrote, relative to the directory assets are served from.
+	Build(ctx context.Context) ([]string, error)
+}
+
+// BuildFunc is a function that fulfills the BuildStep interface.
+type BuildFunc func(ctx context.Context) ([]string, error)
+
+// Build calls the BuildFunc.
+func (fn BuildFunc) Build(ctx context.Context) ([]string, error) {
+	return fn(ctx)
+}
+
+var _ BuildStep = CommandBuildStep{}
+
+// CommandBuildStep is a BuildStep that runs an external command, like:
+//
+//	temple.CommandBuildStep{
+//		Command: []string{"npx", "tailwindcss", "-i", "css/input.css", "-o", "static/css/app.css", "--minify"},
+//		Outputs: []string{"css/app.css"},
+//	}
+type CommandBuildStep struct {
+	// Command is the name of the command to run, followed by its
+	// arguments.
+	Command []string
+
+	// Dir is the directory to run the command in. If empty, the current
+	// directory is used.
+	Dir string
+
+	// Outputs are the paths of the files the command writes, relative to
+	// the directory assets are served from.
+	Outputs []string
+}
+
+// Build runs the command, returning its Outputs if it succeeds. The
+// command's output is included in the error if it fails.
+func (c CommandBuildStep) Build(ctx context.Context) ([]string, error) {
+	if len(c.Command) < 1 {
+		return nil, fmt.Errorf("error running build step: %w", exec.ErrNotFound)
+	}
+	cmd := exec.CommandContext(ctx, c.Command[0], c.Command[1:]...) // #nosec G204
+	cmd.Dir = c.Dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error running %q: %w\n%s", strings.Join(c.Command, " "), err, output)
+	}
+	return c.Outputs, nil
+}
+
+// Builder is an optional interface for Sites. Those fulfilling it have
+// BuildSteps that need to run before they serve requests, which Build and
+// BuildService run.
+type Builder interface {
+	// BuildSteps returns the BuildSteps to run, in the order they should
+	// be run in.
+	BuildSteps(ctx context.Context) []BuildStep
+}
+
+// AssetManifest maps the paths of static assets to URLs that include a
+// fingerprint of their contents, like /static/css/app.css?v=1a2b3c4d5e6f,
+// so browsers can cache them forever and still pick up changes. Its zero
+// value is ready to use, and it can safely be used by multiple goroutines.
+type AssetManifest struct {
+	// Prefix is prepended to the paths of assets to make their URLs,
+	// like "/static/".
+	Prefix string
+
+	urls map[string]string
+	mu   sync.RWMutex
+}
+
+// URL returns the fingerprinted URL of the asset at the path. Assets that
+// haven't been fingerprinted are returned with the Prefix but without a
+// fingerprint.
+func (m *AssetManifest) URL(assetPath string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if url, ok := m.urls[assetPath]; ok {
+		return url
+	}
+	return m.Prefix + assetPath
+}
+
+// Fingerprint reads the assets at the paths from dir and records their
+// fingerprinted URLs, replacing any recorded before.
+func (m *AssetManifest) Fingerprint(dir fs.FS, paths ...string) error {
+	urls := make(map[string]string, len(paths))
+	for _, assetPath := range paths {
+		contents, err := fs.ReadFile(dir, path.Clean(assetPath))
+		if err != nil {
+			return fmt.Errorf("error fingerprinting %q: %w", assetPath, err)
+		}
+		checksum := sha256.Sum256(contents)
+		urls[assetPath] = m.Prefix + assetPath + "?v=" + hex.EncodeToString(checksum[:6])
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.urls == nil {
+		m.urls = map[string]string{}
+	}
+	for assetPath, url := range urls {
+		m.urls[assetPath] = url
+	}
+	return nil
+}
+
+// Build runs the Site's BuildSteps, if it's a Builder, in order, then
+// fingerprints the files they wrote, reading them from assets, and records
+// them in the AssetManifest. It stops at the first BuildStep that fails.
+// It's meant to be run before the Site serves requests, or in CI to check
+// the assets can be built.
+func Build(ctx context.Context, site Site, assets fs.FS, manifest *AssetManifest) error {
+	builder, ok := site.(Builder)
+	if !ok {
+		return nil
+	}
+	var outputs []string
+	for _, step := range builder.BuildSteps(ctx) {
+		logger(ctx).DebugContext(ctx, "running build step", "step", fmt.Sprintf("%T", step))
+		paths, err := step.Build(ctx)
+		if err != nil {
+			return fmt.Errorf("error running build step %T: %w", step, err)
+		}
+		outputs = append(outputs, paths...)
+	}
+	if manifest == nil {
+		return nil
+	}
+	return manifest.Fingerprint(assets, outputs...)
+}
+
+var _ Service = BuildService{}
+
+// BuildService is a Service that runs Build when it's started, so a Site's
+// assets are rebuilt and fingerprinted before it serves requests. Register
+// it with the Site's Lifecycle before any Services that render pages.
+type BuildService struct {
+	// Site is the Site whose BuildSteps are run.
+	Site Site
+
+	// Assets is the directory the BuildSteps write to, which assets are
+	// served from.
+	Assets fs.FS
+
+	// Manifest records the fingerprinted URLs of the files the
+	// BuildSteps write. If nil, they aren't fingerprinted.
+	Manifest *AssetManifest
+}
+
+// Start runs Build, returning any error it returns.
+func (b BuildService) Start(ctx context.Context) error {
+	return Build(ctx, b.Site, b.Assets, b.Manifest)
+}
+
+// Shutdown does nothing, as building finishes before Start returns.
+func (BuildService) Shutdown(_ context.Context) error {
+	return nil
+}
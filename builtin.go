@@ -0,0 +1,19 @@
+package temple
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// builtinTemplates holds the templates for the Components that ship with
+// temple. They all live under templates/temple/, so their paths are prefixed
+// with "temple/" and won't collide with a Site's templates.
+//
+//go:embed templates
+var builtinTemplates embed.FS
+
+// builtinTemplateDir returns the fs.FS containing the templates for the
+// Components that ship with temple.
+func builtinTemplateDir() fs.FS {
+	return SubFS(builtinTemplates, "templates")
+}
@@ -0,0 +1,144 @@
+// Package cache provides a pluggable storage interface for caching the output
+// of temple Sites, so multiple instances of a Site can share their caches, and
+// an http middleware that uses it to cache rendered pages. Cached pages can be
+// purged by the surrogate keys they were tagged with, using Purge.
+//
+// A Backend stores opaque byte slices with a time-to-live. Memory is an
+// in-process Backend, useful for single instances and tests, and Dir stores
+// values on disk. temple deliberately doesn't ship Backends for Redis or
+// memcached, so that using the cache package doesn't add their client
+// libraries to every Site's dependencies. They're thin wrappers around their
+// clients' get, set, and delete commands, which Sites can copy. For Redis,
+// using github.com/redis/go-redis:
+//
+//	type RedisBackend struct {
+//		Client *redis.Client
+//	}
+//
+//	func (r RedisBackend) Get(ctx context.Context, key string) ([]byte, error) {
+//		value, err := r.Client.Get(ctx, key).Bytes()
+//		if errors.Is(err, redis.Nil) {
+//			return nil, cache.ErrNotFound
+//		}
+//		return value, err
+//	}
+//
+//	func (r RedisBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+//		return r.Client.Set(ctx, key, value, ttl).Err()
+//	}
+//
+//	func (r RedisBackend) Delete(ctx context.Context, key string) error {
+//		return r.Client.Del(ctx, key).Err()
+//	}
+//
+// And for memcached, using github.com/bradfitz/gomemcache. Its client
+// doesn't take a context.Context, memcached limits keys to 250 bytes without
+// spaces, so they're hashed, and it treats expirations of more than 30 days
+// as Unix timestamps:
+//
+//	type MemcachedBackend struct {
+//		Client *memcache.Client
+//	}
+//
+//	func memcachedKey(key string) string {
+//		sum := sha256.Sum256([]byte(key))
+//		return hex.EncodeToString(sum[:])
+//	}
+//
+//	func (m MemcachedBackend) Get(_ context.Context, key string) ([]byte, error) {
+//		item, err := m.Client.Get(memcachedKey(key))
+//		if errors.Is(err, memcache.ErrCacheMiss) {
+//			return nil, cache.ErrNotFound
+//		}
+//		if err != nil {
+//			return nil, err
+//		}
+//		return item.Value, nil
+//	}
+//
+//	func (m MemcachedBackend) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+//		expiration := int32(ttl / time.Second)
+//		if ttl > 30*24*time.Hour {
+//			expiration = int32(time.Now().Add(ttl).Unix())
+//		}
+//		return m.Client.Set(&memcache.Item{
+//			Key:        memcachedKey(key),
+//			Value:      value,
+//			Expiration: expiration,
+//		})
+//	}
+//
+//	func (m MemcachedBackend) Delete(_ context.Context, key string) error {
+//		err := m.Client.Delete(memcachedKey(key))
+//		if errors.Is(err, memcache.ErrCacheMiss) {
+//			return nil
+//		}
+//		return err
+//	}
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned by Backends when there's no value stored
+	// for a key, or the value has expired.
+	ErrNotFound = errors.New("not found in cache")
+)
+
+// Backend stores values for a limited time. Implementations must be safe to
+// use from multiple goroutines.
+type Backend interface {
+	// Get returns the value stored for the key. If there's no value
+	// stored, or it has expired, it returns ErrNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set stores the value for the key, replacing any value already
+	// stored. The value expires after ttl; if ttl is 0 or less, it
+	// doesn't expire.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes the value stored for the key, if there is one. It
+	// doesn't return an error if there isn't.
+	Delete(ctx context.Context, key string) error
+}
+
+// Page is a rendered page, as stored in a Backend.
+type Page struct {
+	// Status is the HTTP status code the page was served with.
+	Status int `json:"status"`
+
+	// Header are the HTTP headers the page was served with.
+	Header http.Header `json:"header"`
+
+	// Body is the rendered page.
+	Body []byte `json:"body"`
+
+	// Stored is when the page was stored in the cache. It's the zero
+	// time for pages cached before it was recorded.
+	Stored time.Time `json:"stored"`
+}
+
+// MarshalBinary encodes the Page for storage in a Backend.
+func (p Page) MarshalBinary() ([]byte, error) {
+	out, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding cached page: %w", err)
+	}
+	return out, nil
+}
+
+// UnmarshalBinary decodes a Page encoded with MarshalBinary.
+func (p *Page) UnmarshalBinary(data []byte) error {
+	err := json.Unmarshal(data, p)
+	if err != nil {
+		return fmt.Errorf("error decoding cached page: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var _ Backend = Dir{}
+
+// Dir is a Backend that stores values as files in a directory, so they
+// survive restarts. Each value is stored in a file named after the SHA-256
+// hash of its key, along with when it expires. Expired files are removed when
+// they're next read.
+//
+// Writes are atomic, so multiple processes can share a Dir, but nothing
+// limits its size; pair it with a TTL, and call Prune periodically to remove
+// values that expired without being read again.
+type Dir struct {
+	// Path is the directory values are stored in. It's created when the
+	// first value is stored, if it doesn't exist.
+	Path string
+}
+
+// file returns the path to the file the value for the key is stored in.
+func (d Dir) file(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.Path, hex.EncodeToString(sum[:]))
+}
+
+// Get returns the value stored for the key, or ErrNotFound.
+func (d Dir) Get(_ context.Context, key string) ([]byte, error) {
+	path := d.file(key)
+	contents, err := os.ReadFile(path) // #nosec G304
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading cache file: %w", err)
+	}
+	if len(contents) < 8 {
+		return nil, ErrNotFound
+	}
+	expires := int64(binary.BigEndian.Uint64(contents[:8])) // #nosec G115
+	if expires != 0 && time.Now().UnixNano() > expires {
+		// it doesn't matter if it's already been removed
+		_ = os.Remove(path)
+		return nil, ErrNotFound
+	}
+	return contents[8:], nil
+}
+
+// Set stores the value for the key, expiring after ttl.
+func (d Dir) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	err := os.MkdirAll(d.Path, 0o750)
+	if err != nil {
+		return fmt.Errorf("error creating cache directory: %w", err)
+	}
+	var expires int64
+	if ttl > 0 {
+		expires = time.Now().Add(ttl).UnixNano()
+	}
+	contents := binary.BigEndian.AppendUint64(make([]byte, 0, len(value)+8), uint64(expires)) // #nosec G115
+	contents = append(contents, value...)
+
+	// write to a temporary file and rename it into place, so readers
+	// never see a partially written value
+	tmp, err := os.CreateTemp(d.Path, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("error creating cache file: %w", err)
+	}
+	_, err = tmp.Write(contents)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), d.file(key))
+	}
+	if err != nil {
+		// it doesn't matter if it's already been removed
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("error writing cache file: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the value stored for the key.
+func (d Dir) Delete(_ context.Context, key string) error {
+	err := os.Remove(d.file(key))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error removing cache file: %w", err)
+	}
+	return nil
+}
+
+// staleTempFileAge is how old a temporary file has to be before Prune
+// considers it abandoned by a writer that crashed, rather than still being
+// written.
+const staleTempFileAge = time.Hour
+
+// Prune removes the files of every expired value in the Dir, along with any
+// temporary files left behind by writers that didn't finish, and returns how
+// many files it removed. It's safe to call while the Dir is in use.
+func (d Dir) Prune(ctx context.Context) (int, error) {
+	entries, err := os.ReadDir(d.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error listing cache directory: %w", err)
+	}
+	now := time.Now()
+	var removed int
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return removed, fmt.Errorf("error pruning cache directory: %w", err)
+		}
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		path := filepath.Join(d.Path, entry.Name())
+		if !d.pruneable(path, entry, now) {
+			continue
+		}
+		err := os.Remove(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return removed, fmt.Errorf("error removing cache file: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// pruneable returns true if the file at path is an expired value or an
+// abandoned temporary file.
+func (Dir) pruneable(path string, entry fs.DirEntry, now time.Time) bool {
+	if strings.HasPrefix(entry.Name(), ".tmp-") {
+		info, err := entry.Info()
+		return err == nil && now.Sub(info.ModTime()) > staleTempFileAge
+	}
+	file, err := os.Open(path) // #nosec G304
+	if err != nil {
+		return false
+	}
+	header := make([]byte, 8)
+	_, err = io.ReadFull(file, header)
+	// we only read from the file, so there's nothing to lose if closing
+	// it fails
+	_ = file.Close()
+	if err != nil {
+		return false
+	}
+	expires := int64(binary.BigEndian.Uint64(header)) // #nosec G115
+	return expires != 0 && now.UnixNano() > expires
+}
@@ -0,0 +1,115 @@
+package cache_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing/fstest"
+	"time"
+
+	"impractical.co/temple"
+	"impractical.co/temple/cache"
+)
+
+type HomePage struct {
+	Visitor int
+}
+
+func (HomePage) Templates(_ context.Context) []string {
+	return []string{"home.html.tmpl"}
+}
+
+func (HomePage) Key(_ context.Context) string {
+	return "home.html.tmpl"
+}
+
+func (HomePage) ExecutedTemplate(_ context.Context) string {
+	return "home.html.tmpl"
+}
+
+func ExampleMiddleware() {
+	site := temple.NewCachedSite(fstest.MapFS{
+		"home.html.tmpl": {Data: []byte(`<p>You are visitor {{ .Page.Visitor }}.</p>`)},
+	})
+
+	var visitors int
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		visitors++
+		temple.Render(r.Context(), w, site, HomePage{Visitor: visitors})
+	})
+	cached := cache.Middleware(&cache.Memory{}, cache.MiddlewareOptions{})(handler)
+
+	for range 3 {
+		resp := httptest.NewRecorder()
+		cached.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/", nil))
+		body, _ := io.ReadAll(resp.Body)
+		fmt.Println(string(body))
+	}
+
+	//Output:
+	// <p>You are visitor 1.</p>
+	// <p>You are visitor 1.</p>
+	// <p>You are visitor 1.</p>
+}
+
+func ExampleDir() {
+	dir, err := os.MkdirTemp("", "temple-cache")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	backend := cache.Dir{Path: dir}
+	err = backend.Set(ctx, "greeting", []byte("hello"), time.Hour)
+	if err != nil {
+		panic(err)
+	}
+
+	// a new process can read what an earlier one stored
+	restarted := cache.Dir{Path: dir}
+	value, err := restarted.Get(ctx, "greeting")
+	fmt.Println(string(value), err)
+
+	_, err = restarted.Get(ctx, "farewell")
+	fmt.Println(err)
+
+	//Output:
+	// hello <nil>
+	// not found in cache
+}
+
+func ExampleDir_Prune() {
+	dir, err := os.MkdirTemp("", "temple-cache")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	backend := cache.Dir{Path: dir}
+	err = backend.Set(ctx, "session", []byte("short-lived"), time.Millisecond)
+	if err != nil {
+		panic(err)
+	}
+	err = backend.Set(ctx, "page", []byte("long-lived"), time.Hour)
+	if err != nil {
+		panic(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	// run Prune periodically, e.g. from a temple.BackgroundService, so
+	// values that are never read again don't fill the disk
+	removed, err := backend.Prune(ctx)
+	fmt.Println(removed, err)
+
+	value, err := backend.Get(ctx, "page")
+	fmt.Println(string(value), err)
+
+	//Output:
+	// 1 <nil>
+	// long-lived <nil>
+}
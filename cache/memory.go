@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+var _ Backend = &Memory{}
+
+type memoryEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// Memory is a Backend that stores values in memory. Expired values are
+// removed when they're next read. Its zero value is ready to use.
+type Memory struct {
+	entries map[string]memoryEntry
+	mu      sync.Mutex
+}
+
+// Get returns the value stored for the key, or ErrNotFound.
+func (m *Memory) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		delete(m.entries, key)
+		return nil, ErrNotFound
+	}
+	return slices.Clone(entry.value), nil
+}
+
+// Set stores the value for the key, expiring after ttl.
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.entries == nil {
+		m.entries = map[string]memoryEntry{}
+	}
+	entry := memoryEntry{value: slices.Clone(value)}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	m.entries[key] = entry
+	return nil
+}
+
+// Delete removes the value stored for the key.
+func (m *Memory) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+	return nil
+}
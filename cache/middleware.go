@@ -0,0 +1,366 @@
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"impractical.co/temple"
+)
+
+// MiddlewareOptions configures the middleware returned by Middleware.
+type MiddlewareOptions struct {
+	// TTL is how long rendered pages are cached for. If it's 0 or less,
+	// they're cached until they're deleted from the Backend.
+	TTL time.Duration
+
+	// Key returns the key the response to the request is cached under.
+	// If nil, DefaultKey is used.
+	Key func(*http.Request) string
+
+	// Logger is used to log errors from the Backend, which are otherwise
+	// ignored: if a page can't be read from the cache, it's rendered, and
+	// if it can't be written to the cache, it's rendered again next time.
+	// If nil, errors aren't logged.
+	Logger *slog.Logger
+
+	// MaxSize is the size, in bytes, of the largest page body that's
+	// cached. Larger pages are still written to the client, but the
+	// Middleware stops keeping a copy of them once they pass MaxSize, so
+	// very large pages rendered using temple.WithStreaming aren't held in
+	// memory. If it's 0 or less, pages of any size are cached.
+	MaxSize int
+
+	// Banner, if set, is executed with a BannerData and added to HTML
+	// pages served from the cache, before their closing </body> tag, to
+	// show readers or developers how old the page they're seeing is.
+	// Pages are served without it if it fails to execute.
+	Banner BannerTemplate
+}
+
+// BannerTemplate is a template for the banner added to cached pages. Both
+// html/template and text/template Templates fulfill it; html/template strips
+// comments, so banners that are only an HTML comment, for debugging, need to
+// use text/template.
+type BannerTemplate interface {
+	Execute(w io.Writer, data any) error
+}
+
+// BannerData is the data MiddlewareOptions.Banner is executed with.
+type BannerData struct {
+	// Stored is when the page was stored in the cache.
+	Stored time.Time
+
+	// Age is how long ago the page was stored in the cache, truncated to
+	// the second.
+	Age time.Duration
+}
+
+// DefaultKey returns a key for the request's host and URL, including its
+// query string.
+func DefaultKey(r *http.Request) string {
+	return "temple:page:" + r.Host + r.URL.RequestURI()
+}
+
+// Middleware returns a middleware that caches the responses of the
+// http.Handler it wraps in the Backend, and serves them from the Backend until
+// they expire. Only successful responses to GET requests are cached, and
+// responses that set cookies or have a Cache-Control header with the
+// "no-store" or "private" directives aren't, so pages that use temple's
+// CachePolicy to opt out of shared caches are never stored.
+//
+// Pages are written to the client as they're rendered, and copied for the
+// cache as they're written, rather than buffered again before they're sent.
+// Pages larger than MiddlewareOptions.MaxSize aren't cached. Neither are
+// responses that couldn't be fully written to the client, or pages whose
+// temple.RenderResult has an error, like a page rendered using
+// temple.WithStreaming that failed after its first chunk was written, as they
+// may be truncated.
+//
+// Responses that vary on any request header but Accept-Encoding aren't
+// cached. Responses are cached separately for each set of encodings clients
+// accept, so pages compressed using temple.WithCompression are only served
+// to clients that accept them.
+//
+// Requests with credentials, in an Authorization or Cookie header, are only
+// served from the cache, and their responses only cached, if the response has
+// a Cache-Control header with the "public" directive, as their pages may be
+// personalized.
+//
+// Cached pages are indexed by the surrogate keys in their Surrogate-Key
+// header, which temple sets from the page's temple.SurrogateKeyer Components,
+// so Purge can delete every page tagged with a key when its content changes.
+func Middleware(backend Backend, opts MiddlewareOptions) func(http.Handler) http.Handler {
+	key := opts.Key
+	if key == nil {
+		key = DefaultKey
+	}
+	logError := func(r *http.Request, msg string, err error) {
+		if opts.Logger != nil {
+			opts.Logger.ErrorContext(r.Context(), msg, "error", err)
+		}
+	}
+	// indexMu serializes updates to the surrogate key indexes, so
+	// concurrent requests don't overwrite each other's changes
+	var indexMu sync.Mutex
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx := r.Context()
+			cacheKey := key(r) + "|encoding=" + acceptedEncodings(r)
+			credentials := r.Header.Get("Authorization") != "" || r.Header.Get("Cookie") != ""
+			data, err := backend.Get(ctx, cacheKey)
+			if err == nil {
+				var page Page
+				err = page.UnmarshalBinary(data)
+				if err == nil && (!credentials || hasDirective(page.Header, "public")) {
+					if opts.Banner != nil {
+						err = addBanner(&page, opts.Banner)
+						if err != nil {
+							logError(r, "error adding banner to cached page", err)
+						}
+					}
+					servePage(w, page)
+					return
+				}
+			}
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				logError(r, "error reading page from cache", err)
+			}
+
+			rec := &pageRecorder{ResponseWriter: w, maxSize: opts.MaxSize}
+			// record the RenderResult, so pages that failed after
+			// their output started being written aren't stored
+			r = r.WithContext(temple.RecordRenderResult(ctx))
+			next.ServeHTTP(rec, r)
+			if result, ok := temple.RenderResultFromContext(r.Context()); ok && result.Err != nil {
+				return
+			}
+			if !rec.cacheable() || (credentials && !hasDirective(w.Header(), "public")) {
+				return
+			}
+			// index the page before storing it, so a page is never
+			// stored where Purge can't find it
+			if keys := surrogateKeys(w.Header()); len(keys) > 0 {
+				indexMu.Lock()
+				err = indexPage(ctx, backend, cacheKey, keys, opts.TTL)
+				indexMu.Unlock()
+				if err != nil {
+					logError(r, "error indexing page in cache", err)
+					return
+				}
+			}
+			data, err = Page{
+				Status: rec.statusCode(),
+				Header: w.Header().Clone(),
+				Body:   rec.body.Bytes(),
+				Stored: time.Now(),
+			}.MarshalBinary()
+			if err == nil {
+				err = backend.Set(ctx, cacheKey, data, opts.TTL)
+			}
+			if err != nil {
+				logError(r, "error writing page to cache", err)
+			}
+		})
+	}
+}
+
+// servePage writes the cached Page to the http.ResponseWriter.
+func servePage(w http.ResponseWriter, page Page) {
+	for name, values := range page.Header {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(page.Status)
+	// if the client went away, there's nothing to do about it
+	_, _ = w.Write(page.Body)
+}
+
+// addBanner executes the banner for the Page and adds it to the Page's body,
+// before its closing </body> tag, or at the end if it doesn't have one. Pages
+// that aren't HTML, are compressed, or don't record when they were stored are
+// left unchanged, as are pages the banner fails to execute for.
+func addBanner(page *Page, banner BannerTemplate) error {
+	if page.Stored.IsZero() || page.Header.Get("Content-Encoding") != "" {
+		return nil
+	}
+	contentType := page.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(page.Body)
+	}
+	if !strings.HasPrefix(contentType, "text/html") {
+		return nil
+	}
+	var buf bytes.Buffer
+	err := banner.Execute(&buf, BannerData{
+		Stored: page.Stored,
+		Age:    time.Since(page.Stored).Truncate(time.Second),
+	})
+	if err != nil {
+		return fmt.Errorf("error executing banner: %w", err)
+	}
+	index := bytes.LastIndex(bytes.ToLower(page.Body), []byte("</body>"))
+	if index < 0 {
+		index = len(page.Body)
+	}
+	body := make([]byte, 0, len(page.Body)+buf.Len())
+	body = append(body, page.Body[:index]...)
+	body = append(body, buf.Bytes()...)
+	body = append(body, page.Body[index:]...)
+	page.Body = body
+	page.Header = page.Header.Clone()
+	page.Header.Del("Content-Length")
+	return nil
+}
+
+// pageRecorder is an http.ResponseWriter that tees the response written to
+// the http.ResponseWriter it wraps into a copy for the cache, so the page is
+// only rendered once. The client always comes first: every write goes to the
+// wrapped http.ResponseWriter, and only the bytes it accepted are copied, so
+// the copy can never make the response fail. Once the copy can't be cached,
+// because the client stopped accepting writes or the page grew past maxSize,
+// it's dropped, and nothing more is copied.
+type pageRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+
+	// maxSize is the largest body that's copied, if it's more than 0
+	maxSize int
+
+	// failed is true if writing to the wrapped http.ResponseWriter
+	// failed, meaning the recorded body may be incomplete
+	failed bool
+
+	// oversize is true if the body grew larger than maxSize
+	oversize bool
+}
+
+// WriteHeader records the status code and passes it on to the wrapped
+// http.ResponseWriter. Informational status codes, like 103 Early Hints,
+// aren't recorded, as they're not the final status of the response.
+func (p *pageRecorder) WriteHeader(status int) {
+	if p.status == 0 && (status < 100 || status > 199) {
+		p.status = status
+	}
+	p.ResponseWriter.WriteHeader(status)
+}
+
+// Write passes the bytes on to the wrapped http.ResponseWriter, and copies
+// the ones it wrote, while the copy can still be cached.
+func (p *pageRecorder) Write(b []byte) (int, error) {
+	if p.status == 0 {
+		p.status = http.StatusOK
+	}
+	n, err := p.ResponseWriter.Write(b)
+	switch {
+	case p.failed || p.oversize:
+		// the copy has already been dropped
+	case err != nil || n < len(b):
+		p.failed = true
+	case p.maxSize > 0 && p.body.Len()+n > p.maxSize:
+		p.oversize = true
+	default:
+		p.body.Write(b[:n])
+	}
+	if p.failed || p.oversize {
+		p.body = bytes.Buffer{}
+	}
+	return n, err
+}
+
+// Unwrap returns the wrapped http.ResponseWriter, for use with
+// http.ResponseController.
+func (p *pageRecorder) Unwrap() http.ResponseWriter {
+	return p.ResponseWriter
+}
+
+func (p *pageRecorder) statusCode() int {
+	if p.status == 0 {
+		return http.StatusOK
+	}
+	return p.status
+}
+
+// cacheable returns true if the recorded response can be stored in a shared
+// cache.
+func (p *pageRecorder) cacheable() bool {
+	if p.failed || p.oversize || p.statusCode() != http.StatusOK {
+		return false
+	}
+	header := p.Header()
+	if len(header.Values("Set-Cookie")) > 0 {
+		return false
+	}
+	if hasDirective(header, "no-store") || hasDirective(header, "private") {
+		return false
+	}
+	// only Accept-Encoding is part of the key
+	for _, value := range header.Values("Vary") {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.TrimSpace(field)
+			if field != "" && !strings.EqualFold(field, "Accept-Encoding") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// hasDirective returns true if the Cache-Control header has the directive,
+// with or without a value.
+func hasDirective(header http.Header, name string) bool {
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			directive, _, _ = strings.Cut(strings.TrimSpace(directive), "=")
+			if strings.EqualFold(directive, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// acceptedEncodings returns the content codings the request accepts, sorted
+// and lowercased, so requests accepting the same codings share a cache key
+// no matter how their Accept-Encoding headers are written.
+func acceptedEncodings(r *http.Request) string {
+	var codings []string
+	for _, value := range r.Header.Values("Accept-Encoding") {
+		for _, part := range strings.Split(value, ",") {
+			coding, params, _ := strings.Cut(part, ";")
+			coding = strings.ToLower(strings.TrimSpace(coding))
+			if coding == "" || rejectedCoding(params) || slices.Contains(codings, coding) {
+				continue
+			}
+			codings = append(codings, coding)
+		}
+	}
+	slices.Sort(codings)
+	return strings.Join(codings, ",")
+}
+
+// rejectedCoding returns true if the parameters of a coding in an
+// Accept-Encoding header give it a quality of 0.
+func rejectedCoding(params string) bool {
+	for _, param := range strings.Split(params, ";") {
+		key, val, _ := strings.Cut(param, "=")
+		if strings.TrimSpace(key) != "q" {
+			continue
+		}
+		quality, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		return err == nil && quality <= 0
+	}
+	return false
+}
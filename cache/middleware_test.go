@@ -0,0 +1,331 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"testing/fstest"
+	texttemplate "text/template"
+
+	"impractical.co/temple"
+	"impractical.co/temple/cache"
+)
+
+type countingPage struct {
+	Count  int
+	Header http.Header
+	Err    bool
+}
+
+func (countingPage) Templates(_ context.Context) []string {
+	return []string{"count.html.tmpl"}
+}
+
+func (countingPage) Key(_ context.Context) string {
+	return "count.html.tmpl"
+}
+
+func (countingPage) ExecutedTemplate(_ context.Context) string {
+	return "count.html.tmpl"
+}
+
+func (p countingPage) Headers(_ context.Context) http.Header {
+	return p.Header
+}
+
+func (p countingPage) Check() (string, error) {
+	if p.Err {
+		return "", errors.New("broken page")
+	}
+	return "", nil
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		header http.Header
+		want   int
+	}
+
+	tests := map[string]struct {
+		header   http.Header
+		err      bool
+		requests []request
+	}{
+		"cached": {
+			requests: []request{{want: 1}, {want: 1}},
+		},
+		"encodings-cached-separately": {
+			header: http.Header{"Vary": {"Accept-Encoding"}},
+			requests: []request{
+				{header: http.Header{"Accept-Encoding": {"gzip"}}, want: 1},
+				{want: 2},
+				{header: http.Header{"Accept-Encoding": {"br, GZIP;q=0.5"}}, want: 3},
+				{header: http.Header{"Accept-Encoding": {"gzip, identity;q=0"}}, want: 1},
+				{want: 2},
+			},
+		},
+		"vary-on-other-header": {
+			header:   http.Header{"Vary": {"Accept-Encoding, Cookie"}},
+			requests: []request{{want: 1}, {want: 2}},
+		},
+		"server-error": {
+			err:      true,
+			requests: []request{{want: 1}, {want: 2}},
+		},
+		"credentials-not-stored": {
+			requests: []request{
+				{header: http.Header{"Cookie": {"session=abc"}}, want: 1},
+				{want: 2},
+			},
+		},
+		"credentials-not-served": {
+			requests: []request{
+				{want: 1},
+				{header: http.Header{"Authorization": {"Bearer abc"}}, want: 2},
+				{want: 1},
+			},
+		},
+		"credentials-public": {
+			header: http.Header{"Cache-Control": {"public, max-age=60"}},
+			requests: []request{
+				{header: http.Header{"Cookie": {"session=abc"}}, want: 1},
+				{header: http.Header{"Authorization": {"Bearer abc"}}, want: 1},
+				{want: 1},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			site := temple.NewCachedSite(fstest.MapFS{
+				"count.html.tmpl": {Data: []byte(`{{ .Page.Check }}{{ .Page.Count }}`)},
+			})
+			var count int
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				count++
+				page := countingPage{Count: count, Header: test.header, Err: test.err}
+				temple.Render(r.Context(), w, site, page)
+			})
+			cached := cache.Middleware(&cache.Memory{}, cache.MiddlewareOptions{})(handler)
+
+			for pos, req := range test.requests {
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				for key, values := range req.header {
+					r.Header[key] = values
+				}
+				resp := httptest.NewRecorder()
+				cached.ServeHTTP(resp, r)
+				if test.err {
+					if resp.Code != http.StatusInternalServerError {
+						t.Errorf("request %d: expected status %d, got %d", pos, http.StatusInternalServerError, resp.Code)
+					}
+					if count != req.want {
+						t.Errorf("request %d: expected handler to have run %d times, ran %d times", pos, req.want, count)
+					}
+					continue
+				}
+				if got := resp.Body.String(); got != strconv.Itoa(req.want) {
+					t.Errorf("request %d: expected body %q, got %q", pos, strconv.Itoa(req.want), got)
+				}
+			}
+		})
+	}
+}
+
+// failingWriter is an http.ResponseWriter that fails to write anything past
+// the first `limit` bytes of the body, like a client that went away.
+type failingWriter struct {
+	*httptest.ResponseRecorder
+	limit int
+}
+
+func (f *failingWriter) Write(b []byte) (int, error) {
+	if len(b) <= f.limit {
+		f.limit -= len(b)
+		return f.ResponseRecorder.Write(b)
+	}
+	n, _ := f.ResponseRecorder.Write(b[:f.limit])
+	f.limit = 0
+	return n, errors.New("client went away")
+}
+
+func TestMiddlewareIncompleteResponses(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		template string
+		opts     []temple.RenderOption
+		// writer returns the http.ResponseWriter for the first
+		// request
+		writer func() http.ResponseWriter
+		// failFirst makes the page fail to render the first time
+		failFirst bool
+	}{
+		"write-error": {
+			template: `{{ .Page.Count }} visits`,
+			writer: func() http.ResponseWriter {
+				return &failingWriter{ResponseRecorder: httptest.NewRecorder(), limit: 2}
+			},
+		},
+		"streamed-render-error": {
+			template:  `{{ .Page.Count }} visits{{ .Page.Check }}`,
+			opts:      []temple.RenderOption{temple.WithStreaming(1)},
+			writer:    func() http.ResponseWriter { return httptest.NewRecorder() },
+			failFirst: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			site := temple.NewCachedSite(fstest.MapFS{
+				"count.html.tmpl": {Data: []byte(test.template)},
+			})
+			var count int
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				count++
+				page := countingPage{Count: count, Err: test.failFirst && count == 1}
+				temple.Render(r.Context(), w, site, page, test.opts...)
+			})
+			cached := cache.Middleware(&cache.Memory{}, cache.MiddlewareOptions{})(handler)
+
+			cached.ServeHTTP(test.writer(), httptest.NewRequest(http.MethodGet, "/", nil))
+			resp := httptest.NewRecorder()
+			cached.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/", nil))
+			if got, want := resp.Body.String(), "2 visits"; got != want {
+				t.Errorf("expected the incomplete page not to be cached, and %q to be rendered, got %q", want, got)
+			}
+		})
+	}
+}
+
+func TestMiddlewareBanner(t *testing.T) {
+	t.Parallel()
+
+	notice := template.Must(template.New("banner").Parse(`<p class="cached">Cached {{ .Age }} ago</p>`))
+	comment := texttemplate.Must(texttemplate.New("banner").Parse(`<!-- cached {{ .Age }} ago -->`))
+	tests := map[string]struct {
+		template string
+		header   http.Header
+		banner   cache.BannerTemplate
+		uncached string
+		want     string
+	}{
+		"before-closing-body": {
+			template: `<html><body><p>{{ .Page.Count }}</p></BODY></html>`,
+			banner:   notice,
+			uncached: `<html><body><p>1</p></BODY></html>`,
+			want:     `<html><body><p>1</p><p class="cached">Cached 0s ago</p></BODY></html>`,
+		},
+		"comment": {
+			template: `<html><body><p>{{ .Page.Count }}</p></body></html>`,
+			banner:   comment,
+			uncached: `<html><body><p>1</p></body></html>`,
+			want:     `<html><body><p>1</p><!-- cached 0s ago --></body></html>`,
+		},
+		"no-closing-body": {
+			template: `{{ .Page.Count }}`,
+			header:   http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+			banner:   notice,
+			uncached: `1`,
+			want:     `1<p class="cached">Cached 0s ago</p>`,
+		},
+		"not-html": {
+			template: `{{ .Page.Count }}`,
+			banner:   notice,
+			uncached: `1`,
+			want:     `1`,
+		},
+		"compressed": {
+			template: `<html><body>{{ .Page.Count }}</body></html>`,
+			header:   http.Header{"Content-Encoding": {"gzip"}},
+			banner:   notice,
+			uncached: `<html><body>1</body></html>`,
+			want:     `<html><body>1</body></html>`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			site := temple.NewCachedSite(fstest.MapFS{
+				"count.html.tmpl": {Data: []byte(test.template)},
+			})
+			var count int
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				count++
+				temple.Render(r.Context(), w, site, countingPage{Count: count, Header: test.header})
+			})
+			cached := cache.Middleware(&cache.Memory{}, cache.MiddlewareOptions{Banner: test.banner})(handler)
+
+			for pos, want := range []string{test.uncached, test.want} {
+				resp := httptest.NewRecorder()
+				cached.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/", nil))
+				if got := resp.Body.String(); got != want {
+					t.Errorf("request %d: expected body %q, got %q", pos, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestMiddlewareMaxSize(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		maxSize int
+		opts    []temple.RenderOption
+		want    []string
+	}{
+		"unlimited": {
+			want: []string{"1 visits", "1 visits"},
+		},
+		"fits": {
+			maxSize: 8,
+			want:    []string{"1 visits", "1 visits"},
+		},
+		"too-large": {
+			maxSize: 7,
+			want:    []string{"1 visits", "2 visits"},
+		},
+		"too-large-streamed": {
+			maxSize: 7,
+			opts:    []temple.RenderOption{temple.WithStreaming(2)},
+			want:    []string{"1 visits", "2 visits"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			site := temple.NewCachedSite(fstest.MapFS{
+				"count.html.tmpl": {Data: []byte(`{{ .Page.Count }} visits`)},
+			})
+			var count int
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				count++
+				temple.Render(r.Context(), w, site, countingPage{Count: count}, test.opts...)
+			})
+			cached := cache.Middleware(&cache.Memory{}, cache.MiddlewareOptions{MaxSize: test.maxSize})(handler)
+
+			for pos, want := range test.want {
+				resp := httptest.NewRecorder()
+				cached.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/", nil))
+				if got := resp.Body.String(); got != want {
+					t.Errorf("request %d: expected body %q, got %q", pos, want, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// surrogateKeyPrefix is the prefix of the Backend keys that the Middleware
+// indexes cached pages under, by surrogate key.
+const surrogateKeyPrefix = "temple:surrogate-key:"
+
+// surrogateKeys returns the surrogate keys in the Surrogate-Key header, which
+// temple sets for pages whose Components implement temple.SurrogateKeyer.
+func surrogateKeys(header http.Header) []string {
+	var keys []string
+	for _, value := range header.Values("Surrogate-Key") {
+		for _, key := range strings.Fields(value) {
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// indexPage adds the cache key of a page to the index of each of its
+// surrogate keys. Each index expires after ttl, like the pages in it; as
+// every page in an index was stored no later than the index was last
+// written, the index outlives them.
+func indexPage(ctx context.Context, backend Backend, cacheKey string, keys []string, ttl time.Duration) error {
+	for _, key := range keys {
+		pages, err := getIndex(ctx, backend, key)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(pages, cacheKey) {
+			pages = append(pages, cacheKey)
+		}
+		data, err := json.Marshal(pages)
+		if err != nil {
+			return fmt.Errorf("error encoding surrogate key index: %w", err)
+		}
+		err = backend.Set(ctx, surrogateKeyPrefix+key, data, ttl)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getIndex returns the cache keys of the pages indexed under the surrogate
+// key.
+func getIndex(ctx context.Context, backend Backend, key string) ([]string, error) {
+	data, err := backend.Get(ctx, surrogateKeyPrefix+key)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var pages []string
+	err = json.Unmarshal(data, &pages)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding surrogate key index: %w", err)
+	}
+	return pages, nil
+}
+
+// Purge deletes every page Middleware stored in the Backend that was tagged
+// with the surrogate key, so they're rendered again the next time they're
+// requested. It's the local counterpart of purging the key from a CDN, for
+// use when the content the key stands for changes.
+//
+// Backends can't update the index of a surrogate key atomically, so if
+// multiple instances of a Site store pages with the same surrogate key at the
+// same moment, one of them may be left out of the index, and survive a purge
+// until it expires.
+func Purge(ctx context.Context, backend Backend, surrogateKey string) error {
+	pages, err := getIndex(ctx, backend, surrogateKey)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, page := range pages {
+		err = backend.Delete(ctx, page)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		// keep the index, so purging again can retry the pages
+		// that weren't deleted
+		return errors.Join(errs...)
+	}
+	return backend.Delete(ctx, surrogateKeyPrefix+surrogateKey)
+}
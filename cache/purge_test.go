@@ -0,0 +1,112 @@
+package cache_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"testing/fstest"
+
+	"impractical.co/temple"
+	"impractical.co/temple/cache"
+)
+
+type taggedPage struct {
+	Count int
+	Keys  []string
+}
+
+func (taggedPage) Templates(_ context.Context) []string {
+	return []string{"tagged.html.tmpl"}
+}
+
+func (taggedPage) Key(_ context.Context) string {
+	return "tagged.html.tmpl"
+}
+
+func (taggedPage) ExecutedTemplate(_ context.Context) string {
+	return "tagged.html.tmpl"
+}
+
+func (p taggedPage) SurrogateKeys(_ context.Context) []string {
+	return p.Keys
+}
+
+func TestPurge(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		path string
+		want int
+	}
+
+	tests := map[string]struct {
+		purge []string
+		// requests are made after every page has been requested once,
+		// and the purges have been made
+		requests []request
+	}{
+		"nothing-purged": {
+			requests: []request{{path: "/post", want: 1}, {path: "/list", want: 1}, {path: "/other", want: 1}},
+		},
+		"one-page": {
+			purge:    []string{"post-1"},
+			requests: []request{{path: "/post", want: 2}, {path: "/list", want: 1}, {path: "/other", want: 1}},
+		},
+		"shared-key": {
+			purge:    []string{"posts"},
+			requests: []request{{path: "/post", want: 2}, {path: "/list", want: 2}, {path: "/other", want: 1}},
+		},
+		"unknown-key": {
+			purge:    []string{"post-2"},
+			requests: []request{{path: "/post", want: 1}, {path: "/list", want: 1}, {path: "/other", want: 1}},
+		},
+		"purged-twice": {
+			purge:    []string{"post-1", "post-1"},
+			requests: []request{{path: "/post", want: 2}, {path: "/post", want: 2}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			site := temple.NewCachedSite(fstest.MapFS{
+				"tagged.html.tmpl": {Data: []byte(`{{ .Page.Count }}`)},
+			})
+			keys := map[string][]string{
+				"/post": {"post-1", "posts"},
+				"/list": {"posts"},
+			}
+			counts := map[string]int{}
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				counts[r.URL.Path]++
+				page := taggedPage{Count: counts[r.URL.Path], Keys: keys[r.URL.Path]}
+				temple.Render(r.Context(), w, site, page)
+			})
+			backend := &cache.Memory{}
+			cached := cache.Middleware(backend, cache.MiddlewareOptions{})(handler)
+			get := func(path string) string {
+				resp := httptest.NewRecorder()
+				cached.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, path, nil))
+				return resp.Body.String()
+			}
+
+			for _, path := range []string{"/post", "/list", "/other"} {
+				get(path)
+			}
+			for _, key := range test.purge {
+				err := cache.Purge(context.Background(), backend, key)
+				if err != nil {
+					t.Fatalf("unexpected error purging %q: %s", key, err)
+				}
+			}
+			for pos, req := range test.requests {
+				if got := get(req.path); got != strconv.Itoa(req.want) {
+					t.Errorf("request %d for %s: expected %d, got %s", pos, req.path, req.want, got)
+				}
+			}
+		})
+	}
+}
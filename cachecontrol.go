@@ -0,0 +1,155 @@
+package temple
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CachePolicy is an interface that Renderables can fulfill to control how
+// HTTP caches store the rendered page. When Render is writing to an
+// http.ResponseWriter, the CacheControl will be used to set the Cache-Control
+// and Surrogate-Control headers of the response.
+//
+// The headers are only set if the page renders successfully; server error
+// pages don't inherit the CachePolicy of the page that failed to render.
+type CachePolicy interface {
+	// CacheControl returns the caching policy for the page.
+	CacheControl(context.Context) CacheControl
+}
+
+// CacheControl describes how HTTP caches should store a response.
+type CacheControl struct {
+	// MaxAge is how long the response can be cached for, by browsers and
+	// shared caches alike. It's truncated to the second.
+	MaxAge time.Duration
+
+	// SharedMaxAge is how long the response can be cached for by shared
+	// caches, like CDNs, overriding MaxAge for them. It's truncated to
+	// the second.
+	SharedMaxAge time.Duration
+
+	// SurrogateMaxAge is how long the response can be cached for by
+	// surrogates, like CDNs that support the Surrogate-Control header.
+	// It's truncated to the second.
+	SurrogateMaxAge time.Duration
+
+	// Public marks the response as cacheable by shared caches, even if it
+	// normally wouldn't be.
+	Public bool
+
+	// Private marks the response as only cacheable by the browser, not
+	// by shared caches.
+	Private bool
+
+	// NoStore marks the response as not cacheable at all. If set, all the
+	// other options are ignored.
+	NoStore bool
+}
+
+// cacheControlHeader returns the value of the Cache-Control header for the
+// CacheControl.
+func (c CacheControl) cacheControlHeader() string {
+	if c.NoStore {
+		return "no-store"
+	}
+	var parts []string
+	if c.Public {
+		parts = append(parts, "public")
+	}
+	if c.Private {
+		parts = append(parts, "private")
+	}
+	if c.MaxAge > 0 {
+		parts = append(parts, "max-age="+strconv.FormatInt(int64(c.MaxAge/time.Second), 10))
+	}
+	if c.SharedMaxAge > 0 {
+		parts = append(parts, "s-maxage="+strconv.FormatInt(int64(c.SharedMaxAge/time.Second), 10))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// surrogateControlHeader returns the value of the Surrogate-Control header
+// for the CacheControl.
+func (c CacheControl) surrogateControlHeader() string {
+	if c.NoStore {
+		return "no-store"
+	}
+	if c.SurrogateMaxAge > 0 {
+		return "max-age=" + strconv.FormatInt(int64(c.SurrogateMaxAge/time.Second), 10)
+	}
+	return ""
+}
+
+// setCacheHeaders sets the Cache-Control and Surrogate-Control headers for
+// the page, if `out` is an http.ResponseWriter and the page implements
+// CachePolicy.
+func setCacheHeaders(ctx context.Context, out io.Writer, page Renderable) {
+	resp, ok := out.(http.ResponseWriter)
+	if !ok {
+		return
+	}
+	policy, ok := page.(CachePolicy)
+	if !ok {
+		return
+	}
+	cc := policy.CacheControl(ctx)
+	if header := cc.cacheControlHeader(); header != "" {
+		resp.Header().Set("Cache-Control", header)
+	}
+	if header := cc.surrogateControlHeader(); header != "" {
+		resp.Header().Set("Surrogate-Control", header)
+	}
+}
+
+// SurrogateKeyer is an interface that Components can fulfill to tag the
+// rendered page with surrogate keys (also known as cache tags). CDNs can use
+// these to purge every cached page that relies on some piece of content when
+// that content changes. When Render is writing to an http.ResponseWriter, the
+// surrogate keys of the Renderable and every Component it uses are merged and
+// set as the Surrogate-Key and Cache-Tag headers of the response. The
+// cache.Middleware output cache indexes the pages it stores by these keys, so
+// cache.Purge can purge them locally too.
+type SurrogateKeyer interface {
+	// SurrogateKeys returns the surrogate keys for the Component. Keys
+	// can't contain spaces or commas.
+	SurrogateKeys(context.Context) []string
+}
+
+func getComponentSurrogateKeys(ctx context.Context, components []Component) []string {
+	var results []string
+	seen := map[string]struct{}{}
+	for _, comp := range components {
+		keyer, ok := comp.(SurrogateKeyer)
+		if !ok {
+			continue
+		}
+		for _, key := range keyer.SurrogateKeys(ctx) {
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			results = append(results, key)
+			seen[key] = struct{}{}
+		}
+	}
+	return results
+}
+
+// setSurrogateKeyHeaders sets the Surrogate-Key and Cache-Tag headers for the
+// page, if `out` is an http.ResponseWriter and the page or any of the
+// Components it uses implement SurrogateKeyer.
+func setSurrogateKeyHeaders(ctx context.Context, out io.Writer, components []Component) {
+	resp, ok := out.(http.ResponseWriter)
+	if !ok {
+		return
+	}
+	keys := getComponentSurrogateKeys(ctx, components)
+	if len(keys) < 1 {
+		return
+	}
+	resp.Header().Set("Surrogate-Key", strings.Join(keys, " "))
+	resp.Header().Set("Cache-Tag", strings.Join(keys, ","))
+}
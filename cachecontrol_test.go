@@ -0,0 +1,158 @@
+package temple_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"impractical.co/temple"
+)
+
+func TestCachePolicy(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		policy        temple.CacheControl
+		wantCache     string
+		wantSurrogate string
+	}{
+		"empty": {},
+		"max-age": {
+			policy:    temple.CacheControl{MaxAge: 90*time.Second + 500*time.Millisecond},
+			wantCache: "max-age=90",
+		},
+		"public-shared": {
+			policy:        temple.CacheControl{Public: true, MaxAge: time.Minute, SharedMaxAge: time.Hour, SurrogateMaxAge: 24 * time.Hour},
+			wantCache:     "public, max-age=60, s-maxage=3600",
+			wantSurrogate: "max-age=86400",
+		},
+		"private": {
+			policy:    temple.CacheControl{Private: true, MaxAge: time.Minute},
+			wantCache: "private, max-age=60",
+		},
+		"no-store": {
+			policy:        temple.CacheControl{NoStore: true, Public: true, MaxAge: time.Minute, SurrogateMaxAge: time.Minute},
+			wantCache:     "no-store",
+			wantSurrogate: "no-store",
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			site := temple.NewCachedSite(fstest.MapFS{
+				"page.html.tmpl": {Data: []byte(`{{ .Page.Check }}`)},
+			})
+			resp := httptest.NewRecorder()
+			result := temple.Render(context.Background(), resp, site, testPage{policy: test.policy})
+			if result.Err != nil {
+				t.Fatalf("unexpected error: %s", result.Err)
+			}
+			if got := resp.Header().Get("Cache-Control"); got != test.wantCache {
+				t.Errorf("expected Cache-Control %q, got %q", test.wantCache, got)
+			}
+			if got := resp.Header().Get("Surrogate-Control"); got != test.wantSurrogate {
+				t.Errorf("expected Surrogate-Control %q, got %q", test.wantSurrogate, got)
+			}
+		})
+	}
+}
+
+func TestCachePolicyErrorPage(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string][]temple.RenderOption{
+		"buffered":  nil,
+		"streaming": {temple.WithStreaming(1024)},
+	}
+
+	for name, opts := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			site := errorPageSite{
+				CachedSite: temple.NewCachedSite(fstest.MapFS{
+					"page.html.tmpl":  {Data: []byte(`{{ .Page.Check }}`)},
+					"error.html.tmpl": {Data: []byte(`Something went wrong.`)},
+				}),
+				errorPage: testPage{template: "error.html.tmpl"},
+			}
+			page := testPage{
+				policy: temple.CacheControl{Public: true, MaxAge: time.Hour, SurrogateMaxAge: time.Hour},
+				fail:   true,
+			}
+			resp := httptest.NewRecorder()
+			result := temple.Render(context.Background(), resp, site, page, opts...)
+			if result.Err == nil {
+				t.Fatal("expected an error, got none")
+			}
+			if resp.Code != http.StatusInternalServerError {
+				t.Errorf("expected status %d, got %d", http.StatusInternalServerError, resp.Code)
+			}
+			if got := resp.Body.String(); got != "Something went wrong." {
+				t.Errorf("expected error page, got %q", got)
+			}
+			for _, header := range []string{"Cache-Control", "Surrogate-Control"} {
+				if got := resp.Header().Values(header); len(got) > 0 {
+					t.Errorf("expected no %s header on the error page, got %q", header, got)
+				}
+			}
+		})
+	}
+}
+
+type surrogateComponent struct {
+	keys []string
+}
+
+func (*surrogateComponent) Templates(_ context.Context) []string {
+	return nil
+}
+
+func (c *surrogateComponent) SurrogateKeys(_ context.Context) []string {
+	return c.keys
+}
+
+func TestSurrogateKeys(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		page          testPage
+		wantSurrogate string
+		wantCacheTag  string
+	}{
+		"none": {},
+		"page": {
+			page:          testPage{keys: []string{"post-1", "posts"}},
+			wantSurrogate: "post-1 posts",
+			wantCacheTag:  "post-1,posts",
+		},
+		"merged-with-components": {
+			page:          testPage{keys: []string{"post-1", "posts"}, components: []temple.Component{&surrogateComponent{keys: []string{"posts", "author-2"}}}},
+			wantSurrogate: "post-1 posts author-2",
+			wantCacheTag:  "post-1,posts,author-2",
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			site := temple.NewCachedSite(fstest.MapFS{
+				"page.html.tmpl": {Data: []byte(`ok`)},
+			})
+			resp := httptest.NewRecorder()
+			result := temple.Render(context.Background(), resp, site, test.page)
+			if result.Err != nil {
+				t.Fatalf("unexpected error: %s", result.Err)
+			}
+			if got := resp.Header().Get("Surrogate-Key"); got != test.wantSurrogate {
+				t.Errorf("expected Surrogate-Key %q, got %q", test.wantSurrogate, got)
+			}
+			if got := resp.Header().Get("Cache-Tag"); got != test.wantCacheTag {
+				t.Errorf("expected Cache-Tag %q, got %q", test.wantCacheTag, got)
+			}
+		})
+	}
+}
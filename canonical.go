@@ -0,0 +1,126 @@
+package temple
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// TrailingSlashPolicy controls how CanonicalPolicy handles trailing slashes
+// on URL paths.
+type TrailingSlashPolicy int
+
+const (
+	// TrailingSlashIgnore leaves URL paths as they are, whether they have
+	// a trailing slash or not.
+	TrailingSlashIgnore TrailingSlashPolicy = iota
+
+	// TrailingSlashAdd adds a trailing slash to URL paths that don't have
+	// one. Paths whose last segment contains a dot, like "/robots.txt",
+	// are presumed to be files and left alone.
+	TrailingSlashAdd
+
+	// TrailingSlashRemove removes the trailing slash from URL paths that
+	// have one. The root path, "/", is left alone.
+	TrailingSlashRemove
+)
+
+// CanonicalPolicy describes the canonical form of the URLs a Site serves.
+// It's used by CanonicalRedirectMiddleware to redirect requests to their
+// canonical URL, and can be used to build the URLs for <link rel="canonical">
+// elements, so the two always agree.
+type CanonicalPolicy struct {
+	// TrailingSlash controls whether canonical URL paths end in a slash.
+	TrailingSlash TrailingSlashPolicy
+
+	// Lowercase makes the paths of canonical URLs lowercase.
+	Lowercase bool
+
+	// Host is the host canonical URLs should use. If empty, any host is
+	// considered canonical.
+	Host string
+
+	// Scheme is the scheme CanonicalRedirectMiddleware uses when
+	// redirecting to a different host, like "https". If empty, it's
+	// "https" if the request was made over TLS, or "http" if not, which
+	// is wrong behind a proxy that terminates TLS, so Sites behind one
+	// should set it.
+	Scheme string
+}
+
+// Canonicalize returns the canonical version of the passed URL.
+func (c CanonicalPolicy) Canonicalize(u url.URL) url.URL {
+	if c.Host != "" {
+		u.Host = c.Host
+	}
+	p := u.Path
+	// paths starting with more than one slash would be protocol-relative
+	// URLs if used as a redirect target
+	if strings.HasPrefix(p, "//") {
+		p = "/" + strings.TrimLeft(p, "/")
+	}
+	if c.Lowercase {
+		p = strings.ToLower(p)
+	}
+	switch c.TrailingSlash {
+	case TrailingSlashAdd:
+		if !strings.HasSuffix(p, "/") && !strings.Contains(path.Base(p), ".") {
+			p += "/"
+		}
+	case TrailingSlashRemove:
+		if p != "/" {
+			p = strings.TrimRight(p, "/")
+			if p == "" {
+				p = "/"
+			}
+		}
+	}
+	if p != u.Path {
+		u.Path = p
+		u.RawPath = ""
+	}
+	return u
+}
+
+// CanonicalRedirectMiddleware returns a middleware that redirects requests
+// for non-canonical URLs to their canonical URL, as described by the passed
+// CanonicalPolicy. GET and HEAD requests are redirected with a 301 Moved
+// Permanently status; other requests are redirected with a 308 Permanent
+// Redirect status, so clients will preserve the method and body.
+//
+// Requests for canonical URLs are passed to the wrapped http.Handler. Paths
+// starting with more than one slash are never canonical, so they're
+// redirected to the path with a single leading slash, on the same host.
+func CanonicalRedirectMiddleware(policy CanonicalPolicy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			current := *r.URL
+			current.Host = r.Host
+			canonical := policy.Canonicalize(current)
+			if canonical.Host == current.Host && canonical.EscapedPath() == current.EscapedPath() {
+				next.ServeHTTP(w, r)
+				return
+			}
+			target := canonical.RequestURI()
+			if strings.HasPrefix(target, "//") {
+				// never redirect to a protocol-relative URL
+				target = "/" + strings.TrimLeft(target, "/")
+			}
+			if canonical.Host != current.Host {
+				scheme := policy.Scheme
+				if scheme == "" && r.TLS != nil {
+					scheme = "https"
+				} else if scheme == "" {
+					scheme = "http"
+				}
+				target = scheme + "://" + canonical.Host + target
+			}
+			status := http.StatusPermanentRedirect
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				status = http.StatusMovedPermanently
+			}
+			http.Redirect(w, r, target, status)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package temple_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"impractical.co/temple"
+)
+
+func TestCanonicalRedirectMiddleware(t *testing.T) {
+	t.Parallel()
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	cases := map[string]struct {
+		policy       temple.CanonicalPolicy
+		method       string
+		target       string
+		wantStatus   int
+		wantLocation string
+	}{
+		"canonical": {
+			policy:     temple.CanonicalPolicy{TrailingSlash: temple.TrailingSlashRemove},
+			target:     "http://example.com/about",
+			wantStatus: http.StatusTeapot,
+		},
+		"remove-slash-protocol-relative": {
+			policy:       temple.CanonicalPolicy{TrailingSlash: temple.TrailingSlashRemove},
+			target:       "http://example.com//evil.com/",
+			wantStatus:   http.StatusMovedPermanently,
+			wantLocation: "/evil.com",
+		},
+		"add-slash-protocol-relative": {
+			policy:       temple.CanonicalPolicy{TrailingSlash: temple.TrailingSlashAdd},
+			target:       "http://example.com//evil.com/path",
+			wantStatus:   http.StatusMovedPermanently,
+			wantLocation: "/evil.com/path/",
+		},
+		"lowercase-protocol-relative": {
+			policy:       temple.CanonicalPolicy{Lowercase: true},
+			target:       "http://example.com///Evil.com",
+			wantStatus:   http.StatusMovedPermanently,
+			wantLocation: "/evil.com",
+		},
+		"protocol-relative-already-canonical": {
+			policy:       temple.CanonicalPolicy{},
+			target:       "http://example.com//evil.com",
+			wantStatus:   http.StatusMovedPermanently,
+			wantLocation: "/evil.com",
+		},
+		"host-change": {
+			policy:       temple.CanonicalPolicy{Host: "www.example.com"},
+			target:       "http://example.com/about?ref=1",
+			wantStatus:   http.StatusMovedPermanently,
+			wantLocation: "http://www.example.com/about?ref=1",
+		},
+		"host-change-scheme": {
+			policy:       temple.CanonicalPolicy{Host: "www.example.com", Scheme: "https"},
+			target:       "http://example.com//evil.com",
+			wantStatus:   http.StatusMovedPermanently,
+			wantLocation: "https://www.example.com/evil.com",
+		},
+		"head": {
+			policy:       temple.CanonicalPolicy{Lowercase: true},
+			method:       http.MethodHead,
+			target:       "http://example.com/About",
+			wantStatus:   http.StatusMovedPermanently,
+			wantLocation: "/about",
+		},
+		"post": {
+			policy:       temple.CanonicalPolicy{Lowercase: true},
+			method:       http.MethodPost,
+			target:       "http://example.com/About",
+			wantStatus:   http.StatusPermanentRedirect,
+			wantLocation: "/about",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			method := tc.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			resp := httptest.NewRecorder()
+			temple.CanonicalRedirectMiddleware(tc.policy)(next).ServeHTTP(resp, httptest.NewRequest(method, tc.target, nil))
+			if resp.Code != tc.wantStatus {
+				t.Errorf("expected status %d, got %d", tc.wantStatus, resp.Code)
+			}
+			if got := resp.Header().Get("Location"); got != tc.wantLocation {
+				t.Errorf("expected Location %q, got %q", tc.wantLocation, got)
+			}
+		})
+	}
+}
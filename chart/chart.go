@@ -0,0 +1,277 @@
+// Package chart provides temple Components for rendering simple charts as
+// accessible inline SVG, for dashboards that don't need a JavaScript charting
+// library: Sparkline, a small line chart showing a trend, and BarChart.
+//
+// Both Components supply their own templates and embed the CSS they need, so
+// all that's required to use them is to include them in the UseComponents
+// output of a Component and execute their templates:
+//
+//	{{ template "chart/sparkline.html.tmpl" .Page.Visitors }}
+//	{{ template "chart/bar.html.tmpl" .Page.Signups }}
+//
+// Charts are colored using CSS custom properties, so they can be themed
+// without replacing their templates: --temple-chart-color sets the color of
+// lines and bars, --temple-chart-fill the area under a Sparkline, and
+// --temple-chart-text the color of labels.
+package chart
+
+import (
+	"context"
+	"embed"
+	"html/template"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"impractical.co/temple"
+)
+
+//go:embed templates
+var templates embed.FS
+
+const css = `
+.temple-chart { display: inline-block; vertical-align: middle; overflow: visible; }
+.temple-chart-line { fill: none; stroke: var(--temple-chart-color, currentColor); stroke-width: 1.5; vector-effect: non-scaling-stroke; }
+.temple-chart-area { fill: var(--temple-chart-fill, none); stroke: none; }
+.temple-chart-bar { fill: var(--temple-chart-color, currentColor); }
+.temple-chart-label { fill: var(--temple-chart-text, currentColor); font-size: 10px; text-anchor: middle; }
+`
+
+// labelHeight is the height, in pixels, reserved below a BarChart for the
+// labels of its bars.
+const labelHeight = 14
+
+var (
+	_ temple.Component           = Sparkline{}
+	_ temple.TemplateDirProvider = Sparkline{}
+	_ temple.CSSEmbedder         = Sparkline{}
+)
+
+// Sparkline is a Component that renders a small line chart of its Values,
+// meant to be displayed inline with text.
+type Sparkline struct {
+	// Values are the data points of the line, in order.
+	Values []float64
+
+	// Label is the accessible name of the chart, which should describe
+	// what the data shows, like "Visitors over the last 30 days".
+	Label string
+
+	// Width is the width of the chart, in pixels. Defaults to 100.
+	Width int
+
+	// Height is the height of the chart, in pixels. Defaults to 20.
+	Height int
+
+	// Area fills the area under the line.
+	Area bool
+}
+
+// Templates returns the template needed to render the Sparkline.
+func (Sparkline) Templates(_ context.Context) []string {
+	return []string{"chart/sparkline.html.tmpl"}
+}
+
+// TemplateDir returns the fs.FS containing the Sparkline's template.
+func (Sparkline) TemplateDir(_ context.Context) fs.FS {
+	return temple.SubFS(templates, "templates")
+}
+
+// EmbedCSS returns the CSS used to style charts.
+func (Sparkline) EmbedCSS(_ context.Context) template.CSS {
+	return css
+}
+
+// PixelWidth returns the width of the chart, in pixels.
+func (s Sparkline) PixelWidth() int {
+	return withDefault(s.Width, 100)
+}
+
+// PixelHeight returns the height of the chart, in pixels.
+func (s Sparkline) PixelHeight() int {
+	return withDefault(s.Height, 20)
+}
+
+// Points returns the points of the line, for use as the points attribute of
+// a <polyline> element. The line is scaled to fill the chart, with the
+// smallest value at the bottom and the largest at the top.
+func (s Sparkline) Points() string {
+	width, height := float64(s.PixelWidth()), float64(s.PixelHeight())
+	low, high := valueRange(s.Values)
+	points := make([]string, 0, len(s.Values))
+	for i, val := range s.Values {
+		x := 0.0
+		if len(s.Values) > 1 {
+			x = width * float64(i) / float64(len(s.Values)-1)
+		}
+		y := height / 2
+		if high > low {
+			y = height - height*(val-low)/(high-low)
+		}
+		points = append(points, formatNumber(x)+","+formatNumber(y))
+	}
+	return strings.Join(points, " ")
+}
+
+// AreaPoints returns the points of the area under the line, for use as the
+// points attribute of a <polygon> element.
+func (s Sparkline) AreaPoints() string {
+	if len(s.Values) < 1 {
+		return ""
+	}
+	width, height := formatNumber(float64(s.PixelWidth())), formatNumber(float64(s.PixelHeight()))
+	first := "0"
+	if len(s.Values) == 1 {
+		width = first
+	}
+	return first + "," + height + " " + s.Points() + " " + width + "," + height
+}
+
+// Summary describes the data for screen readers: the first, last, lowest,
+// and highest values.
+func (s Sparkline) Summary() string {
+	if len(s.Values) < 1 {
+		return "No data"
+	}
+	low, high := valueRange(s.Values)
+	return "From " + formatNumber(s.Values[0]) + " to " + formatNumber(s.Values[len(s.Values)-1]) +
+		", lowest " + formatNumber(low) + ", highest " + formatNumber(high)
+}
+
+// Bar is a single bar in a BarChart.
+type Bar struct {
+	// Label is the name of the bar, displayed below it.
+	Label string
+
+	// Value is the height of the bar.
+	Value float64
+}
+
+var (
+	_ temple.Component           = BarChart{}
+	_ temple.TemplateDirProvider = BarChart{}
+	_ temple.CSSEmbedder         = BarChart{}
+)
+
+// BarChart is a Component that renders a vertical bar chart of its Bars.
+// Negative values are drawn as empty bars.
+type BarChart struct {
+	// Bars are the bars of the chart, from left to right.
+	Bars []Bar
+
+	// Label is the accessible name of the chart, which should describe
+	// what the data shows, like "Signups per month".
+	Label string
+
+	// Width is the width of the chart, in pixels. Defaults to 300.
+	Width int
+
+	// Height is the height of the chart, including the labels below the
+	// bars, in pixels. Defaults to 150.
+	Height int
+}
+
+// Templates returns the template needed to render the BarChart.
+func (BarChart) Templates(_ context.Context) []string {
+	return []string{"chart/bar.html.tmpl"}
+}
+
+// TemplateDir returns the fs.FS containing the BarChart's template.
+func (BarChart) TemplateDir(_ context.Context) fs.FS {
+	return temple.SubFS(templates, "templates")
+}
+
+// EmbedCSS returns the CSS used to style charts.
+func (BarChart) EmbedCSS(_ context.Context) template.CSS {
+	return css
+}
+
+// PixelWidth returns the width of the chart, in pixels.
+func (b BarChart) PixelWidth() int {
+	return withDefault(b.Width, 300)
+}
+
+// PixelHeight returns the height of the chart, in pixels.
+func (b BarChart) PixelHeight() int {
+	return withDefault(b.Height, 150)
+}
+
+// BarRect is the position and size of a bar in a BarChart, in pixels, along
+// with the Bar it represents.
+type BarRect struct {
+	Bar
+
+	// X, Y, Width, and Height are the position and size of the bar.
+	X, Y, Width, Height string
+
+	// LabelX and LabelY are the position of the Bar's label.
+	LabelX, LabelY string
+
+	// DisplayValue is the Bar's Value, formatted for display.
+	DisplayValue string
+}
+
+// Rects returns the position and size of each of the chart's Bars, scaled so
+// the largest value fills the height of the chart, above the labels.
+func (b BarChart) Rects() []BarRect {
+	if len(b.Bars) < 1 {
+		return nil
+	}
+	width := float64(b.PixelWidth())
+	plotHeight := float64(b.PixelHeight() - labelHeight)
+	highest := 0.0
+	for _, bar := range b.Bars {
+		highest = max(highest, bar.Value)
+	}
+	slot := width / float64(len(b.Bars))
+	// leave a gap of a fifth of each slot between bars
+	barWidth := slot * 0.8
+	results := make([]BarRect, 0, len(b.Bars))
+	for i, bar := range b.Bars {
+		height := 0.0
+		if highest > 0 && bar.Value > 0 {
+			height = plotHeight * bar.Value / highest
+		}
+		x := slot*float64(i) + (slot-barWidth)/2
+		results = append(results, BarRect{
+			Bar:    bar,
+			X:      formatNumber(x),
+			Y:      formatNumber(plotHeight - height),
+			Width:  formatNumber(barWidth),
+			Height: formatNumber(height),
+			LabelX: formatNumber(x + barWidth/2),
+			LabelY: formatNumber(plotHeight + labelHeight - 3),
+
+			DisplayValue: formatNumber(bar.Value),
+		})
+	}
+	return results
+}
+
+func withDefault(val, def int) int {
+	if val < 1 {
+		return def
+	}
+	return val
+}
+
+// valueRange returns the lowest and highest of the values.
+func valueRange(values []float64) (float64, float64) {
+	if len(values) < 1 {
+		return 0, 0
+	}
+	low, high := values[0], values[0]
+	for _, val := range values[1:] {
+		low = min(low, val)
+		high = max(high, val)
+	}
+	return low, high
+}
+
+// formatNumber formats the number with at most two decimal places, which is
+// plenty of precision for pixel positions.
+func formatNumber(val float64) string {
+	result := strconv.FormatFloat(val, 'f', 2, 64)
+	result = strings.TrimRight(result, "0")
+	return strings.TrimSuffix(result, ".")
+}
@@ -0,0 +1,85 @@
+package chart_test
+
+import (
+	"context"
+	"os"
+	"testing/fstest"
+
+	"impractical.co/temple"
+	"impractical.co/temple/chart"
+)
+
+type DashboardPage struct {
+	Visitors chart.Sparkline
+	Signups  chart.BarChart
+}
+
+func (DashboardPage) Templates(_ context.Context) []string {
+	return []string{"dashboard.html.tmpl"}
+}
+
+func (d DashboardPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{d.Visitors, d.Signups}
+}
+
+func (DashboardPage) Key(_ context.Context) string {
+	return "dashboard.html.tmpl"
+}
+
+func (DashboardPage) ExecutedTemplate(_ context.Context) string {
+	return "dashboard.html.tmpl"
+}
+
+func Example() {
+	templates := fstest.MapFS{
+		"dashboard.html.tmpl": {Data: []byte(`{{ template "chart/sparkline.html.tmpl" .Page.Visitors }}
+{{ template "chart/bar.html.tmpl" .Page.Signups }}`)},
+	}
+	site := temple.NewCachedSite(templates)
+
+	temple.Render(context.Background(), os.Stdout, site, DashboardPage{
+		Visitors: chart.Sparkline{
+			Label:  "Visitors this week",
+			Values: []float64{12, 18, 9, 24, 30},
+			Area:   true,
+		},
+		Signups: chart.BarChart{
+			Label:  "Signups per quarter",
+			Width:  200,
+			Height: 100,
+			Bars: []chart.Bar{
+				{Label: "Q1", Value: 40},
+				{Label: "Q2", Value: 86},
+				{Label: "Q3", Value: 65.5},
+				{Label: "Q4", Value: 0},
+			},
+		},
+	})
+
+	//Output:
+	// <svg class="temple-chart temple-chart-sparkline" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 100 20" width="100" height="20" role="img" aria-label="Visitors this week">
+	// 	<title>Visitors this week</title>
+	// 	<desc>From 12 to 30, lowest 9, highest 30</desc>
+	// 	<polygon class="temple-chart-area" points="0,20 0,17.14 25,11.43 50,20 75,5.71 100,0 100,20"/>
+	// 	<polyline class="temple-chart-line" points="0,17.14 25,11.43 50,20 75,5.71 100,0"/>
+	// </svg>
+	// <svg class="temple-chart temple-chart-bar-chart" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 200 100" width="200" height="100" role="img" aria-label="Signups per quarter">
+	// 	<title>Signups per quarter</title>
+	// 	<g>
+	// 		<rect class="temple-chart-bar" x="5" y="46" width="40" height="40"><title>Q1: 40</title></rect>
+	// 		<text class="temple-chart-label" x="25" y="97">Q1</text>
+	// 	</g>
+	// 	<g>
+	// 		<rect class="temple-chart-bar" x="55" y="0" width="40" height="86"><title>Q2: 86</title></rect>
+	// 		<text class="temple-chart-label" x="75" y="97">Q2</text>
+	// 	</g>
+	// 	<g>
+	// 		<rect class="temple-chart-bar" x="105" y="20.5" width="40" height="65.5"><title>Q3: 65.5</title></rect>
+	// 		<text class="temple-chart-label" x="125" y="97">Q3</text>
+	// 	</g>
+	// 	<g>
+	// 		<rect class="temple-chart-bar" x="155" y="86" width="40" height="0"><title>Q4: 0</title></rect>
+	// 		<text class="temple-chart-label" x="175" y="97">Q4</text>
+	// 	</g>
+	// </svg>
+}
@@ -0,0 +1,8 @@
+package chart
+
+import "impractical.co/temple"
+
+// Package returns the temple.ComponentPackage for Sparkline and BarChart.
+func Package() (temple.ComponentPackage, error) {
+	return temple.NewComponentPackage("chart", templates, "templates/chart", "", Sparkline{}, BarChart{})
+}
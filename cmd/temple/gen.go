@@ -0,0 +1,508 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template/parse"
+)
+
+// genFileName is the name of the file gen writes.
+const genFileName = "temple_gen.go"
+
+// runGen runs the gen command, returning the exit code.
+func runGen(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, `Usage: temple gen [flags]
+
+Generates Go types for annotated templates, with a field for each .Page.X
+the template uses. Templates are annotated with a comment:
+
+	{{/* temple:page HomePage */}}
+	{{/* temple:component Card */}}
+
+Fields are strings unless they're ranged over, which makes them slices, only
+used as conditions, which makes them bools, or have fields of their own,
+which makes them structs. Methods called with arguments, like
+.Page.Date.Format "2006", aren't fields, but methods called without them
+can't be told apart from fields. Types can be set explicitly, importing any
+packages needed, and must be for fields whose methods are called, like
+.Page.Date, with nested fields named by their path:
+
+	{{/*
+	temple:field Date time.Time
+	temple:field Author.Joined time.Time
+	temple:import time
+	*/}}
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	dir := flags.String("dir", ".", "the directory to write "+genFileName+" to")
+	templates := flags.String("templates", "templates", "the Site's template directory, relative to -dir")
+	pkg := flags.String("package", "", "the Go package name; detected from -dir if empty")
+	left := flags.String("left", "", "the left template delimiter, if it isn't {{")
+	right := flags.String("right", "", "the right template delimiter, if it isn't }}")
+	err := flags.Parse(args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return 2
+	}
+	if *pkg == "" {
+		*pkg, err = detectPackage(*dir)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+	types, err := genTypes(os.DirFS(filepath.Join(*dir, *templates)), *left, *right)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	src, err := genSource(*pkg, types)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	out := filepath.Join(*dir, genFileName)
+	err = os.WriteFile(out, src, 0o600)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "generated %d types in %s\n", len(types), out)
+	return 0
+}
+
+// genType is a type to generate for an annotated template.
+type genType struct {
+	// Kind is "page" or "component".
+	Kind string
+
+	// Name is the name of the type.
+	Name string
+
+	// Template is the path of the annotated template.
+	Template string
+
+	// Fields are the fields inferred from the template.
+	Fields *genField
+
+	// Types are the explicitly set types of fields, by the field's name,
+	// or the names of it and the fields containing it joined by dots.
+	Types map[string]string
+
+	// Imports are the packages the explicitly set types need.
+	Imports []string
+}
+
+// genField is something a template refers to, and the things it refers to
+// within it.
+type genField struct {
+	name     string
+	children []*genField
+	// elem describes the elements of the field, if it's ranged over
+	elem *genField
+	// cond is true if the field is used as a condition
+	cond bool
+	// value is true if the field is used other than as a condition
+	value bool
+	// receiver is true if the field's methods are called
+	receiver bool
+}
+
+// child returns the child with the name, adding it if it doesn't exist.
+func (g *genField) child(name string) *genField {
+	for _, c := range g.children {
+		if c.name == name {
+			return c
+		}
+	}
+	c := &genField{name: name}
+	g.children = append(g.children, c)
+	return c
+}
+
+// lookup returns the field reached by following the identifiers from g,
+// adding any fields that don't exist.
+func (g *genField) lookup(idents []string) *genField {
+	field := g
+	for _, ident := range idents {
+		field = field.child(ident)
+	}
+	return field
+}
+
+// genTypes parses every .tmpl file in dir, returning a genType for each one
+// that's annotated, sorted by Name.
+func genTypes(dir fs.FS, left, right string) ([]genType, error) {
+	var types []genType
+	err := fs.WalkDir(dir, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(path) != ".tmpl" {
+			return nil
+		}
+		contents, err := fs.ReadFile(dir, path)
+		if err != nil {
+			return err
+		}
+		trees := map[string]*parse.Tree{}
+		tree := parse.New(path)
+		tree.Mode = parse.SkipFuncCheck | parse.ParseComments
+		_, err = tree.Parse(string(contents), left, right, trees)
+		if err != nil {
+			return err
+		}
+		root, ok := trees[path]
+		if !ok {
+			return nil
+		}
+		typ, ok, err := genAnnotations(root.Root, path)
+		if err != nil || !ok {
+			return err
+		}
+		data := &genField{}
+		// templates defined in the file are usually executed with the
+		// same data, so include their fields too
+		for _, name := range slices.Sorted(maps.Keys(trees)) {
+			genWalk(trees[name].Root, data, data)
+		}
+		if typ.Kind == "page" {
+			typ.Fields = data.child("Page")
+		} else {
+			typ.Fields = data
+		}
+		// fields with explicit types are included even if they're
+		// not used
+		for _, name := range slices.Sorted(maps.Keys(typ.Types)) {
+			if !strings.Contains(name, ".") {
+				typ.Fields.child(name)
+			}
+		}
+		types = append(types, typ)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading templates: %w", err)
+	}
+	slices.SortFunc(types, func(a, b genType) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	for i := 1; i < len(types); i++ {
+		if types[i].Name == types[i-1].Name {
+			return nil, fmt.Errorf("%s and %s both declare %s", types[i-1].Template, types[i].Template, types[i].Name)
+		}
+	}
+	return types, nil
+}
+
+// genAnnotations reads the temple: annotations from the comments at the top
+// level of the template. ok is false if the template doesn't declare a page
+// or component.
+func genAnnotations(root *parse.ListNode, path string) (typ genType, ok bool, err error) {
+	typ = genType{Template: path, Types: map[string]string{}}
+	if root == nil {
+		return typ, false, nil
+	}
+	for _, node := range root.Nodes {
+		comment, isComment := node.(*parse.CommentNode)
+		if !isComment {
+			continue
+		}
+		text := strings.TrimSuffix(strings.TrimPrefix(comment.Text, "/*"), "*/")
+		for _, line := range strings.Split(text, "\n") {
+			fields := strings.Fields(line)
+			if len(fields) < 1 || !strings.HasPrefix(fields[0], "temple:") {
+				continue
+			}
+			switch directive := strings.TrimPrefix(fields[0], "temple:"); directive {
+			case "page", "component":
+				if len(fields) != 2 || !token.IsIdentifier(fields[1]) || !token.IsExported(fields[1]) {
+					return typ, false, fmt.Errorf("%s: temple:%s needs an exported type name", path, directive)
+				}
+				typ.Kind, typ.Name = directive, fields[1]
+			case "field":
+				if len(fields) < 3 {
+					return typ, false, fmt.Errorf("%s: temple:field needs a field name and type", path)
+				}
+				typ.Types[fields[1]] = strings.Join(fields[2:], " ")
+			case "import":
+				if len(fields) != 2 {
+					return typ, false, fmt.Errorf("%s: temple:import needs a package path", path)
+				}
+				typ.Imports = append(typ.Imports, fields[1])
+			default:
+				return typ, false, fmt.Errorf("%s: unknown annotation %s", path, fields[0])
+			}
+		}
+	}
+	return typ, typ.Name != "", nil
+}
+
+// genWalk records the fields referred to in the parse tree rooted at node,
+// where dot refers to the genField `dot`, or nil if it's unknown, and $
+// refers to `data`.
+func genWalk(node parse.Node, dot, data *genField) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			genWalk(child, dot, data)
+		}
+	case *parse.ActionNode:
+		genPipe(n.Pipe, dot, data, false)
+	case *parse.TemplateNode:
+		genPipe(n.Pipe, dot, data, false)
+	case *parse.IfNode:
+		genPipe(n.Pipe, dot, data, true)
+		genWalk(n.List, dot, data)
+		genWalk(n.ElseList, dot, data)
+	case *parse.RangeNode:
+		field := genPipeField(n.Pipe, dot, data)
+		var elem *genField
+		if field != nil {
+			if field.elem == nil {
+				field.elem = &genField{}
+			}
+			elem = field.elem
+		} else {
+			genPipe(n.Pipe, dot, data, false)
+		}
+		genWalk(n.List, elem, data)
+		genWalk(n.ElseList, dot, data)
+	case *parse.WithNode:
+		field := genPipeField(n.Pipe, dot, data)
+		if field == nil {
+			genPipe(n.Pipe, dot, data, false)
+		}
+		genWalk(n.List, field, data)
+		genWalk(n.ElseList, dot, data)
+	}
+}
+
+// genPipeField returns the field the pipeline refers to, if it consists of
+// nothing but a reference to a field.
+func genPipeField(pipe *parse.PipeNode, dot, data *genField) *genField {
+	if pipe == nil || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return nil
+	}
+	return genRef(pipe.Cmds[0].Args[0], dot, data)
+}
+
+// genPipe records the fields referred to by the pipeline, as conditions if
+// cond is true.
+func genPipe(pipe *parse.PipeNode, dot, data *genField, cond bool) {
+	if pipe == nil {
+		return
+	}
+	for _, cmd := range pipe.Cmds {
+		// only a bare field is a condition; anything passed to a
+		// function is a value
+		isCond := cond && len(pipe.Cmds) == 1 && len(cmd.Args) == 1
+		for i, arg := range cmd.Args {
+			if sub, ok := arg.(*parse.PipeNode); ok {
+				genPipe(sub, dot, data, false)
+				continue
+			}
+			// a field followed by arguments is a method call, like
+			// .Page.Date.Format "2006", so the method isn't a field
+			receiver := false
+			if field, ok := arg.(*parse.FieldNode); ok && i == 0 && len(cmd.Args) > 1 && len(field.Ident) > 1 {
+				arg = &parse.FieldNode{NodeType: parse.NodeField, Pos: field.Pos, Ident: field.Ident[:len(field.Ident)-1]}
+				receiver = true
+			}
+			field := genRef(arg, dot, data)
+			if field == nil {
+				continue
+			}
+			field.receiver = field.receiver || receiver
+			if isCond {
+				field.cond = true
+			} else {
+				field.value = true
+			}
+		}
+	}
+}
+
+// genRef returns the field the node refers to, or nil if it isn't a
+// reference to a field that can be resolved.
+func genRef(node parse.Node, dot, data *genField) *genField {
+	switch n := node.(type) {
+	case *parse.FieldNode:
+		if dot == nil {
+			return nil
+		}
+		return dot.lookup(n.Ident)
+	case *parse.VariableNode:
+		if len(n.Ident) < 2 || n.Ident[0] != "$" {
+			return nil
+		}
+		return data.lookup(n.Ident[1:])
+	}
+	return nil
+}
+
+// genStruct is a struct type to declare.
+type genStruct struct {
+	name   string
+	fields []genStructField
+}
+
+// genStructField is a field of a genStruct.
+type genStructField struct {
+	name string
+	typ  string
+}
+
+// genFieldType returns the Go type of the field, appending any struct types
+// it needs to structs. name is the name to give the field's type if it's a
+// struct, and path is the field's name joined to the names of the fields
+// containing it by dots.
+func genFieldType(field *genField, name, path string, explicit map[string]string, structs *[]genStruct) (string, error) {
+	if typ, ok := explicit[path]; ok {
+		return typ, nil
+	}
+	switch {
+	case field.elem != nil:
+		if len(field.elem.children) > 0 {
+			typ, err := genStructType(field.elem, name+"Item", path, explicit, structs)
+			return "[]" + typ, err
+		}
+		return "[]string", nil
+	case field.receiver:
+		// the methods called aren't known, so neither is the type
+		return "", fmt.Errorf("the methods of %s are called, so its type can't be inferred; set it with temple:field %s <type>", path, path)
+	case len(field.children) > 0:
+		return genStructType(field, name, path, explicit, structs)
+	case field.cond && !field.value:
+		return "bool", nil
+	default:
+		return "string", nil
+	}
+}
+
+// genStructType appends a struct type named `name`, with a field for each of
+// the field's children, and any struct types they need, to structs,
+// returning its name. path is the path of the field, as for genFieldType, or
+// an empty string for the generated type itself.
+func genStructType(field *genField, name, path string, explicit map[string]string, structs *[]genStruct) (string, error) {
+	index := len(*structs)
+	*structs = append(*structs, genStruct{name: name})
+	var fields []genStructField
+	for _, child := range field.children {
+		if !token.IsExported(child.name) {
+			// methods and unexported names can't be fields
+			continue
+		}
+		childPath := child.name
+		if path != "" {
+			childPath = path + "." + child.name
+		}
+		typ, err := genFieldType(child, name+child.name, childPath, explicit, structs)
+		if err != nil {
+			return "", err
+		}
+		fields = append(fields, genStructField{name: child.name, typ: typ})
+	}
+	(*structs)[index].fields = fields
+	return name, nil
+}
+
+// genSource returns the formatted Go source declaring the types.
+func genSource(pkg string, types []genType) ([]byte, error) {
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by temple gen. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	imports := []string{"context"}
+	hasPage := false
+	for _, typ := range types {
+		imports = append(imports, typ.Imports...)
+		hasPage = hasPage || typ.Kind == "page"
+	}
+	if hasPage {
+		imports = append(imports, "impractical.co/temple")
+	}
+	slices.Sort(imports)
+	imports = slices.Compact(imports)
+	// the standard library's packages go in their own group, before the
+	// rest, like goimports would do
+	var std, other []string
+	for _, imp := range imports {
+		if strings.Contains(strings.Split(imp, "/")[0], ".") {
+			other = append(other, imp)
+		} else {
+			std = append(std, imp)
+		}
+	}
+	out.WriteString("import (\n")
+	for _, imp := range std {
+		fmt.Fprintf(&out, "\t%q\n", imp)
+	}
+	if len(other) > 0 {
+		out.WriteString("\n")
+	}
+	for _, imp := range other {
+		fmt.Fprintf(&out, "\t%q\n", imp)
+	}
+	out.WriteString(")\n")
+	for _, typ := range types {
+		var structs []genStruct
+		_, err := genStructType(typ.Fields, typ.Name, "", typ.Types, &structs)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", typ.Template, err)
+		}
+		for i, s := range structs {
+			if i == 0 {
+				fmt.Fprintf(&out, "\n// %s is the %s rendered by %s.\n", s.name, typ.Kind, typ.Template)
+			} else {
+				fmt.Fprintf(&out, "\n// %s is part of a %s.\n", s.name, typ.Name)
+			}
+			fmt.Fprintf(&out, "type %s struct {\n", s.name)
+			for _, f := range s.fields {
+				fmt.Fprintf(&out, "\t%s %s\n", f.name, f.typ)
+			}
+			out.WriteString("}\n")
+		}
+		if typ.Kind == "page" {
+			fmt.Fprintf(&out, "\nvar _ temple.Renderable = %s{}\n", typ.Name)
+		}
+		fmt.Fprintf(&out, "\n// Templates returns the templates needed to render the %s.\n", typ.Name)
+		fmt.Fprintf(&out, "func (%s) Templates(_ context.Context) []string {\n\treturn []string{%q}\n}\n", typ.Name, typ.Template)
+		if typ.Kind != "page" {
+			continue
+		}
+		fmt.Fprintf(&out, "\n// Key returns the key the %s's templates are cached under.\n", typ.Name)
+		fmt.Fprintf(&out, "func (%s) Key(_ context.Context) string {\n\treturn %q\n}\n", typ.Name, typ.Template)
+		fmt.Fprintf(&out, "\n// ExecutedTemplate returns the template to execute to render the %s.\n", typ.Name)
+		fmt.Fprintf(&out, "func (%s) ExecutedTemplate(_ context.Context) string {\n\treturn %q\n}\n", typ.Name, typ.Template)
+	}
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("error formatting generated code: %w", err)
+	}
+	return src, nil
+}
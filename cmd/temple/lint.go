@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"impractical.co/temple"
+)
+
+// runLint runs the lint command, returning the exit code.
+func runLint(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, `Usage: temple lint [flags]
+
+Checks the templates for files that can't be parsed, {{ template }} actions
+executing templates no file defines, and files that aren't used: that aren't
+named by a string in the Go source or executed by another template.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	templates := flags.String("templates", "templates", "the Site's template directory")
+	src := flags.String("src", ".", "the directory containing the Site's Go source, searched recursively for template paths")
+	left := flags.String("left", "", "the left template delimiter, if it isn't {{")
+	right := flags.String("right", "", "the right template delimiter, if it isn't }}")
+	err := flags.Parse(args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return 2
+	}
+	used, err := goStrings(*src)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	issues, err := temple.LintFS(os.DirFS(*templates), used, *left, *right)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	for _, issue := range issues {
+		fmt.Fprintf(stdout, "%s%c%s\n", filepath.Clean(*templates), filepath.Separator, issue)
+	}
+	if len(issues) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// goStrings returns every string literal in the Go files in dir and its
+// subdirectories, except tests and vendored packages, as any of them could be
+// a template path.
+func goStrings(dir string) ([]string, error) {
+	results := []string{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != dir && (entry.Name() == "vendor" || entry.Name() == "testdata" || strings.HasPrefix(entry.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".go" || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			lit, ok := node.(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			val, err := strconv.Unquote(lit.Value)
+			if err == nil {
+				results = append(results, val)
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading Go source: %w", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,65 @@
+// Command temple is a tool for working with temple Sites.
+//
+// Usage:
+//
+//	temple new component <Name>
+//	temple new page <Name>
+//	temple lint
+//	temple gen
+//
+// The new command scaffolds the boilerplate for a Component or page: a Go
+// file declaring a type with the methods temple needs, and the template it
+// renders.
+//
+// The lint command checks a Site's templates for problems, using
+// temple.LintFS. Sites that can list their pages should prefer calling
+// temple.Lint from a test, which is more accurate.
+//
+// The gen command generates Go types for annotated templates, with fields
+// inferred from the template's references to .Page, so the template and the
+// type it renders don't drift apart.
+//
+// Run "temple <command> -h" for a command's flags.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `Usage:
+
+	temple new component <Name>	scaffold a Component
+	temple new page <Name>		scaffold a page
+	temple lint			check the templates for problems
+	temple gen			generate Go types for annotated templates
+
+Run "temple <command> -h" for a command's flags.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command described by args, returning the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) < 1 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	switch args[0] {
+	case "new":
+		return runNew(args[1:], stdout, stderr)
+	case "lint":
+		return runLint(args[1:], stdout, stderr)
+	case "gen":
+		return runGen(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	for _, kind := range []string{"component", "page"} {
+		t.Run(kind, func(t *testing.T) {
+			dir := t.TempDir()
+			var stderr strings.Builder
+			code := run([]string{"new", "-dir", dir, "-package", "site", kind, "UserCard"}, io.Discard, &stderr)
+			if code != 0 {
+				t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+			}
+			file, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, "user_card.go"), nil, 0)
+			if err != nil {
+				t.Fatalf("generated Go doesn't parse: %s", err)
+			}
+			if file.Name.Name != "site" {
+				t.Errorf("expected package site, got %s", file.Name.Name)
+			}
+			_, err = os.Stat(filepath.Join(dir, "templates", "user_card.html.tmpl"))
+			if err != nil {
+				t.Errorf("template wasn't generated: %s", err)
+			}
+
+			// running it again shouldn't overwrite anything
+			code = run([]string{"new", "-dir", dir, "-package", "site", kind, "UserCard"}, io.Discard, io.Discard)
+			if code == 0 {
+				t.Error("expected a non-zero exit code when files already exist")
+			}
+		})
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for input, expected := range map[string]string{
+		"UserCard":   "user_card",
+		"HTTPError":  "http_error",
+		"Page2Col":   "page2_col",
+		"Home":       "home",
+		"OAuthLogin": "o_auth_login",
+	} {
+		if got := snakeCase(input); got != expected {
+			t.Errorf("snakeCase(%q): expected %q, got %q", input, expected, got)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"site.go":                          "package site\n\nvar page = []string{\"pages/*.html.tmpl\"}\n",
+		"templates/pages/a.html.tmpl":      `{{ template "partials/nav.html.tmpl" }}{{ template "missing" }}`,
+		"templates/partials/nav.html.tmpl": `<nav></nav>`,
+		"templates/old.html.tmpl":          `<p></p>`,
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		err := os.MkdirAll(filepath.Dir(path), 0o750)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, []byte(contents), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	var stdout strings.Builder
+	code := run([]string{"lint", "-src", dir, "-templates", filepath.Join(dir, "templates")}, &stdout, io.Discard)
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+	expected := filepath.Join(dir, "templates", "old.html.tmpl") + ": isn't used\n" +
+		filepath.Join(dir, "templates", "pages", "a.html.tmpl") + ": executes undefined template \"missing\"\n"
+	if stdout.String() != expected {
+		t.Errorf("expected output:\n%s\ngot:\n%s", expected, stdout.String())
+	}
+}
+
+func TestGen(t *testing.T) {
+	dir := t.TempDir()
+	err := os.MkdirAll(filepath.Join(dir, "templates"), 0o750)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "templates", "post.html.tmpl"), []byte(`{{/*
+temple:page PostPage
+temple:field Date time.Time
+temple:import time
+*/}}
+<h1>{{ .Page.Title }}</h1>
+{{ if .Page.Draft }}<p>Draft</p>{{ end }}
+{{ range .Page.Comments }}<p>{{ .Author.Name }}</p>{{ end }}
+{{ .Page.Date.Format "2006" }}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stderr strings.Builder
+	code := run([]string{"gen", "-dir", dir, "-package", "main"}, io.Discard, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	src, err := os.ReadFile(filepath.Join(dir, genFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = parser.ParseFile(token.NewFileSet(), genFileName, src, 0)
+	if err != nil {
+		t.Fatalf("generated Go doesn't parse: %s", err)
+	}
+	for _, expected := range []string{
+		"type PostPage struct {\n\tTitle    string\n\tDraft    bool\n\tComments []PostPageCommentsItem\n\tDate     time.Time\n}",
+		"type PostPageCommentsItem struct {\n\tAuthor PostPageCommentsItemAuthor\n}",
+		"type PostPageCommentsItemAuthor struct {\n\tName string\n}",
+		`func (PostPage) ExecutedTemplate(_ context.Context) string {`,
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("expected generated code to contain:\n%s\ngot:\n%s", expected, src)
+		}
+	}
+
+	// the generated type has to render the template it was generated
+	// from
+	if testing.Short() {
+		t.Skip("skipping building the generated code in short mode")
+	}
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"go.mod": "module example.com/blog\n\ngo 1.23.0\n\nrequire impractical.co/temple v0.0.0\n\nreplace impractical.co/temple => " + strconv.Quote(root) + "\n",
+		"go.sum": string(sum),
+		"main.go": `package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"impractical.co/temple"
+)
+
+func main() {
+	site := temple.NewCachedSite(os.DirFS("templates"))
+	page := PostPage{
+		Title:    "Hello",
+		Comments: []PostPageCommentsItem{{Author: PostPageCommentsItemAuthor{Name: "Ada"}}},
+		Date:     time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+	}
+	result := temple.Render(context.Background(), os.Stdout, site, page)
+	if result.Err != nil {
+		panic(result.Err)
+	}
+}
+`,
+	}
+	for name, contents := range files {
+		err = os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := exec.Command("go", "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOWORK=off")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("error running generated code: %s\n%s", err, out)
+	}
+	expected := "\n<h1>Hello</h1>\n\n<p>Ada</p>\n2024"
+	if string(out) != expected {
+		t.Errorf("expected rendered page %q, got %q", expected, out)
+	}
+}
+
+func TestGenMethodReceiver(t *testing.T) {
+	dir := t.TempDir()
+	err := os.MkdirAll(filepath.Join(dir, "templates"), 0o750)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "templates", "post.html.tmpl"), []byte(`{{/* temple:page PostPage */}}
+{{ .Page.Author.Joined.Format "2006" }}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stderr strings.Builder
+	code := run([]string{"gen", "-dir", dir, "-package", "blog"}, io.Discard, &stderr)
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	expected := "post.html.tmpl: the methods of Author.Joined are called, so its type can't be inferred; set it with temple:field Author.Joined <type>\n"
+	if stderr.String() != expected {
+		t.Errorf("expected error %q, got %q", expected, stderr.String())
+	}
+}
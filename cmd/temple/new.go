@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// scaffold describes the files to generate for a Component or page.
+type scaffold struct {
+	// Kind is "component" or "page".
+	Kind string
+
+	// Name is the name of the Go type.
+	Name string
+
+	// Package is the name of the Go package the type is in.
+	Package string
+
+	// Template is the path of the template, relative to the Site's
+	// TemplateDir.
+	Template string
+
+	// Class is the CSS class for the Component's root element.
+	Class string
+}
+
+var goTemplate = template.Must(template.New("go").Parse(`package {{ .Package }}
+
+{{ if eq .Kind "page" -}}
+import (
+	"context"
+
+	"impractical.co/temple"
+)
+{{- else -}}
+import "context"
+{{- end }}
+
+{{ if eq .Kind "page" -}}
+var _ temple.Renderable = {{ .Name }}{}
+
+// {{ .Name }} is a page.
+{{- else -}}
+// {{ .Name }} is a Component.
+{{- end }}
+type {{ .Name }} struct{}
+
+// Templates returns the templates needed to render the {{ .Name }}.
+func ({{ .Name }}) Templates(_ context.Context) []string {
+	return []string{"{{ .Template }}"}
+}
+{{- if eq .Kind "page" }}
+
+// UseComponents returns the Components the {{ .Name }} uses.
+func ({{ .Name }}) UseComponents(_ context.Context) []temple.Component {
+	return nil
+}
+
+// Key returns the key the {{ .Name }}'s templates are cached under.
+func ({{ .Name }}) Key(_ context.Context) string {
+	return "{{ .Template }}"
+}
+
+// ExecutedTemplate returns the template to execute to render the {{ .Name }}.
+func ({{ .Name }}) ExecutedTemplate(_ context.Context) string {
+	return "{{ .Template }}"
+}
+{{- end }}
+`))
+
+var htmlTemplates = map[string]string{
+	"component": `<div class="{{ .Class }}">
+</div>
+`,
+	"page": `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<title>{{ .Name }}</title>
+	{{"{{"}} .CriticalCSSTags {{"}}"}}
+	{{"{{"}} range .LinkedCSS {{"}}"}}<link rel="stylesheet" href="{{"{{"}} . {{"}}"}}">{{"{{"}} end {{"}}"}}
+	<style>{{"{{"}} .EmbeddedCSS {{"}}"}}</style>
+	{{"{{"}} .ImportMapTag {{"}}"}}
+</head>
+<body>
+	{{"{{"}} .JSONDataTags {{"}}"}}
+	{{"{{"}} range .LinkedJS {{"}}"}}<script src="{{"{{"}} . {{"}}"}}"></script>{{"{{"}} end {{"}}"}}
+	<script>{{"{{"}} .EmbeddedJS {{"}}"}}</script>
+	{{"{{"}} .IdleJSLoader {{"}}"}}
+</body>
+</html>
+`,
+}
+
+// runNew runs the new command, returning the exit code.
+func runNew(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("new", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, "Usage: temple new [flags] component|page <Name>\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	dir := flags.String("dir", ".", "the directory to write the Go file to")
+	templates := flags.String("templates", "templates", "the Site's template directory, relative to -dir")
+	pkg := flags.String("package", "", "the Go package name; detected from -dir if empty")
+	err := flags.Parse(args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if flags.NArg() != 2 || (flags.Arg(0) != "component" && flags.Arg(0) != "page") {
+		flags.Usage()
+		return 2
+	}
+	kind, name := flags.Arg(0), flags.Arg(1)
+	if !isExportedIdent(name) {
+		fmt.Fprintf(stderr, "%q isn't an exported Go identifier\n", name)
+		return 2
+	}
+	if *pkg == "" {
+		*pkg, err = detectPackage(*dir)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+	base := snakeCase(name)
+	s := scaffold{
+		Kind:     kind,
+		Name:     name,
+		Package:  *pkg,
+		Template: base + ".html.tmpl",
+		Class:    strings.ReplaceAll(base, "_", "-"),
+	}
+	goPath := filepath.Join(*dir, base+".go")
+	tmplPath := filepath.Join(*dir, *templates, s.Template)
+	for _, path := range []string{goPath, tmplPath} {
+		_, err := os.Stat(path)
+		if err == nil {
+			fmt.Fprintf(stderr, "%s already exists\n", path)
+			return 1
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+
+	var goSrc bytes.Buffer
+	err = goTemplate.Execute(&goSrc, s)
+	if err != nil {
+		fmt.Fprintf(stderr, "error generating Go: %s\n", err)
+		return 1
+	}
+	formatted, err := format.Source(goSrc.Bytes())
+	if err != nil {
+		fmt.Fprintf(stderr, "error formatting Go: %s\n", err)
+		return 1
+	}
+	var htmlSrc bytes.Buffer
+	err = template.Must(template.New("html").Parse(htmlTemplates[kind])).Execute(&htmlSrc, s)
+	if err != nil {
+		fmt.Fprintf(stderr, "error generating template: %s\n", err)
+		return 1
+	}
+
+	err = os.MkdirAll(filepath.Dir(tmplPath), 0o750)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	err = os.WriteFile(goPath, formatted, 0o600)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	err = os.WriteFile(tmplPath, htmlSrc.Bytes(), 0o600)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "created %s\ncreated %s\n", goPath, tmplPath)
+	return 0
+}
+
+// detectPackage returns the name of the Go package in dir, or a name based on
+// the directory's name if it doesn't have any Go files yet.
+func detectPackage(dir string) (string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		parsed, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.PackageClauseOnly)
+		if err != nil {
+			return "", fmt.Errorf("error detecting package: %w", err)
+		}
+		return parsed.Name.Name, nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("error detecting package: %w", err)
+	}
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, filepath.Base(abs))
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		return "", errors.New("can't detect the package name, use -package")
+	}
+	return name, nil
+}
+
+// isExportedIdent returns true if s is an exported Go identifier.
+func isExportedIdent(s string) bool {
+	if !token.IsIdentifier(s) {
+		return false
+	}
+	return token.IsExported(s)
+}
+
+// snakeCase converts a Go identifier, like UserCard or HTTPError, to
+// snake_case, like user_card or http_error.
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var out strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (nextLower && unicode.IsUpper(runes[i-1])) {
+				out.WriteByte('_')
+			}
+		}
+		out.WriteRune(unicode.ToLower(r))
+	}
+	return out.String()
+}
@@ -0,0 +1,216 @@
+package temple
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// minCompressSize is the size below which pages aren't compressed, as the
+// compression overhead would outweigh the savings.
+const minCompressSize = 1024
+
+// Compressor compresses pages for the WithCompression RenderOption.
+type Compressor interface {
+	// Encoding returns the content coding the Compressor produces, as
+	// used in the Accept-Encoding and Content-Encoding headers, like
+	// "gzip" or "br".
+	Encoding() string
+
+	// Compress writes the compressed src to dst.
+	Compress(dst io.Writer, src []byte) error
+}
+
+var _ Compressor = GzipCompressor{}
+
+// GzipCompressor is a Compressor using gzip.
+type GzipCompressor struct {
+	// Level is the gzip compression level, from gzip.HuffmanOnly to
+	// gzip.BestCompression. The zero value means gzip.DefaultCompression.
+	Level int
+}
+
+// gzipWriters pools gzip.Writers for each compression level, indexed by the
+// level minus gzip.HuffmanOnly, as they're expensive to create.
+var gzipWriters [gzip.BestCompression - gzip.HuffmanOnly + 1]sync.Pool
+
+// Encoding returns "gzip".
+func (GzipCompressor) Encoding() string {
+	return "gzip"
+}
+
+// Compress writes the gzipped src to dst.
+func (g GzipCompressor) Compress(dst io.Writer, src []byte) error {
+	level := g.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		return fmt.Errorf("invalid gzip compression level %d", level)
+	}
+	pool := &gzipWriters[level-gzip.HuffmanOnly]
+	writer, ok := pool.Get().(*gzip.Writer)
+	if ok {
+		writer.Reset(dst)
+	} else {
+		var err error
+		writer, err = gzip.NewWriterLevel(dst, level)
+		if err != nil {
+			return fmt.Errorf("error creating gzip writer: %w", err)
+		}
+	}
+	defer pool.Put(writer)
+	_, err := writer.Write(src)
+	if err != nil {
+		return fmt.Errorf("error gzipping: %w", err)
+	}
+	err = writer.Close()
+	if err != nil {
+		return fmt.Errorf("error gzipping: %w", err)
+	}
+	return nil
+}
+
+// compression is the configuration set by WithCompression.
+type compression struct {
+	acceptEncoding string
+	compressors    []Compressor
+}
+
+// WithCompression is a RenderOption that, when Render is writing to an
+// http.ResponseWriter, compresses the page using whichever of the
+// Compressors the client prefers, according to acceptEncoding, which should
+// be the request's Accept-Encoding header:
+//
+//	temple.Render(ctx, w, site, page, temple.WithCompression(r.Header.Get("Accept-Encoding")))
+//
+// The page is compressed straight from the buffer it was rendered into, so
+// there's no need for compression middleware to copy it again. The
+// Content-Encoding and Content-Length headers are set to match, and
+// Accept-Encoding is added to the Vary header whether the page is compressed
+// or not. Pages smaller than 1KiB, and responses that already have a
+// Content-Encoding header, aren't compressed.
+//
+// If the client likes more than one of the Compressors equally, the one
+// passed first is used. If none are passed, a GzipCompressor is used. temple
+// only includes a gzip Compressor, but others, like Brotli, can be added by
+// implementing Compressor:
+//
+//	type brotliCompressor struct{}
+//
+//	func (brotliCompressor) Encoding() string {
+//		return "br"
+//	}
+//
+//	func (brotliCompressor) Compress(dst io.Writer, src []byte) error {
+//		w := brotli.NewWriter(dst)
+//		if _, err := w.Write(src); err != nil {
+//			return err
+//		}
+//		return w.Close()
+//	}
+//
+// WithStreaming is ignored when WithCompression is used. The RenderResult's
+// BytesWritten is the size of the compressed page.
+func WithCompression(acceptEncoding string, compressors ...Compressor) RenderOption {
+	if len(compressors) < 1 {
+		compressors = []Compressor{GzipCompressor{}}
+	}
+	return func(opts *renderOptions) {
+		opts.compression = &compression{
+			acceptEncoding: acceptEncoding,
+			compressors:    compressors,
+		}
+	}
+}
+
+// compressBuffers holds the buffers pages are compressed into, so they can be
+// reused across renders.
+var compressBuffers = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// writeCompressed writes the status code and rendered page to `out`,
+// compressed as configured, if `out` is an http.ResponseWriter.
+func writeCompressed(out io.Writer, page *bytes.Buffer, config compression, status int) (int64, error) {
+	resp, ok := out.(http.ResponseWriter)
+	if !ok {
+		return page.WriteTo(out)
+	}
+	header := resp.Header()
+	if !slices.ContainsFunc(header.Values("Vary"), func(val string) bool {
+		return slices.ContainsFunc(strings.Split(val, ","), func(field string) bool {
+			return strings.EqualFold(strings.TrimSpace(field), "Accept-Encoding")
+		})
+	}) {
+		header.Add("Vary", "Accept-Encoding")
+	}
+	compressor := negotiateCompressor(config.acceptEncoding, config.compressors)
+	if compressor == nil || page.Len() < minCompressSize || header.Get("Content-Encoding") != "" {
+		header.Set("Content-Length", strconv.Itoa(page.Len()))
+		writeStatus(out, status)
+		return page.WriteTo(out)
+	}
+
+	// the pool only ever holds *bytes.Buffer
+	compressed := compressBuffers.Get().(*bytes.Buffer)
+	compressed.Reset()
+	defer func() {
+		if compressed.Cap() <= maxPooledBufferSize {
+			compressBuffers.Put(compressed)
+		}
+	}()
+	err := compressor.Compress(compressed, page.Bytes())
+	if err != nil {
+		return 0, fmt.Errorf("error compressing with %s: %w", compressor.Encoding(), err)
+	}
+	header.Set("Content-Encoding", compressor.Encoding())
+	header.Set("Content-Length", strconv.Itoa(compressed.Len()))
+	writeStatus(out, status)
+	return compressed.WriteTo(out)
+}
+
+// negotiateCompressor returns the Compressor the Accept-Encoding header
+// prefers, or nil if it doesn't accept any of them.
+func negotiateCompressor(acceptEncoding string, compressors []Compressor) Compressor {
+	accepted := map[string]float64{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, val, _ := strings.Cut(param, "=")
+			if strings.TrimSpace(key) != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+			if err == nil {
+				quality = parsed
+			}
+		}
+		accepted[coding] = quality
+	}
+	var best Compressor
+	var bestQuality float64
+	for _, compressor := range compressors {
+		quality, ok := accepted[strings.ToLower(compressor.Encoding())]
+		if !ok {
+			quality = accepted["*"]
+		}
+		if quality > bestQuality {
+			best, bestQuality = compressor, quality
+		}
+	}
+	return best
+}
@@ -0,0 +1,89 @@
+package temple
+
+import (
+	"text/template/parse"
+)
+
+// WithStrictTemplateNames is a RenderOption that makes it an error for two
+// template files to define a template with the same name, like two Components
+// that both {{ define "item" }}. All templates are parsed into one set, so
+// without it, the definition parsed last silently replaces the others.
+//
+// A file defining a template that it also executes, like a layout's
+// {{ block "body" . }}, is declaring a default meant to be replaced, and
+// doesn't conflict with other definitions. Neither do empty definitions.
+//
+// The check only happens when templates are parsed, so if the Site is a
+// TemplateCacher, it only applies when the template isn't already cached.
+func WithStrictTemplateNames() RenderOption {
+	return func(opts *renderOptions) {
+		opts.strictTemplateNames = true
+	}
+}
+
+// templateDefiner records which file first defined a template name.
+type templateDefiner struct {
+	path      string
+	component Component
+}
+
+// checkTemplateConflicts parses the contents of the template file, and
+// returns a TemplateConflictError if it defines a template that a file in
+// definers has already defined. Otherwise, it adds the templates the file
+// defines to definers.
+func checkTemplateConflicts(file templatePath, contents string, definers map[string]templateDefiner) error {
+	trees := map[string]*parse.Tree{}
+	tree := parse.New(file.path)
+	tree.Mode = parse.SkipFuncCheck
+	_, err := tree.Parse(contents, file.delims[0], file.delims[1], trees)
+	if err != nil {
+		// the error will be reported when the file is parsed for real
+		return nil
+	}
+	executed := map[string]struct{}{}
+	for _, t := range trees {
+		executedTemplates(t.Root, executed)
+	}
+	for name, t := range trees {
+		if name == file.path || parse.IsEmptyTree(t.Root) {
+			continue
+		}
+		if _, ok := executed[name]; ok {
+			continue
+		}
+		if first, ok := definers[name]; ok && first.path != file.path {
+			return TemplateConflictError{
+				Name:       name,
+				Paths:      [2]string{first.path, file.path},
+				Components: [2]Component{first.component, file.component},
+			}
+		}
+		definers[name] = templateDefiner{path: file.path, component: file.component}
+	}
+	return nil
+}
+
+// executedTemplates adds the name of every template executed by the parse
+// tree rooted at `node` to `names`.
+func executedTemplates(node parse.Node, names map[string]struct{}) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			executedTemplates(child, names)
+		}
+	case *parse.TemplateNode:
+		names[n.Name] = struct{}{}
+	case *parse.IfNode:
+		executedTemplates(n.List, names)
+		executedTemplates(n.ElseList, names)
+	case *parse.RangeNode:
+		executedTemplates(n.List, names)
+		executedTemplates(n.ElseList, names)
+	case *parse.WithNode:
+		executedTemplates(n.List, names)
+		executedTemplates(n.ElseList, names)
+	}
+}
@@ -0,0 +1,283 @@
+// Package content indexes a directory of Markdown and JSON documents, like
+// the posts of a blog or the pages of a documentation Site, so pages can list,
+// filter, and paginate them.
+//
+// Markdown documents are rendered using the markdown package, and their
+// frontmatter is used to index them. JSON documents must contain an object,
+// whose top-level fields are used the same way. The fields temple looks for
+// are:
+//
+//   - slug: the document's identifier; defaults to its path, without its
+//     extension.
+//   - title: the document's title.
+//   - date: when the document was published, as 2006-01-02 or RFC 3339.
+//   - tags: a list of the document's tags.
+//   - draft: true for documents that shouldn't be published yet.
+package content
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"iter"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"impractical.co/temple"
+	"impractical.co/temple/markdown"
+)
+
+var (
+	// ErrDuplicateSlug is returned when two documents in a Collection
+	// have the same slug.
+	ErrDuplicateSlug = errors.New("duplicate slug")
+
+	// ErrInvalidDate is returned when a document's date can't be parsed.
+	ErrInvalidDate = errors.New("invalid date")
+)
+
+// Entry is a single document in a Collection.
+type Entry struct {
+	// Slug identifies the Entry within its Collection.
+	Slug string
+
+	// Path is the path of the document within the Collection's fs.FS.
+	Path string
+
+	// Title is the "title" field of the document.
+	Title string
+
+	// Date is the "date" field of the document, or the zero time if it
+	// doesn't have one.
+	Date time.Time
+
+	// Tags are the "tags" field of the document.
+	Tags []string
+
+	// Draft is the "draft" field of the document.
+	Draft bool
+
+	// Fields are all the fields from the document's frontmatter, or the
+	// top-level fields of its JSON object.
+	Fields map[string]any
+
+	// Document is the rendered Markdown, for Markdown documents. It's a
+	// temple.Component, so pages displaying the Entry can include it in
+	// their UseComponents output and execute its template.
+	Document markdown.Document
+}
+
+// HasTag returns true if the Entry is tagged with the tag.
+func (e Entry) HasTag(tag string) bool {
+	return slices.Contains(e.Tags, tag)
+}
+
+// Body returns the rendered Markdown of the Entry, or nothing for JSON
+// documents.
+func (e Entry) Body() template.HTML {
+	return e.Document.Body
+}
+
+// Options configure how a Collection is loaded.
+type Options struct {
+	// Markdown configures how Markdown documents are rendered. Its Path
+	// is ignored. It needs a Renderer and a Sanitizer if the Collection
+	// has any Markdown documents.
+	Markdown markdown.Markdown
+
+	// IncludeDrafts includes documents marked as drafts in the
+	// Collection. They're left out by default.
+	IncludeDrafts bool
+}
+
+// Collection is an index of documents. It can safely be used by multiple
+// goroutines.
+type Collection struct {
+	// entries are sorted newest first
+	entries []Entry
+	bySlug  map[string]int
+}
+
+// Load reads every Markdown (.md) and JSON (.json) document in the fs.FS,
+// recursively, and returns a Collection indexing them. Other files are
+// ignored.
+func Load(ctx context.Context, dir fs.FS, opts Options) (*Collection, error) {
+	coll := &Collection{bySlug: map[string]int{}}
+	err := fs.WalkDir(dir, ".", func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		var parsed Entry
+		switch path.Ext(file) {
+		case ".md":
+			parsed, err = loadMarkdown(ctx, dir, file, opts)
+		case ".json":
+			parsed, err = loadJSON(dir, file)
+		default:
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if parsed.Draft && !opts.IncludeDrafts {
+			return nil
+		}
+		coll.entries = append(coll.entries, parsed)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(coll.entries, func(a, b Entry) int {
+		if cmp := b.Date.Compare(a.Date); cmp != 0 {
+			return cmp
+		}
+		return strings.Compare(a.Slug, b.Slug)
+	})
+	for pos, entry := range coll.entries {
+		if other, ok := coll.bySlug[entry.Slug]; ok {
+			return nil, fmt.Errorf("%w %q: %q and %q", ErrDuplicateSlug, entry.Slug, coll.entries[other].Path, entry.Path)
+		}
+		coll.bySlug[entry.Slug] = pos
+	}
+	return coll, nil
+}
+
+func loadMarkdown(ctx context.Context, dir fs.FS, file string, opts Options) (Entry, error) {
+	md := opts.Markdown
+	md.Path = file
+	doc, err := md.LoadFS(ctx, dir)
+	if err != nil {
+		return Entry{}, err
+	}
+	entry, err := newEntry(file, doc.Frontmatter)
+	if err != nil {
+		return Entry{}, err
+	}
+	entry.Document = doc
+	return entry, nil
+}
+
+func loadJSON(dir fs.FS, file string) (Entry, error) {
+	src, err := fs.ReadFile(dir, file)
+	if err != nil {
+		return Entry{}, fmt.Errorf("error reading %q: %w", file, err)
+	}
+	var fields map[string]any
+	err = json.Unmarshal(src, &fields)
+	if err != nil {
+		return Entry{}, fmt.Errorf("error parsing %q: %w", file, err)
+	}
+	return newEntry(file, fields)
+}
+
+// newEntry builds the Entry for the document at `file` from its fields.
+func newEntry(file string, fields map[string]any) (Entry, error) {
+	entry := Entry{
+		Slug:   strings.TrimSuffix(file, path.Ext(file)),
+		Path:   file,
+		Fields: fields,
+	}
+	if slug, ok := fields["slug"].(string); ok && slug != "" {
+		entry.Slug = slug
+	}
+	entry.Title, _ = fields["title"].(string)
+	entry.Draft, _ = fields["draft"].(bool)
+	switch tags := fields["tags"].(type) {
+	case []string:
+		entry.Tags = tags
+	case []any:
+		for _, tag := range tags {
+			if s, ok := tag.(string); ok {
+				entry.Tags = append(entry.Tags, s)
+			}
+		}
+	}
+	if date, ok := fields["date"].(string); ok && date != "" {
+		parsed, err := parseDate(date)
+		if err != nil {
+			return Entry{}, fmt.Errorf("error parsing date of %q: %w", file, err)
+		}
+		entry.Date = parsed
+	}
+	return entry, nil
+}
+
+// parseDate parses a date in either the 2006-01-02 or RFC 3339 format.
+func parseDate(date string) (time.Time, error) {
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		parsed, err := time.Parse(layout, date)
+		if err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidDate, date)
+}
+
+// Get returns the Entry with the slug, if there is one.
+func (c *Collection) Get(slug string) (Entry, bool) {
+	pos, ok := c.bySlug[slug]
+	if !ok {
+		return Entry{}, false
+	}
+	return c.entries[pos], true
+}
+
+// Len returns the number of Entries in the Collection.
+func (c *Collection) Len() int {
+	return len(c.entries)
+}
+
+// All returns every Entry in the Collection, newest first. Entries with the
+// same date are sorted by slug.
+func (c *Collection) All() iter.Seq[Entry] {
+	return slices.Values(c.entries)
+}
+
+// Filter returns the Entries `keep` returns true for, newest first.
+func (c *Collection) Filter(keep func(Entry) bool) iter.Seq[Entry] {
+	return func(yield func(Entry) bool) {
+		for _, entry := range c.entries {
+			if keep(entry) && !yield(entry) {
+				return
+			}
+		}
+	}
+}
+
+// Tagged returns the Entries tagged with the tag, newest first.
+func (c *Collection) Tagged(tag string) iter.Seq[Entry] {
+	return c.Filter(func(e Entry) bool {
+		return e.HasTag(tag)
+	})
+}
+
+// Tags returns every tag used by an Entry in the Collection, sorted
+// alphabetically.
+func (c *Collection) Tags() []string {
+	var results []string
+	for _, entry := range c.entries {
+		for _, tag := range entry.Tags {
+			if !slices.Contains(results, tag) {
+				results = append(results, tag)
+			}
+		}
+	}
+	slices.Sort(results)
+	return results
+}
+
+// Paginate returns the 1-indexed page of `perPage` Entries from the sequence,
+// like one returned by All, Filter, or Tagged. page and perPage are treated
+// as 1 if they're less than 1, as in temple.PaginateSeq.
+func Paginate(entries iter.Seq[Entry], page, perPage int) temple.SeqPage[Entry] {
+	return temple.PaginateSeq(entries, page, perPage)
+}
@@ -0,0 +1,68 @@
+package content_test
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"testing/fstest"
+
+	"impractical.co/temple/content"
+	"impractical.co/temple/markdown"
+)
+
+// paragraphs is a stand-in for a real Markdown parser, like goldmark, that
+// only knows about paragraphs
+var paragraphs = markdown.RendererFunc(func(_ context.Context, src []byte) ([]byte, error) {
+	return []byte("<p>" + html.EscapeString(strings.TrimSpace(string(src))) + "</p>"), nil
+})
+
+func Example() {
+	posts := fstest.MapFS{
+		"2024/hello.md": {Data: []byte(`---
+title: Hello, world
+date: 2024-01-02
+tags: [meta]
+---
+My first post.`)},
+		"2024/templates.md": {Data: []byte(`---
+title: Why templates?
+date: 2024-03-04
+tags: [go, meta]
+---
+Some thoughts.`)},
+		"2024/generics.md": {Data: []byte(`---
+title: Generics in templates
+date: 2024-05-06
+tags: [go]
+draft: true
+---
+Coming soon.`)},
+		"links.json": {Data: []byte(`{"slug": "links", "title": "Links", "tags": ["meta"]}`)},
+	}
+
+	coll, err := content.Load(context.Background(), posts, content.Options{
+		Markdown: markdown.Markdown{Renderer: paragraphs, Sanitizer: markdown.Trusted{}},
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(coll.Tags())
+	page := content.Paginate(coll.Tagged("meta"), 1, 2)
+	for _, entry := range page.Items {
+		fmt.Printf("%s: %s\n", entry.Slug, entry.Title)
+	}
+	fmt.Println("more:", page.HasNext)
+
+	post, _ := coll.Get("2024/hello")
+	fmt.Println(post.Date.Format("Jan 2, 2006"), post.Body())
+
+	//Output:
+	// [go meta]
+	// 2024/templates: Why templates?
+	// 2024/hello: Hello, world
+	// more: true
+	// Jan 2, 2024 <p>My first post.</p>
+}
@@ -0,0 +1,113 @@
+package temple
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+)
+
+const (
+	// CSRFFieldName is the name of the form field CSRFField renders the
+	// CSRF token into, and that CSRFMiddleware reads it from.
+	CSRFFieldName = "csrf_token"
+
+	// CSRFHeaderName is the name of the header CSRFMiddleware will read
+	// the CSRF token from, if it's not in the form. This is useful for
+	// requests made by JavaScript.
+	CSRFHeaderName = "X-CSRF-Token"
+)
+
+var (
+	// ErrNoCSRFProvider is returned when a CSRF token is needed, but the
+	// Site doesn't implement CSRFProvider.
+	ErrNoCSRFProvider = errors.New("site doesn't implement CSRFProvider")
+)
+
+// CSRFProvider is an optional interface for Sites. Those fulfilling it supply
+// the CSRF tokens used by the csrfToken template function and CSRFField
+// Component, and validated by CSRFMiddleware.
+type CSRFProvider interface {
+	// CSRFToken returns the CSRF token for the request the context.Context
+	// belongs to. It should return the same token for every call in the
+	// same session, so tokens rendered into pages can be validated when
+	// the page's forms are submitted.
+	CSRFToken(ctx context.Context) (string, error)
+}
+
+// csrfToken returns the CSRF token from the Site, if it implements
+// CSRFProvider.
+func csrfToken(ctx context.Context, site Site) (string, error) {
+	provider, ok := site.(CSRFProvider)
+	if !ok {
+		return "", ErrNoCSRFProvider
+	}
+	token, err := provider.CSRFToken(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error retrieving CSRF token: %w", err)
+	}
+	return token, nil
+}
+
+var _ Component = CSRFField{}
+var _ TemplateDirProvider = CSRFField{}
+
+// CSRFField is a Component that renders a hidden form field containing the
+// CSRF token from the Site's CSRFProvider. Components that include forms
+// should include it in their UseComponents output, and render it inside their
+// forms with:
+//
+//	{{ template "temple/csrf_field.html.tmpl" }}
+//
+// Templates can also use the csrfToken function to retrieve the token
+// directly, which doesn't require the CSRFField Component.
+type CSRFField struct{}
+
+// Templates returns the template for the CSRF field.
+func (CSRFField) Templates(_ context.Context) []string {
+	return []string{"temple/csrf_field.html.tmpl"}
+}
+
+// TemplateDir returns the fs.FS containing the CSRF field template.
+func (CSRFField) TemplateDir(_ context.Context) fs.FS {
+	return builtinTemplateDir()
+}
+
+// CSRFMiddleware returns a middleware that rejects requests that could change
+// state (anything but GET, HEAD, OPTIONS, and TRACE requests) unless they
+// include the CSRF token from the Site's CSRFProvider, either in the form
+// field named by CSRFFieldName or the header named by CSRFHeaderName.
+// Rejected requests get a 403 Forbidden response.
+//
+// If the Site doesn't implement CSRFProvider, all state-changing requests are
+// rejected.
+func CSRFMiddleware(site Site) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx := r.Context()
+			expected, err := csrfToken(ctx, site)
+			if err != nil {
+				logger(ctx).ErrorContext(ctx, "error retrieving CSRF token", "error", err)
+				http.Error(w, "Forbidden.", http.StatusForbidden)
+				return
+			}
+			submitted := r.Header.Get(CSRFHeaderName)
+			if submitted == "" {
+				submitted = r.PostFormValue(CSRFFieldName)
+			}
+			if expected == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(submitted)) != 1 {
+				logger(ctx).DebugContext(ctx, "rejecting request with invalid CSRF token")
+				http.Error(w, "Forbidden.", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -3,9 +3,11 @@ package temple
 import (
 	"context"
 	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
+	"html"
 	"html/template"
+	"io/fs"
+	"strings"
 )
 
 // CSSEmbedder is an interface that Components can fulfill to include some CSS
@@ -32,32 +34,62 @@ type CSSLinker interface {
 	LinkCSS(context.Context) []string
 }
 
-func getComponentCSSEmbeds(ctx context.Context, component Component) template.CSS {
-	var results template.CSS
-	seen := map[string]struct{}{}
-	components := getRecursiveComponents(ctx, component)
+// CSSLink is a stylesheet that a Component links to, with extra information
+// about how it should be loaded.
+type CSSLink struct {
+	// URL is the URL browsers should load the stylesheet from.
+	URL string
+
+	// Path is the path to the stylesheet's contents within the Site's
+	// TemplateDir. It's only used when Critical is true.
+	Path string
+
+	// Critical marks the stylesheet as necessary for the first paint of
+	// the page. The contents of critical stylesheets are read from Path
+	// and inlined into the page as .CriticalCSS, and their URLs are made
+	// available as .PreloadedCSS instead of .LinkedCSS, so they can be
+	// loaded without blocking rendering.
+	Critical bool
+}
+
+// CSSResourceLinker is an interface that Components can fulfill to include
+// some CSS that should be loaded through a <link> element in the template, but
+// that needs more control over how it's loaded than CSSLinker offers.
+// Non-critical stylesheets are made available to the template as .LinkedCSS,
+// alongside the output of CSSLinker.
+type CSSResourceLinker interface {
+	// LinkCSSResources returns a list of stylesheets that should be
+	// linked to from the output HTML.
+	//
+	// If this Component embeds any other Components, it should include
+	// their LinkCSSResources output in its own LinkCSSResources output.
+	LinkCSSResources(context.Context) []CSSLink
+}
+
+func getComponentCSSEmbeds(ctx context.Context, components []Component) template.CSS {
+	var results strings.Builder
+	seen := map[[sha256.Size]byte]struct{}{}
 	for _, comp := range components {
 		embed, ok := comp.(CSSEmbedder)
 		if !ok {
 			continue
 		}
 		css := embed.EmbedCSS(ctx)
-		checksum := hex.EncodeToString(sha256.New().Sum([]byte(css)))
+		checksum := sha256.Sum256([]byte(css))
 		if _, ok := seen[checksum]; ok {
 			continue
 		}
 		seen[checksum] = struct{}{}
-		results += template.CSS(fmt.Sprintf(`
+		fmt.Fprintf(&results, `
 /* embedded CSS from %T */
-%s`, comp, css)) // #nosec G203
+%s`, comp, css)
 	}
-	return results
+	return template.CSS(results.String()) // #nosec G203
 }
 
-func getComponentCSSLinks(ctx context.Context, component Component) []string {
+func getComponentCSSLinks(ctx context.Context, components []Component) []string {
 	var results []string
 	seen := map[string]struct{}{}
-	components := getRecursiveComponents(ctx, component)
 	for _, comp := range components {
 		link, ok := comp.(CSSLinker)
 		if !ok {
@@ -72,5 +104,113 @@ func getComponentCSSLinks(ctx context.Context, component Component) []string {
 			seen[source] = struct{}{}
 		}
 	}
+	for _, comp := range components {
+		link, ok := comp.(CSSResourceLinker)
+		if !ok {
+			continue
+		}
+		for _, css := range link.LinkCSSResources(ctx) {
+			if css.Critical {
+				continue
+			}
+			if _, ok := seen[css.URL]; ok {
+				continue
+			}
+			results = append(results, css.URL)
+			seen[css.URL] = struct{}{}
+		}
+	}
 	return results
 }
+
+// CriticalCSSCacher is an optional interface for Sites. Those fulfilling it
+// can cache the contents of the critical stylesheets read from their
+// TemplateDir, so they aren't read again for every render. The contents are
+// cached by the stylesheet's CSSLink.Path, and the Theme's Name if the Site is
+// a Themer.
+type CriticalCSSCacher interface {
+	// GetCachedCriticalCSS returns the contents of the critical
+	// stylesheet cached for the key, and false if they haven't been cached
+	// yet.
+	GetCachedCriticalCSS(ctx context.Context, key string) ([]byte, bool)
+
+	// SetCachedCriticalCSS caches the contents of a critical stylesheet
+	// for the key, for later retrieval with GetCachedCriticalCSS.
+	SetCachedCriticalCSS(ctx context.Context, key string, css []byte)
+}
+
+// criticalCSSCommentEscaper keeps a stylesheet's path from ending the comment
+// it's written in, or the <style> element around it.
+var criticalCSSCommentEscaper = strings.NewReplacer("*/", `*\/`, "</", `<\/`)
+
+// getComponentCriticalCSS returns the merged contents of every critical
+// stylesheet used by the Component, read from the Site's TemplateDir or, if
+// the Site is a CriticalCSSCacher, its cache, and the URLs those stylesheets
+// should be preloaded from.
+func getComponentCriticalCSS(ctx context.Context, site Site, components []Component) (template.CSS, []string, error) {
+	var contents template.CSS
+	var preloads []string
+	seen := map[string]struct{}{}
+	for _, comp := range components {
+		link, ok := comp.(CSSResourceLinker)
+		if !ok {
+			continue
+		}
+		for _, css := range link.LinkCSSResources(ctx) {
+			if !css.Critical {
+				continue
+			}
+			if _, ok := seen[css.URL]; ok {
+				continue
+			}
+			seen[css.URL] = struct{}{}
+			file, err := readCriticalCSS(ctx, site, css.Path)
+			if err != nil {
+				return "", nil, ResourceRenderError{Key: css.Path, Kind: ResourceKindCriticalCSS, Component: comp, Err: err}
+			}
+			contents += template.CSS(fmt.Sprintf(`
+/* critical CSS from %s */
+%s`, criticalCSSCommentEscaper.Replace(css.Path), file)) // #nosec G203
+			preloads = append(preloads, css.URL)
+		}
+	}
+	return contents, preloads, nil
+}
+
+// readCriticalCSS returns the contents of the critical stylesheet at path,
+// from the Site's cache if it's a CriticalCSSCacher, or its TemplateDir.
+func readCriticalCSS(ctx context.Context, site Site, path string) ([]byte, error) {
+	cache, ok := site.(CriticalCSSCacher)
+	if !ok {
+		return fs.ReadFile(site.TemplateDir(ctx), path)
+	}
+	key := themedKey(ctx, path)
+	if css, ok := cache.GetCachedCriticalCSS(ctx, key); ok {
+		return css, nil
+	}
+	css, err := fs.ReadFile(site.TemplateDir(ctx), path)
+	if err != nil {
+		return nil, err
+	}
+	cache.SetCachedCriticalCSS(ctx, key, css)
+	return css, nil
+}
+
+// criticalCSSTags builds the HTML to inline the passed critical CSS and
+// preload the stylesheets it came from, applying them once they've loaded.
+// Stylesheets with an entry in priorities are preloaded with that
+// fetchpriority.
+func criticalCSSTags(css template.CSS, preloads []string, priorities map[string]FetchPriority) template.HTML {
+	var out strings.Builder
+	if css != "" {
+		out.WriteString("<style>")
+		out.WriteString(string(css))
+		out.WriteString("\n</style>\n")
+	}
+	for _, url := range preloads {
+		href := html.EscapeString(url)
+		fmt.Fprintf(&out, `<link rel="preload" href="%s" as="style"%s onload="this.onload=null;this.rel='stylesheet'">`+"\n", href, fetchPriorityAttr(priorities[url]))
+		fmt.Fprintf(&out, `<noscript><link rel="stylesheet" href="%s"></noscript>`+"\n", href)
+	}
+	return template.HTML(out.String()) // #nosec G203
+}
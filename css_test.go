@@ -0,0 +1,87 @@
+package temple_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"impractical.co/temple"
+	"impractical.co/temple/templetest"
+)
+
+type criticalStyles struct {
+	path string
+}
+
+func (criticalStyles) Templates(_ context.Context) []string {
+	return nil
+}
+
+func (c criticalStyles) LinkCSSResources(_ context.Context) []temple.CSSLink {
+	return []temple.CSSLink{{URL: "/critical.css", Path: c.path, Critical: true}}
+}
+
+func TestCriticalCSSCache(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		opts       []temple.SiteOption
+		invalidate bool
+		wantReads  int
+	}{
+		"cached": {
+			wantReads: 1,
+		},
+		"development": {
+			opts:      []temple.SiteOption{temple.WithDevelopment()},
+			wantReads: 2,
+		},
+		"invalidated": {
+			invalidate: true,
+			wantReads:  2,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			site := templetest.NewSite(fstest.MapFS{
+				"page.html.tmpl": {Data: []byte(`{{ .CriticalCSS }}`)},
+				"critical.css":   {Data: []byte(`body { margin: 0; }`)},
+			}, test.opts...)
+			page := testPage{components: []temple.Component{criticalStyles{path: "critical.css"}}}
+			for range 2 {
+				got := templetest.RenderToString(t, site, page)
+				if !strings.Contains(got, "body { margin: 0; }") {
+					t.Errorf("expected critical CSS to be inlined, got %q", got)
+				}
+				if test.invalidate {
+					temple.InvalidateTemplates(context.Background(), site, temple.TemplateIndex{}, "critical.css")
+				}
+			}
+			if got := site.Reads("critical.css"); got != test.wantReads {
+				t.Errorf("expected critical.css to be read %d times, got %d", test.wantReads, got)
+			}
+		})
+	}
+}
+
+func TestCriticalCSSCommentEscaping(t *testing.T) {
+	t.Parallel()
+
+	site := temple.NewCachedSite(fstest.MapFS{
+		"page.html.tmpl":          {Data: []byte(`{{ .CriticalCSSTags }}`)},
+		"x*/ y</style>/extra.css": {Data: []byte(`body { margin: 0; }`)},
+	})
+	page := testPage{components: []temple.Component{criticalStyles{path: "x*/ y</style>/extra.css"}}}
+	var out strings.Builder
+	result := temple.Render(context.Background(), &out, site, page)
+	if result.Err != nil {
+		t.Fatalf("unexpected error: %s", result.Err)
+	}
+	want := "<style>\n/* critical CSS from x*\\/ y<\\/style>/extra.css */\nbody { margin: 0; }\n</style>\n"
+	if got := out.String(); !strings.HasPrefix(got, want) {
+		t.Errorf("expected output starting with %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,177 @@
+package temple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+var (
+	// ErrInvalidCSS is returned by BasicCSSValidator when CSS is
+	// obviously broken.
+	ErrInvalidCSS = errors.New("invalid CSS")
+)
+
+// CSSValidator checks CSS for errors. It's used by the WithCSSValidator
+// RenderOption and ValidateEmbeddedCSS to catch CSS broken by the values
+// interpolated into it, which browsers otherwise ignore silently.
+type CSSValidator interface {
+	// ValidateCSS returns an error if the CSS is invalid.
+	ValidateCSS(ctx context.Context, css template.CSS) error
+}
+
+// CSSValidatorFunc is a function that fills the CSSValidator interface.
+type CSSValidatorFunc func(ctx context.Context, css template.CSS) error
+
+// ValidateCSS calls the CSSValidatorFunc.
+func (fn CSSValidatorFunc) ValidateCSS(ctx context.Context, css template.CSS) error {
+	return fn(ctx, css)
+}
+
+var _ CSSValidator = BasicCSSValidator{}
+
+// BasicCSSValidator is a CSSValidator that catches obvious errors: unbalanced
+// braces, brackets, and parentheses, unterminated strings and comments, and
+// the "ZgotmplZ" placeholder html/template substitutes for unsafe values. It
+// doesn't parse the CSS, so it won't catch invalid properties or values.
+type BasicCSSValidator struct{}
+
+// ValidateCSS returns an error wrapping ErrInvalidCSS if the CSS has one of
+// the errors BasicCSSValidator catches.
+func (BasicCSSValidator) ValidateCSS(_ context.Context, css template.CSS) error {
+	src := string(css)
+	if idx := strings.Index(src, "ZgotmplZ"); idx >= 0 {
+		return fmt.Errorf("%w: unsafe value replaced with ZgotmplZ on line %d", ErrInvalidCSS, lineOf(src, idx))
+	}
+	var open []int
+	for i := 0; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			// skip the escaped character
+			i++
+		case '/':
+			if i+1 >= len(src) || src[i+1] != '*' {
+				continue
+			}
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return fmt.Errorf("%w: unterminated comment on line %d", ErrInvalidCSS, lineOf(src, i))
+			}
+			i += end + 3
+		case '"', '\'':
+			end := cssStringEnd(src, i)
+			if end < 0 {
+				return fmt.Errorf("%w: unterminated string on line %d", ErrInvalidCSS, lineOf(src, i))
+			}
+			i = end
+		case '{', '(', '[':
+			open = append(open, i)
+		case '}', ')', ']':
+			if len(open) < 1 {
+				return fmt.Errorf("%w: unexpected %q on line %d", ErrInvalidCSS, src[i], lineOf(src, i))
+			}
+			opener := src[open[len(open)-1]]
+			if closerOf(opener) != src[i] {
+				return fmt.Errorf("%w: %q on line %d closes %q from line %d", ErrInvalidCSS, src[i], lineOf(src, i), opener, lineOf(src, open[len(open)-1]))
+			}
+			open = open[:len(open)-1]
+		}
+	}
+	if len(open) > 0 {
+		last := open[len(open)-1]
+		return fmt.Errorf("%w: unclosed %q from line %d", ErrInvalidCSS, src[last], lineOf(src, last))
+	}
+	return nil
+}
+
+// cssStringEnd returns the index of the quote that closes the string starting
+// at start, or -1 if the string isn't closed before the end of the line.
+func cssStringEnd(src string, start int) int {
+	quote := src[start]
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '\n':
+			return -1
+		case quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// closerOf returns the character that closes the passed brace, bracket, or
+// parenthesis.
+func closerOf(opener byte) byte {
+	switch opener {
+	case '{':
+		return '}'
+	case '(':
+		return ')'
+	default:
+		return ']'
+	}
+}
+
+// lineOf returns the 1-indexed line the byte at idx is on.
+func lineOf(src string, idx int) int {
+	return strings.Count(src[:idx], "\n") + 1
+}
+
+// WithCSSValidator is a RenderOption that checks the CSS each Component embeds
+// with the CSSValidator before rendering the page. If any of it is invalid,
+// Render fails with a ResourceRenderError identifying the Component. It's
+// meant to be used in development and tests, usually with
+// BasicCSSValidator.
+func WithCSSValidator(validator CSSValidator) RenderOption {
+	return func(opts *renderOptions) {
+		opts.cssValidator = validator
+	}
+}
+
+// EmbeddedCSS returns the CSS the Component and every Component it uses would
+// embed in a page, merged exactly as it would be made available to the
+// template as .EmbeddedCSS, but without rendering anything. It returns an
+// error if the Component's Components can't be resolved, in which case Render
+// would fail too.
+func EmbeddedCSS(ctx context.Context, component Component) (template.CSS, error) {
+	components, err := getRecursiveComponents(ctx, component)
+	if err != nil {
+		return "", err
+	}
+	return getComponentCSSEmbeds(ctx, components), nil
+}
+
+// ValidateEmbeddedCSS checks the CSS the Component and every Component it uses
+// embed with the CSSValidator, returning a ResourceRenderError identifying the
+// first Component whose CSS is invalid. It's useful for checking pages in
+// tests without rendering them.
+func ValidateEmbeddedCSS(ctx context.Context, component Component, validator CSSValidator) error {
+	components, err := getRecursiveComponents(ctx, component)
+	if err != nil {
+		return err
+	}
+	return validateComponentCSS(ctx, components, validator)
+}
+
+func validateComponentCSS(ctx context.Context, components []Component, validator CSSValidator) error {
+	for _, comp := range components {
+		embed, ok := comp.(CSSEmbedder)
+		if !ok {
+			continue
+		}
+		err := validator.ValidateCSS(ctx, embed.EmbedCSS(ctx))
+		if err != nil {
+			return ResourceRenderError{
+				Key:       fmt.Sprintf("%T", comp),
+				Kind:      ResourceKindEmbeddedCSS,
+				Component: comp,
+				Err:       err,
+			}
+		}
+	}
+	return nil
+}
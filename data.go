@@ -0,0 +1,76 @@
+package temple
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+)
+
+// JSONData is a Go value that should be serialized to JSON and embedded in
+// the rendered HTML in a <script type="application/json"> element, so client
+// scripts can read it.
+type JSONData struct {
+	// ID is the id attribute of the <script> element, which client
+	// scripts can use to find it. It should be unique within the page.
+	ID string
+
+	// Value is the Go value to serialize using encoding/json.
+	Value any
+
+	// Type is the type attribute of the <script> element. Defaults to
+	// "application/json"; "application/ld+json" can be used to embed
+	// structured data for search engines.
+	Type string
+}
+
+// JSONDataEmbedder is an interface that Components can fulfill to embed some
+// data in the rendered HTML for client scripts to use. The data will be made
+// available to the template as .JSONData.
+type JSONDataEmbedder interface {
+	// EmbedJSONData returns the data that should be embedded in the
+	// output HTML. If more than one Component on the page embeds data
+	// with the same ID, only the first will be used.
+	EmbedJSONData(context.Context) []JSONData
+}
+
+func getComponentJSONData(ctx context.Context, components []Component) []JSONData {
+	var results []JSONData
+	seen := map[string]struct{}{}
+	for _, comp := range components {
+		embed, ok := comp.(JSONDataEmbedder)
+		if !ok {
+			continue
+		}
+		for _, data := range embed.EmbedJSONData(ctx) {
+			if _, ok := seen[data.ID]; ok {
+				continue
+			}
+			results = append(results, data)
+			seen[data.ID] = struct{}{}
+		}
+	}
+	return results
+}
+
+// jsonDataTags serializes each JSONData and returns a <script> element for
+// each.
+func jsonDataTags(data []JSONData) (template.HTML, error) {
+	var out strings.Builder
+	for _, d := range data {
+		// json.Marshal escapes <, >, and & so the contents can't close
+		// the script element early
+		contents, err := json.Marshal(d.Value)
+		if err != nil {
+			return "", ResourceRenderError{Key: d.ID, Kind: ResourceKindJSONData, Err: fmt.Errorf("error encoding JSON: %w", err)}
+		}
+		typ := d.Type
+		if typ == "" {
+			typ = "application/json"
+		}
+		fmt.Fprintf(&out, `<script type="%s" id="%s">%s</script>`+"\n", html.EscapeString(typ), html.EscapeString(d.ID), contents)
+	}
+	return template.HTML(out.String()), nil // #nosec G203
+}
@@ -0,0 +1,173 @@
+package temple
+
+import (
+	"context"
+	"html/template"
+	"io/fs"
+	"slices"
+	"strings"
+	"time"
+)
+
+// WithDebugInfo is a RenderOption that records information about the render,
+// like how long each phase took and which Components and resources were
+// used, and makes it available to templates as .Debug. It's meant to be used
+// in development, usually along with the DebugToolbar Component.
+func WithDebugInfo() RenderOption {
+	return func(opts *renderOptions) {
+		opts.debug = true
+	}
+}
+
+// DebugInfo is information about a render, recorded when the WithDebugInfo
+// RenderOption is used.
+type DebugInfo struct {
+	// Key is the output of the Key method of the page being rendered.
+	Key string
+
+	// CachedTemplate is true if the page's templates were retrieved from
+	// the Site's TemplateCacher instead of being parsed.
+	CachedTemplate bool
+
+	// Timings are how long each phase of the render took, in the order
+	// they happened. Executing the template isn't included, as it's still
+	// happening when the DebugInfo is rendered.
+	Timings []DebugTiming
+
+	// Components are the page and every Component it uses, in the order
+	// they're resolved.
+	Components []DebugComponent
+
+	// Templates are the paths of the templates parsed for the page, in
+	// the order they're parsed.
+	Templates []string
+
+	// LinkedCSS, PreloadedCSS, and LinkedJS are the URLs of the
+	// stylesheets and scripts included in the page, in the order they're
+	// included.
+	LinkedCSS, PreloadedCSS, LinkedJS []string
+
+	// ImportMap is the page's merged import map.
+	ImportMap JSImportMap
+
+	// JSONData are the IDs of the JSONData embedded in the page, in the
+	// order they're embedded.
+	JSONData []string
+}
+
+// DebugTiming is how long a phase of a render took.
+type DebugTiming struct {
+	Phase    string
+	Duration time.Duration
+}
+
+// DebugComponent is a Component in the tree of Components used by a page.
+type DebugComponent struct {
+	// Type is the Go type of the Component.
+	Type string `json:"type"`
+
+	// Depth is how deep in the tree the Component is; the page is at
+	// depth 0, the Components it uses are at depth 1, and so on.
+	Depth int `json:"depth"`
+}
+
+// Indent returns a string of spaces for displaying the Component at its
+// Depth in the tree.
+func (d DebugComponent) Indent() string {
+	return strings.Repeat("  ", d.Depth)
+}
+
+// debugTimer records the DebugTimings of a render, if debug info is enabled.
+type debugTimer struct {
+	enabled bool
+	last    time.Time
+	timings []DebugTiming
+}
+
+func newDebugTimer(enabled bool) *debugTimer {
+	return &debugTimer{enabled: enabled, last: time.Now()}
+}
+
+// phase records that the named phase just finished.
+func (d *debugTimer) phase(name string) {
+	if !d.enabled {
+		return
+	}
+	now := time.Now()
+	d.timings = append(d.timings, DebugTiming{Phase: name, Duration: now.Sub(d.last)})
+	d.last = now
+}
+
+// debugComponentTree returns the Component and every Component it uses,
+// recursively, with their depth in the tree.
+func debugComponentTree(ctx context.Context, component Component, depth int) []DebugComponent {
+	results := []DebugComponent{{Type: componentType(component), Depth: depth}}
+	if uses, ok := component.(ComponentUser); ok {
+		for _, child := range uses.UseComponents(ctx) {
+			results = append(results, debugComponentTree(ctx, child, depth+1)...)
+		}
+	}
+	return results
+}
+
+// buildDebugInfo returns the DebugInfo for a render.
+func buildDebugInfo[SiteType Site, PageType Renderable](ctx context.Context, site SiteType, page PageType, components []Component, data RenderData[SiteType, PageType], cached bool, timer *debugTimer) *DebugInfo {
+	info := &DebugInfo{
+		Key:            page.Key(ctx),
+		CachedTemplate: cached,
+		Timings:        slices.Clone(timer.timings),
+		Components:     debugComponentTree(ctx, page, 0),
+		Templates:      templatePathStrings(getComponentTemplatePaths(ctx, site, components)),
+		LinkedCSS:      data.LinkedCSS,
+		PreloadedCSS:   data.PreloadedCSS,
+		LinkedJS:       data.LinkedJS,
+		ImportMap:      data.ImportMap,
+	}
+	for _, d := range data.JSONData {
+		info.JSONData = append(info.JSONData, d.ID)
+	}
+	return info
+}
+
+const debugToolbarCSS = `
+.temple-debug-toolbar { position: fixed; bottom: 0; right: 0; z-index: 2147483647; max-width: 100%; max-height: 60vh; overflow: auto; background: #1e1e1e; color: #ddd; font: 12px/1.4 monospace; }
+.temple-debug-toolbar summary { cursor: pointer; padding: 0.25em 0.75em; }
+.temple-debug-toolbar section { padding: 0 0.75em 0.5em; }
+.temple-debug-toolbar h2 { font-size: 1em; margin: 0.5em 0 0.25em; color: #fff; }
+.temple-debug-toolbar ul { list-style: none; margin: 0; padding: 0; }
+.temple-debug-toolbar pre { margin: 0; }
+`
+
+var (
+	_ Component           = DebugToolbar{}
+	_ TemplateDirProvider = DebugToolbar{}
+	_ CSSEmbedder         = DebugToolbar{}
+)
+
+// DebugToolbar is a Component that renders the DebugInfo recorded by the
+// WithDebugInfo RenderOption as a collapsible panel fixed to the bottom of the
+// page. Include it in the UseComponents output of the page's layout, and
+// render it at the end of the <body>:
+//
+//	{{ template "temple/debug_toolbar.html.tmpl" .Debug }}
+//
+// If WithDebugInfo wasn't used, .Debug is nil and nothing is rendered, so the
+// toolbar can be left in place in production.
+type DebugToolbar struct{}
+
+// Templates returns the template for the debug toolbar.
+func (DebugToolbar) Templates(_ context.Context) []string {
+	return []string{"temple/debug_toolbar.html.tmpl"}
+}
+
+// TemplateDir returns the fs.FS containing the debug toolbar template.
+func (DebugToolbar) TemplateDir(_ context.Context) fs.FS {
+	return builtinTemplateDir()
+}
+
+// EmbedCSS returns the CSS used to style the debug toolbar. It's only needed
+// when the toolbar is rendered, but as the CSS is collected before the page
+// is rendered, it's always included.
+func (DebugToolbar) EmbedCSS(_ context.Context) template.CSS {
+	return debugToolbarCSS
+}
@@ -0,0 +1,118 @@
+package temple
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// DebugHandlerOptions configures the http.Handler returned by DebugHandler.
+type DebugHandlerOptions struct {
+	// Authorize decides whether the request may see the debug
+	// information, which describes the Site's internals and shouldn't be
+	// public. If it's nil, every request is rejected.
+	Authorize func(*http.Request) bool
+
+	// Pages are the pages the handler can describe. temple can't discover
+	// a Site's pages on its own, so any page that should be inspectable
+	// needs to be listed here, usually with zero values for any data.
+	Pages []Renderable
+}
+
+// debugPage describes a page listed by DebugHandler.
+type debugPage struct {
+	Key  string `json:"key"`
+	Type string `json:"type"`
+}
+
+// DebugHandler returns an http.Handler that exposes information about the
+// Site as JSON, for debugging it while it's running. It's opt-in and
+// protected by the Authorize function in the DebugHandlerOptions. It serves:
+//
+//   - / lists the endpoints below.
+//   - /cache returns the CacheMemory report for the Site's caches.
+//   - /pages lists the Key and type of each page in the DebugHandlerOptions.
+//   - /pages/{key} returns the InspectReport for the page with that key.
+//   - /templates/{path} returns the keys of the pages that use the template
+//     at that path.
+//   - /errors returns the pages that recently failed to render, if the Site
+//     is a RecentRenderErrorLister, like a Site embedding RecentErrors.
+//
+// The handler expects its paths to be relative to where it's mounted, so it's
+// usually mounted using http.StripPrefix:
+//
+//	mux.Handle("/_temple/", http.StripPrefix("/_temple", temple.DebugHandler(site, opts)))
+func DebugHandler[SiteType Site](site SiteType, opts DebugHandlerOptions) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, _ *http.Request) {
+		writeDebugJSON(w, map[string][]string{
+			"endpoints": {"/cache", "/pages", "/pages/{key}", "/templates/{path}", "/errors"},
+		})
+	})
+	mux.HandleFunc("GET /cache", func(w http.ResponseWriter, r *http.Request) {
+		writeDebugJSON(w, CacheMemory(r.Context(), site))
+	})
+	mux.HandleFunc("GET /pages", func(w http.ResponseWriter, r *http.Request) {
+		pages := make([]debugPage, 0, len(opts.Pages))
+		for _, page := range opts.Pages {
+			pages = append(pages, debugPage{Key: page.Key(r.Context()), Type: componentType(page)})
+		}
+		writeDebugJSON(w, pages)
+	})
+	mux.HandleFunc("GET /pages/{key...}", func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		key := r.PathValue("key")
+		for _, page := range opts.Pages {
+			if page.Key(ctx) != key {
+				continue
+			}
+			report, err := Inspect(ctx, site, page)
+			if err != nil {
+				logger(ctx).ErrorContext(ctx, "error inspecting page", "key", key, "error", err)
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			writeDebugJSON(w, report)
+			return
+		}
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("GET /templates/{path...}", func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		index, err := IndexTemplates(ctx, site, opts.Pages...)
+		if err != nil {
+			logger(ctx).ErrorContext(ctx, "error indexing templates", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		keys := index.Keys(r.PathValue("path"))
+		if keys == nil {
+			keys = []string{}
+		}
+		writeDebugJSON(w, keys)
+	})
+	mux.HandleFunc("GET /errors", func(w http.ResponseWriter, r *http.Request) {
+		lister, ok := Site(site).(RecentRenderErrorLister)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeDebugJSON(w, lister.RecentRenderErrors(r.Context()))
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if opts.Authorize == nil || !opts.Authorize(r) {
+			http.Error(w, "Forbidden.", http.StatusForbidden)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// writeDebugJSON writes the value to the http.ResponseWriter as indented JSON.
+func writeDebugJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	// if the client went away, there's nothing to do about it
+	_ = enc.Encode(v)
+}
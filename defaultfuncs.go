@@ -0,0 +1,192 @@
+package temple
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"reflect"
+	"strings"
+)
+
+// DefaultFuncsIncluder is an optional interface for Sites. Sites whose
+// IncludeDefaultFuncs method returns true will have the functions from
+// DefaultFuncs available to all their templates, without needing to return
+// them from a FuncMapExtender. Functions from FuncMapExtenders take precedence
+// over the default functions with the same name.
+type DefaultFuncsIncluder interface {
+	// IncludeDefaultFuncs returns true if the functions from
+	// DefaultFuncs should be available to the Site's templates.
+	IncludeDefaultFuncs(context.Context) bool
+}
+
+// DefaultFuncs returns a template.FuncMap containing functions that most
+// Sites end up needing:
+//
+//   - dict builds a map from alternating keys and values, for passing
+//     multiple values to a template: {{ template "card" dict "Title" .Title
+//     "Body" .Body }}
+//   - list builds a slice from its arguments: {{ range list "a" "b" }}. It's
+//     not called slice, so it doesn't shadow the builtin slice function.
+//   - default returns its second argument, unless it's the zero value, in
+//     which case it returns its first: {{ .Page.Name | default "Anonymous" }}
+//   - trim removes leading and trailing whitespace from a string.
+//   - truncate shortens a string to a number of characters using Truncate:
+//     {{ .Page.Summary | truncate 140 }}
+//   - highlight escapes a string, wrapping each word of a search query in
+//     <mark> elements using Highlight: {{ .Page.Title | highlight .Page.Query }}
+//   - markdownInline renders a small, safe subset of inline Markdown
+//     (**bold**, *emphasis*, `code`, and [links](https://example.com)) to
+//     HTML, escaping everything else.
+//   - jsonEncode encodes a value as JSON.
+//   - sanitize cleans untrusted HTML using BasicSanitizer, so user-generated
+//     content can be included in the page without escaping it. Sites can
+//     use a different policy by overriding it with SanitizeFunc.
+//
+// Functions that mark content as safe, bypassing html/template's escaping,
+// aren't included; they're available from TrustedContentFuncs for Sites that
+// want to opt into them.
+func DefaultFuncs() template.FuncMap {
+	return template.FuncMap{
+		"dict":           dict,
+		"list":           func(items ...any) []any { return items },
+		"default":        defaultValue,
+		"trim":           strings.TrimSpace,
+		"truncate":       func(n int, s string) string { return Truncate(s, n) },
+		"highlight":      highlightQuery,
+		"markdownInline": markdownInline,
+		"jsonEncode":     jsonEncode,
+		"sanitize":       SanitizeFunc(BasicSanitizer{}),
+	}
+}
+
+// TrustedContentFuncs returns a template.FuncMap containing functions that
+// mark strings as safe to include in the output without escaping: safeHTML,
+// safeJS, safeCSS, and safeURL. Passing user-controlled content to them will
+// introduce cross-site scripting vulnerabilities, so they're not included in
+// DefaultFuncs, and Sites need to opt into them explicitly by returning them
+// from a FuncMapExtender.
+func TrustedContentFuncs() template.FuncMap {
+	return template.FuncMap{
+		"safeHTML": func(s string) template.HTML { return template.HTML(s) }, // #nosec G203
+		"safeJS":   func(s string) template.JS { return template.JS(s) },     // #nosec G203
+		"safeCSS":  func(s string) template.CSS { return template.CSS(s) },   // #nosec G203
+		"safeURL":  func(s string) template.URL { return template.URL(s) },   // #nosec G203
+	}
+}
+
+var errDictOddArgs = errors.New("dict needs an even number of arguments")
+
+func dict(pairs ...any) (map[string]any, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errDictOddArgs
+	}
+	results := make(map[string]any, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict keys must be strings, got %T", pairs[i])
+		}
+		results[key] = pairs[i+1]
+	}
+	return results, nil
+}
+
+func defaultValue(def, val any) any {
+	if val == nil {
+		return def
+	}
+	if reflect.ValueOf(val).IsZero() {
+		return def
+	}
+	return val
+}
+
+func jsonEncode(val any) (string, error) {
+	out, err := json.Marshal(val)
+	if err != nil {
+		return "", fmt.Errorf("error encoding JSON: %w", err)
+	}
+	return string(out), nil
+}
+
+// markdownInline renders **bold**, *emphasis*, `code`, and [links](url) to
+// HTML. Everything else is escaped, and links are only rendered if they're
+// relative or use the http, https, or mailto schemes.
+func markdownInline(s string) template.HTML {
+	var out strings.Builder
+	for len(s) > 0 {
+		switch {
+		case strings.HasPrefix(s, "`"):
+			if end := strings.Index(s[1:], "`"); end >= 0 {
+				out.WriteString("<code>" + html.EscapeString(s[1:1+end]) + "</code>")
+				s = s[end+2:]
+				continue
+			}
+		case strings.HasPrefix(s, "**"):
+			if end := strings.Index(s[2:], "**"); end > 0 {
+				out.WriteString("<strong>" + string(markdownInline(s[2:2+end])) + "</strong>")
+				s = s[end+4:]
+				continue
+			}
+		case strings.HasPrefix(s, "*"):
+			if end := strings.Index(s[1:], "*"); end > 0 {
+				out.WriteString("<em>" + string(markdownInline(s[1:1+end])) + "</em>")
+				s = s[end+2:]
+				continue
+			}
+		case strings.HasPrefix(s, "["):
+			if text, href, rest, ok := parseMarkdownLink(s); ok {
+				out.WriteString(`<a href="` + html.EscapeString(href) + `">` + string(markdownInline(text)) + "</a>")
+				s = rest
+				continue
+			}
+		}
+		// not the start of any markup, so escape the next character
+		// and move on
+		next := 1
+		for next < len(s) && !strings.ContainsRune("`*[", rune(s[next])) {
+			next++
+		}
+		out.WriteString(html.EscapeString(s[:next]))
+		s = s[next:]
+	}
+	return template.HTML(out.String()) // #nosec G203
+}
+
+// parseMarkdownLink parses a [text](href) link at the start of `s`, returning
+// the text, the href, and the rest of the string after the link. It returns
+// false if `s` doesn't start with a link or the link's href isn't safe.
+func parseMarkdownLink(s string) (string, string, string, bool) {
+	// the link text ends at the first ], which has to be followed by the
+	// href, so brackets before a link aren't mistaken for its text
+	closeText := strings.Index(s, "]")
+	if closeText < 0 || !strings.HasPrefix(s[closeText:], "](") {
+		return "", "", "", false
+	}
+	closeHref := strings.Index(s[closeText:], ")")
+	if closeHref < 0 {
+		return "", "", "", false
+	}
+	text := s[1:closeText]
+	href := s[closeText+2 : closeText+closeHref]
+	if !safeLinkHref(href) {
+		return "", "", "", false
+	}
+	return text, href, s[closeText+closeHref+1:], true
+}
+
+func safeLinkHref(href string) bool {
+	scheme, _, found := strings.Cut(href, ":")
+	if !found || strings.ContainsAny(scheme, "/?#") {
+		// no scheme, so it's a relative URL
+		return true
+	}
+	switch strings.ToLower(scheme) {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package temple_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"impractical.co/temple"
+)
+
+type defaultFuncsPage struct {
+	Text string
+}
+
+func (defaultFuncsPage) Templates(_ context.Context) []string {
+	return []string{"default-funcs.html.tmpl"}
+}
+
+func (defaultFuncsPage) Key(_ context.Context) string {
+	return "default-funcs.html.tmpl"
+}
+
+func (defaultFuncsPage) ExecutedTemplate(_ context.Context) string {
+	return "default-funcs.html.tmpl"
+}
+
+func TestDefaultFuncs(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		template string
+		text     string
+		want     string
+	}{
+		"list": {
+			template: `{{ range list "a" "b" }}{{ . }}{{ end }}`,
+			want:     "ab",
+		},
+		"builtin-slice": {
+			template: `{{ slice "abcd" 1 3 }}`,
+			want:     "bc",
+		},
+		"markdown-link": {
+			template: `{{ markdownInline .Page.Text }}`,
+			text:     "see [the docs](/docs)",
+			want:     `see <a href="/docs">the docs</a>`,
+		},
+		"markdown-brackets-before-link": {
+			template: `{{ markdownInline .Page.Text }}`,
+			text:     "[a] b [c](d)",
+			want:     `[a] b <a href="d">c</a>`,
+		},
+		"markdown-unclosed-link": {
+			template: `{{ markdownInline .Page.Text }}`,
+			text:     "[a] (b)",
+			want:     `[a] (b)`,
+		},
+		"markdown-unsafe-link": {
+			template: `{{ markdownInline .Page.Text }}`,
+			text:     "[a](javascript:alert(1))",
+			want:     `[a](javascript:alert(1))`,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			site := temple.NewCachedSite(fstest.MapFS{
+				"default-funcs.html.tmpl": {Data: []byte(test.template)},
+			}, temple.WithDefaultFuncs())
+			var out strings.Builder
+			result := temple.Render(context.Background(), &out, site, defaultFuncsPage{Text: test.text})
+			if result.Err != nil {
+				t.Fatalf("unexpected error: %s", result.Err)
+			}
+			if out.String() != test.want {
+				t.Errorf("expected %q, got %q", test.want, out.String())
+			}
+		})
+	}
+}
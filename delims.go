@@ -0,0 +1,37 @@
+package temple
+
+import (
+	"context"
+)
+
+// DelimsProvider is an interface that Sites can fulfill to parse their
+// templates with action delimiters other than {{ and }}, like Sites whose
+// output is used by front-end frameworks that claim those delimiters for
+// themselves. Components that supply their own templates using
+// TemplateDirProvider can fulfill it to set the delimiters for their own
+// templates; they're otherwise parsed with the default delimiters, so
+// packages of Components keep working regardless of the Site's delimiters.
+type DelimsProvider interface {
+	// Delims returns the left and right action delimiters to use when
+	// parsing templates. An empty delimiter means the default, {{ or }}.
+	Delims(ctx context.Context) (left, right string)
+}
+
+// templateDelims returns the delimiters the Component's templates should be
+// parsed with. Templates from the Site's TemplateDir use the Site's
+// delimiters, and templates from the Component's own TemplateDir use the
+// Component's.
+func templateDelims(ctx context.Context, site Site, comp Component) [2]string {
+	var provider DelimsProvider
+	var ok bool
+	if _, ownDir := comp.(TemplateDirProvider); ownDir {
+		provider, ok = comp.(DelimsProvider)
+	} else {
+		provider, ok = site.(DelimsProvider)
+	}
+	if !ok {
+		return [2]string{}
+	}
+	left, right := provider.Delims(ctx)
+	return [2]string{left, right}
+}
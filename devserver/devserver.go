@@ -0,0 +1,362 @@
+// Package devserver provides an http.Handler for developing temple Sites,
+// which reloads pages in the browser whenever their templates change.
+//
+// A Server wraps the Site's own http.Handler. It watches the Site's
+// templates for changes, invalidates the cached templates of the pages that
+// use them, and tells any open pages to reload, using a small script it adds
+// to every HTML response:
+//
+//	dev := &devserver.Server{
+//		Site:    site,
+//		Handler: mux,
+//		Pages:   []temple.Renderable{HomePage{}, PostPage{}},
+//	}
+//	err := dev.Start(ctx)
+//	if err != nil {
+//		// handle error
+//	}
+//	defer dev.Shutdown(ctx)
+//	http.ListenAndServe("localhost:8080", dev)
+//
+// It's meant for development only, and should never be used in production.
+package devserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"impractical.co/temple"
+)
+
+const (
+	// DefaultEventsPath is the path the reload script listens for events
+	// on, if the Server doesn't have an EventsPath.
+	DefaultEventsPath = "/_temple/reload"
+
+	// DefaultInterval is how often templates are checked for changes, if
+	// the Server doesn't have an Interval.
+	DefaultInterval = 500 * time.Millisecond
+)
+
+// reloadScript reloads the page when the server sends an event. The %s is
+// replaced by the quoted path to listen for events on.
+const reloadScript = `<script>new EventSource(%s).addEventListener("reload",function(){location.reload()})</script>`
+
+var (
+	_ http.Handler   = &Server{}
+	_ temple.Service = &Server{}
+)
+
+// Server is an http.Handler that serves a Site, reloading its pages in the
+// browser when their templates change. It's a temple.Service, and needs to
+// be started before it notices any changes.
+type Server struct {
+	// Site is the Site being developed.
+	Site temple.Site
+
+	// Handler serves the Site's pages.
+	Handler http.Handler
+
+	// Templates is the fs.FS to watch for changes. If nil, the Site's
+	// TemplateDir is used.
+	Templates fs.FS
+
+	// Pages are the pages whose cached templates are invalidated when
+	// templates they use change. temple can't discover a Site's pages on
+	// its own; if the Site caches templates, pages that aren't listed
+	// won't pick up changes, so Sites without a complete list should use
+	// temple.WithDevelopment to turn off caching instead.
+	Pages []temple.Renderable
+
+	// Interval is how often to check for changes. If 0, DefaultInterval
+	// is used.
+	Interval time.Duration
+
+	// EventsPath is the path browsers listen for reload events on. If
+	// empty, DefaultEventsPath is used.
+	EventsPath string
+
+	watcher  *temple.BackgroundService
+	clients  map[chan struct{}]struct{}
+	clientMu sync.Mutex
+}
+
+// Start starts watching the templates for changes.
+func (s *Server) Start(ctx context.Context) error {
+	templates := s.Templates
+	if templates == nil {
+		templates = s.Site.TemplateDir(ctx)
+	}
+	files, err := snapshot(templates)
+	if err != nil {
+		return err
+	}
+	interval := s.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	s.watcher = &temple.BackgroundService{
+		Run: func(ctx context.Context) error {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-ticker.C:
+				}
+				current, err := snapshot(templates)
+				if err != nil {
+					// the templates are probably mid-save,
+					// try again next time
+					continue
+				}
+				changed := diff(files, current)
+				files = current
+				if len(changed) > 0 {
+					s.reload(ctx, changed)
+				}
+			}
+		},
+	}
+	return s.watcher.Start(ctx)
+}
+
+// Shutdown stops watching the templates for changes, and disconnects any
+// browsers listening for reload events.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.clientMu.Lock()
+	for client := range s.clients {
+		close(client)
+	}
+	s.clients = nil
+	s.clientMu.Unlock()
+	if s.watcher == nil {
+		return nil
+	}
+	return s.watcher.Shutdown(ctx)
+}
+
+// reload invalidates the cached templates for the pages that use the changed
+// templates, and tells every connected browser to reload.
+func (s *Server) reload(ctx context.Context, changed []string) {
+	if len(s.Pages) > 0 {
+		index, err := temple.IndexTemplates(ctx, s.Site, s.Pages...)
+		if err == nil {
+			temple.InvalidateTemplates(ctx, s.Site, index, changed...)
+		}
+	}
+	s.clientMu.Lock()
+	defer s.clientMu.Unlock()
+	for client := range s.clients {
+		select {
+		case client <- struct{}{}:
+		default:
+			// a reload is already pending for this client
+		}
+	}
+}
+
+// ServeHTTP serves reload events on the EventsPath, and passes every other
+// request to the Handler, adding the reload script to HTML responses.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	eventsPath := s.EventsPath
+	if eventsPath == "" {
+		eventsPath = DefaultEventsPath
+	}
+	if r.URL.Path == eventsPath {
+		s.serveEvents(w, r)
+		return
+	}
+	writer := &injectingWriter{ResponseWriter: w, script: fmt.Sprintf(reloadScript, strconv.Quote(eventsPath))}
+	s.Handler.ServeHTTP(writer, r)
+	writer.finish()
+}
+
+// serveEvents streams a reload event to the browser whenever templates
+// change, until the browser disconnects or the Server shuts down.
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	client := make(chan struct{}, 1)
+	s.clientMu.Lock()
+	if s.clients == nil {
+		s.clients = map[chan struct{}]struct{}{}
+	}
+	s.clients[client] = struct{}{}
+	s.clientMu.Unlock()
+	defer func() {
+		s.clientMu.Lock()
+		delete(s.clients, client)
+		s.clientMu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	controller := http.NewResponseController(w)
+	// errors flushing mean the browser has gone, which the request's
+	// context.Context will tell us about
+	_ = controller.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case _, ok := <-client:
+			if !ok {
+				return
+			}
+			_, err := w.Write([]byte("event: reload\ndata: {}\n\n"))
+			if err != nil {
+				return
+			}
+			_ = controller.Flush()
+		}
+	}
+}
+
+// injectingWriter is an http.ResponseWriter that buffers HTML responses, so
+// the reload script can be added to them before they're written.
+type injectingWriter struct {
+	http.ResponseWriter
+	script string
+	status int
+	html   bool
+	// decided is true once we know whether the response is HTML
+	decided bool
+	buf     bytes.Buffer
+}
+
+// WriteHeader records the status code of HTML responses, to be written
+// when they're finished, and passes it on to the wrapped http.ResponseWriter
+// for other responses. Informational status codes, like 103 Early Hints,
+// are always passed on, as they're not the final status of the response.
+func (w *injectingWriter) WriteHeader(status int) {
+	if status >= 100 && status <= 199 && status != http.StatusSwitchingProtocols {
+		if !w.decided {
+			w.ResponseWriter.WriteHeader(status)
+		}
+		return
+	}
+	if w.decided {
+		return
+	}
+	w.decide()
+	w.status = status
+	if !w.html {
+		w.ResponseWriter.WriteHeader(status)
+	}
+}
+
+// Write buffers HTML responses, and passes other responses on to the wrapped
+// http.ResponseWriter.
+func (w *injectingWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.html {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.buf.Write(b)
+}
+
+// FlushError flushes responses that aren't HTML. HTML responses are buffered
+// until they're finished, so flushing them does nothing.
+func (w *injectingWriter) FlushError() error {
+	if !w.decided {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.html {
+		return nil
+	}
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped http.ResponseWriter, for use with
+// http.ResponseController.
+func (w *injectingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// decide records whether the response is HTML, based on its headers.
+func (w *injectingWriter) decide() {
+	w.decided = true
+	w.html = strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") && w.Header().Get("Content-Encoding") == ""
+}
+
+// finish writes the buffered HTML response, if there is one, with the
+// reload script added before the closing </body> tag, or at the end if
+// there isn't one.
+func (w *injectingWriter) finish() {
+	if !w.html {
+		return
+	}
+	body := w.buf.Bytes()
+	index := bytes.LastIndex(bytes.ToLower(body), []byte("</body>"))
+	if index < 0 {
+		index = len(body)
+	}
+	out := make([]byte, 0, len(body)+len(w.script))
+	out = append(out, body[:index]...)
+	out = append(out, w.script...)
+	out = append(out, body[index:]...)
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(w.status)
+	// errors writing mean the browser has gone, there's nothing to do
+	_, _ = w.ResponseWriter.Write(out)
+}
+
+// fileState is what's compared to tell if a file has changed.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// snapshot returns the state of every file in the fs.FS.
+func snapshot(dir fs.FS) (map[string]fileState, error) {
+	files := map[string]fileState{}
+	err := fs.WalkDir(dir, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		files[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading templates: %w", err)
+	}
+	return files, nil
+}
+
+// diff returns the paths of the files that were added, removed, or changed
+// between the two snapshots.
+func diff(before, after map[string]fileState) []string {
+	var changed []string
+	for path, state := range after {
+		prev, ok := before[path]
+		if !ok || !prev.modTime.Equal(state.modTime) || prev.size != state.size {
+			changed = append(changed, path)
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,106 @@
+package devserver_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"impractical.co/temple/devserver"
+)
+
+func TestServerEarlyHints(t *testing.T) {
+	t.Parallel()
+
+	dev := &devserver.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Link", "</style.css>; rel=preload; as=style")
+			w.WriteHeader(http.StatusEarlyHints)
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, "<html><body>Not found</body></html>")
+		}),
+	}
+	server := httptest.NewServer(dev)
+	defer server.Close()
+
+	var hints []string
+	trace := &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			if code == http.StatusEarlyHints {
+				hints = append(hints, header.Values("Link")...)
+			}
+			return nil
+		},
+	}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("error creating request: %s", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("error making request: %s", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("error reading response: %s", err)
+	}
+
+	if len(hints) != 1 || hints[0] != "</style.css>; rel=preload; as=style" {
+		t.Errorf("expected early hints with the preload link, got %q", hints)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, resp.StatusCode)
+	}
+	if !strings.Contains(string(body), "<script>") {
+		t.Errorf("expected reload script to be added, got %q", body)
+	}
+}
+
+func TestServerResponseController(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		contentType string
+		wantScript  bool
+	}{
+		"html": {contentType: "text/html", wantScript: true},
+		"text": {contentType: "text/plain", wantScript: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dev := &devserver.Server{
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					w.Header().Set("Content-Type", test.contentType)
+					_, _ = io.WriteString(w, "<p>one</p>")
+					err := http.NewResponseController(w).Flush()
+					if err != nil {
+						t.Errorf("error flushing: %s", err)
+					}
+					_, _ = io.WriteString(w, "<p>two</p>")
+				}),
+			}
+			resp := httptest.NewRecorder()
+			dev.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			body := resp.Body.String()
+			if !strings.HasPrefix(body, "<p>one</p><p>two</p>") {
+				t.Errorf("expected both writes in the body, got %q", body)
+			}
+			if got := strings.Contains(body, "<script>"); got != test.wantScript {
+				t.Errorf("expected reload script %v, got %q", test.wantScript, body)
+			}
+			if got := resp.Flushed; got == test.wantScript {
+				t.Errorf("expected flushed %v, got %v", !test.wantScript, got)
+			}
+		})
+	}
+}
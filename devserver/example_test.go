@@ -0,0 +1,112 @@
+package devserver_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"impractical.co/temple"
+	"impractical.co/temple/devserver"
+)
+
+type HomePage struct{}
+
+func (HomePage) Templates(_ context.Context) []string {
+	return []string{"home.html.tmpl"}
+}
+
+func (HomePage) Key(_ context.Context) string {
+	return "home.html.tmpl"
+}
+
+func (HomePage) ExecutedTemplate(_ context.Context) string {
+	return "home.html.tmpl"
+}
+
+func Example() {
+	dir, err := os.MkdirTemp("", "devserver-example")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	home := filepath.Join(dir, "home.html.tmpl")
+	err = os.WriteFile(home, []byte(`<html><body><h1>Hello</h1></body></html>`), 0o600)
+	if err != nil {
+		panic(err)
+	}
+	site := temple.NewCachedSite(os.DirFS(dir))
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		temple.Render(r.Context(), w, site, HomePage{})
+	})
+
+	ctx := context.Background()
+	dev := &devserver.Server{
+		Site:     site,
+		Handler:  mux,
+		Pages:    []temple.Renderable{HomePage{}},
+		Interval: 10 * time.Millisecond,
+	}
+	err = dev.Start(ctx)
+	if err != nil {
+		panic(err)
+	}
+	defer dev.Shutdown(ctx)
+	server := httptest.NewServer(dev)
+	defer server.Close()
+
+	// every HTML page gets the reload script
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		panic(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	fmt.Println(string(body))
+
+	// and is told to reload when the templates change
+	events, err := http.Get(server.URL + devserver.DefaultEventsPath)
+	if err != nil {
+		panic(err)
+	}
+	defer events.Body.Close()
+	err = os.WriteFile(home, []byte(`<html><body><h1>Hello, world</h1></body></html>`), 0o600)
+	if err != nil {
+		panic(err)
+	}
+	// make sure the change is noticed, even on filesystems with coarse
+	// modification times
+	err = os.Chtimes(home, time.Time{}, time.Now().Add(time.Hour))
+	if err != nil {
+		panic(err)
+	}
+	lines := bufio.NewScanner(events.Body)
+	for lines.Scan() {
+		if strings.HasPrefix(lines.Text(), "event: ") {
+			fmt.Println(lines.Text())
+			break
+		}
+	}
+
+	// and the cached templates are invalidated, so the change shows up
+	resp, err = http.Get(server.URL)
+	if err != nil {
+		panic(err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	fmt.Println(strings.Contains(string(body), "Hello, world"))
+
+	//Output:
+	// <html><body><h1>Hello</h1><script>new EventSource("/_temple/reload").addEventListener("reload",function(){location.reload()})</script></body></html>
+	// event: reload
+	// true
+}
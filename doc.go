@@ -27,4 +27,21 @@
 // so all its methods (the templates it uses, any CSS or JS that it embeds or
 // links to, any Components _it_ relies on...) will all get included whenever
 // the homepage Component is rendered.
+//
+// By default, Render renders the entire page into memory before writing any
+// of it, so that if rendering fails partway through, a server error page can
+// be written instead of a truncated page, and so response headers can depend
+// on whether the page rendered successfully. For very large pages, that
+// buffer can get expensive; the WithStreaming RenderOption writes the page in
+// bounded chunks as it renders, at the cost of not being able to replace the
+// page with an error page once the first chunk has been written.
+//
+// Render records an OpenTelemetry span for every render, using the global
+// TracerProvider, with child spans for each phase of the render: resolving the
+// tree of Components, collecting their CSS, JavaScript, and data, parsing
+// templates, and executing them. Resolving the tree records a "use
+// components" span for each Component in it, covering its UseComponents call
+// and nesting the spans of the Components it uses, with its Go type in the
+// temple.component.type attribute. Collecting resources and executing
+// templates are recorded for the page as a whole, not per Component.
 package temple
@@ -0,0 +1,323 @@
+package temple
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// PlainTextTemplater is an optional interface for Renderables rendered with
+// RenderEmail. Those fulfilling it supply a template for the text/plain part
+// of the email, instead of having it derived from the HTML.
+type PlainTextTemplater interface {
+	// PlainTextTemplate returns the name of the template to execute to
+	// render the text/plain part of the email. It must be one of the
+	// templates parsed for the Renderable, so it can use the same
+	// RenderData as the HTML.
+	//
+	// The template is executed by html/template, so anything it outputs
+	// is escaped for HTML; RenderEmail unescapes it again before
+	// returning it.
+	PlainTextTemplate(ctx context.Context) string
+}
+
+// Email is the output of RenderEmail, ready to be assembled into a
+// multipart/alternative message.
+type Email struct {
+	// HTML is the text/html part of the email.
+	HTML string
+
+	// Text is the text/plain part of the email.
+	Text string
+}
+
+// withExecutedTemplate is a RenderOption that executes the named template
+// instead of the page's ExecutedTemplate.
+func withExecutedTemplate(name string) RenderOption {
+	return func(opts *renderOptions) {
+		opts.executedTemplate = name
+	}
+}
+
+// RenderEmail renders the page as an email, returning both an HTML part and
+// a plain text alternative. If the page is a PlainTextTemplater, the plain
+// text is rendered from its PlainTextTemplate; otherwise, it's derived from
+// the HTML using HTMLToText.
+//
+// The page is rendered using Render, with the passed RenderOptions, so it
+// can use everything a web page can. Unlike Render, if rendering fails, the
+// error is returned instead of an error page being rendered.
+func RenderEmail[SiteType Site, PageType Renderable](ctx context.Context, site SiteType, page PageType, opts ...RenderOption) (Email, error) {
+	var htmlPart strings.Builder
+	result := Render(ctx, &htmlPart, site, page, opts...)
+	if result.Err != nil {
+		return Email{}, result.Err
+	}
+	email := Email{HTML: htmlPart.String()}
+	templater, ok := Renderable(page).(PlainTextTemplater)
+	if !ok {
+		email.Text = HTMLToText(email.HTML)
+		return email, nil
+	}
+	var textPart strings.Builder
+	opts = append(slices.Clone(opts), withExecutedTemplate(templater.PlainTextTemplate(ctx)))
+	result = Render(ctx, &textPart, site, page, opts...)
+	if result.Err != nil {
+		return Email{}, fmt.Errorf("error rendering plain text for %T: %w", page, result.Err)
+	}
+	email.Text = html.UnescapeString(textPart.String())
+	return email, nil
+}
+
+// plainTextDroppedElements are the elements HTMLToText removes along with
+// their contents.
+var plainTextDroppedElements = map[string]struct{}{
+	"head":     {},
+	"noscript": {},
+	"script":   {},
+	"style":    {},
+	"template": {},
+	"title":    {},
+}
+
+// plainTextBlockElements are the elements HTMLToText puts on their own
+// lines, mapped to how many line breaks should separate them from the text
+// around them.
+var plainTextBlockElements = map[string]int{
+	"address":    1,
+	"article":    1,
+	"aside":      1,
+	"blockquote": 2,
+	"div":        1,
+	"dl":         2,
+	"dt":         1,
+	"dd":         1,
+	"figure":     2,
+	"footer":     1,
+	"h1":         2,
+	"h2":         2,
+	"h3":         2,
+	"h4":         2,
+	"h5":         2,
+	"h6":         2,
+	"header":     1,
+	"hr":         2,
+	"li":         1,
+	"main":       1,
+	"nav":        1,
+	"ol":         2,
+	"p":          2,
+	"pre":        2,
+	"section":    1,
+	"table":      2,
+	"tr":         1,
+	"ul":         2,
+}
+
+// HTMLToText converts HTML to plain text suitable for the text/plain part of
+// an email. Block elements, like paragraphs and headings, are separated by
+// line breaks, list items are prefixed with "- ", the URLs of links are
+// included in brackets after their text, and images are replaced by their
+// alt text. Whitespace is collapsed everywhere except in <pre> elements, and
+// scripts, styles, and the document's <head> are removed.
+func HTMLToText(s string) string {
+	// start as if two line breaks have already been written, so the
+	// output doesn't start with any
+	writer := plainTextWriter{newlines: 2}
+	// how many elements whose contents are being dropped are open
+	dropping := 0
+	// how many <pre> elements are open
+	pre := 0
+	// whether a <pre> element was just opened, as browsers ignore a line
+	// break straight after the opening tag
+	preStart := false
+	// the hrefs of the open <a> elements, and where their text starts
+	type link struct {
+		href  string
+		start int
+	}
+	var links []link
+	tokenizer := xhtml.NewTokenizer(strings.NewReader(s))
+	for {
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case xhtml.ErrorToken:
+			// either we've reached the end of the input, or it's
+			// malformed; either way, we're done
+			return strings.TrimSpace(writer.out.String())
+		case xhtml.TextToken:
+			if dropping > 0 {
+				continue
+			}
+			if pre > 0 {
+				text := string(tokenizer.Text())
+				if preStart {
+					text = strings.TrimPrefix(text, "\n")
+					preStart = false
+				}
+				writer.raw(text)
+				continue
+			}
+			writer.words(string(tokenizer.Text()))
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			token := tokenizer.Token()
+			if _, ok := plainTextDroppedElements[token.Data]; ok {
+				if tokenType == xhtml.StartTagToken {
+					dropping++
+				}
+				continue
+			}
+			if dropping > 0 {
+				continue
+			}
+			if breaks, ok := plainTextBlockElements[token.Data]; ok {
+				writer.lineBreak(breaks)
+			}
+			switch token.Data {
+			case "br":
+				writer.newline()
+			case "hr":
+				writer.words("---")
+			case "li":
+				writer.words("- ")
+			case "td", "th":
+				writer.space = true
+			case "pre":
+				if tokenType == xhtml.StartTagToken {
+					pre++
+					preStart = true
+				}
+			case "img":
+				writer.words(tokenAttr(token, "alt"))
+			case "a":
+				if tokenType == xhtml.StartTagToken {
+					links = append(links, link{href: tokenAttr(token, "href"), start: writer.out.Len()})
+				}
+			}
+		case xhtml.EndTagToken:
+			token := tokenizer.Token()
+			if _, ok := plainTextDroppedElements[token.Data]; ok {
+				if dropping > 0 {
+					dropping--
+				}
+				continue
+			}
+			if dropping > 0 {
+				continue
+			}
+			if breaks, ok := plainTextBlockElements[token.Data]; ok {
+				writer.lineBreak(breaks)
+			}
+			switch token.Data {
+			case "pre":
+				if pre > 0 {
+					pre--
+				}
+			case "a":
+				if len(links) < 1 {
+					continue
+				}
+				open := links[len(links)-1]
+				links = links[:len(links)-1]
+				text := strings.TrimSpace(writer.out.String()[open.start:])
+				// fragment links are useless outside the page,
+				// and links showing their own URL don't need it
+				// repeated
+				if open.href == "" || strings.HasPrefix(open.href, "#") || text == open.href || "mailto:"+text == open.href {
+					continue
+				}
+				writer.words(" (" + open.href + ")")
+			}
+		}
+		// comments and doctypes are always removed
+	}
+}
+
+// tokenAttr returns the value of the token's attribute with the passed key,
+// or an empty string if it doesn't have one.
+func tokenAttr(token xhtml.Token, key string) string {
+	for _, a := range token.Attr {
+		if a.Namespace == "" && a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// plainTextWriter builds the output of HTMLToText, collapsing whitespace
+// and line breaks.
+type plainTextWriter struct {
+	out strings.Builder
+
+	// space is true if a space should be written before the next word
+	space bool
+
+	// newlines is how many line breaks the output currently ends with
+	newlines int
+}
+
+// words writes the text with its whitespace collapsed.
+func (w *plainTextWriter) words(s string) {
+	if s == "" {
+		return
+	}
+	first, _ := utf8.DecodeRuneInString(s)
+	last, _ := utf8.DecodeLastRuneInString(s)
+	if unicode.IsSpace(first) {
+		w.space = true
+	}
+	for i, word := range strings.Fields(s) {
+		if i > 0 {
+			w.space = true
+		}
+		if w.space && w.newlines == 0 {
+			w.out.WriteByte(' ')
+		}
+		w.space = false
+		w.out.WriteString(word)
+		w.newlines = 0
+	}
+	if unicode.IsSpace(last) {
+		w.space = true
+	}
+}
+
+// raw writes the text exactly as it is.
+func (w *plainTextWriter) raw(s string) {
+	if s == "" {
+		return
+	}
+	if w.space && w.newlines == 0 {
+		w.out.WriteByte(' ')
+	}
+	w.space = false
+	w.out.WriteString(s)
+	trimmed := strings.TrimRight(s, "\n")
+	if trimmed == "" {
+		w.newlines += len(s)
+		return
+	}
+	w.newlines = len(s) - len(trimmed)
+}
+
+// newline writes a line break.
+func (w *plainTextWriter) newline() {
+	w.out.WriteByte('\n')
+	w.newlines++
+	w.space = false
+}
+
+// lineBreak writes line breaks until the output ends with at least `n` of
+// them.
+func (w *plainTextWriter) lineBreak(n int) {
+	for w.newlines < n {
+		w.newline()
+	}
+	w.space = false
+}
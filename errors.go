@@ -0,0 +1,171 @@
+package temple
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TemplateParseError is returned when one of the templates a page needs can't
+// be found, read, or parsed. Use errors.As to retrieve it from the error
+// Render records in its RenderResult.
+type TemplateParseError struct {
+	// Path is the path of the template that couldn't be parsed, or the
+	// pattern that didn't match any templates.
+	Path string
+
+	// Component is the Component whose Templates method returned the
+	// path.
+	Component Component
+
+	// Err is the underlying error.
+	Err error
+}
+
+func (e TemplateParseError) Error() string {
+	return fmt.Sprintf("error parsing template %q for %T: %s", e.Path, e.Component, e.Err)
+}
+
+func (e TemplateParseError) Unwrap() error {
+	return e.Err
+}
+
+// Kinds of resources that can cause a ResourceRenderError.
+const (
+	// ResourceKindCriticalCSS is the Kind of ResourceRenderError returned
+	// when a critical stylesheet from a CSSResourceLinker can't be read.
+	ResourceKindCriticalCSS = "critical CSS"
+
+	// ResourceKindEmbeddedCSS is the Kind of ResourceRenderError
+	// returned when the CSS from a CSSEmbedder fails the CSSValidator
+	// passed to WithCSSValidator.
+	ResourceKindEmbeddedCSS = "embedded CSS"
+
+	// ResourceKindImportMap is the Kind of ResourceRenderError returned
+	// when the import maps from JSImportMappers can't be merged.
+	ResourceKindImportMap = "import map"
+
+	// ResourceKindJSONData is the Kind of ResourceRenderError returned
+	// when JSONData from a JSONDataEmbedder can't be encoded.
+	ResourceKindJSONData = "JSON data"
+)
+
+// ResourceRenderError is returned when one of the resources a page uses, like
+// its critical CSS or import map, can't be included in it. Use errors.As to
+// retrieve it from the error Render records in its RenderResult.
+type ResourceRenderError struct {
+	// Key identifies the resource within its Kind: the Path of a
+	// critical stylesheet, the type of the Component whose embedded CSS
+	// is invalid, the specifier of an import map entry, or the ID of the
+	// JSONData.
+	Key string
+
+	// Kind is the kind of resource, one of the ResourceKind constants.
+	Kind string
+
+	// Component is the Component that supplied the resource, if it's
+	// known.
+	Component Component
+
+	// Err is the underlying error.
+	Err error
+}
+
+func (e ResourceRenderError) Error() string {
+	if e.Component == nil {
+		return fmt.Sprintf("error rendering %s %q: %s", e.Kind, e.Key, e.Err)
+	}
+	return fmt.Sprintf("error rendering %s %q for %T: %s", e.Kind, e.Key, e.Component, e.Err)
+}
+
+func (e ResourceRenderError) Unwrap() error {
+	return e.Err
+}
+
+// CycleError is returned when a Component uses itself, directly or through
+// the Components it uses, which would otherwise make resolving the page's
+// Components loop forever. Use errors.As to retrieve it from the error Render
+// records in its RenderResult.
+type CycleError struct {
+	// Components are the Components that make up the cycle, in the order
+	// they use each other. The first and last Components are the same.
+	Components []Component
+}
+
+func (e CycleError) Error() string {
+	return "component cycle: " + componentPath(e.Components)
+}
+
+// componentPath describes a chain of Components that use each other, like
+// "pkg.Page -> pkg.Layout -> pkg.Nav".
+func componentPath(components []Component) string {
+	types := make([]string, 0, len(components))
+	for _, comp := range components {
+		types = append(types, componentType(comp))
+	}
+	return strings.Join(types, " -> ")
+}
+
+// MissingSlotsError is returned when a page doesn't fill in all the required
+// Slots of a Component it uses, like a Layout, instead of rendering the page
+// with those regions left empty. Use errors.As to retrieve it from the error
+// Render records in its RenderResult.
+type MissingSlotsError struct {
+	// Component is the Component that declared the Slots.
+	Component Component
+
+	// Slots are the names of the required Slots that weren't filled in,
+	// in the order the Component declared them.
+	Slots []string
+}
+
+func (e MissingSlotsError) Error() string {
+	return fmt.Sprintf("%s is missing required slots: %s", componentName(e.Component), strings.Join(e.Slots, ", "))
+}
+
+// componentName describes a Component for error messages, including the
+// template path of Layouts, as the type alone doesn't distinguish them.
+func componentName(comp Component) string {
+	if layout, ok := comp.(Layout); ok {
+		return fmt.Sprintf("layout %q", layout.Path)
+	}
+	return componentType(comp)
+}
+
+// TemplateConflictError is returned when the WithStrictTemplateNames
+// RenderOption is used and two template files define a template with the same
+// name, so one would silently replace the other. Use errors.As to retrieve it
+// from the error Render records in its RenderResult.
+type TemplateConflictError struct {
+	// Name is the name of the template defined by both files.
+	Name string
+
+	// Paths are the paths of the two template files defining the
+	// template, in the order they were parsed.
+	Paths [2]string
+
+	// Components are the Components whose Templates methods returned
+	// each of the Paths.
+	Components [2]Component
+}
+
+func (e TemplateConflictError) Error() string {
+	return fmt.Sprintf("template %q is defined by both %q for %s and %q for %s", e.Name, e.Paths[0], componentName(e.Components[0]), e.Paths[1], componentName(e.Components[1]))
+}
+
+// UnmetRequirementsError is returned when Components on a page require
+// capabilities, using the Requirer interface, that nothing on the page
+// provides. Use errors.As to retrieve it from the error Render records in its
+// RenderResult.
+type UnmetRequirementsError struct {
+	// Requirements are the capabilities that aren't provided, in the
+	// order the Components requiring them were resolved.
+	Requirements []UnmetRequirement
+}
+
+func (e UnmetRequirementsError) Error() string {
+	unmet := make([]string, 0, len(e.Requirements))
+	for _, req := range e.Requirements {
+		unmet = append(unmet, fmt.Sprintf("%s requires %q", componentName(req.Component), req.Requirement))
+	}
+	return "unmet requirements: " + strings.Join(unmet, ", ")
+}
@@ -0,0 +1,58 @@
+package temple_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"impractical.co/temple"
+)
+
+type MemberPage struct {
+	Name string
+}
+
+func (MemberPage) Templates(_ context.Context) []string {
+	return []string{"member.html.tmpl"}
+}
+
+func (MemberPage) Key(_ context.Context) string {
+	return "member.html.tmpl"
+}
+
+func (MemberPage) ExecutedTemplate(_ context.Context) string {
+	return "member.html.tmpl"
+}
+
+func ExampleAccessLogMiddleware() {
+	var templates = staticFS{
+		"member.html.tmpl": `<h1>{{ .Page.Name }}</h1>`,
+	}
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+
+	// durations change from run to run, so leave them and the time out
+	// of the log
+	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey || attr.Key == "duration" {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	}))
+	handler := temple.AccessLogMiddleware(log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		temple.Render(r.Context(), w, site, MemberPage{Name: "Ada"})
+	}))
+
+	for range 2 {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/members/ada", nil))
+	}
+
+	//Output:
+	// level=INFO msg="served request" method=GET path=/members/ada status=200 bytes=12 render.key=member.html.tmpl render.cached_template=false
+	// level=INFO msg="served request" method=GET path=/members/ada status=200 bytes=12 render.key=member.html.tmpl render.cached_template=true
+}
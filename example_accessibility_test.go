@@ -0,0 +1,75 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"impractical.co/temple"
+)
+
+type GalleryPage struct{}
+
+func (GalleryPage) Templates(_ context.Context) []string {
+	return []string{"gallery.html.tmpl"}
+}
+
+func (GalleryPage) Key(_ context.Context) string {
+	return "gallery.html.tmpl"
+}
+
+func (GalleryPage) ExecutedTemplate(_ context.Context) string {
+	return "gallery.html.tmpl"
+}
+
+func ExampleAccessibilityAudit() {
+	var templates = staticFS{
+		"gallery.html.tmpl": `<html>
+<body>
+	<h1 id="gallery">Gallery</h1>
+	<a href="/photos/1"><img src="/photos/1.jpg"></a>
+	<a href="/photos/2"><img src="/photos/2.jpg" alt="A lighthouse at dusk"></a>
+	<a href="/next" aria-label="Next page"><svg></svg></a>
+	<section id="gallery"></section>
+</body>
+</html>`,
+	}
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+
+	audit := temple.AccessibilityAudit{
+		Report: func(_ context.Context, issues []temple.AccessibilityIssue) {
+			for _, issue := range issues {
+				fmt.Println(issue)
+			}
+		},
+	}
+	var out strings.Builder
+	temple.Render(context.Background(), &out, site, GalleryPage{}, temple.WithOutputFilters(audit))
+
+	//Output:
+	// missing-lang: <html>
+	// missing-alt: <img src="/photos/1.jpg">
+	// empty-link: <a href="/photos/1">
+	// duplicate-id: <section id="gallery">
+}
+
+func ExampleWithOutputFilters() {
+	var templates = staticFS{
+		"gallery.html.tmpl": `<p>Gallery</p>`,
+	}
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+
+	signature := temple.OutputFilterFunc(func(_ context.Context, html []byte) ([]byte, error) {
+		return append(html, "<!-- rendered by temple -->"...), nil
+	})
+	var out strings.Builder
+	temple.Render(context.Background(), &out, site, GalleryPage{}, temple.WithOutputFilters(signature))
+	fmt.Println(out.String())
+
+	//Output:
+	// <p>Gallery</p><!-- rendered by temple -->
+}
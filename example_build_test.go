@@ -0,0 +1,47 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"testing/fstest"
+
+	"impractical.co/temple"
+)
+
+type BuiltSite struct {
+	*temple.CachedSite
+	Static fstest.MapFS
+}
+
+// usually this would be a CommandBuildStep running Tailwind
+func (s BuiltSite) BuildSteps(_ context.Context) []temple.BuildStep {
+	return []temple.BuildStep{
+		temple.BuildFunc(func(_ context.Context) ([]string, error) {
+			s.Static["css/app.css"] = &fstest.MapFile{Data: []byte(".btn{padding:4px}")}
+			return []string{"css/app.css"}, nil
+		}),
+	}
+}
+
+func ExampleBuild() {
+	site := BuiltSite{
+		CachedSite: temple.NewCachedSite(staticFS{}),
+		Static:     fstest.MapFS{},
+	}
+	manifest := &temple.AssetManifest{Prefix: "/static/"}
+
+	// usually this would be done by registering a BuildService with the
+	// Site's Lifecycle
+	err := temple.Build(context.Background(), site, site.Static, manifest)
+	if err != nil {
+		panic(err)
+	}
+
+	// Components can use the manifest to link to the built assets
+	fmt.Println(manifest.URL("css/app.css"))
+	fmt.Println(manifest.URL("css/unbuilt.css"))
+
+	//Output:
+	// /static/css/app.css?v=3051fb3ab5f8
+	// /static/css/unbuilt.css
+}
@@ -0,0 +1,59 @@
+package temple_test
+
+import (
+	"context"
+	"os"
+
+	"impractical.co/temple"
+)
+
+type Sidebar struct {
+	Links []string
+}
+
+func (Sidebar) Templates(_ context.Context) []string {
+	return []string{"sidebar.html.tmpl"}
+}
+
+func (Sidebar) EntryTemplate(_ context.Context) string {
+	return "sidebar"
+}
+
+type AccountPage struct {
+	Sidebar Sidebar
+}
+
+func (AccountPage) Templates(_ context.Context) []string {
+	return []string{"account.html.tmpl"}
+}
+
+func (d AccountPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{d.Sidebar}
+}
+
+func (AccountPage) Key(_ context.Context) string {
+	return "account.html.tmpl"
+}
+
+func (AccountPage) ExecutedTemplate(_ context.Context) string {
+	return "account.html.tmpl"
+}
+
+func ExampleEntryTemplater() {
+	var templates = staticFS{
+		"account.html.tmpl": `<main>Account</main>
+{{ component .Page.Sidebar "Menu" }}`,
+		"sidebar.html.tmpl": `{{ define "sidebar" }}<nav>{{ .Data }}:{{ range .Component.Links }} <a href="{{ . }}">{{ . }}</a>{{ end }}</nav>{{ end }}`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	temple.Render(context.Background(), os.Stdout, site, AccountPage{
+		Sidebar: Sidebar{Links: []string{"/settings", "/billing"}},
+	})
+
+	//Output:
+	// <main>Account</main>
+	// <nav>Menu: <a href="/settings">/settings</a> <a href="/billing">/billing</a></nav>
+}
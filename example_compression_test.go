@@ -0,0 +1,66 @@
+package temple_test
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+
+	"impractical.co/temple"
+)
+
+type ChangelogPage struct {
+	Entries []string
+}
+
+func (ChangelogPage) Templates(_ context.Context) []string {
+	return []string{"changelog.html.tmpl"}
+}
+
+func (ChangelogPage) Key(_ context.Context) string {
+	return "changelog.html.tmpl"
+}
+
+func (ChangelogPage) ExecutedTemplate(_ context.Context) string {
+	return "changelog.html.tmpl"
+}
+
+func ExampleWithCompression() {
+	var templates = staticFS{
+		"changelog.html.tmpl": `<ul>{{ range .Page.Entries }}<li>{{ . }}</li>{{ end }}</ul>`,
+	}
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	page := ChangelogPage{}
+	for i := range 100 {
+		page.Entries = append(page.Entries, fmt.Sprintf("Fixed bug #%d", i))
+	}
+
+	req := httptest.NewRequest("GET", "/changelog", nil)
+	req.Header.Set("Accept-Encoding", "br;q=1.0, gzip;q=0.8")
+	resp := httptest.NewRecorder()
+	result := temple.Render(context.Background(), resp, site, page,
+		temple.WithCompression(req.Header.Get("Accept-Encoding")))
+	fmt.Println(resp.Header().Get("Content-Encoding"))
+	fmt.Println(resp.Header().Get("Vary"))
+	fmt.Println(resp.Header().Get("Content-Length") == fmt.Sprint(result.BytesWritten))
+
+	body, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		panic(err)
+	}
+	html, err := io.ReadAll(body)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(strings.HasSuffix(string(html), "<li>Fixed bug #99</li></ul>"))
+
+	//Output:
+	// gzip
+	// Accept-Encoding
+	// true
+	// true
+}
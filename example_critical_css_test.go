@@ -0,0 +1,54 @@
+package temple_test
+
+import (
+	"context"
+	"os"
+
+	"impractical.co/temple"
+)
+
+type CriticalPage struct{}
+
+func (CriticalPage) Templates(_ context.Context) []string {
+	return []string{"critical.html.tmpl"}
+}
+
+func (CriticalPage) Key(_ context.Context) string {
+	return "critical.html.tmpl"
+}
+
+func (CriticalPage) ExecutedTemplate(_ context.Context) string {
+	return "critical.html.tmpl"
+}
+
+func (CriticalPage) LinkCSSResources(_ context.Context) []temple.CSSLink {
+	return []temple.CSSLink{
+		{URL: "/static/above-the-fold.css", Path: "above-the-fold.css", Critical: true},
+		{URL: "/static/everything-else.css"},
+	}
+}
+
+func ExampleRender_criticalCSS() {
+	var templates = staticFS{
+		"above-the-fold.css": `body { margin: 0; }`,
+		"critical.html.tmpl": `<head>
+{{ .CriticalCSSTags }}{{ range .LinkedCSS }}<link rel="stylesheet" href="{{ . }}">{{ end }}
+</head>`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	temple.Render(context.Background(), os.Stdout, site, CriticalPage{})
+
+	//Output:
+	// <head>
+	// <style>
+	// /* critical CSS from above-the-fold.css */
+	// body { margin: 0; }
+	// </style>
+	// <link rel="preload" href="/static/above-the-fold.css" as="style" onload="this.onload=null;this.rel='stylesheet'">
+	// <noscript><link rel="stylesheet" href="/static/above-the-fold.css"></noscript>
+	// <link rel="stylesheet" href="/static/everything-else.css">
+	// </head>
+}
@@ -0,0 +1,66 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"impractical.co/temple"
+)
+
+type sessionCtxKey struct{}
+
+type CSRFSite struct {
+	*temple.CachedSite
+}
+
+// CSRFToken would usually look up the token for the user's session; here we
+// just pull it out of the context.
+func (CSRFSite) CSRFToken(ctx context.Context) (string, error) {
+	token, _ := ctx.Value(sessionCtxKey{}).(string)
+	return token, nil
+}
+
+type ContactPage struct {
+	CSRF temple.CSRFField
+}
+
+func (ContactPage) Templates(_ context.Context) []string {
+	return []string{"contact.html.tmpl"}
+}
+
+func (c ContactPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{c.CSRF}
+}
+
+func (ContactPage) Key(_ context.Context) string {
+	return "contact.html.tmpl"
+}
+
+func (ContactPage) ExecutedTemplate(_ context.Context) string {
+	return "contact.html.tmpl"
+}
+
+func ExampleCSRFField() {
+	var templates = staticFS{
+		"contact.html.tmpl": `<form method="post">{{ template "temple/csrf_field.html.tmpl" }}</form>
+`,
+	}
+
+	site := CSRFSite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+
+	// each request gets its own token, even though the parsed template is
+	// cached after the first request
+	for _, token := range []string{"first-token", "second-token"} {
+		ctx := context.WithValue(context.Background(), sessionCtxKey{}, token)
+		var out strings.Builder
+		temple.Render(ctx, &out, site, ContactPage{})
+		fmt.Print(out.String())
+	}
+
+	//Output:
+	// <form method="post"><input type="hidden" name="csrf_token" value="first-token"></form>
+	// <form method="post"><input type="hidden" name="csrf_token" value="second-token"></form>
+}
@@ -0,0 +1,62 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+
+	"impractical.co/temple"
+)
+
+type PromoWidget struct {
+	Color string
+}
+
+func (PromoWidget) Templates(_ context.Context) []string {
+	return nil
+}
+
+func (b PromoWidget) EmbedCSS(_ context.Context) template.CSS {
+	// a value with a stray brace breaks the rule, and everything after it
+	return template.CSS(".promo { color: " + b.Color + "; }") // #nosec G203
+}
+
+type PromoPage struct {
+	Promo PromoWidget
+}
+
+func (PromoPage) Templates(_ context.Context) []string {
+	return []string{"promo.html.tmpl"}
+}
+
+func (b PromoPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{b.Promo}
+}
+
+func (PromoPage) Key(_ context.Context) string {
+	return "promo.html.tmpl"
+}
+
+func (PromoPage) ExecutedTemplate(_ context.Context) string {
+	return "promo.html.tmpl"
+}
+
+func ExampleValidateEmbeddedCSS() {
+	ctx := context.Background()
+	page := PromoPage{Promo: PromoWidget{Color: "red"}}
+	css, err := temple.EmbeddedCSS(ctx, page)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(css)
+	fmt.Println(temple.ValidateEmbeddedCSS(ctx, page, temple.BasicCSSValidator{}))
+
+	page.Promo.Color = "red }"
+	fmt.Println(temple.ValidateEmbeddedCSS(ctx, page, temple.BasicCSSValidator{}))
+
+	//Output:
+	// /* embedded CSS from temple_test.PromoWidget */
+	// .promo { color: red; }
+	// <nil>
+	// error rendering embedded CSS "temple_test.PromoWidget" for temple_test.PromoWidget: invalid CSS: unexpected '}' on line 1
+}
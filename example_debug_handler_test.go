@@ -0,0 +1,58 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"impractical.co/temple"
+)
+
+func ExampleDebugHandler() {
+	var templates = staticFS{
+		"base.html.tmpl":  `<body>{{ block "body" . }}{{ end }}</body>`,
+		"home.html.tmpl":  `{{ define "body" }}Home{{ end }}`,
+		"about.html.tmpl": `{{ define "body" }}About{{ end }}`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/_temple/", http.StripPrefix("/_temple", temple.DebugHandler(site, temple.DebugHandlerOptions{
+		Authorize: func(r *http.Request) bool {
+			// use real authentication in production!
+			return r.Header.Get("X-Debug-Token") == "secret"
+		},
+		Pages: []temple.Renderable{HomePage{}, AboutPage{}},
+	})))
+
+	get := func(path, token string) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, path, nil)
+		req.Header.Set("X-Debug-Token", token)
+		resp := httptest.NewRecorder()
+		mux.ServeHTTP(resp, req)
+		fmt.Print(resp.Code, " ", resp.Body.String())
+	}
+	get("/_temple/pages", "wrong")
+	get("/_temple/pages", "secret")
+	get("/_temple/templates/base.html.tmpl", "secret")
+
+	//Output:
+	// 403 Forbidden.
+	// 200 [
+	//   {
+	//     "key": "home.html.tmpl",
+	//     "type": "temple_test.HomePage"
+	//   },
+	//   {
+	//     "key": "about.html.tmpl",
+	//     "type": "temple_test.AboutPage"
+	//   }
+	// ]
+	// 200 [
+	//   "about.html.tmpl",
+	//   "home.html.tmpl"
+	// ]
+}
@@ -0,0 +1,70 @@
+package temple_test
+
+import (
+	"context"
+	"os"
+
+	"impractical.co/temple"
+)
+
+type DebugLayout struct{}
+
+func (DebugLayout) Templates(_ context.Context) []string {
+	return []string{"debug_layout.html.tmpl"}
+}
+
+func (DebugLayout) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{temple.DebugToolbar{}}
+}
+
+func (DebugLayout) LinkCSS(_ context.Context) []string {
+	return []string{"/static/site.css"}
+}
+
+type DebugPage struct {
+	Layout DebugLayout
+}
+
+func (DebugPage) Templates(_ context.Context) []string {
+	return []string{"debug_page.html.tmpl"}
+}
+
+func (d DebugPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{d.Layout}
+}
+
+func (DebugPage) Key(_ context.Context) string {
+	return "debug_page.html.tmpl"
+}
+
+func (DebugPage) ExecutedTemplate(_ context.Context) string {
+	return "debug_layout.html.tmpl"
+}
+
+func ExampleWithDebugInfo() {
+	var templates = staticFS{
+		"debug_layout.html.tmpl": `{{ block "body" . }}{{ end }}`,
+		"debug_page.html.tmpl": `{{ define "body" -}}
+{{ with .Debug }}{{ .Key }}
+{{ range .Components }}{{ .Indent }}{{ .Type }}
+{{ end }}{{ range .Templates }}{{ . }}
+{{ end }}{{ range .LinkedCSS }}{{ . }}
+{{ end }}{{ end }}
+{{- end }}`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	temple.Render(context.Background(), os.Stdout, site, DebugPage{}, temple.WithDebugInfo())
+
+	//Output:
+	// debug_page.html.tmpl
+	// temple_test.DebugPage
+	//   temple_test.DebugLayout
+	//     temple.DebugToolbar
+	// debug_page.html.tmpl
+	// debug_layout.html.tmpl
+	// temple/debug_toolbar.html.tmpl
+	// /static/site.css
+}
@@ -0,0 +1,59 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"impractical.co/temple"
+)
+
+type FuncsSite struct {
+	*temple.CachedSite
+}
+
+func (FuncsSite) IncludeDefaultFuncs(_ context.Context) bool {
+	return true
+}
+
+type CommentPage struct {
+	Author  string
+	Comment string
+}
+
+func (CommentPage) Templates(_ context.Context) []string {
+	return []string{"comment.html.tmpl", "card.html.tmpl"}
+}
+
+func (CommentPage) Key(_ context.Context) string {
+	return "comment.html.tmpl"
+}
+
+func (CommentPage) ExecutedTemplate(_ context.Context) string {
+	return "comment.html.tmpl"
+}
+
+func ExampleDefaultFuncs() {
+	var templates = staticFS{
+		"card.html.tmpl":    `<p><b>{{ .Author }}</b>: {{ .Body }}</p>`,
+		"comment.html.tmpl": `{{ template "card.html.tmpl" dict "Author" (.Page.Author | default "Anonymous") "Body" (markdownInline .Page.Comment) }}`,
+	}
+
+	site := FuncsSite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	temple.Render(context.Background(), os.Stdout, site, CommentPage{
+		Comment: "This is **great**, see [the docs](https://example.com) <script>",
+	})
+
+	//Output:
+	// <p><b>Anonymous</b>: This is <strong>great</strong>, see <a href="https://example.com">the docs</a> &lt;script&gt;</p>
+}
+
+func ExampleBasicSanitizer() {
+	var sanitizer temple.BasicSanitizer
+	fmt.Println(sanitizer.Sanitize(`<p onclick="steal()">Hi <b>there</b><script>alert(1)</script>, <a href="javascript:steal()">click</a> <a href="/docs" class="x">docs</a>`))
+
+	//Output:
+	// <p>Hi <b>there</b>, <a rel="nofollow">click</a> <a href="/docs" rel="nofollow">docs</a></p>
+}
@@ -0,0 +1,110 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+
+	"impractical.co/temple"
+)
+
+type WelcomeEmail struct {
+	Name string
+}
+
+func (WelcomeEmail) Templates(_ context.Context) []string {
+	return []string{"welcome.html.tmpl"}
+}
+
+func (WelcomeEmail) Key(_ context.Context) string {
+	return "welcome.html.tmpl"
+}
+
+func (WelcomeEmail) ExecutedTemplate(_ context.Context) string {
+	return "welcome.html.tmpl"
+}
+
+func ExampleRenderEmail() {
+	var templates = staticFS{
+		"welcome.html.tmpl": `<html><head><title>Welcome</title></head><body>
+<h1>Welcome, {{ .Page.Name }}!</h1>
+<p>Thanks for signing up. Here's what to do next:</p>
+<ul>
+	<li>Fill out <a href="https://example.com/profile">your profile</a></li>
+	<li>Invite your team</li>
+</ul>
+</body></html>`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	email, err := temple.RenderEmail(context.Background(), site, WelcomeEmail{Name: "Sam & Alex"})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(email.Text)
+
+	//Output:
+	// Welcome, Sam & Alex!
+	//
+	// Thanks for signing up. Here's what to do next:
+	//
+	// - Fill out your profile (https://example.com/profile)
+	// - Invite your team
+}
+
+type ReceiptEmail struct {
+	Total string
+}
+
+func (ReceiptEmail) Templates(_ context.Context) []string {
+	return []string{"receipt.html.tmpl", "receipt.txt.tmpl"}
+}
+
+func (ReceiptEmail) Key(_ context.Context) string {
+	return "receipt.html.tmpl"
+}
+
+func (ReceiptEmail) ExecutedTemplate(_ context.Context) string {
+	return "receipt.html.tmpl"
+}
+
+func (ReceiptEmail) PlainTextTemplate(_ context.Context) string {
+	return "receipt.txt.tmpl"
+}
+
+func ExamplePlainTextTemplater() {
+	var templates = staticFS{
+		"receipt.html.tmpl": `<p>You paid <b>{{ .Page.Total }}</b>.</p>`,
+		"receipt.txt.tmpl":  `You paid {{ .Page.Total }} <including tax>.`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	email, err := temple.RenderEmail(context.Background(), site, ReceiptEmail{Total: "£5 & change"})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(email.HTML)
+	fmt.Println(email.Text)
+
+	//Output:
+	// <p>You paid <b>£5 &amp; change</b>.</p>
+	// You paid £5 & change <including tax>.
+}
+
+func ExampleHTMLToText() {
+	fmt.Println(temple.HTMLToText(`<p>Questions? Email <a href="mailto:help@example.com">help@example.com</a>.<br>We're here to help.</p><pre>
+  indented
+    code</pre><p><img src="/logo.png" alt="Example Inc."></p>`))
+
+	//Output:
+	// Questions? Email help@example.com.
+	// We're here to help.
+	//
+	//   indented
+	//     code
+	//
+	// Example Inc.
+}
@@ -0,0 +1,289 @@
+package temple_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"impractical.co/temple"
+)
+
+type SidebarWidget struct{}
+
+func (SidebarWidget) Templates(_ context.Context) []string {
+	return []string{"sidebar.html.tmpl"}
+}
+
+type SettingsPage struct {
+	Sidebar SidebarWidget
+}
+
+func (SettingsPage) Templates(_ context.Context) []string {
+	return []string{"settings.html.tmpl"}
+}
+
+func (p SettingsPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{p.Sidebar}
+}
+
+func (SettingsPage) Key(_ context.Context) string {
+	return "settings.html.tmpl"
+}
+
+func (SettingsPage) ExecutedTemplate(_ context.Context) string {
+	return "settings.html.tmpl"
+}
+
+func ExampleTemplateParseError() {
+	var templates = staticFS{
+		"settings.html.tmpl": `{{ template "sidebar.html.tmpl" }}`,
+		"sidebar.html.tmpl":  `{{ if .Page }}<aside></aside>`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	result := temple.Render(context.Background(), io.Discard, site, SettingsPage{})
+
+	var parseErr temple.TemplateParseError
+	if errors.As(result.Err, &parseErr) {
+		fmt.Printf("%s, needed by %T\n", parseErr.Path, parseErr.Component)
+	}
+
+	//Output:
+	// sidebar.html.tmpl, needed by temple_test.SidebarWidget
+}
+
+type DevSite struct {
+	*temple.CachedSite
+}
+
+func (DevSite) ServerErrorPageWithError(_ context.Context, data temple.ErrorPageData) temple.Renderable {
+	return DevErrorPage{Data: data}
+}
+
+type DevErrorPage struct {
+	Data temple.ErrorPageData
+}
+
+func (DevErrorPage) Templates(_ context.Context) []string {
+	return []string{"dev_error.html.tmpl"}
+}
+
+func (DevErrorPage) Key(_ context.Context) string {
+	return "dev_error.html.tmpl"
+}
+
+func (DevErrorPage) ExecutedTemplate(_ context.Context) string {
+	return "dev_error.html.tmpl"
+}
+
+func ExampleServerErrorPagerWithError() {
+	var templates = staticFS{
+		"settings.html.tmpl":  `{{ template "sidebar.html.tmpl" }}`,
+		"dev_error.html.tmpl": `<h1>Error rendering {{ .Page.Data.Key }}</h1><pre>{{ .Page.Data.Err }}</pre>`,
+	}
+
+	site := DevSite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	temple.Render(context.Background(), os.Stdout, site, SettingsPage{})
+
+	//Output:
+	// <h1>Error rendering settings.html.tmpl</h1><pre>error parsing templates [settings.html.tmpl sidebar.html.tmpl] for page temple_test.SettingsPage: error parsing template &#34;sidebar.html.tmpl&#34; for temple_test.SidebarWidget: pattern matches no files</pre>
+}
+
+type MenuWidget struct{}
+
+func (MenuWidget) Templates(_ context.Context) []string {
+	return []string{"menu.html.tmpl"}
+}
+
+func (MenuWidget) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{SubmenuWidget{}}
+}
+
+type SubmenuWidget struct{}
+
+func (SubmenuWidget) Templates(_ context.Context) []string {
+	return []string{"submenu.html.tmpl"}
+}
+
+func (SubmenuWidget) UseComponents(_ context.Context) []temple.Component {
+	// oops: the submenu uses the menu, which uses the submenu
+	return []temple.Component{MenuWidget{}}
+}
+
+type MenuPage struct{}
+
+func (MenuPage) Templates(_ context.Context) []string {
+	return []string{"menu_page.html.tmpl"}
+}
+
+func (MenuPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{MenuWidget{}}
+}
+
+func (MenuPage) Key(_ context.Context) string {
+	return "menu_page.html.tmpl"
+}
+
+func (MenuPage) ExecutedTemplate(_ context.Context) string {
+	return "menu_page.html.tmpl"
+}
+
+func ExampleCycleError() {
+	site := MySite{
+		CachedSite: temple.NewCachedSite(staticFS{}),
+	}
+	result := temple.Render(context.Background(), io.Discard, site, MenuPage{})
+
+	var cycleErr temple.CycleError
+	if errors.As(result.Err, &cycleErr) {
+		fmt.Println(cycleErr)
+	}
+
+	//Output:
+	// component cycle: temple_test.MenuWidget -> temple_test.SubmenuWidget -> temple_test.MenuWidget
+}
+
+type MonitoredSite struct {
+	*temple.CachedSite
+	*temple.RecentErrors
+}
+
+func ExampleRecentErrors() {
+	var templates = staticFS{
+		"settings.html.tmpl": `{{ template "sidebar.html.tmpl" }}`,
+		"sidebar.html.tmpl":  `{{ if .Page }}<aside></aside>`,
+	}
+
+	recent := temple.NewRecentErrors(10)
+	recent.Redact = func(e temple.RecentError) temple.RecentError {
+		// don't reveal the names of our templates
+		e.Error = strings.ReplaceAll(e.Error, "sidebar.html.tmpl", "[redacted]")
+		return e
+	}
+	site := MonitoredSite{
+		CachedSite:   temple.NewCachedSite(templates),
+		RecentErrors: recent,
+	}
+	temple.Render(context.Background(), io.Discard, site, SettingsPage{})
+
+	for _, e := range site.RecentRenderErrors(context.Background()) {
+		fmt.Println(e.Key)
+		fmt.Println(e.Error)
+	}
+
+	//Output:
+	// settings.html.tmpl
+	// error parsing templates [settings.html.tmpl [redacted]] for page temple_test.SettingsPage: error parsing template "[redacted]" for temple_test.SidebarWidget: template: [redacted]:1: unexpected EOF
+}
+
+type ProductList struct{}
+
+func (ProductList) Templates(_ context.Context) []string {
+	return []string{"product_list.html.tmpl"}
+}
+
+type OrderList struct{}
+
+func (OrderList) Templates(_ context.Context) []string {
+	return []string{"order_list.html.tmpl"}
+}
+
+type StorePage struct{}
+
+func (StorePage) Templates(_ context.Context) []string {
+	return []string{"store.html.tmpl"}
+}
+
+func (StorePage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{ProductList{}, OrderList{}}
+}
+
+func (StorePage) Key(_ context.Context) string {
+	return "store.html.tmpl"
+}
+
+func (StorePage) ExecutedTemplate(_ context.Context) string {
+	return "store.html.tmpl"
+}
+
+func ExampleTemplateConflictError() {
+	var templates = staticFS{
+		"store.html.tmpl":        `{{ template "product_list.html.tmpl" . }}{{ template "order_list.html.tmpl" . }}`,
+		"product_list.html.tmpl": `{{ define "item" }}<li>A product</li>{{ end }}`,
+		"order_list.html.tmpl":   `{{ define "item" }}<li>An order</li>{{ end }}`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	result := temple.Render(context.Background(), io.Discard, site, StorePage{}, temple.WithStrictTemplateNames())
+
+	var conflictErr temple.TemplateConflictError
+	if errors.As(result.Err, &conflictErr) {
+		fmt.Println(conflictErr)
+	}
+
+	//Output:
+	// template "item" is defined by both "product_list.html.tmpl" for temple_test.ProductList and "order_list.html.tmpl" for temple_test.OrderList
+}
+
+type DatePicker struct{}
+
+func (DatePicker) Templates(_ context.Context) []string {
+	return []string{"date_picker.html.tmpl"}
+}
+
+func (DatePicker) Requires(_ context.Context) []string {
+	return []string{"jquery", "modal-root"}
+}
+
+type JQuery struct{}
+
+func (JQuery) Templates(_ context.Context) []string {
+	return []string{"jquery.html.tmpl"}
+}
+
+func (JQuery) Provides(_ context.Context) []string {
+	return []string{"jquery"}
+}
+
+type BookingPage struct{}
+
+func (BookingPage) Templates(_ context.Context) []string {
+	return []string{"booking.html.tmpl"}
+}
+
+func (BookingPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{JQuery{}, DatePicker{}}
+}
+
+func (BookingPage) Key(_ context.Context) string {
+	return "booking.html.tmpl"
+}
+
+func (BookingPage) ExecutedTemplate(_ context.Context) string {
+	return "booking.html.tmpl"
+}
+
+func ExampleUnmetRequirementsError() {
+	site := MySite{
+		CachedSite: temple.NewCachedSite(staticFS{}),
+	}
+	// nothing on the page provides the modal root the DatePicker needs
+	err := temple.CheckRequirements(context.Background(), site, BookingPage{})
+
+	var unmetErr temple.UnmetRequirementsError
+	if errors.As(err, &unmetErr) {
+		fmt.Println(unmetErr)
+	}
+
+	//Output:
+	// unmet requirements: temple_test.DatePicker requires "modal-root"
+}
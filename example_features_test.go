@@ -0,0 +1,21 @@
+package temple_test
+
+import (
+	"fmt"
+
+	"impractical.co/temple"
+)
+
+func ExampleHasFeature() {
+	// a Component library can fall back to linking its scripts normally
+	// when the version of temple it's used with can't load them lazily
+	fmt.Println(temple.HasFeature(temple.FeatureJSLoadStrategies))
+
+	// Features newer than the version of temple the library was built
+	// against can be checked for by name
+	fmt.Println(temple.HasFeature(temple.Feature("time-travel")))
+
+	//Output:
+	// true
+	// false
+}
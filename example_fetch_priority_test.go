@@ -0,0 +1,62 @@
+package temple_test
+
+import (
+	"context"
+	"os"
+
+	"impractical.co/temple"
+)
+
+type PrioritizedSite struct {
+	*temple.CachedSite
+}
+
+func (PrioritizedSite) FetchPriorityPolicy(_ context.Context) temple.FetchPriorityPolicy {
+	return temple.FetchPriorityPolicy{
+		FirstCSS: temple.FetchPriorityHigh,
+		JS:       temple.FetchPriorityLow,
+	}
+}
+
+type HeroPage struct{}
+
+func (HeroPage) Templates(_ context.Context) []string {
+	return []string{"hero.html.tmpl"}
+}
+
+func (HeroPage) Key(_ context.Context) string {
+	return "hero.html.tmpl"
+}
+
+func (HeroPage) ExecutedTemplate(_ context.Context) string {
+	return "hero.html.tmpl"
+}
+
+func (HeroPage) LinkCSS(_ context.Context) []string {
+	return []string{"/css/site.css", "/css/hero.css"}
+}
+
+func (HeroPage) LinkJS(_ context.Context) []string {
+	return []string{"/js/analytics.js"}
+}
+
+func ExampleFetchPriorityPolicy() {
+	var templates = staticFS{
+		"hero.html.tmpl": `{{ range .LinkedCSS -}}
+<link rel="stylesheet" href="{{ . }}"{{ $.FetchPriority . }}>
+{{ end -}}
+{{ range .LinkedJS -}}
+<script src="{{ . }}"{{ $.FetchPriority . }} defer></script>
+{{ end -}}`,
+	}
+
+	site := PrioritizedSite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	temple.Render(context.Background(), os.Stdout, site, HeroPage{})
+
+	//Output:
+	// <link rel="stylesheet" href="/css/site.css" fetchpriority="high">
+	// <link rel="stylesheet" href="/css/hero.css">
+	// <script src="/js/analytics.js" fetchpriority="low" defer></script>
+}
@@ -0,0 +1,60 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"impractical.co/temple"
+)
+
+type GraphCachedSite struct {
+	*temple.CachedSite
+	*temple.GraphCache
+}
+
+func ExampleGraphCache() {
+	var templates = staticFS{
+		"base.html.tmpl": `{{ with .Debug }}{{ range .Timings }}{{ .Phase }}
+{{ end }}{{ end }}`,
+		"home.html.tmpl": ``,
+	}
+
+	site := GraphCachedSite{
+		CachedSite: temple.NewCachedSite(templates),
+		GraphCache: temple.NewGraphCache(),
+	}
+	ctx := context.Background()
+	// the first render resolves HomePage's Components and caches them
+	temple.Render(ctx, io.Discard, site, HomePage{})
+	// later renders of pages with the same key use the cached Components
+	temple.Render(ctx, os.Stdout, site, HomePage{}, temple.WithDebugInfo())
+
+	//Output:
+	// get cached components
+	// collect resources
+	// get cached templates
+}
+
+func ExampleCacheMemory() {
+	var templates = staticFS{
+		"base.html.tmpl": `<body>{{ block "body" . }}{{ end }}</body>`,
+		"home.html.tmpl": `{{ define "body" }}Hello, {{ .Site.Title }}{{ end }}`,
+	}
+
+	site := GraphCachedSite{
+		CachedSite: temple.NewCachedSite(templates),
+		GraphCache: temple.NewGraphCache(),
+	}
+	ctx := context.Background()
+	temple.Render(ctx, io.Discard, site, HomePage{})
+
+	report := temple.CacheMemory(ctx, site)
+	fmt.Printf("%d keys, %d templates, %d nodes, %d bytes of text\n", report.Templates.Keys, report.Templates.Templates, report.Templates.Nodes, report.Templates.TextBytes)
+	fmt.Printf("%d keys, %d components\n", report.Graphs.Keys, report.Graphs.Components)
+
+	//Output:
+	// 1 keys, 4 templates, 16 nodes, 20 bytes of text
+	// 1 keys, 1 components
+}
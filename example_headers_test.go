@@ -0,0 +1,70 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"impractical.co/temple"
+)
+
+type VideoEmbed struct{}
+
+func (VideoEmbed) Templates(_ context.Context) []string {
+	return []string{"video_embed.html.tmpl"}
+}
+
+// the embed declares the headers it needs, so every page using it gets them
+func (VideoEmbed) Headers(_ context.Context) http.Header {
+	return http.Header{
+		"Link":               {"<https://video.example.com>; rel=preconnect"},
+		"Permissions-Policy": {"fullscreen=(self \"https://video.example.com\")"},
+	}
+}
+
+type LessonPage struct{}
+
+func (LessonPage) Templates(_ context.Context) []string {
+	return []string{"lesson.html.tmpl"}
+}
+
+func (LessonPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{VideoEmbed{}}
+}
+
+func (LessonPage) Headers(_ context.Context) http.Header {
+	return http.Header{
+		"Link": {"</fonts/lesson.woff2>; rel=preload; as=font; crossorigin"},
+	}
+}
+
+func (LessonPage) Key(_ context.Context) string {
+	return "lesson.html.tmpl"
+}
+
+func (LessonPage) ExecutedTemplate(_ context.Context) string {
+	return "lesson.html.tmpl"
+}
+
+func ExampleHeaderSetter() {
+	var templates = staticFS{
+		"lesson.html.tmpl":      `<main>{{ template "video_embed.html.tmpl" }}</main>`,
+		"video_embed.html.tmpl": `<iframe src="https://video.example.com/embed/1" allowfullscreen></iframe>`,
+	}
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+
+	resp := httptest.NewRecorder()
+	temple.Render(context.Background(), resp, site, LessonPage{})
+	for _, link := range resp.Header().Values("Link") {
+		fmt.Println(link)
+	}
+	fmt.Println(resp.Header().Get("Permissions-Policy"))
+
+	//Output:
+	// </fonts/lesson.woff2>; rel=preload; as=font; crossorigin
+	// <https://video.example.com>; rel=preconnect
+	// fullscreen=(self "https://video.example.com")
+}
@@ -0,0 +1,88 @@
+package temple_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"impractical.co/temple"
+)
+
+type ArchivePage struct {
+	Posts []string
+}
+
+func (ArchivePage) Templates(_ context.Context) []string {
+	return []string{"archive.html.tmpl"}
+}
+
+func (ArchivePage) Key(_ context.Context) string {
+	return "archive.html.tmpl"
+}
+
+func (ArchivePage) ExecutedTemplate(_ context.Context) string {
+	return "archive.html.tmpl"
+}
+
+func ExampleHTMLValidator() {
+	// a stray <div> in the head, a <div> that's never closed, and list
+	// items outside of a list
+	var templates = staticFS{
+		"archive.html.tmpl": `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<title>Archive</title>
+	<div class="banner"></div>
+</head>
+<body>
+	<main>
+		<div class="posts">
+		{{ range .Page.Posts }}<li>{{ . }}</li>{{ end }}
+	</main>
+</body>
+</html>`,
+	}
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+
+	validator := temple.HTMLValidator{
+		Report: func(_ context.Context, issues []temple.HTMLIssue) {
+			for _, issue := range issues {
+				fmt.Println(issue)
+			}
+		},
+		Strict: true,
+	}
+	var out strings.Builder
+	result := temple.Render(context.Background(), &out, site, ArchivePage{Posts: []string{"Hello", "Goodbye"}}, temple.WithOutputFilters(validator))
+	fmt.Println(errors.Is(result.Err, temple.ErrInvalidHTML))
+
+	//Output:
+	// line 5: invalid-context: <div> inside <head>
+	// line 10: invalid-context: <li> inside <div>
+	// line 10: invalid-context: <li> inside <div>
+	// line 11: unclosed-element: <div> inside <main>
+	// true
+}
+
+func ExampleValidateHTML() {
+	// end tags the HTML spec lets pages leave out are fine, but a <div>
+	// closes the <p> it's in, leaving the </p> after it without a <p>
+	html := `<html>
+<head><title>Lists</title>
+<body>
+	<ul><li>One<li>Two</ul>
+	<p>First<p>Second
+	<table><tr><td>A<td>B<tr><td>C</table>
+	<p>Intro<div>Details</div></p>
+</body>
+</html>`
+	for _, issue := range temple.ValidateHTML([]byte(html)) {
+		fmt.Println(issue)
+	}
+
+	//Output:
+	// line 7: unexpected-end-tag: <p>
+}
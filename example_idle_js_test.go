@@ -0,0 +1,64 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"impractical.co/temple"
+)
+
+type ChatWidget struct{}
+
+func (ChatWidget) Templates(_ context.Context) []string {
+	return []string{"chat_widget.html.tmpl"}
+}
+
+// the chat widget isn't needed until the user's had a chance to look at the
+// page, so there's no need for it to slow the page down
+func (ChatWidget) LinkJSResources(_ context.Context) []temple.JSLink {
+	return []temple.JSLink{
+		{URL: "https://chat.example.com/widget.js", Strategy: temple.JSLoadIdle},
+	}
+}
+
+type SupportPage struct{}
+
+func (SupportPage) Templates(_ context.Context) []string {
+	return []string{"support.html.tmpl"}
+}
+
+func (SupportPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{ChatWidget{}}
+}
+
+func (SupportPage) Key(_ context.Context) string {
+	return "support.html.tmpl"
+}
+
+func (SupportPage) ExecutedTemplate(_ context.Context) string {
+	return "support.html.tmpl"
+}
+
+func ExampleJSLoadIdle() {
+	var templates = staticFS{
+		"support.html.tmpl":     `<body>{{ .IdleJSLoader }}</body>`,
+		"chat_widget.html.tmpl": ``,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	var out strings.Builder
+	temple.Render(context.Background(), &out, site, SupportPage{})
+	fmt.Println(strings.Contains(out.String(), "requestIdleCallback"))
+	fmt.Println(strings.Contains(out.String(), `["https://chat.example.com/widget.js"]`))
+
+	inspected, _ := temple.Inspect(context.Background(), site, SupportPage{})
+	fmt.Println(inspected.IdleJS, inspected.LinkedJS)
+
+	//Output:
+	// true
+	// true
+	// [https://chat.example.com/widget.js] []
+}
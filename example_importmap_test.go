@@ -0,0 +1,63 @@
+package temple_test
+
+import (
+	"context"
+	"os"
+
+	"impractical.co/temple"
+)
+
+type ChartWidget struct{}
+
+func (ChartWidget) Templates(_ context.Context) []string {
+	return nil
+}
+
+func (ChartWidget) ImportMap(_ context.Context) temple.JSImportMap {
+	return temple.JSImportMap{
+		"charts": "/static/charts.js",
+		"utils":  "/static/utils.js",
+	}
+}
+
+type DashboardPage struct {
+	Chart ChartWidget
+}
+
+func (DashboardPage) Templates(_ context.Context) []string {
+	return []string{"dashboard.html.tmpl"}
+}
+
+func (d DashboardPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{d.Chart}
+}
+
+func (DashboardPage) ImportMap(_ context.Context) temple.JSImportMap {
+	return temple.JSImportMap{
+		"utils": "/static/utils.js",
+	}
+}
+
+func (DashboardPage) Key(_ context.Context) string {
+	return "dashboard.html.tmpl"
+}
+
+func (DashboardPage) ExecutedTemplate(_ context.Context) string {
+	return "dashboard.html.tmpl"
+}
+
+func ExampleRender_importMap() {
+	var templates = staticFS{
+		"dashboard.html.tmpl": `{{ .ImportMapTag }}
+<script type="module">import { draw } from "charts";</script>`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	temple.Render(context.Background(), os.Stdout, site, DashboardPage{})
+
+	//Output:
+	// <script type="importmap">{"imports":{"charts":"/static/charts.js","utils":"/static/utils.js"}}</script>
+	// <script type="module">import { draw } from "charts";</script>
+}
@@ -0,0 +1,59 @@
+package temple_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"impractical.co/temple"
+)
+
+func ExampleInspect() {
+	var templates = staticFS{
+		"debug_layout.html.tmpl": `{{ block "body" . }}{{ end }}`,
+		"debug_page.html.tmpl":   `{{ define "body" }}Hello{{ end }}`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	report, err := temple.Inspect(context.Background(), site, DebugPage{})
+	if err != nil {
+		panic(err)
+	}
+	out, err := json.MarshalIndent(map[string]any{
+		"components": report.Components,
+		"templates":  report.Templates,
+		"linkedCSS":  report.LinkedCSS,
+	}, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(out))
+
+	//Output:
+	// {
+	//   "components": [
+	//     {
+	//       "type": "temple_test.DebugPage",
+	//       "depth": 0
+	//     },
+	//     {
+	//       "type": "temple_test.DebugLayout",
+	//       "depth": 1
+	//     },
+	//     {
+	//       "type": "temple.DebugToolbar",
+	//       "depth": 2
+	//     }
+	//   ],
+	//   "linkedCSS": [
+	//     "/static/site.css"
+	//   ],
+	//   "templates": [
+	//     "debug_page.html.tmpl",
+	//     "debug_layout.html.tmpl",
+	//     "temple/debug_toolbar.html.tmpl"
+	//   ]
+	// }
+}
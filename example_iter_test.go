@@ -0,0 +1,78 @@
+package temple_test
+
+import (
+	"context"
+	"html/template"
+	"iter"
+	"os"
+
+	"impractical.co/temple"
+)
+
+type ListingPage struct {
+	Rows iter.Seq[string]
+}
+
+func (ListingPage) Templates(_ context.Context) []string {
+	return []string{"listing.html.tmpl"}
+}
+
+func (ListingPage) FuncMap(_ context.Context) template.FuncMap {
+	return temple.IterFuncs()
+}
+
+func (ListingPage) Key(_ context.Context) string {
+	return "listing.html.tmpl"
+}
+
+func (ListingPage) ExecutedTemplate(_ context.Context) string {
+	return "listing.html.tmpl"
+}
+
+func ExamplePaginateSeq() {
+	// rows would usually come from something like a database cursor
+	rows := func(yield func(string) bool) {
+		for _, row := range []string{"a", "b", "c", "d", "e"} {
+			if !yield(row) {
+				return
+			}
+		}
+	}
+
+	page := temple.PaginateSeq(rows, 2, 2)
+	for _, item := range page.Items {
+		os.Stdout.WriteString(item + "\n")
+	}
+	if page.HasNext {
+		os.Stdout.WriteString("more on the next page\n")
+	}
+
+	//Output:
+	// c
+	// d
+	// more on the next page
+}
+
+func ExampleIterFuncs() {
+	var templates = staticFS{
+		"listing.html.tmpl": `{{ range take 3 (skip 1 .Page.Rows) }}{{ . }}
+{{ end }}`,
+	}
+	rows := func(yield func(string) bool) {
+		for _, row := range []string{"a", "b", "c", "d", "e"} {
+			if !yield(row) {
+				return
+			}
+		}
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	temple.Render(context.Background(), os.Stdout, site, ListingPage{Rows: rows})
+
+	//Output:
+	// b
+	// c
+	// d
+}
@@ -0,0 +1,60 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"strconv"
+
+	"impractical.co/temple"
+)
+
+type CounterWidget struct {
+	Start int
+}
+
+func (CounterWidget) Templates(_ context.Context) []string {
+	return nil
+}
+
+func (c CounterWidget) EmbedJS(_ context.Context) template.JS {
+	return template.JS("let count = " + strconv.Itoa(c.Start) + ";") // #nosec G203
+}
+
+type CounterPage struct {
+	Counter CounterWidget
+}
+
+func (CounterPage) Templates(_ context.Context) []string {
+	return []string{"counter.html.tmpl"}
+}
+
+func (c CounterPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{c.Counter}
+}
+
+func (CounterPage) EmbedJS(_ context.Context) template.JS {
+	return `document.title = "Counter";`
+}
+
+func (CounterPage) Key(_ context.Context) string {
+	return "counter.html.tmpl"
+}
+
+func (CounterPage) ExecutedTemplate(_ context.Context) string {
+	return "counter.html.tmpl"
+}
+
+func ExampleEmbeddedJS() {
+	js, err := temple.EmbeddedJS(context.Background(), CounterPage{Counter: CounterWidget{Start: 3}})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(js)
+
+	//Output:
+	// /* embedded JavaScript from temple_test.CounterPage */
+	// document.title = "Counter";
+	// /* embedded JavaScript from temple_test.CounterWidget */
+	// let count = 3;
+}
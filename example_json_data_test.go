@@ -0,0 +1,44 @@
+package temple_test
+
+import (
+	"context"
+	"os"
+
+	"impractical.co/temple"
+)
+
+type ProfilePage struct {
+	Name string
+}
+
+func (ProfilePage) Templates(_ context.Context) []string {
+	return []string{"profile.html.tmpl"}
+}
+
+func (p ProfilePage) EmbedJSONData(_ context.Context) []temple.JSONData {
+	return []temple.JSONData{
+		{ID: "profile", Value: map[string]string{"name": p.Name}},
+	}
+}
+
+func (ProfilePage) Key(_ context.Context) string {
+	return "profile.html.tmpl"
+}
+
+func (ProfilePage) ExecutedTemplate(_ context.Context) string {
+	return "profile.html.tmpl"
+}
+
+func ExampleRender_jsonData() {
+	var templates = staticFS{
+		"profile.html.tmpl": `{{ .JSONDataTags }}`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	temple.Render(context.Background(), os.Stdout, site, ProfilePage{Name: "</script><b>"})
+
+	//Output:
+	// <script type="application/json" id="profile">{"name":"\u003c/script\u003e\u003cb\u003e"}</script>
+}
@@ -0,0 +1,126 @@
+package temple_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"impractical.co/temple"
+)
+
+var articleLayout = temple.Layout{
+	Path: "article_layout.html.tmpl",
+	Slots: []temple.Slot{
+		{Name: "body", Required: true},
+		{Name: "sidebar", Required: true},
+		{Name: "head-extra"},
+	},
+}
+
+type ArticlePage struct {
+	Template string
+}
+
+func (a ArticlePage) Templates(_ context.Context) []string {
+	return []string{a.Template}
+}
+
+func (ArticlePage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{articleLayout}
+}
+
+func (a ArticlePage) Key(_ context.Context) string {
+	return a.Template
+}
+
+func (ArticlePage) ExecutedTemplate(_ context.Context) string {
+	return articleLayout.BaseTemplate()
+}
+
+func ExampleLayout() {
+	var templates = staticFS{
+		"article_layout.html.tmpl": `<head>{{ block "head-extra" . }}{{ end }}</head>
+<main>{{ block "body" . }}{{ end }}</main>
+<aside>{{ block "sidebar" . }}{{ end }}</aside>`,
+		"article.html.tmpl": `{{ define "body" }}An article.{{ end }}
+{{ define "sidebar" }}Related links.{{ end }}`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	temple.Render(context.Background(), os.Stdout, site, ArticlePage{Template: "article.html.tmpl"})
+
+	//Output:
+	// <head></head>
+	// <main>An article.</main>
+	// <aside>Related links.</aside>
+}
+
+func ExampleMissingSlotsError() {
+	var templates = staticFS{
+		"article_layout.html.tmpl": `<main>{{ block "body" . }}{{ end }}</main>
+<aside>{{ block "sidebar" . }}{{ end }}</aside>`,
+		"draft.html.tmpl": `{{ define "title" }}A draft{{ end }}`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	result := temple.Render(context.Background(), io.Discard, site, ArticlePage{Template: "draft.html.tmpl"})
+
+	var slotsErr temple.MissingSlotsError
+	if errors.As(result.Err, &slotsErr) {
+		fmt.Println(slotsErr)
+	}
+
+	//Output:
+	// layout "article_layout.html.tmpl" is missing required slots: body, sidebar
+}
+
+var siteLayout = temple.Layout{
+	Path:  "site_layout.html.tmpl",
+	Slots: []temple.Slot{{Name: "body", Required: true}},
+}
+
+var marketingLayout = temple.Layout{
+	Path:   "marketing_layout.html.tmpl",
+	Slots:  []temple.Slot{{Name: "content", Required: true}},
+	Parent: &siteLayout,
+}
+
+type LandingPage struct{}
+
+func (LandingPage) Templates(_ context.Context) []string {
+	return []string{"landing.html.tmpl"}
+}
+
+func (LandingPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{marketingLayout}
+}
+
+func (LandingPage) Key(_ context.Context) string {
+	return "landing.html.tmpl"
+}
+
+func (LandingPage) ExecutedTemplate(_ context.Context) string {
+	return marketingLayout.BaseTemplate()
+}
+
+func ExampleLayout_nested() {
+	var templates = staticFS{
+		"site_layout.html.tmpl":      `<body>{{ block "body" . }}{{ end }}</body>`,
+		"marketing_layout.html.tmpl": `{{ define "body" }}<div class="hero">{{ block "content" . }}{{ end }}</div>{{ end }}`,
+		"landing.html.tmpl":          `{{ define "content" }}Buy now!{{ end }}`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	temple.Render(context.Background(), os.Stdout, site, LandingPage{})
+
+	//Output:
+	// <body><div class="hero">Buy now!</div></body>
+}
@@ -0,0 +1,47 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+
+	"impractical.co/temple"
+)
+
+type ManagedSite struct {
+	*temple.CachedSite
+	*temple.Lifecycle
+}
+
+func ExampleLifecycle() {
+	site := ManagedSite{
+		CachedSite: temple.NewCachedSite(staticFS{}),
+		Lifecycle:  &temple.Lifecycle{},
+	}
+
+	started := make(chan struct{})
+	site.Register(&temple.BackgroundService{
+		Run: func(ctx context.Context) error {
+			fmt.Println("watching templates")
+			close(started)
+			<-ctx.Done()
+			fmt.Println("stopped watching templates")
+			return ctx.Err()
+		},
+	})
+
+	ctx := context.Background()
+	err := site.Start(ctx)
+	if err != nil {
+		panic(err)
+	}
+	<-started
+
+	// usually called when the server receives SIGTERM
+	err = site.Shutdown(ctx)
+	fmt.Println(err)
+
+	//Output:
+	// watching templates
+	// stopped watching templates
+	// <nil>
+}
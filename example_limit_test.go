@@ -0,0 +1,76 @@
+package temple_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"impractical.co/temple"
+)
+
+// only one report can render at a time, and others wait up to 10ms
+var reportLimiter = temple.NewRenderLimiter(1, 10*time.Millisecond)
+
+type ReportPage struct {
+	Generate func() string
+}
+
+func (ReportPage) Templates(_ context.Context) []string {
+	return []string{"report.html.tmpl"}
+}
+
+func (r ReportPage) FuncMap(_ context.Context) template.FuncMap {
+	return template.FuncMap{"generate": r.Generate}
+}
+
+func (ReportPage) RenderLimiter(_ context.Context) *temple.RenderLimiter {
+	return reportLimiter
+}
+
+func (ReportPage) Key(_ context.Context) string {
+	return "report.html.tmpl"
+}
+
+func (ReportPage) ExecutedTemplate(_ context.Context) string {
+	return "report.html.tmpl"
+}
+
+func ExampleRenderLimiter() {
+	var templates = staticFS{
+		"report.html.tmpl": `{{ generate }}`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	ctx := context.Background()
+
+	// start a slow report rendering
+	generating := make(chan struct{})
+	finish := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		temple.Render(ctx, io.Discard, site, ReportPage{Generate: func() string {
+			close(generating)
+			<-finish
+			return "slow report"
+		}})
+	}()
+	<-generating
+
+	// while it's rendering, another report has to wait, and gives up
+	result := temple.Render(ctx, io.Discard, site, ReportPage{})
+	fmt.Println(errors.Is(result.Err, temple.ErrRenderQueueTimeout))
+	fmt.Println(result.QueueWait >= 10*time.Millisecond)
+
+	close(finish)
+	<-done
+
+	//Output:
+	// true
+	// true
+}
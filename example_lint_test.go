@@ -0,0 +1,58 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"testing/fstest"
+
+	"impractical.co/temple"
+)
+
+type SiteFooter struct{}
+
+func (SiteFooter) Templates(_ context.Context) []string {
+	return []string{"footer.html.tmpl"}
+}
+
+type FeedbackPage struct{}
+
+func (FeedbackPage) Templates(_ context.Context) []string {
+	return []string{"feedback.html.tmpl"}
+}
+
+func (FeedbackPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{SiteFooter{}}
+}
+
+func (FeedbackPage) Key(_ context.Context) string {
+	return "feedback.html.tmpl"
+}
+
+func (FeedbackPage) ExecutedTemplate(_ context.Context) string {
+	return "feedback.html.tmpl"
+}
+
+func ExampleLint() {
+	// Lint needs to list the templates, which staticFS can't do
+	var templates = fstest.MapFS{
+		"feedback.html.tmpl": {Data: []byte(`<main>{{ template "form" . }}</main>{{ template "footer.html.tmpl" }}`)},
+		"old.html.tmpl":      {Data: []byte(`<p>Nothing uses this any more.</p>`)},
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	issues, err := temple.Lint(context.Background(), site, FeedbackPage{})
+	if err != nil {
+		panic(err)
+	}
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+
+	//Output:
+	// feedback.html.tmpl: executes undefined template "footer.html.tmpl" (used by temple_test.FeedbackPage)
+	// feedback.html.tmpl: executes undefined template "form" (used by temple_test.FeedbackPage)
+	// footer.html.tmpl: doesn't match any template files (used by temple_test.SiteFooter)
+	// old.html.tmpl: isn't used
+}
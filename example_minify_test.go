@@ -0,0 +1,69 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"impractical.co/temple"
+)
+
+type PricingPage struct{}
+
+func (PricingPage) Templates(_ context.Context) []string {
+	return []string{"pricing.html.tmpl"}
+}
+
+func (PricingPage) Key(_ context.Context) string {
+	return "pricing.html.tmpl"
+}
+
+func (PricingPage) ExecutedTemplate(_ context.Context) string {
+	return "pricing.html.tmpl"
+}
+
+// html/template removes comments from templates, so comments can only be
+// rendered from template.HTML values
+func (PricingPage) License() template.HTML {
+	return "<!--! Copyright Example, Inc. --><!-- generated -->"
+}
+
+func ExampleMinifier() {
+	var templates = staticFS{
+		"pricing.html.tmpl": `<!DOCTYPE html>
+<html lang="en">
+	<head>
+		{{ .Page.License }}
+		<title>Pricing</title>
+		<style>
+			.price { font-weight: bold; }
+		</style>
+	</head>
+	<body>
+		<ul>
+			<li>Basic:   <span class="price">$5</span>   a month</li>
+			<li>Pro: <span class="price">$20</span> a month</li>
+		</ul>
+		<pre>
+  indented
+    code</pre>
+	</body>
+</html>
+`,
+	}
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+
+	var out strings.Builder
+	temple.Render(context.Background(), &out, site, PricingPage{}, temple.WithOutputFilters(temple.Minifier{}))
+	fmt.Println(out.String())
+
+	//Output:
+	// <!DOCTYPE html><html lang="en"><head><!--! Copyright Example, Inc. --><title>Pricing</title><style>
+	// 			.price { font-weight: bold; }
+	// 		</style></head><body><ul><li>Basic: <span class="price">$5</span> a month</li><li>Pro: <span class="price">$20</span> a month</li></ul><pre>
+	//   indented
+	//     code</pre></body></html>
+}
@@ -0,0 +1,70 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing/fstest"
+
+	"impractical.co/temple"
+)
+
+// vendoredButton is a Component from a library that ships its own templates,
+// which the Site mounts under vendor/ui
+type vendoredButton struct{}
+
+func (vendoredButton) Templates(_ context.Context) []string {
+	return []string{"vendor/ui/button.html.tmpl"}
+}
+
+type NewsletterPage struct{}
+
+func (NewsletterPage) Templates(_ context.Context) []string {
+	return []string{"newsletter.html.tmpl"}
+}
+
+func (NewsletterPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{vendoredButton{}}
+}
+
+func (NewsletterPage) Key(_ context.Context) string {
+	return "newsletter.html.tmpl"
+}
+
+func (NewsletterPage) ExecutedTemplate(_ context.Context) string {
+	return "newsletter.html.tmpl"
+}
+
+func ExampleWithMount() {
+	app := fstest.MapFS{
+		"newsletter.html.tmpl": {Data: []byte(`<form>{{ template "vendor/ui/button.html.tmpl" "Subscribe" }}</form>`)},
+	}
+	// the library's templates don't know where they'll be mounted
+	ui := fstest.MapFS{
+		"button.html.tmpl": {Data: []byte(`<button class="ui-button">{{ . }}</button>`)},
+	}
+	site := MySite{
+		CachedSite: temple.NewCachedSite(app, temple.WithMount("vendor/ui", ui)),
+	}
+
+	var out strings.Builder
+	temple.Render(context.Background(), &out, site, NewsletterPage{})
+	fmt.Println(out.String())
+
+	err := fs.WalkDir(site.TemplateDir(context.Background()), ".", func(path string, _ fs.DirEntry, err error) error {
+		fmt.Println(path)
+		return err
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	//Output:
+	// <form><button class="ui-button">Subscribe</button></form>
+	// .
+	// newsletter.html.tmpl
+	// vendor
+	// vendor/ui
+	// vendor/ui/button.html.tmpl
+}
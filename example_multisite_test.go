@@ -0,0 +1,54 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"impractical.co/temple"
+)
+
+type StorefrontPage struct{}
+
+func (StorefrontPage) Templates(_ context.Context) []string {
+	return []string{"storefront.html.tmpl"}
+}
+
+func (StorefrontPage) Key(_ context.Context) string {
+	return "storefront.html.tmpl"
+}
+
+func (StorefrontPage) ExecutedTemplate(_ context.Context) string {
+	return "storefront.html.tmpl"
+}
+
+func ExampleSiteMiddleware() {
+	// each customer gets their own Site, with their own templates and
+	// caches
+	sites := temple.HostSites[MySite]{
+		"shop.acme.example": {
+			Title:      "ACME",
+			CachedSite: temple.NewCachedSite(staticFS{"storefront.html.tmpl": `<h1>{{ .Site.Title }} Store</h1>`}),
+		},
+		"*.globex.example": {
+			Title:      "Globex",
+			CachedSite: temple.NewCachedSite(staticFS{"storefront.html.tmpl": `<h1>Welcome to {{ .Site.Title }}</h1>`}),
+		},
+	}
+	handler := temple.SiteMiddleware(sites)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		site, _ := temple.SiteFromContext[MySite](r.Context())
+		temple.Render(r.Context(), w, site, StorefrontPage{})
+	}))
+
+	for _, host := range []string{"shop.acme.example", "eu.globex.example:8080", "initech.example"} {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "http://"+host+"/", nil))
+		fmt.Println(resp.Code, resp.Body.String())
+	}
+
+	//Output:
+	// 200 <h1>ACME Store</h1>
+	// 200 <h1>Welcome to Globex</h1>
+	// 404 Not found.
+}
@@ -0,0 +1,90 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing/fstest"
+
+	"impractical.co/temple"
+)
+
+// alertPackage is a third-party library of Components, bringing its own
+// templates and stylesheet
+type alertPackage struct{}
+
+func (alertPackage) PackageName() string {
+	return "alerts"
+}
+
+func (alertPackage) PackageTemplates() fs.FS {
+	return fstest.MapFS{
+		"alert.html.tmpl": {Data: []byte(`<div class="alert">{{ . }}</div>`)},
+	}
+}
+
+func (alertPackage) PackageAssets() fs.FS {
+	return fstest.MapFS{
+		"alerts.css": {Data: []byte(`.alert { border: 1px solid red; }`)},
+	}
+}
+
+func (alertPackage) PackageComponents() []temple.Component {
+	return []temple.Component{Alert{}}
+}
+
+type Alert struct{}
+
+func (Alert) Templates(_ context.Context) []string {
+	return []string{"alerts/alert.html.tmpl"}
+}
+
+func (Alert) LinkCSS(_ context.Context) []string {
+	return []string{temple.PackageAssetURL("alerts", "alerts.css")}
+}
+
+type MaintenancePage struct{}
+
+func (MaintenancePage) Templates(_ context.Context) []string {
+	return []string{"maintenance.html.tmpl"}
+}
+
+func (MaintenancePage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{Alert{}}
+}
+
+func (MaintenancePage) Key(_ context.Context) string {
+	return "maintenance.html.tmpl"
+}
+
+func (MaintenancePage) ExecutedTemplate(_ context.Context) string {
+	return "maintenance.html.tmpl"
+}
+
+func ExampleWithPackages() {
+	templates := fstest.MapFS{
+		"maintenance.html.tmpl": {Data: []byte(`{{ range .LinkedCSS }}<link rel="stylesheet" href="{{ . }}">{{ end }}
+{{ template "alerts/alert.html.tmpl" "Down for maintenance." }}`)},
+	}
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates, temple.WithPackages(alertPackage{})),
+	}
+
+	var out strings.Builder
+	temple.Render(context.Background(), &out, site, MaintenancePage{})
+	fmt.Println(out.String())
+
+	mux := http.NewServeMux()
+	mux.Handle(temple.PackageAssetsPath, temple.PackageAssetsHandler(site))
+	resp := httptest.NewRecorder()
+	mux.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/_temple/packages/alerts/alerts.css", nil))
+	fmt.Println(resp.Code, resp.Body.String())
+
+	//Output:
+	// <link rel="stylesheet" href="/_temple/packages/alerts/alerts.css">
+	// <div class="alert">Down for maintenance.</div>
+	// 200 .alert { border: 1px solid red; }
+}
@@ -0,0 +1,102 @@
+package temple_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"sync"
+	"sync/atomic"
+
+	"impractical.co/temple"
+)
+
+type CountingSite struct {
+	*temple.CachedSite
+	parses *atomic.Int32
+}
+
+func (c CountingSite) SetCachedTemplate(ctx context.Context, key string, tmpl *template.Template) {
+	c.parses.Add(1)
+	c.CachedSite.SetCachedTemplate(ctx, key, tmpl)
+}
+
+func ExampleTemplateCacher_concurrent() {
+	var templates = staticFS{
+		"base.html.tmpl": `<body>{{ block "body" . }}{{ end }}</body>`,
+		"home.html.tmpl": `{{ define "body" }}Home{{ end }}`,
+	}
+
+	site := CountingSite{
+		CachedSite: temple.NewCachedSite(templates),
+		parses:     &atomic.Int32{},
+	}
+
+	// a burst of requests for a page whose templates aren't cached yet
+	// only parses them once
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			temple.Render(context.Background(), io.Discard, site, HomePage{})
+		}()
+	}
+	close(start)
+	wg.Wait()
+	fmt.Println(site.parses.Load())
+
+	//Output:
+	// 1
+}
+
+type CountingFS struct {
+	fs.FS
+	opens *atomic.Int32
+}
+
+func (c CountingFS) Open(name string) (fs.File, error) {
+	c.opens.Add(1)
+	return c.FS.Open(name)
+}
+
+type BrokenPage struct{}
+
+func (BrokenPage) Templates(_ context.Context) []string {
+	return []string{"typo.html.tmpl"}
+}
+
+func (BrokenPage) Key(_ context.Context) string {
+	return "broken.html.tmpl"
+}
+
+func (BrokenPage) ExecutedTemplate(_ context.Context) string {
+	return "broken.html.tmpl"
+}
+
+func ExampleTemplateCacher_missingTemplates() {
+	templates := CountingFS{
+		FS:    staticFS{"broken.html.tmpl": `Hello`},
+		opens: &atomic.Int32{},
+	}
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+
+	// a page with a bad template path only looks for it once every few
+	// seconds, no matter how often it's requested
+	for range 10 {
+		result := temple.Render(context.Background(), io.Discard, site, BrokenPage{})
+		if !errors.Is(result.Err, temple.ErrTemplatePatternMatchesNoFiles) {
+			panic(result.Err)
+		}
+	}
+	fmt.Println(templates.opens.Load())
+
+	//Output:
+	// 1
+}
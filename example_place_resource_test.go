@@ -0,0 +1,64 @@
+package temple_test
+
+import (
+	"context"
+	"os"
+
+	"impractical.co/temple"
+)
+
+type AdSlot struct{}
+
+func (AdSlot) Templates(_ context.Context) []string {
+	return []string{"ad_slot.html.tmpl"}
+}
+
+func (AdSlot) LinkJS(_ context.Context) []string {
+	return []string{"/js/ads.js"}
+}
+
+type ArticleWithAdsPage struct{}
+
+func (ArticleWithAdsPage) Templates(_ context.Context) []string {
+	return []string{"article_with_ads.html.tmpl"}
+}
+
+func (ArticleWithAdsPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{AdSlot{}}
+}
+
+func (ArticleWithAdsPage) LinkJS(_ context.Context) []string {
+	return []string{"/js/site.js"}
+}
+
+func (ArticleWithAdsPage) Key(_ context.Context) string {
+	return "article_with_ads.html.tmpl"
+}
+
+func (ArticleWithAdsPage) ExecutedTemplate(_ context.Context) string {
+	return "article_with_ads.html.tmpl"
+}
+
+func ExampleRenderData_LinkedJS() {
+	var templates = staticFS{
+		"article_with_ads.html.tmpl": `<head>{{ range .LinkedJS }}<script src="{{ . }}"></script>{{ end }}</head>
+<p>Some of the article.</p>
+{{ template "ad_slot.html.tmpl" }}
+<p>More of the article.</p>
+{{ template "ad_slot.html.tmpl" }}`,
+		// the ad script needs to be loaded where the first ad goes
+		"ad_slot.html.tmpl": `<div class="ad">{{ placeResource "/js/ads.js" }}</div>`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	temple.Render(context.Background(), os.Stdout, site, ArticleWithAdsPage{})
+
+	//Output:
+	// <head><script src="/js/site.js"></script></head>
+	// <p>Some of the article.</p>
+	// <div class="ad"><script src="/js/ads.js"></script></div>
+	// <p>More of the article.</p>
+	// <div class="ad"></div>
+}
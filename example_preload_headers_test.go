@@ -0,0 +1,53 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+
+	"impractical.co/temple"
+)
+
+type ProductPage struct{}
+
+func (ProductPage) Templates(_ context.Context) []string {
+	return []string{"product.html.tmpl"}
+}
+
+func (ProductPage) Key(_ context.Context) string {
+	return "product.html.tmpl"
+}
+
+func (ProductPage) ExecutedTemplate(_ context.Context) string {
+	return "product.html.tmpl"
+}
+
+func (ProductPage) LinkCSS(_ context.Context) []string {
+	return []string{"/css/site.css", "/css/product.css"}
+}
+
+func (ProductPage) LinkJS(_ context.Context) []string {
+	return []string{"/js/gallery.js"}
+}
+
+func ExampleWithPreloadHeaders() {
+	var templates = staticFS{
+		"product.html.tmpl": `A product.`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	resp := httptest.NewRecorder()
+	// only preload the two most important resources
+	temple.Render(context.Background(), resp, site, ProductPage{}, temple.WithPreloadHeaders(2))
+	fmt.Println(resp.Code)
+	for _, link := range resp.Header().Values("Link") {
+		fmt.Println(link)
+	}
+
+	//Output:
+	// 200
+	// </css/site.css>; rel=preload; as=style
+	// </css/product.css>; rel=preload; as=style
+}
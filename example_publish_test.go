@@ -0,0 +1,77 @@
+package temple_test
+
+import (
+	"context"
+	"os"
+
+	"impractical.co/temple"
+)
+
+type StoreMap struct {
+	Address string
+}
+
+func (StoreMap) Templates(_ context.Context) []string {
+	return []string{"store_map.html.tmpl"}
+}
+
+// tell the layout a map is on the page while the Components are being
+// resolved
+func (m StoreMap) UseComponents(ctx context.Context) []temple.Component {
+	temple.Publish(ctx, "maps", m.Address)
+	return nil
+}
+
+type MapsLayout struct{}
+
+func (MapsLayout) Templates(_ context.Context) []string {
+	return []string{"maps_layout.html.tmpl"}
+}
+
+// only link to the maps script if a Component published a map
+func (MapsLayout) LinkJS(ctx context.Context) []string {
+	if len(temple.Published(ctx, "maps")) > 0 {
+		return []string{"/js/maps.js"}
+	}
+	return nil
+}
+
+type StoresPage struct{}
+
+func (StoresPage) Templates(_ context.Context) []string {
+	return []string{"stores.html.tmpl"}
+}
+
+func (StoresPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{
+		MapsLayout{},
+		StoreMap{Address: "1 Main St"},
+		StoreMap{Address: "2 High St"},
+	}
+}
+
+func (StoresPage) Key(_ context.Context) string {
+	return "stores.html.tmpl"
+}
+
+func (StoresPage) ExecutedTemplate(_ context.Context) string {
+	return "maps_layout.html.tmpl"
+}
+
+func ExamplePublish() {
+	var templates = staticFS{
+		"maps_layout.html.tmpl": `{{ range .LinkedJS }}<script src="{{ . }}"></script>
+{{ end }}{{ block "body" . }}{{ end }}`,
+		"stores.html.tmpl":    `{{ define "body" }}{{ len (published "maps") }} stores{{ end }}`,
+		"store_map.html.tmpl": ``,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	temple.Render(context.Background(), os.Stdout, site, StoresPage{})
+
+	//Output:
+	// <script src="/js/maps.js"></script>
+	// 2 stores
+}
@@ -0,0 +1,97 @@
+package temple_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"impractical.co/temple"
+)
+
+// CMSContent is a page's content, as stored in a CMS's database, including
+// which kind of page it should be rendered as
+type CMSContent struct {
+	Kind  string
+	Title string
+	Body  string
+}
+
+type CMSArticle struct {
+	Content CMSContent
+}
+
+func (CMSArticle) Templates(_ context.Context) []string {
+	return []string{"cms_article.html.tmpl"}
+}
+
+func (CMSArticle) Key(_ context.Context) string {
+	return "cms_article.html.tmpl"
+}
+
+func (CMSArticle) ExecutedTemplate(_ context.Context) string {
+	return "cms_article.html.tmpl"
+}
+
+type CMSLanding struct {
+	Content CMSContent
+}
+
+func (CMSLanding) Templates(_ context.Context) []string {
+	return []string{"cms_landing.html.tmpl"}
+}
+
+func (CMSLanding) Key(_ context.Context) string {
+	return "cms_landing.html.tmpl"
+}
+
+func (CMSLanding) ExecutedTemplate(_ context.Context) string {
+	return "cms_landing.html.tmpl"
+}
+
+func ExampleRegistry() {
+	var templates = staticFS{
+		"cms_article.html.tmpl": `<article><h1>{{ .Page.Content.Title }}</h1>{{ .Page.Content.Body }}</article>`,
+		"cms_landing.html.tmpl": `<section class="hero">{{ .Page.Content.Title }}</section>`,
+	}
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+
+	var pages temple.Registry[temple.Renderable]
+	err := pages.RegisterFunc("article", func(_ context.Context, data any) (temple.Renderable, error) {
+		return CMSArticle{Content: data.(CMSContent)}, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	err = pages.RegisterFunc("landing", func(_ context.Context, data any) (temple.Renderable, error) {
+		return CMSLanding{Content: data.(CMSContent)}, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(pages.Names())
+
+	// the kind of page to render comes from the content itself
+	for _, content := range []CMSContent{
+		{Kind: "landing", Title: "Spring Sale"},
+		{Kind: "article", Title: "Our Story", Body: "It started in a garage."},
+		{Kind: "gallery", Title: "Photos"},
+	} {
+		page, err := pages.Resolve(context.Background(), content.Kind, content)
+		if errors.Is(err, temple.ErrNotRegistered) {
+			fmt.Println(err)
+			continue
+		}
+		var out strings.Builder
+		temple.Render(context.Background(), &out, site, page)
+		fmt.Println(out.String())
+	}
+
+	//Output:
+	// [article landing]
+	// <section class="hero">Spring Sale</section>
+	// <article><h1>Our Story</h1>It started in a garage.</article>
+	// error resolving "gallery": nothing registered under that name
+}
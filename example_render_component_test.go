@@ -0,0 +1,53 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"os"
+
+	"impractical.co/temple"
+)
+
+type CommentComponent struct{}
+
+func (CommentComponent) Templates(_ context.Context) []string {
+	return []string{"comment.html.tmpl"}
+}
+
+func (CommentComponent) EmbedCSS(_ context.Context) template.CSS {
+	return ".comment { margin: 1em; }"
+}
+
+type Comment struct {
+	Author string
+	Body   string
+}
+
+func ExampleRenderComponent() {
+	var templates = staticFS{
+		"comment.html.tmpl": `{{ define "comment" -}}
+<style>{{ .EmbeddedCSS }}</style>
+<div class="comment"><b>{{ .Data.Author }}</b>: {{ .Data.Body }}</div>
+{{- end }}`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	// render just the comment, for an HTMX request that's adding it to
+	// the page
+	err := temple.RenderComponent(context.Background(), os.Stdout, site, CommentComponent{}, "comment", Comment{
+		Author: "Paddy",
+		Body:   "Nice post!",
+	})
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	//Output:
+	// <style>
+	// /* embedded CSS from temple_test.CommentComponent */
+	// .comment { margin: 1em; }</style>
+	// <div class="comment"><b>Paddy</b>: Nice post!</div>
+}
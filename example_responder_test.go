@@ -0,0 +1,70 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"impractical.co/temple"
+)
+
+type OrderPage struct {
+	ID      string
+	Moved   map[string]string
+	Missing bool
+}
+
+func (OrderPage) Templates(_ context.Context) []string {
+	return []string{"order.html.tmpl"}
+}
+
+func (OrderPage) Key(_ context.Context) string {
+	return "order.html.tmpl"
+}
+
+func (OrderPage) ExecutedTemplate(_ context.Context) string {
+	return "order.html.tmpl"
+}
+
+// the page knows whether the order exists or has moved once its data is
+// loaded, so it decides how to respond instead of the handler
+func (o OrderPage) Respond(_ context.Context) (temple.Response, error) {
+	if newID, ok := o.Moved[o.ID]; ok {
+		return temple.Response{Status: http.StatusMovedPermanently, Redirect: "/orders/" + newID}, nil
+	}
+	response := temple.Response{
+		Cookies: []*http.Cookie{{Name: "last_order", Value: o.ID, Path: "/", HttpOnly: true}},
+	}
+	if o.Missing {
+		response.Status = http.StatusNotFound
+	}
+	return response, nil
+}
+
+func ExampleResponder() {
+	var templates = staticFS{
+		"order.html.tmpl": `{{ if .Page.Missing }}No such order.{{ else }}Order {{ .Page.ID }}{{ end }}`,
+	}
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	moved := map[string]string{"1001": "2001"}
+
+	resp := httptest.NewRecorder()
+	temple.Render(context.Background(), resp, site, OrderPage{ID: "2001", Moved: moved})
+	fmt.Println(resp.Code, resp.Header().Get("Set-Cookie"), resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	result := temple.Render(context.Background(), resp, site, OrderPage{ID: "1001", Moved: moved})
+	fmt.Println(resp.Code, resp.Header().Get("Location"), result.Redirect, resp.Body.Len())
+
+	resp = httptest.NewRecorder()
+	temple.Render(context.Background(), resp, site, OrderPage{ID: "404", Missing: true})
+	fmt.Println(resp.Code, resp.Body.String())
+
+	//Output:
+	// 200 last_order=2001; Path=/; HttpOnly Order 2001
+	// 301 /orders/2001 /orders/2001 0
+	// 404 No such order.
+}
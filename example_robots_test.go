@@ -0,0 +1,50 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+
+	"impractical.co/temple"
+)
+
+type SearchResultsPage struct{}
+
+func (SearchResultsPage) Templates(_ context.Context) []string {
+	return []string{"search.html.tmpl"}
+}
+
+func (SearchResultsPage) Key(_ context.Context) string {
+	return "search.html.tmpl"
+}
+
+func (SearchResultsPage) ExecutedTemplate(_ context.Context) string {
+	return "search.html.tmpl"
+}
+
+// search results change too often to be worth indexing
+func (SearchResultsPage) Indexable(_ context.Context) bool {
+	return false
+}
+
+func ExampleIndexable() {
+	var templates = staticFS{
+		"search.html.tmpl": `<head>{{ .RobotsMetaTag }}</head>`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	resp := httptest.NewRecorder()
+	temple.Render(context.Background(), resp, site, SearchResultsPage{})
+	fmt.Println(resp.Header().Get("X-Robots-Tag"))
+	fmt.Println(resp.Body.String())
+
+	// sitemaps should leave out pages that aren't indexable
+	fmt.Println(temple.IsIndexable(context.Background(), SearchResultsPage{}))
+
+	//Output:
+	// noindex
+	// <head><meta name="robots" content="noindex"></head>
+	// false
+}
@@ -0,0 +1,18 @@
+package temple_test
+
+import (
+	"fmt"
+
+	"impractical.co/temple"
+)
+
+func ExampleContentSecurityPolicy() {
+	csp := temple.ContentSecurityPolicy{}
+	csp.Add("default-src", "'self'")
+	csp.Add("script-src", "'self'", "https://cdn.example.com")
+	csp.Add("upgrade-insecure-requests")
+	fmt.Println(csp)
+
+	//Output:
+	// default-src 'self'; script-src 'self' https://cdn.example.com; upgrade-insecure-requests
+}
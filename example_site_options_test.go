@@ -0,0 +1,90 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+
+	"impractical.co/temple"
+)
+
+func ExampleNewCachedSite() {
+	var templates = staticFS{
+		"card.html.tmpl":    `<p><b>{{ .Author }}</b>: {{ .Body }}</p>`,
+		"comment.html.tmpl": `{{ template "card.html.tmpl" dict "Author" (.Page.Author | default "Anonymous" | shout) "Body" (markdownInline .Page.Comment) }}`,
+	}
+
+	// the options take the place of implementing DefaultFuncsIncluder and
+	// FuncMapExtender on the Site
+	site := temple.NewCachedSite(templates,
+		temple.WithDefaultFuncs(),
+		temple.WithFuncs(template.FuncMap{
+			"shout": strings.ToUpper,
+		}),
+	)
+	temple.Render(context.Background(), os.Stdout, site, CommentPage{
+		Comment: "This is **great**",
+	})
+
+	//Output:
+	// <p><b>ANONYMOUS</b>: This is <strong>great</strong></p>
+}
+
+func ExampleWithMaxCachedTemplates() {
+	var templates = staticFS{
+		"home.html.tmpl":    `{{ define "body" }}Home{{ end }}`,
+		"base.html.tmpl":    `{{ block "body" . }}{{ end }}`,
+		"card.html.tmpl":    `<p>{{ .Author }}: {{ .Body }}</p>`,
+		"comment.html.tmpl": `{{ template "card.html.tmpl" dict "Author" .Page.Author "Body" .Page.Comment }}`,
+	}
+
+	ctx := context.Background()
+	site := temple.NewCachedSite(templates,
+		temple.WithDefaultFuncs(),
+		temple.WithMaxCachedTemplates(1),
+	)
+	temple.Render(ctx, io.Discard, site, HomePage{})
+	fmt.Println(site.GetCachedTemplate(ctx, "home.html.tmpl") != nil)
+
+	// caching the comment page's templates evicts the home page's
+	temple.Render(ctx, io.Discard, site, CommentPage{Author: "Jo", Comment: "Hi"})
+	fmt.Println(site.GetCachedTemplate(ctx, "home.html.tmpl") != nil)
+	fmt.Println(site.GetCachedTemplate(ctx, "comment.html.tmpl") != nil)
+
+	//Output:
+	// true
+	// false
+	// true
+}
+
+type GreetingPage struct {
+	Name string
+}
+
+func (GreetingPage) Templates(_ context.Context) []string {
+	return []string{"greeting.html.tmpl"}
+}
+
+func (GreetingPage) Key(_ context.Context) string {
+	return "greeting.html.tmpl"
+}
+
+func (GreetingPage) ExecutedTemplate(_ context.Context) string {
+	return "greeting.html.tmpl"
+}
+
+func ExampleWithDelims() {
+	// the {{ }} delimiters are left for the front-end framework to use
+	var templates = staticFS{
+		"greeting.html.tmpl": `<div id="app">Hello, [[ .Page.Name ]]. You have {{ unread }} messages.</div>`,
+	}
+
+	site := temple.NewCachedSite(templates, temple.WithDelims("[[", "]]"))
+	temple.Render(context.Background(), os.Stdout, site, GreetingPage{Name: "Sam"})
+
+	//Output:
+	// <div id="app">Hello, Sam. You have {{ unread }} messages.</div>
+}
@@ -0,0 +1,88 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"impractical.co/temple"
+)
+
+type AboutPage struct {
+	Layout BaseLayout
+}
+
+func (AboutPage) Templates(_ context.Context) []string {
+	return []string{"about.html.tmpl"}
+}
+
+func (a AboutPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{a.Layout}
+}
+
+func (AboutPage) Key(_ context.Context) string {
+	return "about.html.tmpl"
+}
+
+func (a AboutPage) ExecutedTemplate(_ context.Context) string {
+	return a.Layout.BaseTemplate()
+}
+
+func ExampleIndexTemplates() {
+	var templates = staticFS{
+		"base.html.tmpl":  `<body>{{ block "body" . }}{{ end }}</body>`,
+		"home.html.tmpl":  `{{ define "body" }}Home{{ end }}`,
+		"about.html.tmpl": `{{ define "body" }}About{{ end }}`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	index, err := temple.IndexTemplates(context.Background(), site, HomePage{}, AboutPage{})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(index.Templates())
+
+	// what breaks if I edit base.html.tmpl?
+	for _, user := range index.Users("base.html.tmpl") {
+		fmt.Printf("%s, through %T\n", user.Key, user.Component)
+	}
+	fmt.Println(index.Keys("about.html.tmpl"))
+
+	//Output:
+	// [about.html.tmpl base.html.tmpl home.html.tmpl]
+	// home.html.tmpl, through temple_test.BaseLayout
+	// about.html.tmpl, through temple_test.BaseLayout
+	// [about.html.tmpl]
+}
+
+func ExampleInvalidateTemplates() {
+	var templates = staticFS{
+		"base.html.tmpl":  `<body>{{ block "body" . }}{{ end }}</body>`,
+		"home.html.tmpl":  `{{ define "body" }}Home{{ end }}`,
+		"about.html.tmpl": `{{ define "body" }}About{{ end }}`,
+	}
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	ctx := context.Background()
+	index, err := temple.IndexTemplates(ctx, site, HomePage{}, AboutPage{})
+	if err != nil {
+		panic(err)
+	}
+	temple.Render(ctx, io.Discard, site, HomePage{})
+	temple.Render(ctx, io.Discard, site, AboutPage{})
+
+	// about.html.tmpl was edited, so only the about page needs parsing
+	// again
+	fmt.Println(temple.InvalidateTemplates(ctx, site, index, "about.html.tmpl"))
+	fmt.Println(site.GetCachedTemplate(ctx, "home.html.tmpl") != nil)
+	fmt.Println(site.GetCachedTemplate(ctx, "about.html.tmpl") != nil)
+
+	//Output:
+	// [about.html.tmpl]
+	// true
+	// false
+}
@@ -0,0 +1,23 @@
+package temple_test
+
+import (
+	"fmt"
+
+	"impractical.co/temple"
+)
+
+func ExampleTruncate() {
+	fmt.Println(temple.Truncate("Café society 👩🏽‍💻 meets", 16))
+	fmt.Println(temple.Truncate("Thumbs up 👍🏾👍🏾👍🏾", 12))
+
+	//Output:
+	// Café society 👩🏽‍💻…
+	// Thumbs up 👍🏾…
+}
+
+func ExampleHighlight() {
+	fmt.Println(temple.Highlight("Fish & Chips, fishing <tips>", "fish", "amp", "tips"))
+
+	//Output:
+	// <mark>Fish</mark> &amp; Chips, <mark>fish</mark>ing &lt;<mark>tips</mark>&gt;
+}
@@ -0,0 +1,84 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"impractical.co/temple"
+)
+
+type themeCtxKey struct{}
+
+type ThemedSite struct {
+	*temple.CachedSite
+}
+
+var siteThemes = map[string]temple.Theme{
+	"light": {Name: "light", Vars: map[string]string{"accent": "#0055ff"}},
+	"dark":  {Name: "dark", Vars: map[string]string{"accent": "#ffaa00"}},
+}
+
+// usually the Theme would come from a cookie or the user's settings
+func (ThemedSite) Theme(ctx context.Context) temple.Theme {
+	name, _ := ctx.Value(themeCtxKey{}).(string)
+	if theme, ok := siteThemes[name]; ok {
+		return theme
+	}
+	return siteThemes["light"]
+}
+
+func (ThemedSite) Themes(_ context.Context) []temple.Theme {
+	return []temple.Theme{siteThemes["light"], siteThemes["dark"]}
+}
+
+type ThemedCard struct{}
+
+func (ThemedCard) Templates(_ context.Context) []string {
+	return []string{"themed_card.html.tmpl"}
+}
+
+func (ThemedCard) LinkCSS(ctx context.Context) []string {
+	return []string{"/css/card-" + temple.CurrentTheme(ctx).Name + ".css"}
+}
+
+type ThemedPage struct{}
+
+func (ThemedPage) Templates(_ context.Context) []string {
+	return []string{"themed.html.tmpl"}
+}
+
+func (ThemedPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{ThemedCard{}}
+}
+
+func (ThemedPage) Key(_ context.Context) string {
+	return "themed.html.tmpl"
+}
+
+func (ThemedPage) ExecutedTemplate(_ context.Context) string {
+	return "themed.html.tmpl"
+}
+
+func ExampleThemer() {
+	var templates = staticFS{
+		"themed.html.tmpl": `<html class="{{ .Theme.Name }}" style="--accent: {{ .Theme.Var "accent" }}">
+{{- range .LinkedCSS }}<link rel="stylesheet" href="{{ . }}">{{ end -}}
+{{ template "themed_card.html.tmpl" }}</html>
+`,
+		"themed_card.html.tmpl": `<div class="card"></div>`,
+	}
+
+	site := ThemedSite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	ctx := context.Background()
+	var out strings.Builder
+	temple.Render(ctx, &out, site, ThemedPage{})
+	temple.Render(context.WithValue(ctx, themeCtxKey{}, "dark"), &out, site, ThemedPage{})
+	fmt.Print(out.String())
+
+	//Output:
+	// <html class="light" style="--accent: #0055ff"><link rel="stylesheet" href="/css/card-light.css"><div class="card"></div></html>
+	// <html class="dark" style="--accent: #ffaa00"><link rel="stylesheet" href="/css/card-dark.css"><div class="card"></div></html>
+}
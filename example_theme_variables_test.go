@@ -0,0 +1,73 @@
+package temple_test
+
+import (
+	"context"
+	"os"
+
+	"impractical.co/temple"
+)
+
+type DesignTokens struct {
+	AccentColor string
+	FontStack   string `css:"font"`
+	Spacing     int
+	Colors      struct {
+		Text       string
+		Background string
+	}
+	Internal string `css:"-"`
+}
+
+type TokensPage struct {
+	Tokens DesignTokens
+}
+
+func (TokensPage) Templates(_ context.Context) []string {
+	return []string{"tokens.html.tmpl"}
+}
+
+func (p TokensPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{temple.ThemeVariables{Vars: p.Tokens}}
+}
+
+func (TokensPage) Key(_ context.Context) string {
+	return "tokens.html.tmpl"
+}
+
+func (TokensPage) ExecutedTemplate(_ context.Context) string {
+	return "tokens.html.tmpl"
+}
+
+func ExampleThemeVariables() {
+	var templates = staticFS{
+		"tokens.html.tmpl": `<style>{{ .EmbeddedCSS }}</style>`,
+	}
+
+	tokens := DesignTokens{
+		AccentColor: "#ff6600",
+		// values can't end the declaration or the <style> element, but
+		// can quote names
+		FontStack: `"Inter Var", 'Inter', sans-serif; } </style>`,
+		Spacing:   4,
+		Internal:  "not rendered",
+	}
+	tokens.Colors.Text = "#222"
+	tokens.Colors.Background = "white"
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	temple.Render(context.Background(), os.Stdout, site, TokensPage{Tokens: tokens})
+
+	//Output:
+	// <style>
+	// /* embedded CSS from temple.ThemeVariables */
+	// :root {
+	// 	--accent-color: #ff6600;
+	// 	--font: "Inter Var", 'Inter', sans-serif\3b  \7d  \3c /style>;
+	// 	--spacing: 4;
+	// 	--colors-text: #222;
+	// 	--colors-background: white;
+	// }
+	// </style>
+}
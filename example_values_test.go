@@ -0,0 +1,40 @@
+package temple_test
+
+import (
+	"context"
+	"os"
+
+	"impractical.co/temple"
+)
+
+type BannerPage struct{}
+
+func (BannerPage) Templates(_ context.Context) []string {
+	return []string{"banner.html.tmpl"}
+}
+
+func (BannerPage) Key(_ context.Context) string {
+	return "banner.html.tmpl"
+}
+
+func (BannerPage) ExecutedTemplate(_ context.Context) string {
+	return "banner.html.tmpl"
+}
+
+func ExampleWithValue() {
+	var templates = staticFS{
+		"banner.html.tmpl": `<p>Signed in as {{ ctxstring "username" }}; {{ ctxval "unread" }} unread messages.</p>`,
+	}
+
+	// usually middleware would do this
+	ctx := temple.WithValue(context.Background(), "username", "paddy")
+	ctx = temple.WithValue(ctx, "unread", 3)
+
+	site := MySite{
+		CachedSite: temple.NewCachedSite(templates),
+	}
+	temple.Render(ctx, os.Stdout, site, BannerPage{})
+
+	//Output:
+	// <p>Signed in as paddy; 3 unread messages.</p>
+}
@@ -0,0 +1,247 @@
+package temple
+
+import (
+	"runtime/debug"
+	"slices"
+)
+
+// Feature is a capability of temple that libraries building on it, like
+// packages of Components or adapters for other frameworks, can check for
+// before relying on it, so they can work with older versions of temple that
+// don't have it. Optional interfaces that a version of temple doesn't know
+// about are silently ignored, so checking for the Feature is the only way to
+// be sure an interface will be used.
+type Feature string
+
+const (
+	// FeatureCriticalCSS means CSSResourceLinker is supported, and
+	// critical stylesheets are inlined as .CriticalCSS.
+	FeatureCriticalCSS Feature = "critical-css"
+
+	// FeatureJSImportMaps means JSImportMapper is supported, and import
+	// maps are merged into .ImportMap.
+	FeatureJSImportMaps Feature = "js-import-maps"
+
+	// FeatureJSLoadStrategies means JSResourceLinker is supported, along
+	// with the JSLoadIdle strategy.
+	FeatureJSLoadStrategies Feature = "js-load-strategies"
+
+	// FeatureJSONData means JSONDataEmbedder is supported.
+	FeatureJSONData Feature = "json-data"
+
+	// FeatureFetchPriority means FetchPriorityPolicier is supported.
+	FeatureFetchPriority Feature = "fetch-priority"
+
+	// FeaturePlaceResource means the placeResource template function is
+	// available.
+	FeaturePlaceResource Feature = "place-resource"
+
+	// FeatureComponentFunc means the component template function and
+	// EntryTemplater are supported.
+	FeatureComponentFunc Feature = "component-func"
+
+	// FeatureRegistry means Registry is available.
+	FeatureRegistry Feature = "registry"
+
+	// FeatureRenderComponent means RenderComponent is available.
+	FeatureRenderComponent Feature = "render-component"
+
+	// FeatureSlots means SlotDeclarer is supported.
+	FeatureSlots Feature = "slots"
+
+	// FeaturePublish means Publish and Published, and the published
+	// template function, are available.
+	FeaturePublish Feature = "publish"
+
+	// FeatureRequirements means Requirer and CapabilityProvider are
+	// supported.
+	FeatureRequirements Feature = "requirements"
+
+	// FeatureDelims means DelimsProvider is supported.
+	FeatureDelims Feature = "delims"
+
+	// FeatureComponentPackages means ComponentPackage and Packager are
+	// supported, and WithPackages and PackageAssetsHandler are
+	// available.
+	FeatureComponentPackages Feature = "component-packages"
+
+	// FeatureContextFuncs means ContextFuncMapExtender is supported.
+	FeatureContextFuncs Feature = "context-funcs"
+
+	// FeatureGraphCache means GraphCacher is supported.
+	FeatureGraphCache Feature = "graph-cache"
+
+	// FeatureBuildSteps means Builder and BuildStep are supported, and
+	// Build, BuildService, and AssetManifest are available.
+	FeatureBuildSteps Feature = "build-steps"
+
+	// FeatureCachePolicy means CachePolicy and SurrogateKeyer are
+	// supported.
+	FeatureCachePolicy Feature = "cache-policy"
+
+	// FeatureEarlyHints means the WithEarlyHints and WithPreloadHeaders
+	// RenderOptions are available.
+	FeatureEarlyHints Feature = "early-hints"
+
+	// FeatureStreaming means the WithStreaming RenderOption is available.
+	FeatureStreaming Feature = "streaming"
+
+	// FeatureCSRF means CSRFProvider is supported, and the csrfToken
+	// template function is available.
+	FeatureCSRF Feature = "csrf"
+
+	// FeatureResponder means Responder is supported.
+	FeatureResponder Feature = "responder"
+
+	// FeatureSecurityPolicy means SecurityPolicier is supported.
+	FeatureSecurityPolicy Feature = "security-policy"
+
+	// FeatureEmail means RenderEmail and PlainTextTemplater are
+	// supported.
+	FeatureEmail Feature = "email"
+
+	// FeatureThemes means Themer is supported, and the Theme is made
+	// available to templates as .Theme.
+	FeatureThemes Feature = "themes"
+
+	// FeatureThemeVariables means the ThemeVariables Component is
+	// available.
+	FeatureThemeVariables Feature = "theme-variables"
+
+	// FeatureLint means Lint and LintFS are available.
+	FeatureLint Feature = "lint"
+
+	// FeatureHeaders means HeaderSetter is supported.
+	FeatureHeaders Feature = "headers"
+
+	// FeatureInspect means Inspect is available.
+	FeatureInspect Feature = "inspect"
+
+	// FeatureMount means MountFS and the WithMount SiteOption are
+	// available.
+	FeatureMount Feature = "mount"
+
+	// FeatureMultiSite means SiteResolver, HostSites, and SiteMiddleware
+	// are available.
+	FeatureMultiSite Feature = "multi-site"
+
+	// FeatureOutputFilters means the WithOutputFilters RenderOption is
+	// available.
+	FeatureOutputFilters Feature = "output-filters"
+
+	// FeatureCompression means the WithCompression RenderOption and
+	// Compressor are supported.
+	FeatureCompression Feature = "compression"
+
+	// FeatureMinify means the Minifier OutputFilter is available.
+	FeatureMinify Feature = "minify"
+
+	// FeatureHTMLValidation means ValidateHTML and the HTMLValidator
+	// OutputFilter are available.
+	FeatureHTMLValidation Feature = "html-validation"
+
+	// FeatureAccessibilityAudit means AuditAccessibility and the
+	// AccessibilityAudit OutputFilter are available.
+	FeatureAccessibilityAudit Feature = "accessibility-audit"
+)
+
+// features are the Features this version of temple supports.
+var features = []Feature{
+	FeatureAccessibilityAudit,
+	FeatureBuildSteps,
+	FeatureCachePolicy,
+	FeatureComponentFunc,
+	FeatureComponentPackages,
+	FeatureCompression,
+	FeatureContextFuncs,
+	FeatureCriticalCSS,
+	FeatureCSRF,
+	FeatureDelims,
+	FeatureEarlyHints,
+	FeatureEmail,
+	FeatureFetchPriority,
+	FeatureGraphCache,
+	FeatureHeaders,
+	FeatureHTMLValidation,
+	FeatureInspect,
+	FeatureJSImportMaps,
+	FeatureJSLoadStrategies,
+	FeatureJSONData,
+	FeatureLint,
+	FeatureMinify,
+	FeatureMount,
+	FeatureMultiSite,
+	FeatureOutputFilters,
+	FeaturePlaceResource,
+	FeaturePublish,
+	FeatureRegistry,
+	FeatureRenderComponent,
+	FeatureRequirements,
+	FeatureResponder,
+	FeatureSecurityPolicy,
+	FeatureSlots,
+	FeatureStreaming,
+	FeatureThemeVariables,
+	FeatureThemes,
+}
+
+// Features returns every Feature this version of temple supports, sorted
+// alphabetically.
+func Features() []Feature {
+	return slices.Sorted(slices.Values(features))
+}
+
+// HasFeature returns true if this version of temple supports the Feature.
+// Features added after the version of temple a library was built against
+// can't be referred to by their constants, but can still be checked for by
+// converting their names to a Feature:
+//
+//	if temple.HasFeature(temple.Feature("new-feature")) {
+//		// ...
+//	}
+func HasFeature(feature Feature) bool {
+	return slices.Contains(features, feature)
+}
+
+// modulePath is the path of the module temple is in.
+const modulePath = "impractical.co/temple"
+
+// develVersion is the version Go reports for modules built from a local
+// directory, rather than a released version.
+const develVersion = "(devel)"
+
+// Version returns the version of temple the running binary was built with,
+// like "v1.2.0", read from the binary's build information. It returns
+// "(devel)" if temple is the main module being built, or was replaced with a
+// local directory using a replace directive, and an empty string if the build
+// information isn't available. Prefer HasFeature for deciding whether
+// something can be used; Version is mostly useful for logging and debugging.
+func Version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	if info.Main.Path == modulePath {
+		return orDevel(info.Main.Version)
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != modulePath {
+			continue
+		}
+		if dep.Replace != nil {
+			// modules replaced with a directory have no version
+			return orDevel(dep.Replace.Version)
+		}
+		return dep.Version
+	}
+	return ""
+}
+
+// orDevel returns the version, or "(devel)" if it's empty, as it is for
+// modules built from a directory.
+func orDevel(version string) string {
+	if version == "" {
+		return develVersion
+	}
+	return version
+}
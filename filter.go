@@ -0,0 +1,50 @@
+package temple
+
+import (
+	"context"
+	"fmt"
+)
+
+// OutputFilter inspects or transforms the HTML a page rendered to, before
+// it's written. It's used by the WithOutputFilters RenderOption.
+type OutputFilter interface {
+	// FilterOutput returns the HTML that should be written instead of
+	// the rendered HTML. Filters that only inspect the output should
+	// return it unchanged. If it returns an error, the page fails to
+	// render.
+	FilterOutput(ctx context.Context, html []byte) ([]byte, error)
+}
+
+// OutputFilterFunc is a function that fills the OutputFilter interface.
+type OutputFilterFunc func(ctx context.Context, html []byte) ([]byte, error)
+
+// FilterOutput calls the OutputFilterFunc.
+func (fn OutputFilterFunc) FilterOutput(ctx context.Context, html []byte) ([]byte, error) {
+	return fn(ctx, html)
+}
+
+// WithOutputFilters is a RenderOption that passes the rendered page through
+// each of the OutputFilters in turn, in the order they're passed, before it's
+// written. It can be used more than once, with later filters running after
+// earlier ones.
+//
+// Filters need the whole page, so WithStreaming is ignored when there are
+// any.
+func WithOutputFilters(filters ...OutputFilter) RenderOption {
+	return func(opts *renderOptions) {
+		opts.outputFilters = append(opts.outputFilters, filters...)
+	}
+}
+
+// applyOutputFilters passes the HTML through each of the OutputFilters, in
+// order.
+func applyOutputFilters(ctx context.Context, filters []OutputFilter, html []byte) ([]byte, error) {
+	for _, filter := range filters {
+		filtered, err := filter.FilterOutput(ctx, html)
+		if err != nil {
+			return nil, fmt.Errorf("error filtering output with %T: %w", filter, err)
+		}
+		html = filtered
+	}
+	return html, nil
+}
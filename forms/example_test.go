@@ -0,0 +1,214 @@
+package forms_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing/fstest"
+
+	"impractical.co/temple"
+	"impractical.co/temple/forms"
+)
+
+type SignupPage struct {
+	Form forms.Form
+}
+
+func (SignupPage) Templates(_ context.Context) []string {
+	return []string{"signup.html.tmpl"}
+}
+
+func (s SignupPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{s.Form}
+}
+
+func (SignupPage) Key(_ context.Context) string {
+	return "signup.html.tmpl"
+}
+
+func (SignupPage) ExecutedTemplate(_ context.Context) string {
+	return "signup.html.tmpl"
+}
+
+func ExampleForm() {
+	templates := fstest.MapFS{
+		"signup.html.tmpl": {Data: []byte(`{{ template "forms/form.html.tmpl" .Page.Form }}`)},
+	}
+	site := temple.NewCachedSite(templates)
+
+	form := forms.Form{
+		Action: "/signup",
+		Fields: []forms.Input{
+			forms.Field{Name: "email", Label: "Email", Type: "email", Required: true},
+			forms.Select{Name: "plan", Label: "Plan", Options: []forms.Option{
+				{Value: "free", Label: "Free"},
+				{Value: "pro", Label: "Pro"},
+			}},
+			forms.Checkbox{Name: "terms", Label: "I agree to the terms"},
+		},
+		Submit: "Sign up",
+	}
+
+	// pretend the user submitted the form and it failed validation
+	submitted := url.Values{"email": {"not-an-email"}, "plan": {"pro"}}
+	form = form.Bind(submitted).WithErrors(map[string][]string{
+		"email": {"Enter a valid email address."},
+		"terms": {"You must agree to the terms."},
+	})
+
+	temple.Render(context.Background(), os.Stdout, site, SignupPage{Form: form})
+
+	//Output:
+	// <form class="temple-forms-form" action="/signup" method="post">
+	// 	<div class="temple-forms-field temple-forms-invalid">
+	// 	<label for="field-email">Email</label>
+	// 	<input type="email" id="field-email" name="email" value="not-an-email" required aria-invalid="true" aria-describedby="field-email-errors">
+	// 	<ul class="temple-forms-errors" id="field-email-errors">
+	// 		<li>Enter a valid email address.</li>
+	// 	</ul>
+	// </div>
+	// 	<div class="temple-forms-field">
+	// 	<label for="field-plan">Plan</label>
+	// 	<select id="field-plan" name="plan">
+	// 		<option value="free">Free</option>
+	// 		<option value="pro" selected>Pro</option>
+	// 	</select>
+	// </div>
+	// 	<div class="temple-forms-field temple-forms-checkbox temple-forms-invalid">
+	// 	<input type="checkbox" id="field-terms" name="terms" value="on" aria-invalid="true" aria-describedby="field-terms-errors">
+	// 	<label for="field-terms">I agree to the terms</label>
+	// 	<ul class="temple-forms-errors" id="field-terms-errors">
+	// 		<li>You must agree to the terms.</li>
+	// 	</ul>
+	// </div>
+	// 	<button type="submit">Sign up</button>
+	// </form>
+}
+
+func ExampleFileField_Files() {
+	field := forms.FileField{
+		Name:     "photo",
+		Label:    "Photo",
+		Accept:   []string{"image/*"},
+		MaxSize:  1_000_000,
+		Required: true,
+	}
+
+	// build a request uploading a text file, to show the validation
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("photo", "notes.png")
+	if err != nil {
+		panic(err)
+	}
+	_, err = part.Write([]byte("not really a PNG"))
+	if err != nil {
+		panic(err)
+	}
+	err = writer.Close()
+	if err != nil {
+		panic(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/photos", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	err = forms.ParseUpload(httptest.NewRecorder(), req, 10_000_000)
+	if err != nil {
+		panic(err)
+	}
+	files, errs := field.Files(req)
+	fmt.Println(len(files), errs)
+
+	//Output:
+	// 1 [notes.png isn't an accepted kind of file.]
+}
+
+type CheckoutPage struct {
+	Wizard forms.WizardView
+}
+
+func (CheckoutPage) Templates(_ context.Context) []string {
+	return []string{"checkout.html.tmpl"}
+}
+
+func (c CheckoutPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{c.Wizard}
+}
+
+func (CheckoutPage) Key(_ context.Context) string {
+	return "checkout.html.tmpl"
+}
+
+func (CheckoutPage) ExecutedTemplate(_ context.Context) string {
+	return "checkout.html.tmpl"
+}
+
+func ExampleWizard() {
+	templates := fstest.MapFS{
+		"checkout.html.tmpl": {Data: []byte(`{{ template "forms/wizard.html.tmpl" .Page.Wizard }}`)},
+	}
+	site := temple.NewCachedSite(templates)
+
+	wizard := forms.Wizard{
+		Steps: []forms.WizardStep{
+			{
+				Title:  "Contact",
+				Fields: []forms.Input{forms.Field{Name: "email", Label: "Email", Type: "email"}},
+				Validate: func(_ context.Context, values url.Values) map[string][]string {
+					if values.Get("email") == "" {
+						return map[string][]string{"email": {"Enter your email address."}}
+					}
+					return nil
+				},
+			},
+			{
+				Title:  "Shipping",
+				Fields: []forms.Input{forms.Field{Name: "address", Label: "Address"}},
+			},
+		},
+		Store: &forms.MemoryWizardStore{},
+		Submit: func(_ context.Context, values url.Values) error {
+			fmt.Println("ordered to", values.Get("address"), "for", values.Get("email"))
+			return nil
+		},
+	}
+	ctx := context.Background()
+	post := func(values url.Values) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/checkout", bytes.NewBufferString(values.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+
+	// the email is required, so the first step is shown again
+	view, _, _ := wizard.Handle(ctx, "session-1", post(url.Values{"wizard_step": {"0"}, "wizard_action": {"next"}}))
+	fmt.Println(view.Number, view.Title)
+
+	view, _, _ = wizard.Handle(ctx, "session-1", post(url.Values{"wizard_step": {"0"}, "wizard_action": {"next"}, "email": {"jo@example.com"}}))
+	var out strings.Builder
+	temple.Render(ctx, &out, site, CheckoutPage{Wizard: view})
+	fmt.Println(out.String())
+
+	_, done, _ := wizard.Handle(ctx, "session-1", post(url.Values{"wizard_step": {"1"}, "wizard_action": {"next"}, "address": {"1 Main St"}}))
+	fmt.Println(done)
+
+	//Output:
+	// 1 Contact
+	// <form class="temple-forms-form temple-forms-wizard" action="" method="post">
+	// 	<p class="temple-forms-wizard-step">Step 2 of 2: Shipping</p>
+	// 	<input type="hidden" name="wizard_step" value="1">
+	// 	<div class="temple-forms-field">
+	// 	<label for="field-address">Address</label>
+	// 	<input type="text" id="field-address" name="address" value="">
+	// </div>
+	// 	<button type="submit" name="wizard_action" value="back" formnovalidate>Back</button>
+	// 	<button type="submit" name="wizard_action" value="next">Finish</button>
+	// </form>
+	// ordered to 1 Main St for jo@example.com
+	// true
+}
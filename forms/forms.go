@@ -0,0 +1,433 @@
+// Package forms provides temple Components for rendering HTML forms,
+// including the values a user submitted and any validation errors for them.
+//
+// Every Component in this package supplies its own templates and embeds the
+// CSS it needs, so all that's required to use them is to include them in the
+// UseComponents output of a Component and execute their templates. A Form
+// can be rendered with:
+//
+//	{{ template "forms/form.html.tmpl" .Page.ContactForm }}
+//
+// and individual fields can be rendered outside a Form using the
+// "forms/field.html.tmpl", "forms/select.html.tmpl",
+// "forms/checkbox.html.tmpl", and "forms/file.html.tmpl" templates.
+//
+// Forms containing a FileField are submitted as multipart/form-data; handlers
+// can parse them with ParseUpload and validate the uploaded files with
+// FileField.Files.
+package forms
+
+import (
+	"context"
+	"embed"
+	"html/template"
+	"io/fs"
+	"net/url"
+
+	"impractical.co/temple"
+)
+
+//go:embed templates
+var templates embed.FS
+
+const css = `
+.temple-forms-field { margin-bottom: 1em; }
+.temple-forms-field label { display: block; }
+.temple-forms-checkbox label { display: inline; }
+.temple-forms-invalid input, .temple-forms-invalid select { border-color: #b00020; }
+.temple-forms-errors { color: #b00020; margin: 0.25em 0 0; padding-left: 1.25em; }
+`
+
+// Input is a single form control that can be included in a Form. The Field,
+// Select, Checkbox, and FileField types are all Inputs.
+type Input interface {
+	temple.Component
+
+	// Kind returns the kind of control the Input is, so the Form
+	// template knows how to render it.
+	Kind() string
+
+	// bind returns a copy of the Input with its value set from the
+	// submitted values.
+	bind(url.Values) Input
+
+	// withErrors returns a copy of the Input with its errors set to the
+	// passed errors.
+	withErrors([]string) Input
+
+	// name returns the name of the form field the Input controls.
+	name() string
+}
+
+var (
+	_ Input = Field{}
+	_ Input = Select{}
+	_ Input = Checkbox{}
+)
+
+// Field is a form control rendered as an <input> element.
+type Field struct {
+	// Name is the name of the form field.
+	Name string
+
+	// ID is the id attribute of the <input> element. If empty, it will be
+	// derived from Name.
+	ID string
+
+	// Label is the text of the field's <label>.
+	Label string
+
+	// Type is the type attribute of the <input> element. Defaults to
+	// "text".
+	Type string
+
+	// Value is the current value of the field.
+	Value string
+
+	// Placeholder is the placeholder text for the field.
+	Placeholder string
+
+	// Required marks the field as required.
+	Required bool
+
+	// Errors are the validation errors for the field's Value.
+	Errors []string
+}
+
+// Templates returns the templates needed to render the Field.
+func (Field) Templates(_ context.Context) []string {
+	return []string{"forms/field.html.tmpl", "forms/errors.html.tmpl"}
+}
+
+// TemplateDir returns the fs.FS containing the Field's templates.
+func (Field) TemplateDir(_ context.Context) fs.FS {
+	return temple.SubFS(templates, "templates")
+}
+
+// EmbedCSS returns the CSS used to style form controls.
+func (Field) EmbedCSS(_ context.Context) template.CSS {
+	return css
+}
+
+// Kind returns "field".
+func (Field) Kind() string {
+	return "field"
+}
+
+// ElementID returns the id attribute of the <input> element.
+func (f Field) ElementID() string {
+	return elementID(f.ID, f.Name)
+}
+
+// InputType returns the type attribute of the <input> element.
+func (f Field) InputType() string {
+	if f.Type == "" {
+		return "text"
+	}
+	return f.Type
+}
+
+func (f Field) bind(values url.Values) Input {
+	// never re-render submitted passwords
+	if f.Type != "password" {
+		f.Value = values.Get(f.Name)
+	}
+	return f
+}
+
+func (f Field) withErrors(errs []string) Input {
+	f.Errors = errs
+	return f
+}
+
+func (f Field) name() string {
+	return f.Name
+}
+
+// Option is a single choice in a Select.
+type Option struct {
+	// Value is the value submitted when the Option is chosen.
+	Value string
+
+	// Label is the text displayed for the Option.
+	Label string
+}
+
+// Select is a form control rendered as a <select> element.
+type Select struct {
+	// Name is the name of the form field.
+	Name string
+
+	// ID is the id attribute of the <select> element. If empty, it will
+	// be derived from Name.
+	ID string
+
+	// Label is the text of the field's <label>.
+	Label string
+
+	// Options are the choices available in the Select.
+	Options []Option
+
+	// Value is the value of the currently selected Option.
+	Value string
+
+	// Required marks the field as required.
+	Required bool
+
+	// Errors are the validation errors for the field's Value.
+	Errors []string
+}
+
+// Templates returns the templates needed to render the Select.
+func (Select) Templates(_ context.Context) []string {
+	return []string{"forms/select.html.tmpl", "forms/errors.html.tmpl"}
+}
+
+// TemplateDir returns the fs.FS containing the Select's templates.
+func (Select) TemplateDir(_ context.Context) fs.FS {
+	return temple.SubFS(templates, "templates")
+}
+
+// EmbedCSS returns the CSS used to style form controls.
+func (Select) EmbedCSS(_ context.Context) template.CSS {
+	return css
+}
+
+// Kind returns "select".
+func (Select) Kind() string {
+	return "select"
+}
+
+// ElementID returns the id attribute of the <select> element.
+func (s Select) ElementID() string {
+	return elementID(s.ID, s.Name)
+}
+
+// Selected returns true if the passed Option is the currently selected one.
+func (s Select) Selected(opt Option) bool {
+	return s.Value == opt.Value
+}
+
+func (s Select) bind(values url.Values) Input {
+	s.Value = values.Get(s.Name)
+	return s
+}
+
+func (s Select) withErrors(errs []string) Input {
+	s.Errors = errs
+	return s
+}
+
+func (s Select) name() string {
+	return s.Name
+}
+
+// Checkbox is a form control rendered as an <input type="checkbox"> element.
+type Checkbox struct {
+	// Name is the name of the form field.
+	Name string
+
+	// ID is the id attribute of the <input> element. If empty, it will be
+	// derived from Name.
+	ID string
+
+	// Label is the text of the field's <label>.
+	Label string
+
+	// Value is the value submitted when the Checkbox is checked. Defaults
+	// to "on", like browsers do.
+	Value string
+
+	// Checked marks the Checkbox as checked.
+	Checked bool
+
+	// Required marks the field as required.
+	Required bool
+
+	// Errors are the validation errors for the field.
+	Errors []string
+}
+
+// Templates returns the templates needed to render the Checkbox.
+func (Checkbox) Templates(_ context.Context) []string {
+	return []string{"forms/checkbox.html.tmpl", "forms/errors.html.tmpl"}
+}
+
+// TemplateDir returns the fs.FS containing the Checkbox's templates.
+func (Checkbox) TemplateDir(_ context.Context) fs.FS {
+	return temple.SubFS(templates, "templates")
+}
+
+// EmbedCSS returns the CSS used to style form controls.
+func (Checkbox) EmbedCSS(_ context.Context) template.CSS {
+	return css
+}
+
+// Kind returns "checkbox".
+func (Checkbox) Kind() string {
+	return "checkbox"
+}
+
+// ElementID returns the id attribute of the <input> element.
+func (c Checkbox) ElementID() string {
+	return elementID(c.ID, c.Name)
+}
+
+// CheckedValue returns the value submitted when the Checkbox is checked.
+func (c Checkbox) CheckedValue() string {
+	if c.Value == "" {
+		return "on"
+	}
+	return c.Value
+}
+
+func (c Checkbox) bind(values url.Values) Input {
+	c.Checked = false
+	for _, v := range values[c.Name] {
+		if v == c.CheckedValue() {
+			c.Checked = true
+			break
+		}
+	}
+	return c
+}
+
+func (c Checkbox) withErrors(errs []string) Input {
+	c.Errors = errs
+	return c
+}
+
+func (c Checkbox) name() string {
+	return c.Name
+}
+
+// Form is a Component that renders a <form> element containing Inputs.
+type Form struct {
+	// Action is the URL the Form is submitted to. If empty, the Form is
+	// submitted to the current URL.
+	Action string
+
+	// Method is the HTTP method used to submit the Form. Defaults to
+	// "post".
+	Method string
+
+	// Fields are the Inputs in the Form, in the order they should be
+	// rendered.
+	Fields []Input
+
+	// Submit is the text of the submit button. Defaults to "Submit".
+	Submit string
+
+	// Errors are validation errors that apply to the Form as a whole,
+	// rather than a single Input.
+	Errors []string
+
+	// CSRF includes a hidden CSRF token field in the Form, using
+	// temple.CSRFField. The Site must implement temple.CSRFProvider.
+	CSRF bool
+
+	// UploadProgress submits the Form in the background, displaying a
+	// progress bar while its files upload. If the handler redirects after
+	// the upload, the browser follows the redirect; otherwise, the Form is
+	// replaced with the Form in the response, so handlers can re-render
+	// the page with validation errors as they usually would. Without
+	// JavaScript, the Form is submitted normally.
+	UploadProgress bool
+}
+
+// Templates returns the templates needed to render the Form.
+func (Form) Templates(_ context.Context) []string {
+	return []string{"forms/form.html.tmpl", "forms/input.html.tmpl"}
+}
+
+// TemplateDir returns the fs.FS containing the Form's templates.
+func (Form) TemplateDir(_ context.Context) fs.FS {
+	return temple.SubFS(templates, "templates")
+}
+
+// EmbedCSS returns the CSS used to style forms.
+func (Form) EmbedCSS(_ context.Context) template.CSS {
+	return css
+}
+
+// UseComponents returns the Form's Inputs, along with the Components the
+// Form relies on.
+func (f Form) UseComponents(_ context.Context) []temple.Component {
+	// the templates for every kind of Input need to be available, even
+	// if the Form doesn't use them, as the Form template refers to them
+	results := []temple.Component{
+		temple.CSRFField{},
+		Field{},
+		Select{},
+		Checkbox{},
+		FileField{},
+	}
+	if f.UploadProgress {
+		results = append(results, uploadProgress{})
+	}
+	for _, field := range f.Fields {
+		results = append(results, field)
+	}
+	return results
+}
+
+// FormMethod returns the method attribute of the <form> element.
+func (f Form) FormMethod() string {
+	if f.Method == "" {
+		return "post"
+	}
+	return f.Method
+}
+
+// EncType returns the enctype attribute of the <form> element, which is
+// "multipart/form-data" if the Form contains a FileField, and empty
+// otherwise.
+func (f Form) EncType() string {
+	for _, field := range f.Fields {
+		if field.Kind() == "file" {
+			return "multipart/form-data"
+		}
+	}
+	return ""
+}
+
+// SubmitLabel returns the text of the submit button.
+func (f Form) SubmitLabel() string {
+	if f.Submit == "" {
+		return "Submit"
+	}
+	return f.Submit
+}
+
+// Bind returns a copy of the Form with the value of each of its Inputs set
+// from the submitted values, usually an http.Request's PostForm. This lets a
+// Form that failed validation be rendered again with the user's input intact.
+// Password fields are never filled from the submitted values.
+func (f Form) Bind(values url.Values) Form {
+	fields := make([]Input, 0, len(f.Fields))
+	for _, field := range f.Fields {
+		fields = append(fields, field.bind(values))
+	}
+	f.Fields = fields
+	return f
+}
+
+// WithErrors returns a copy of the Form with the validation errors for each
+// of its Inputs set from the passed map, which is keyed by the name of the
+// Input. Errors under the empty string key are set as the Form's own Errors.
+func (f Form) WithErrors(errs map[string][]string) Form {
+	fields := make([]Input, 0, len(f.Fields))
+	for _, field := range f.Fields {
+		fields = append(fields, field.withErrors(errs[field.name()]))
+	}
+	f.Fields = fields
+	f.Errors = errs[""]
+	return f
+}
+
+func elementID(id, name string) string {
+	if id != "" {
+		return id
+	}
+	return "field-" + name
+}
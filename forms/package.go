@@ -0,0 +1,8 @@
+package forms
+
+import "impractical.co/temple"
+
+// Package returns the temple.ComponentPackage for Form, Field, Select, Checkbox, FileField, and WizardView.
+func Package() (temple.ComponentPackage, error) {
+	return temple.NewComponentPackage("forms", templates, "templates/forms", "", Form{}, Field{}, Select{}, Checkbox{}, FileField{}, WizardView{})
+}
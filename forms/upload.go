@@ -0,0 +1,294 @@
+package forms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"impractical.co/temple"
+)
+
+// ErrUploadTooLarge is returned by ParseUpload when the request body is larger
+// than the maximum size allowed.
+var ErrUploadTooLarge = errors.New("upload too large")
+
+// uploadMemory is how much of an upload ParseUpload keeps in memory before
+// storing the rest in temporary files.
+const uploadMemory = 32 << 20
+
+// ParseUpload parses the multipart form submitted in the request, so the
+// files can be retrieved with FileField.Files. Request bodies larger than
+// maxBytes are rejected with ErrUploadTooLarge, and the connection is closed
+// once the response is written, so the client stops sending the upload.
+func ParseUpload(w http.ResponseWriter, r *http.Request, maxBytes int64) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+	err := r.ParseMultipartForm(uploadMemory)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return fmt.Errorf("%w: limit is %d bytes", ErrUploadTooLarge, maxBytes)
+		}
+		return fmt.Errorf("error parsing upload: %w", err)
+	}
+	return nil
+}
+
+// FileField is a form control rendered as an <input type="file"> element.
+// Forms containing a FileField are submitted as multipart/form-data.
+type FileField struct {
+	// Name is the name of the form field.
+	Name string
+
+	// ID is the id attribute of the <input> element. If empty, it will be
+	// derived from Name.
+	ID string
+
+	// Label is the text of the field's <label>.
+	Label string
+
+	// Accept are the kinds of files the field accepts, as file extensions
+	// like ".pdf" or MIME types like "image/png" or "image/*". If empty,
+	// any kind of file is accepted.
+	Accept []string
+
+	// MaxSize is the largest file, in bytes, the field accepts. If 0,
+	// files of any size are accepted, up to the limit passed to
+	// ParseUpload.
+	MaxSize int64
+
+	// Multiple lets more than one file be chosen.
+	Multiple bool
+
+	// Required marks the field as required.
+	Required bool
+
+	// Errors are the validation errors for the field.
+	Errors []string
+}
+
+var _ Input = FileField{}
+
+// Templates returns the templates needed to render the FileField.
+func (FileField) Templates(_ context.Context) []string {
+	return []string{"forms/file.html.tmpl", "forms/errors.html.tmpl"}
+}
+
+// TemplateDir returns the fs.FS containing the FileField's templates.
+func (FileField) TemplateDir(_ context.Context) fs.FS {
+	return temple.SubFS(templates, "templates")
+}
+
+// EmbedCSS returns the CSS used to style form controls.
+func (FileField) EmbedCSS(_ context.Context) template.CSS {
+	return css
+}
+
+// Kind returns "file".
+func (FileField) Kind() string {
+	return "file"
+}
+
+// ElementID returns the id attribute of the <input> element.
+func (f FileField) ElementID() string {
+	return elementID(f.ID, f.Name)
+}
+
+// AcceptAttr returns the accept attribute of the <input> element.
+func (f FileField) AcceptAttr() string {
+	return strings.Join(f.Accept, ",")
+}
+
+// Files returns the files submitted for the field, after ParseUpload has
+// been called, along with validation errors for any that are missing, too
+// large, or not an accepted kind of file. Files are checked against MIME
+// types in Accept by sniffing their contents, not by trusting the type the
+// browser reported.
+func (f FileField) Files(r *http.Request) ([]*multipart.FileHeader, []string) {
+	var files []*multipart.FileHeader
+	if r.MultipartForm != nil {
+		files = r.MultipartForm.File[f.Name]
+	}
+	var errs []string
+	if len(files) < 1 {
+		if f.Required {
+			errs = append(errs, "Choose a file to upload.")
+		}
+		return nil, errs
+	}
+	if !f.Multiple && len(files) > 1 {
+		errs = append(errs, "Choose only one file.")
+	}
+	for _, file := range files {
+		if f.MaxSize > 0 && file.Size > f.MaxSize {
+			errs = append(errs, fmt.Sprintf("%s is larger than %s.", file.Filename, formatBytes(f.MaxSize)))
+			continue
+		}
+		ok, err := f.accepts(file)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s couldn't be read.", file.Filename))
+			continue
+		}
+		if !ok {
+			errs = append(errs, fmt.Sprintf("%s isn't an accepted kind of file.", file.Filename))
+		}
+	}
+	return files, errs
+}
+
+// accepts returns true if the file matches one of the kinds of file in
+// Accept.
+func (f FileField) accepts(file *multipart.FileHeader) (bool, error) {
+	if len(f.Accept) < 1 {
+		return true, nil
+	}
+	var sniffed string
+	for _, accept := range f.Accept {
+		accept = strings.ToLower(strings.TrimSpace(accept))
+		if strings.HasPrefix(accept, ".") {
+			if strings.ToLower(path.Ext(file.Filename)) == accept {
+				return true, nil
+			}
+			continue
+		}
+		if sniffed == "" {
+			var err error
+			sniffed, err = sniffContentType(file)
+			if err != nil {
+				return false, err
+			}
+		}
+		if prefix, ok := strings.CutSuffix(accept, "/*"); ok {
+			if strings.HasPrefix(sniffed, prefix+"/") {
+				return true, nil
+			}
+			continue
+		}
+		if sniffed == accept {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// sniffContentType returns the MIME type of the file, without parameters,
+// based on its contents.
+func sniffContentType(file *multipart.FileHeader) (string, error) {
+	contents, err := file.Open()
+	if err != nil {
+		return "", fmt.Errorf("error opening %q: %w", file.Filename, err)
+	}
+	defer func() {
+		// we've only read the file, so there's nothing useful to do
+		// if it fails to close
+		_ = contents.Close()
+	}()
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(contents, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("error reading %q: %w", file.Filename, err)
+	}
+	mimeType, _, _ := strings.Cut(http.DetectContentType(buf[:n]), ";")
+	return mimeType, nil
+}
+
+func (f FileField) bind(_ url.Values) Input {
+	// browsers don't let pages pre-fill file inputs
+	return f
+}
+
+func (f FileField) withErrors(errs []string) Input {
+	f.Errors = errs
+	return f
+}
+
+func (f FileField) name() string {
+	return f.Name
+}
+
+// formatBytes formats a number of bytes for display, like "5 MB".
+func formatBytes(n int64) string {
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%d bytes", n)
+	}
+	value, suffix := float64(n), ""
+	for _, s := range []string{"KB", "MB", "GB", "TB"} {
+		value /= unit
+		suffix = s
+		if value < unit {
+			break
+		}
+	}
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", value), ".0") + " " + suffix
+}
+
+// uploadProgressJS submits forms marked with data-temple-upload using
+// XMLHttpRequest, which, unlike fetch, reports upload progress. When the
+// server redirects after a successful upload, the browser follows the
+// redirect; otherwise the form is replaced with the one in the response,
+// usually the form re-rendered with validation errors.
+const uploadProgressJS = `
+(function () {
+	document.addEventListener("submit", function (event) {
+		var form = event.target;
+		if (!form.matches || !form.matches("form[data-temple-upload]") || !window.FormData) {
+			return;
+		}
+		event.preventDefault();
+		var bar = form.querySelector(".temple-forms-progress");
+		var xhr = new XMLHttpRequest();
+		xhr.open(form.method, form.action);
+		xhr.setRequestHeader("Accept", "text/html");
+		xhr.upload.addEventListener("progress", function (e) {
+			if (bar && e.lengthComputable) {
+				bar.hidden = false;
+				bar.max = e.total;
+				bar.value = e.loaded;
+			}
+		});
+		xhr.addEventListener("load", function () {
+			if (xhr.responseURL && xhr.responseURL !== form.action) {
+				window.location.assign(xhr.responseURL);
+				return;
+			}
+			var container = document.createElement("div");
+			container.innerHTML = xhr.responseText;
+			var next = container.querySelector("form[data-temple-upload]");
+			if (next) {
+				form.replaceWith(next);
+				return;
+			}
+			document.open();
+			document.write(xhr.responseText);
+			document.close();
+		});
+		xhr.addEventListener("error", function () {
+			// fall back to submitting the form without progress
+			form.submit();
+		});
+		xhr.send(new FormData(form));
+	});
+})();
+`
+
+var _ temple.JSEmbedder = uploadProgress{}
+
+// uploadProgress is the Component that supplies the JavaScript for Forms with
+// UploadProgress set.
+type uploadProgress struct{}
+
+func (uploadProgress) Templates(_ context.Context) []string {
+	return nil
+}
+
+func (uploadProgress) EmbedJS(_ context.Context) template.JS {
+	return uploadProgressJS
+}
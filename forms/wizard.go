@@ -0,0 +1,297 @@
+package forms
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"maps"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"impractical.co/temple"
+)
+
+const (
+	// WizardActionField is the name of the form field the Back and Next
+	// buttons of a WizardView submit.
+	WizardActionField = "wizard_action"
+
+	// WizardStepField is the name of the hidden form field holding the
+	// number of the step being submitted, so submissions from stale pages
+	// can be detected.
+	WizardStepField = "wizard_step"
+)
+
+// WizardState is the progress of a user through a Wizard.
+type WizardState struct {
+	// Step is the index of the step the user is on.
+	Step int
+
+	// Values are the values submitted for every step so far.
+	Values url.Values
+}
+
+// WizardStore persists the WizardState of users between requests, usually in
+// their session.
+type WizardStore interface {
+	// LoadWizard returns the WizardState stored under the ID. If there
+	// isn't one, it should return the zero value of WizardState and a
+	// nil error.
+	LoadWizard(ctx context.Context, id string) (WizardState, error)
+
+	// SaveWizard stores the WizardState under the ID.
+	SaveWizard(ctx context.Context, id string, state WizardState) error
+
+	// ClearWizard removes the WizardState stored under the ID, once the
+	// Wizard is finished.
+	ClearWizard(ctx context.Context, id string) error
+}
+
+// WizardStep is a single step of a Wizard.
+type WizardStep struct {
+	// Title describes the step to the user, like "Shipping address".
+	Title string
+
+	// Fields are the Inputs the user fills in during the step. Only the
+	// values of these Inputs are accepted when the step is submitted.
+	Fields []Input
+
+	// Validate checks the values submitted so far, including the ones
+	// for this step, and returns validation errors in the format
+	// Form.WithErrors accepts. If it returns any errors, the step is
+	// displayed again with them. If nil, every submission is accepted.
+	Validate func(ctx context.Context, values url.Values) map[string][]string
+}
+
+// Wizard is a form split into multiple steps. The user's answers are stored
+// in a WizardStore between steps, and the user can go back to earlier steps
+// without losing them. Once the last step is submitted, the answers to every
+// step are passed to Submit.
+type Wizard struct {
+	// Steps are the steps of the Wizard, in order.
+	Steps []WizardStep
+
+	// Store persists the user's progress between requests.
+	Store WizardStore
+
+	// Submit is called with the values of every step once the last step
+	// is submitted successfully. If it returns an error, the last step is
+	// displayed again and the error is returned from Handle.
+	Submit func(ctx context.Context, values url.Values) error
+
+	// Action is the URL the steps are submitted to. If empty, they're
+	// submitted to the current URL.
+	Action string
+
+	// CSRF includes a hidden CSRF token field in each step, using
+	// temple.CSRFField. The Site must implement temple.CSRFProvider.
+	CSRF bool
+}
+
+// Handle advances the Wizard stored under the ID based on the request, and
+// returns the WizardView to render. GET requests display the step the user
+// is on. POST requests submit it: the Back button returns to the previous
+// step, and the Next button validates the step before moving on to the next
+// one, or calling Submit if it was the last step. Once Submit succeeds, the
+// WizardState is cleared and Handle returns true, at which point the caller
+// should usually redirect to a confirmation page.
+func (w Wizard) Handle(ctx context.Context, id string, r *http.Request) (WizardView, bool, error) {
+	if len(w.Steps) < 1 {
+		return WizardView{}, false, fmt.Errorf("wizard %q has no steps", id)
+	}
+	state, err := w.Store.LoadWizard(ctx, id)
+	if err != nil {
+		return WizardView{}, false, fmt.Errorf("error loading wizard %q: %w", id, err)
+	}
+	if state.Values == nil {
+		state.Values = url.Values{}
+	}
+	state.Step = min(max(state.Step, 0), len(w.Steps)-1)
+
+	if r.Method != http.MethodPost {
+		return w.view(state, nil), false, nil
+	}
+	err = r.ParseForm()
+	if err != nil {
+		return WizardView{}, false, fmt.Errorf("error parsing wizard %q submission: %w", id, err)
+	}
+	if r.PostForm.Get(WizardStepField) != strconv.Itoa(state.Step) {
+		// the submission came from a page showing a different step,
+		// like a stale tab; show the step the user is really on
+		return w.view(state, nil), false, nil
+	}
+
+	// only accept the values of the current step's fields
+	for _, field := range w.Steps[state.Step].Fields {
+		state.Values[field.name()] = r.PostForm[field.name()]
+	}
+
+	if r.PostForm.Get(WizardActionField) == "back" {
+		state.Step = max(state.Step-1, 0)
+		return w.save(ctx, id, state, nil)
+	}
+
+	if validate := w.Steps[state.Step].Validate; validate != nil {
+		if errs := validate(ctx, state.Values); len(errs) > 0 {
+			return w.save(ctx, id, state, errs)
+		}
+	}
+	if state.Step < len(w.Steps)-1 {
+		state.Step++
+		return w.save(ctx, id, state, nil)
+	}
+
+	if w.Submit != nil {
+		err = w.Submit(ctx, state.Values)
+		if err != nil {
+			view, _, saveErr := w.save(ctx, id, state, nil)
+			if saveErr != nil {
+				return view, false, saveErr
+			}
+			return view, false, fmt.Errorf("error submitting wizard %q: %w", id, err)
+		}
+	}
+	err = w.Store.ClearWizard(ctx, id)
+	if err != nil {
+		return WizardView{}, false, fmt.Errorf("error clearing wizard %q: %w", id, err)
+	}
+	return WizardView{}, true, nil
+}
+
+// save stores the WizardState and returns the view of its current step, with
+// the passed validation errors.
+func (w Wizard) save(ctx context.Context, id string, state WizardState, errs map[string][]string) (WizardView, bool, error) {
+	err := w.Store.SaveWizard(ctx, id, state)
+	if err != nil {
+		return WizardView{}, false, fmt.Errorf("error saving wizard %q: %w", id, err)
+	}
+	return w.view(state, errs), false, nil
+}
+
+// view returns the WizardView for the current step of the WizardState.
+func (w Wizard) view(state WizardState, errs map[string][]string) WizardView {
+	step := w.Steps[state.Step]
+	form := Form{
+		Action: w.Action,
+		Fields: step.Fields,
+		CSRF:   w.CSRF,
+	}.Bind(state.Values)
+	if errs != nil {
+		form = form.WithErrors(errs)
+	}
+	return WizardView{
+		Form:   form,
+		Title:  step.Title,
+		Number: state.Step + 1,
+		Count:  len(w.Steps),
+	}
+}
+
+var (
+	_ temple.Component           = WizardView{}
+	_ temple.TemplateDirProvider = WizardView{}
+	_ temple.CSSEmbedder         = WizardView{}
+)
+
+// WizardView is a Component that renders a single step of a Wizard, with
+// Back and Next buttons. It's returned by Wizard.Handle, and can be rendered
+// with:
+//
+//	{{ template "forms/wizard.html.tmpl" .Page.Wizard }}
+type WizardView struct {
+	// Form contains the step's Inputs, with the values submitted so far
+	// and any validation errors.
+	Form Form
+
+	// Title describes the step.
+	Title string
+
+	// Number is the number of the step, starting from 1.
+	Number int
+
+	// Count is the number of steps in the Wizard.
+	Count int
+}
+
+// Templates returns the templates needed to render the WizardView.
+func (WizardView) Templates(_ context.Context) []string {
+	return []string{"forms/wizard.html.tmpl"}
+}
+
+// TemplateDir returns the fs.FS containing the WizardView's templates.
+func (WizardView) TemplateDir(_ context.Context) fs.FS {
+	return temple.SubFS(templates, "templates")
+}
+
+// EmbedCSS returns the CSS used to style forms.
+func (WizardView) EmbedCSS(_ context.Context) template.CSS {
+	return css
+}
+
+// UseComponents returns the step's Form.
+func (v WizardView) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{v.Form}
+}
+
+// StepIndex returns the index of the step, for the hidden WizardStepField.
+func (v WizardView) StepIndex() int {
+	return v.Number - 1
+}
+
+// IsFirst returns true if this is the first step, which has no Back button.
+func (v WizardView) IsFirst() bool {
+	return v.Number <= 1
+}
+
+// NextLabel returns the text of the Next button, which is "Finish" on the
+// last step.
+func (v WizardView) NextLabel() string {
+	if v.Number >= v.Count {
+		return "Finish"
+	}
+	return "Next"
+}
+
+var _ WizardStore = &MemoryWizardStore{}
+
+// MemoryWizardStore is a WizardStore that keeps WizardStates in memory. It's
+// useful for tests and development, but as its contents are lost when the
+// server restarts and aren't shared between servers, production Sites should
+// store WizardStates in their sessions instead. Its zero value is ready to
+// use.
+type MemoryWizardStore struct {
+	mu     sync.Mutex
+	states map[string]WizardState
+}
+
+// LoadWizard returns the WizardState stored under the ID.
+func (m *MemoryWizardStore) LoadWizard(_ context.Context, id string) (WizardState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state := m.states[id]
+	state.Values = maps.Clone(state.Values)
+	return state, nil
+}
+
+// SaveWizard stores the WizardState under the ID.
+func (m *MemoryWizardStore) SaveWizard(_ context.Context, id string, state WizardState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.states == nil {
+		m.states = map[string]WizardState{}
+	}
+	state.Values = maps.Clone(state.Values)
+	m.states[id] = state
+	return nil
+}
+
+// ClearWizard removes the WizardState stored under the ID.
+func (m *MemoryWizardStore) ClearWizard(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.states, id)
+	return nil
+}
@@ -0,0 +1,147 @@
+package temple_test
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"impractical.co/temple"
+)
+
+type funcsComponent struct {
+	funcs template.FuncMap
+}
+
+func (*funcsComponent) Templates(_ context.Context) []string {
+	return nil
+}
+
+func (f *funcsComponent) FuncMap(_ context.Context) template.FuncMap {
+	return f.funcs
+}
+
+type funcsPage struct {
+	components []temple.Component
+}
+
+func (funcsPage) Templates(_ context.Context) []string {
+	return []string{"funcs.html.tmpl"}
+}
+
+func (p funcsPage) UseComponents(_ context.Context) []temple.Component {
+	return p.components
+}
+
+func (funcsPage) Key(_ context.Context) string {
+	return "funcs.html.tmpl"
+}
+
+func (funcsPage) ExecutedTemplate(_ context.Context) string {
+	return "funcs.html.tmpl"
+}
+
+// localeSite has a context func, "locale".
+type localeSite struct {
+	*temple.CachedSite
+}
+
+func (localeSite) ContextFuncMap(_ context.Context) template.FuncMap {
+	return template.FuncMap{
+		"locale": func() string { return "en" },
+	}
+}
+
+func greeting(name string) func() string {
+	return func() string { return "hello, " + name }
+}
+
+type greeter struct {
+	name string
+}
+
+func (g greeter) Greet() string {
+	return "hello, " + g.name
+}
+
+func TestWithStrictFuncMaps(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		funcs    []template.FuncMap
+		template string
+		want     string
+		wantErr  bool
+	}{
+		"different-names": {
+			funcs:    []template.FuncMap{{"a": strings.ToUpper}, {"b": strings.ToLower}},
+			template: `{{ a "x" }}{{ b "Y" }}`,
+			want:     "Xy",
+		},
+		"same-function": {
+			funcs:    []template.FuncMap{{"upper": strings.ToUpper}, {"upper": strings.ToUpper}},
+			template: `{{ upper "x" }}`,
+			want:     "X",
+		},
+		"different-functions": {
+			funcs:   []template.FuncMap{{"fmt": strings.ToUpper}, {"fmt": strings.ToLower}},
+			wantErr: true,
+		},
+		"closures-from-same-literal": {
+			funcs:   []template.FuncMap{{"greet": greeting("ada")}, {"greet": greeting("grace")}},
+			wantErr: true,
+		},
+		"method-values": {
+			funcs:   []template.FuncMap{{"greet": greeter{name: "ada"}.Greet}, {"greet": greeter{name: "grace"}.Greet}},
+			wantErr: true,
+		},
+		"overridden-by-builtin-context-func": {
+			funcs:   []template.FuncMap{{"ctxval": strings.ToUpper}},
+			wantErr: true,
+		},
+		"overridden-by-site-context-func": {
+			funcs:   []template.FuncMap{{"locale": strings.ToUpper}},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			tmpl := test.template
+			if tmpl == "" {
+				tmpl = "unused"
+			}
+			site := localeSite{CachedSite: temple.NewCachedSite(fstest.MapFS{
+				"funcs.html.tmpl": {Data: []byte(tmpl)},
+			})}
+			var page funcsPage
+			for _, funcs := range test.funcs {
+				page.components = append(page.components, &funcsComponent{funcs: funcs})
+			}
+			var out strings.Builder
+			result := temple.Render(context.Background(), &out, site, page, temple.WithStrictFuncMaps())
+			if test.wantErr {
+				if !errors.Is(result.Err, temple.ErrFuncMapConflict) {
+					t.Errorf("expected ErrFuncMapConflict, got %v", result.Err)
+				}
+				return
+			}
+			if result.Err != nil {
+				t.Fatalf("unexpected error: %s", result.Err)
+			}
+			if out.String() != test.want {
+				t.Errorf("expected %q, got %q", test.want, out.String())
+			}
+
+			// without WithStrictFuncMaps, conflicts aren't reported
+			out.Reset()
+			result = temple.Render(context.Background(), &out, site, page)
+			if result.Err != nil {
+				t.Errorf("unexpected error without strict FuncMaps: %s", result.Err)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package temple
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"text/template/parse"
+)
+
+// contextFuncsTemplate is the name of an empty template added to parsed
+// template sets that call context funcs, so we know they need to be bound to
+// the context.Context of each render.
+const contextFuncsTemplate = "temple:context-funcs"
+
+// ContextFuncMapExtender is an optional interface for Sites. Those fulfilling
+// it can add template functions that rely on the context.Context of the render
+// they're being used in, like functions that format values for the locale of
+// the current request. Unlike the functions from FuncMapExtender, which are
+// added once when the templates are parsed, these functions are bound to the
+// context.Context of every render.
+//
+// The functions temple supplies itself, like csrfToken, ctxval, and
+// component, take precedence over functions with the same name returned by
+// ContextFuncMap, which take precedence over functions with the same name from
+// FuncMapExtender.
+type ContextFuncMapExtender interface {
+	// ContextFuncMap returns an html/template.FuncMap containing
+	// functions bound to the passed context.Context. It must always
+	// return functions with the same names, regardless of the
+	// context.Context.
+	ContextFuncMap(ctx context.Context) template.FuncMap
+}
+
+// contextFuncs returns the template functions that rely on the
+// context.Context of the render they're being used in. These are available to
+// every template, and override any functions with the same name supplied by a
+// FuncMapExtender.
+//
+// When templates are parsed, these functions are bound to the context.Context
+// used to parse them; before each render, templates that call them get a copy
+// with the functions bound to the context.Context of that render.
+func contextFuncs(ctx context.Context, site Site) template.FuncMap {
+	results := template.FuncMap{}
+	if extender, ok := site.(ContextFuncMapExtender); ok {
+		results = extender.ContextFuncMap(ctx)
+	}
+	return mergeFuncMaps(results, template.FuncMap{
+		"csrfToken": func() (string, error) {
+			return csrfToken(ctx, site)
+		},
+		"ctxval": func(key string) any {
+			val, _ := Value[any](ctx, key)
+			return val
+		},
+		"ctxstring": func(key string) string {
+			val, _ := Value[string](ctx, key)
+			return val
+		},
+		"published": func(topic string) []any {
+			return Published(ctx, topic)
+		},
+		"placeResource": placeResourceFunc(ctx),
+		"component":     unboundComponentFunc,
+	})
+}
+
+// addContextFuncsMarker adds an empty template named contextFuncsTemplate to
+// the template set if any of the templates in it call context funcs.
+func addContextFuncsMarker(ctx context.Context, site Site, tmpl *template.Template) error {
+	names := contextFuncs(ctx, site)
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil || !callsFuncs(t.Tree.Root, names) {
+			continue
+		}
+		_, err := tmpl.New(contextFuncsTemplate).Parse("")
+		if err != nil {
+			return fmt.Errorf("error marking template as using context funcs: %w", err)
+		}
+		return nil
+	}
+	return nil
+}
+
+// bindContextFuncs returns a copy of the template set with the context funcs
+// bound to the passed context.Context, and the component func bound to the
+// copy, if the template set calls any context funcs. If it doesn't, it's
+// returned as-is.
+func bindContextFuncs(ctx context.Context, site Site, tmpl *template.Template) (*template.Template, error) {
+	if tmpl.Lookup(contextFuncsTemplate) == nil {
+		return tmpl, nil
+	}
+	clone, err := tmpl.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("error copying template to bind context funcs: %w", err)
+	}
+	clone = clone.Funcs(contextFuncs(ctx, site))
+	return clone.Funcs(template.FuncMap{
+		"component": componentFunc(ctx, site, clone),
+	}), nil
+}
+
+// callsFuncs returns true if the parse tree rooted at `node` calls any of the
+// functions in `funcs`.
+func callsFuncs(node parse.Node, funcs template.FuncMap) bool {
+	switch n := node.(type) {
+	case nil:
+		return false
+	case *parse.IdentifierNode:
+		_, ok := funcs[n.Ident]
+		return ok
+	case *parse.ListNode:
+		if n == nil {
+			return false
+		}
+		for _, child := range n.Nodes {
+			if callsFuncs(child, funcs) {
+				return true
+			}
+		}
+	case *parse.ActionNode:
+		return callsFuncs(n.Pipe, funcs)
+	case *parse.PipeNode:
+		if n == nil {
+			return false
+		}
+		for _, cmd := range n.Cmds {
+			if callsFuncs(cmd, funcs) {
+				return true
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if callsFuncs(arg, funcs) {
+				return true
+			}
+		}
+	case *parse.ChainNode:
+		return callsFuncs(n.Node, funcs)
+	case *parse.IfNode:
+		return callsFuncs(n.Pipe, funcs) || callsFuncs(n.List, funcs) || callsFuncs(n.ElseList, funcs)
+	case *parse.RangeNode:
+		return callsFuncs(n.Pipe, funcs) || callsFuncs(n.List, funcs) || callsFuncs(n.ElseList, funcs)
+	case *parse.WithNode:
+		return callsFuncs(n.Pipe, funcs) || callsFuncs(n.List, funcs) || callsFuncs(n.ElseList, funcs)
+	case *parse.TemplateNode:
+		return callsFuncs(n.Pipe, funcs)
+	}
+	return false
+}
@@ -1,14 +1,17 @@
 module impractical.co/temple
 
-go 1.21
+go 1.23.0
 
 require (
+	github.com/rivo/uniseg v0.4.7
 	go.opentelemetry.io/otel v1.28.0
+	go.opentelemetry.io/otel/metric v1.28.0
 	go.opentelemetry.io/otel/trace v1.28.0
+	golang.org/x/net v0.41.0
+	golang.org/x/text v0.26.0
 )
 
 require (
 	github.com/go-logr/logr v1.4.2 // indirect
 	github.com/go-logr/stdr v1.2.2 // indirect
-	go.opentelemetry.io/otel/metric v1.28.0 // indirect
 )
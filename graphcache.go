@@ -0,0 +1,131 @@
+package temple
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// ComponentGraph is the resolved tree of Components used by a page, as cached
+// by a GraphCacher. It doesn't include the page itself, so the page being
+// rendered is always used. It includes the values published while the tree
+// was resolved, so they can be published again when it's retrieved.
+type ComponentGraph struct {
+	components []Component
+	published  map[string][]any
+}
+
+// GraphCacher is an optional interface for Sites. Those fulfilling it can
+// cache the Components each page uses, using the output of Key from each
+// Renderable, to save on resolving the tree of Components on every render.
+//
+// Only the page being rendered is used as-is; every other Component, and the
+// CSS, JavaScript, and data it supplies, comes from the cached
+// ComponentGraph. Sites should only implement GraphCacher if the Components
+// their pages use, and the resources those Components supply, are the same
+// every time for a given key.
+type GraphCacher interface {
+	// GetCachedGraph returns the ComponentGraph cached for the key, or
+	// nil if there isn't one.
+	GetCachedGraph(ctx context.Context, key string) *ComponentGraph
+
+	// SetCachedGraph caches the ComponentGraph for the key.
+	SetCachedGraph(ctx context.Context, key string, graph *ComponentGraph)
+}
+
+var _ GraphCacher = &GraphCache{}
+var _ GraphCacheInvalidator = &GraphCache{}
+var _ GraphCacheStatser = &GraphCache{}
+
+// GraphCache is an implementation of the GraphCacher interface that can be
+// embedded in Site implementations, caching ComponentGraphs in memory. A
+// GraphCache must be instantiated through NewGraphCache, its empty value is
+// not usable.
+type GraphCache struct {
+	graphs   map[string]*ComponentGraph
+	graphsMu sync.RWMutex
+}
+
+// NewGraphCache returns a GraphCache instance that is ready to be used.
+func NewGraphCache() *GraphCache {
+	return &GraphCache{
+		graphs: map[string]*ComponentGraph{},
+	}
+}
+
+// GetCachedGraph returns the ComponentGraph associated with the passed key,
+// if one exists. If no ComponentGraph is cached for that key, it returns nil.
+//
+// It can safely be used by multiple goroutines.
+func (g *GraphCache) GetCachedGraph(_ context.Context, key string) *ComponentGraph {
+	g.graphsMu.RLock()
+	defer g.graphsMu.RUnlock()
+	return g.graphs[key]
+}
+
+// SetCachedGraph caches a ComponentGraph for the given key.
+//
+// It can safely be used by multiple goroutines.
+func (g *GraphCache) SetCachedGraph(_ context.Context, key string, graph *ComponentGraph) {
+	g.graphsMu.Lock()
+	defer g.graphsMu.Unlock()
+	g.graphs[key] = graph
+}
+
+// InvalidateCachedGraphs removes the ComponentGraphs cached for each of the
+// keys, so they'll be resolved again the next time they're needed.
+//
+// It can safely be used by multiple goroutines.
+func (g *GraphCache) InvalidateCachedGraphs(_ context.Context, keys ...string) {
+	g.graphsMu.Lock()
+	defer g.graphsMu.Unlock()
+	for _, key := range keys {
+		delete(g.graphs, key)
+	}
+}
+
+// GraphCacheStats returns statistics about the ComponentGraphs currently
+// cached, including an estimate of how much memory they use.
+//
+// It can safely be used by multiple goroutines.
+func (g *GraphCache) GraphCacheStats(_ context.Context) GraphCacheStats {
+	g.graphsMu.RLock()
+	defer g.graphsMu.RUnlock()
+	var stats GraphCacheStats
+	for _, graph := range g.graphs {
+		stats.add(graph)
+	}
+	return stats
+}
+
+// getCachedComponents returns the page and the Components in its cached
+// ComponentGraph, if the Site is a GraphCacher with a ComponentGraph cached
+// for the page's key. The values published while the ComponentGraph was
+// resolved are published again.
+func getCachedComponents(ctx context.Context, site Site, page Renderable) ([]Component, bool) {
+	cache, ok := site.(GraphCacher)
+	if !ok {
+		return nil, false
+	}
+	graph := cache.GetCachedGraph(ctx, themedKey(ctx, page.Key(ctx)))
+	if graph == nil {
+		return nil, false
+	}
+	restorePublished(ctx, graph.published)
+	return append([]Component{page}, graph.components...), true
+}
+
+// setCachedComponents caches the Components the page uses, and the values
+// they published, if the Site is a GraphCacher.
+func setCachedComponents(ctx context.Context, site Site, page Renderable, components []Component) {
+	cache, ok := site.(GraphCacher)
+	if !ok {
+		return
+	}
+	// the page is always the first Component, and we never want to
+	// cache it
+	cache.SetCachedGraph(ctx, themedKey(ctx, page.Key(ctx)), &ComponentGraph{
+		components: slices.Clone(components[1:]),
+		published:  snapshotPublished(ctx),
+	})
+}
@@ -0,0 +1,96 @@
+package temple
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"slices"
+)
+
+// HeaderSetter is an interface that Sites, Renderables, and Components can
+// fulfill to add headers to the response when Render is writing to an
+// http.ResponseWriter. It lets headers like Content-Security-Policy, Link,
+// or Permissions-Policy be declared next to the resources that need them,
+// instead of in every handler that renders a page using them.
+//
+// The headers of the Site, then the Renderable, then every Component it uses,
+// in order, are merged into the response's headers: each value is added to
+// the values already set for the header, unless it's already one of them.
+// Headers that can only have a single value, like Content-Type, should only
+// be set by one of them. Headers temple sets itself, like Cache-Control from
+// a CachePolicy, replace any set by a HeaderSetter.
+//
+// If the page fails to render, every header value Render added is removed
+// before the server error page is rendered, leaving the headers the response
+// had before Render was called.
+type HeaderSetter interface {
+	// Headers returns the headers to add to the response.
+	Headers(ctx context.Context) http.Header
+}
+
+// getHeaders returns the merged headers of the Site and Components, if they
+// implement HeaderSetter.
+func getHeaders(ctx context.Context, site Site, components []Component) http.Header {
+	results := http.Header{}
+	if setter, ok := site.(HeaderSetter); ok {
+		mergeHeaders(results, setter.Headers(ctx))
+	}
+	for _, comp := range components {
+		setter, ok := comp.(HeaderSetter)
+		if !ok {
+			continue
+		}
+		mergeHeaders(results, setter.Headers(ctx))
+	}
+	return results
+}
+
+// mergeHeaders adds each value in src to dst, unless dst already has it.
+func mergeHeaders(dst, src http.Header) {
+	for key, vals := range src {
+		for _, val := range vals {
+			if slices.Contains(dst.Values(key), val) {
+				continue
+			}
+			dst.Add(key, val)
+		}
+	}
+}
+
+// setHeaders merges the headers into the response's headers, if `out` is an
+// http.ResponseWriter.
+func setHeaders(out io.Writer, headers http.Header) {
+	resp, ok := out.(http.ResponseWriter)
+	if !ok || len(headers) < 1 {
+		return
+	}
+	mergeHeaders(resp.Header(), headers)
+}
+
+// snapshotHeaders returns a copy of the response's headers, if `out` is an
+// http.ResponseWriter, so they can be restored if the page fails to render.
+func snapshotHeaders(out io.Writer) http.Header {
+	resp, ok := out.(http.ResponseWriter)
+	if !ok {
+		return nil
+	}
+	return resp.Header().Clone()
+}
+
+// restoreHeaders resets the response's headers to the snapshot, if `out` is
+// an http.ResponseWriter, removing every value added since it was taken and
+// restoring any that were replaced, while keeping the headers that were set
+// before rendering started, like the ones set by the handler.
+func restoreHeaders(out io.Writer, snapshot http.Header) {
+	resp, ok := out.(http.ResponseWriter)
+	if !ok {
+		return
+	}
+	header := resp.Header()
+	for key := range header {
+		delete(header, key)
+	}
+	for key, vals := range snapshot {
+		header[key] = vals
+	}
+}
@@ -0,0 +1,73 @@
+package temple_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"impractical.co/temple"
+)
+
+func TestHeaderSetterErrorPage(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		fail bool
+		opts []temple.RenderOption
+		want http.Header
+	}{
+		"rendered": {
+			want: http.Header{
+				"Content-Security-Policy": {"default-src 'self'", "script-src 'self'"},
+				"Link":                    {"</handler.css>; rel=preload; as=style", "</page.css>; rel=preload; as=style"},
+				"X-Page":                  {"yes"},
+			},
+		},
+		"failed": {
+			fail: true,
+			want: http.Header{
+				"Content-Security-Policy": {"default-src 'self'"},
+				"Link":                    {"</handler.css>; rel=preload; as=style"},
+			},
+		},
+		"failed-streaming": {
+			fail: true,
+			opts: []temple.RenderOption{temple.WithStreaming(1024)},
+			want: http.Header{
+				"Content-Security-Policy": {"default-src 'self'"},
+				"Link":                    {"</handler.css>; rel=preload; as=style"},
+			},
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			site := temple.NewCachedSite(fstest.MapFS{
+				"page.html.tmpl": {Data: []byte(`{{ .Page.Check }}`)},
+			})
+			resp := httptest.NewRecorder()
+			// headers the handler set before rendering are kept, even
+			// if the page fails
+			resp.Header().Set("Content-Security-Policy", "default-src 'self'")
+			resp.Header().Set("Link", "</handler.css>; rel=preload; as=style")
+			page := testPage{
+				headers: http.Header{
+					"Content-Security-Policy": {"script-src 'self'"},
+					"Link":                    {"</page.css>; rel=preload; as=style"},
+					"X-Page":                  {"yes"},
+				},
+				fail: test.fail,
+			}
+			temple.Render(context.Background(), resp, site, page, test.opts...)
+			for _, header := range []string{"Content-Security-Policy", "Link", "X-Page"} {
+				if got := resp.Header().Values(header); !slices.Equal(got, test.want[header]) {
+					t.Errorf("expected %s %q, got %q", header, test.want[header], got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package highlight_test
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"html/template"
+	"os"
+	"regexp"
+	"testing/fstest"
+
+	"impractical.co/temple"
+	"impractical.co/temple/highlight"
+)
+
+type DocsPage struct {
+	Example highlight.CodeBlock
+}
+
+func (DocsPage) Templates(_ context.Context) []string {
+	return []string{"docs.html.tmpl"}
+}
+
+func (p DocsPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{p.Example}
+}
+
+func (DocsPage) Key(_ context.Context) string {
+	return "docs.html.tmpl"
+}
+
+func (DocsPage) ExecutedTemplate(_ context.Context) string {
+	return "docs.html.tmpl"
+}
+
+// keywords is a stand-in for a real highlighter, like chroma, that only
+// highlights a few Go keywords
+type keywords struct{}
+
+var keywordPattern = regexp.MustCompile(`\b(func|return)\b`)
+
+func (keywords) Highlight(code, _ string) (template.HTML, error) {
+	return template.HTML(keywordPattern.ReplaceAllString(html.EscapeString(code), `<span class="k">$1</span>`)), nil
+}
+
+func (keywords) CSS() template.CSS {
+	return ".k { color: purple; }\n"
+}
+
+func Example() {
+	templates := fstest.MapFS{
+		"docs.html.tmpl": {Data: []byte(`<style>{{ .EmbeddedCSS }}</style>
+{{ template "highlight/code_block.html.tmpl" .Page.Example }}`)},
+	}
+	site := temple.NewCachedSite(templates)
+
+	temple.Render(context.Background(), os.Stdout, site, DocsPage{
+		Example: highlight.CodeBlock{
+			Code:        `func answer() int { return 42 }`,
+			Language:    "go",
+			Highlighter: keywords{},
+		},
+	})
+	fmt.Println()
+
+	//Output:
+	// <style>
+	// /* embedded CSS from highlight.CodeBlock */
+	//
+	// .temple-code { overflow-x: auto; padding: 1em; }
+	// .k { color: purple; }
+	// </style>
+	// <pre class="temple-code" data-language="go"><code class="language-go"><span class="k">func</span> answer() int { <span class="k">return</span> 42 }</code></pre>
+}
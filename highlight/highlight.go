@@ -0,0 +1,116 @@
+// Package highlight provides a temple Component for rendering blocks of
+// source code with syntax highlighting, server-side, so pages don't need any
+// JavaScript to highlight their code.
+//
+// temple doesn't include a syntax highlighter. Any highlighter that can
+// output HTML using CSS classes can be used by wrapping it in a Highlighter;
+// for chroma, that looks like:
+//
+//	type Chroma struct {
+//		Style *chroma.Style
+//	}
+//
+//	func (c Chroma) Highlight(code, language string) (template.HTML, error) {
+//		lexer := lexers.Get(language)
+//		if lexer == nil {
+//			lexer = lexers.Fallback
+//		}
+//		tokens, err := lexer.Tokenise(nil, code)
+//		if err != nil {
+//			return "", err
+//		}
+//		var buf bytes.Buffer
+//		err = html.New(html.WithClasses(true), html.PreventSurroundingPre(true)).Format(&buf, c.Style, tokens)
+//		return template.HTML(buf.String()), err
+//	}
+//
+//	func (c Chroma) CSS() template.CSS {
+//		var buf bytes.Buffer
+//		_ = html.New(html.WithClasses(true)).WriteCSS(&buf, c.Style)
+//		return template.CSS(buf.String())
+//	}
+//
+// The CodeBlock Component supplies its own template and embeds the CSS it
+// and its Highlighter need, so all that's required to use it is to include
+// it in the UseComponents output of a Component and execute its template:
+//
+//	{{ template "highlight/code_block.html.tmpl" .Page.Example }}
+package highlight
+
+import (
+	"context"
+	"embed"
+	"html"
+	"html/template"
+	"io/fs"
+
+	"impractical.co/temple"
+)
+
+//go:embed templates
+var templates embed.FS
+
+const css = `
+.temple-code { overflow-x: auto; padding: 1em; }
+`
+
+// Highlighter renders source code as HTML with syntax highlighting.
+type Highlighter interface {
+	// Highlight returns the code, in the named language, as HTML. It
+	// shouldn't include the surrounding <pre> or <code> elements.
+	Highlight(code, language string) (template.HTML, error)
+
+	// CSS returns the styles the HTML returned by Highlight needs.
+	CSS() template.CSS
+}
+
+var (
+	_ temple.Component           = CodeBlock{}
+	_ temple.TemplateDirProvider = CodeBlock{}
+	_ temple.CSSEmbedder         = CodeBlock{}
+)
+
+// CodeBlock is a Component that renders a block of source code, highlighted
+// by its Highlighter. The styles the Highlighter needs are embedded in the
+// page once, no matter how many CodeBlocks use it.
+type CodeBlock struct {
+	// Code is the source code to render.
+	Code string
+
+	// Language is the name of the language the Code is in, like "go".
+	// It's passed to the Highlighter, and used to set the
+	// language-{{ .Language }} class on the <code> element.
+	Language string
+
+	// Highlighter highlights the Code. If nil, the Code is rendered
+	// without highlighting.
+	Highlighter Highlighter
+}
+
+// Templates returns the template needed to render the CodeBlock.
+func (CodeBlock) Templates(_ context.Context) []string {
+	return []string{"highlight/code_block.html.tmpl"}
+}
+
+// TemplateDir returns the fs.FS containing the CodeBlock's template.
+func (CodeBlock) TemplateDir(_ context.Context) fs.FS {
+	return temple.SubFS(templates, "templates")
+}
+
+// EmbedCSS returns the CSS used to style CodeBlocks, along with the CSS the
+// Highlighter needs.
+func (c CodeBlock) EmbedCSS(_ context.Context) template.CSS {
+	if c.Highlighter == nil {
+		return css
+	}
+	return css + c.Highlighter.CSS()
+}
+
+// HTML returns the Code as HTML, highlighted by the Highlighter if there is
+// one.
+func (c CodeBlock) HTML() (template.HTML, error) {
+	if c.Highlighter == nil {
+		return template.HTML(html.EscapeString(c.Code)), nil // #nosec G203
+	}
+	return c.Highlighter.Highlight(c.Code, c.Language)
+}
@@ -0,0 +1,8 @@
+package highlight
+
+import "impractical.co/temple"
+
+// Package returns the temple.ComponentPackage for CodeBlock.
+func Package() (temple.ComponentPackage, error) {
+	return temple.NewComponentPackage("highlight", templates, "templates/highlight", "", CodeBlock{})
+}
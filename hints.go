@@ -0,0 +1,112 @@
+package temple
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WithEarlyHints is a RenderOption that, when Render is writing to an
+// http.ResponseWriter, sets a Link header preloading each of the page's linked
+// CSS and JavaScript files and sends a 103 Early Hints response with those
+// headers before the page is rendered. Browsers that support Early Hints can
+// start loading those files while the page is still rendering; those that
+// don't will ignore the 103 response.
+//
+// The Link headers remain set on the final response, as well, unless the page
+// fails to render, in which case they're removed before the server error page
+// is rendered.
+//
+// If Render isn't writing to an http.ResponseWriter, WithEarlyHints does
+// nothing.
+func WithEarlyHints() RenderOption {
+	return func(opts *renderOptions) {
+		opts.earlyHints = true
+	}
+}
+
+// WithPreloadHeaders is a RenderOption that, when Render is writing to an
+// http.ResponseWriter, sets a Link header preloading the page's
+// highest-priority CSS and JavaScript files on the response, without sending
+// a 103 Early Hints response. Some CDNs turn these headers into Early Hints or
+// server pushes of their own.
+//
+// Resources are prioritized in the order they appear in the document, with
+// critical stylesheets first, then linked stylesheets, then scripts. Only the
+// first limit resources get a Link header; if limit is less than 1, they all
+// do.
+//
+// If WithEarlyHints is also used, its Link headers are left as-is, and
+// WithPreloadHeaders does nothing. If Render isn't writing to an
+// http.ResponseWriter, WithPreloadHeaders does nothing.
+func WithPreloadHeaders(limit int) RenderOption {
+	return func(opts *renderOptions) {
+		opts.preloadHeaders = true
+		opts.preloadLimit = limit
+	}
+}
+
+// preload is a resource that should be preloaded by browsers.
+type preload struct {
+	url      string
+	as       string
+	priority FetchPriority
+}
+
+// linkHeader returns the value of the Link header that preloads the resource.
+func (p preload) linkHeader() string {
+	if p.priority != "" {
+		return fmt.Sprintf("<%s>; rel=preload; as=%s; fetchpriority=%s", p.url, p.as, p.priority)
+	}
+	return fmt.Sprintf("<%s>; rel=preload; as=%s", p.url, p.as)
+}
+
+// preloads returns the resources linked to by the page being rendered, in the
+// order they'll appear in the document.
+func (r RenderData[SiteType, PageType]) preloads() []preload {
+	results := make([]preload, 0, len(r.PreloadedCSS)+len(r.LinkedCSS)+len(r.LinkedJS))
+	for _, url := range r.PreloadedCSS {
+		results = append(results, preload{url: url, as: "style", priority: r.FetchPriorities[url]})
+	}
+	for _, url := range r.LinkedCSS {
+		results = append(results, preload{url: url, as: "style", priority: r.FetchPriorities[url]})
+	}
+	for _, url := range r.LinkedJS {
+		results = append(results, preload{url: url, as: "script", priority: r.FetchPriorities[url]})
+	}
+	return results
+}
+
+// setPreloadHeaders adds a Link header to `out` for each of the passed
+// preloads, if `out` is an http.ResponseWriter. It returns false if `out`
+// isn't an http.ResponseWriter or there's nothing to preload.
+func setPreloadHeaders(out io.Writer, preloads []preload) bool {
+	resp, ok := out.(http.ResponseWriter)
+	if !ok || len(preloads) < 1 {
+		return false
+	}
+	for _, pre := range preloads {
+		resp.Header().Add("Link", pre.linkHeader())
+	}
+	return true
+}
+
+// setPagePreloadHeaders sets Link headers for the first `limit` preloads, or
+// all of them if `limit` is less than 1, if `out` is an http.ResponseWriter.
+func setPagePreloadHeaders(out io.Writer, preloads []preload, limit int) {
+	if limit > 0 && len(preloads) > limit {
+		preloads = preloads[:limit]
+	}
+	setPreloadHeaders(out, preloads)
+}
+
+// sendEarlyHints sets Link headers for the passed preloads and writes a 103
+// Early Hints response, if `out` is an http.ResponseWriter.
+func sendEarlyHints(ctx context.Context, out io.Writer, preloads []preload) {
+	if !setPreloadHeaders(out, preloads) {
+		return
+	}
+	out.(http.ResponseWriter).WriteHeader(http.StatusEarlyHints)
+	logger(ctx).DebugContext(ctx, "sent early hints", "preloads", len(preloads))
+}
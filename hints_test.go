@@ -0,0 +1,83 @@
+package temple_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"impractical.co/temple"
+)
+
+func TestWithEarlyHints(t *testing.T) {
+	t.Parallel()
+
+	preloads := []string{"</styles.css>; rel=preload; as=style"}
+	cases := map[string]struct {
+		fail       bool
+		wantStatus int
+		wantFinal  []string
+	}{
+		"rendered": {
+			wantStatus: http.StatusOK,
+			wantFinal:  preloads,
+		},
+		"failed": {
+			fail:       true,
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			site := temple.NewCachedSite(fstest.MapFS{
+				"page.html.tmpl": {Data: []byte(`{{ .Page.Check }}`)},
+			})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				temple.Render(r.Context(), w, site, testPage{components: []temple.Component{respondingStyles{}}, fail: test.fail}, temple.WithEarlyHints())
+			}))
+			defer server.Close()
+
+			var hints [][]string
+			trace := &httptrace.ClientTrace{
+				Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+					if code == http.StatusEarlyHints {
+						hints = append(hints, header.Values("Link"))
+					}
+					return nil
+				},
+			}
+			req, err := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), http.MethodGet, server.URL, nil)
+			if err != nil {
+				t.Fatalf("error creating request: %s", err)
+			}
+			resp, err := server.Client().Do(req)
+			if err != nil {
+				t.Fatalf("error making request: %s", err)
+			}
+			defer resp.Body.Close()
+			_, err = io.Copy(io.Discard, resp.Body)
+			if err != nil {
+				t.Fatalf("error reading response: %s", err)
+			}
+
+			// the hints are sent before the page renders, so they're
+			// sent whether it fails or not
+			if len(hints) != 1 || !slices.Equal(hints[0], preloads) {
+				t.Errorf("expected one 103 response with Link headers %q, got %q", preloads, hints)
+			}
+			if resp.StatusCode != test.wantStatus {
+				t.Errorf("expected status %d, got %d", test.wantStatus, resp.StatusCode)
+			}
+			if got := resp.Header.Values("Link"); !slices.Equal(got, test.wantFinal) {
+				t.Errorf("expected final Link headers %q, got %q", test.wantFinal, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package temple
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	xhtml "golang.org/x/net/html"
+)
+
+var (
+	// ErrInvalidHTML is returned by HTMLValidator when it's strict and a
+	// page has structural problems.
+	ErrInvalidHTML = errors.New("invalid HTML")
+)
+
+// HTMLIssueKind identifies the kind of problem an HTMLIssue describes.
+type HTMLIssueKind string
+
+const (
+	// HTMLUnclosedElement means an element that needs an end tag was
+	// never closed, or was closed after an element it contains.
+	HTMLUnclosedElement HTMLIssueKind = "unclosed-element"
+
+	// HTMLUnexpectedEndTag means there's an end tag for an element that
+	// isn't open.
+	HTMLUnexpectedEndTag HTMLIssueKind = "unexpected-end-tag"
+
+	// HTMLVoidEndTag means there's an end tag for a void element, like
+	// </br> or </img>, which can't have one.
+	HTMLVoidEndTag HTMLIssueKind = "void-end-tag"
+
+	// HTMLInvalidContext means an element is somewhere it isn't allowed,
+	// like a <div> inside the <head>, an <li> outside of a list, or a
+	// <form> inside another <form>. Browsers move or close elements to fix these, so the page
+	// usually doesn't have the structure the template intended.
+	HTMLInvalidContext HTMLIssueKind = "invalid-context"
+)
+
+// HTMLIssue is a structural problem found by ValidateHTML.
+type HTMLIssue struct {
+	// Kind is the kind of problem found.
+	Kind HTMLIssueKind
+
+	// Element is the name of the element with the problem, like "div".
+	Element string
+
+	// Parent is the name of the element Element is inside of, if it's
+	// relevant to the problem, or an empty string.
+	Parent string
+
+	// Line is the 1-indexed line of the HTML the problem was found on.
+	Line int
+}
+
+// String returns a description of the HTMLIssue.
+func (issue HTMLIssue) String() string {
+	if issue.Parent != "" {
+		return fmt.Sprintf("line %d: %s: <%s> inside <%s>", issue.Line, issue.Kind, issue.Element, issue.Parent)
+	}
+	return fmt.Sprintf("line %d: %s: <%s>", issue.Line, issue.Kind, issue.Element)
+}
+
+var (
+	// voidElements can't have contents or end tags.
+	voidElements = []string{"area", "base", "br", "col", "embed", "hr", "img", "input", "link", "meta", "source", "track", "wbr"}
+
+	// optionalEndElements may be closed implicitly, by their parent
+	// closing or a sibling starting.
+	optionalEndElements = []string{"body", "caption", "colgroup", "dd", "dt", "head", "html", "li", "optgroup", "option", "p", "rp", "rt", "tbody", "td", "tfoot", "th", "thead", "tr"}
+
+	// impliedEndTags are the start tags that implicitly close each
+	// optionalEndElements element when it's the innermost open element,
+	// following the HTML spec's rules for omitting end tags.
+	impliedEndTags = map[string][]string{
+		"head":     {"body"},
+		"li":       {"li"},
+		"dt":       {"dt", "dd"},
+		"dd":       {"dt", "dd"},
+		"p":        blockElements,
+		"rt":       {"rt", "rp"},
+		"rp":       {"rt", "rp"},
+		"optgroup": {"optgroup"},
+		"option":   {"option", "optgroup"},
+		"colgroup": {"caption", "colgroup", "thead", "tbody", "tfoot", "tr"},
+		"caption":  {"caption", "colgroup", "thead", "tbody", "tfoot", "tr"},
+		"thead":    {"tbody", "tfoot"},
+		"tbody":    {"tbody", "tfoot"},
+		"tr":       {"tr", "tbody", "tfoot"},
+		"td":       {"td", "th", "tr", "tbody", "tfoot"},
+		"th":       {"td", "th", "tr", "tbody", "tfoot"},
+	}
+
+	// headElements are the only elements allowed directly inside <head>.
+	headElements = []string{"base", "link", "meta", "noscript", "script", "style", "template", "title"}
+
+	// blockElements close an open <p> when they start, so they can't be
+	// inside one; a </p> after them is an unexpected end tag.
+	blockElements = []string{"address", "article", "aside", "blockquote", "details", "div", "dl", "fieldset", "figcaption", "figure", "footer", "form", "h1", "h2", "h3", "h4", "h5", "h6", "header", "hgroup", "hr", "main", "menu", "nav", "ol", "p", "pre", "section", "table", "ul"}
+
+	// requiredParents are elements that are only allowed inside one of
+	// the listed elements.
+	requiredParents = map[string][]string{
+		"li":     {"ul", "ol", "menu"},
+		"dt":     {"dl", "div"},
+		"dd":     {"dl", "div"},
+		"tr":     {"table", "thead", "tbody", "tfoot"},
+		"td":     {"tr"},
+		"th":     {"tr"},
+		"thead":  {"table"},
+		"tbody":  {"table"},
+		"tfoot":  {"table"},
+		"option": {"select", "datalist", "optgroup"},
+	}
+
+	// unnestableElements can't be inside another element of the same
+	// kind.
+	unnestableElements = []string{"a", "button", "form", "label"}
+)
+
+// ValidateHTML parses an HTML document and returns the structural problems
+// in it, in the order they appear: elements that are never closed, end tags
+// that don't match an open element, and elements in contexts they aren't
+// allowed in. Browsers silently fix these, usually by restructuring the page,
+// so they tend to show up as layout bugs far from the template that caused
+// them.
+//
+// It's a quick check for template bugs, not a conformance checker, and only
+// knows about the most common rules. Elements whose end tags are optional,
+// like <li> and <p>, aren't reported as unclosed, and are closed by the start
+// tags that imply their end, so <ul><li>a<li>b</ul> and <p>a<p>b are valid.
+func ValidateHTML(html []byte) []HTMLIssue {
+	var issues []HTMLIssue
+	var open []string
+	line := 1
+	tokenizer := xhtml.NewTokenizer(bytes.NewReader(html))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == xhtml.ErrorToken {
+			break
+		}
+		tokenLine := line
+		line += bytes.Count(tokenizer.Raw(), []byte("\n"))
+		name, _ := tokenizer.TagName()
+		tag := string(name)
+		switch tokenType {
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			open = closeImplied(open, tag)
+			if parent, ok := invalidContext(open, tag); !ok {
+				issues = append(issues, HTMLIssue{Kind: HTMLInvalidContext, Element: tag, Parent: parent, Line: tokenLine})
+			}
+			if tokenType == xhtml.StartTagToken && !slices.Contains(voidElements, tag) {
+				open = append(open, tag)
+			}
+		case xhtml.EndTagToken:
+			if slices.Contains(voidElements, tag) {
+				issues = append(issues, HTMLIssue{Kind: HTMLVoidEndTag, Element: tag, Line: tokenLine})
+				continue
+			}
+			idx := lastIndex(open, tag)
+			if idx < 0 {
+				issues = append(issues, HTMLIssue{Kind: HTMLUnexpectedEndTag, Element: tag, Line: tokenLine})
+				continue
+			}
+			for _, unclosed := range open[idx+1:] {
+				if !slices.Contains(optionalEndElements, unclosed) {
+					issues = append(issues, HTMLIssue{Kind: HTMLUnclosedElement, Element: unclosed, Parent: tag, Line: tokenLine})
+				}
+			}
+			open = open[:idx]
+		}
+	}
+	for _, unclosed := range open {
+		if !slices.Contains(optionalEndElements, unclosed) {
+			issues = append(issues, HTMLIssue{Kind: HTMLUnclosedElement, Element: unclosed, Line: line})
+		}
+	}
+	return issues
+}
+
+// lastIndex returns the index of the last occurrence of tag in open, or -1 if
+// it isn't there.
+func lastIndex(open []string, tag string) int {
+	for i := len(open) - 1; i >= 0; i-- {
+		if open[i] == tag {
+			return i
+		}
+	}
+	return -1
+}
+
+// closeImplied pops the elements off the end of open that a start tag for the
+// element implicitly closes.
+func closeImplied(open []string, tag string) []string {
+	for len(open) > 0 {
+		innermost := open[len(open)-1]
+		if !slices.Contains(impliedEndTags[innermost], tag) {
+			break
+		}
+		open = open[:len(open)-1]
+	}
+	return open
+}
+
+// invalidContext checks whether the element is allowed inside the open
+// elements, returning false and the name of the element it isn't allowed in
+// if not.
+func invalidContext(open []string, tag string) (string, bool) {
+	var parent string
+	if len(open) > 0 {
+		parent = open[len(open)-1]
+	}
+	// <template> contents are parsed separately, so anything goes
+	if slices.Contains(open, "template") {
+		return "", true
+	}
+	if parent == "head" && !slices.Contains(headElements, tag) {
+		return parent, false
+	}
+	if parents, ok := requiredParents[tag]; ok && !slices.Contains(parents, parent) {
+		return parent, false
+	}
+	if slices.Contains(unnestableElements, tag) && slices.Contains(open, tag) {
+		return tag, false
+	}
+	return "", true
+}
+
+var _ OutputFilter = HTMLValidator{}
+
+// HTMLValidator is an OutputFilter that runs ValidateHTML on every page
+// rendered with it, leaving the output unchanged. It's meant to be used in
+// development and tests:
+//
+//	temple.Render(ctx, w, site, page, temple.WithOutputFilters(temple.HTMLValidator{Strict: true}))
+type HTMLValidator struct {
+	// Report is called with the issues found on each page that has any.
+	// If nil, each issue is logged as a warning using the slog.Logger
+	// from LoggingContext.
+	Report func(ctx context.Context, issues []HTMLIssue)
+
+	// Strict makes pages with any issues fail to render, with an error
+	// wrapping ErrInvalidHTML, after they've been reported.
+	Strict bool
+}
+
+// FilterOutput validates the HTML, reporting any issues found, and returns it
+// unchanged.
+func (validator HTMLValidator) FilterOutput(ctx context.Context, html []byte) ([]byte, error) {
+	issues := ValidateHTML(html)
+	if len(issues) < 1 {
+		return html, nil
+	}
+	if validator.Report != nil {
+		validator.Report(ctx, issues)
+	} else {
+		for _, issue := range issues {
+			logger(ctx).WarnContext(ctx, "invalid HTML", "kind", string(issue.Kind), "element", issue.Element, "parent", issue.Parent, "line", issue.Line)
+		}
+	}
+	if validator.Strict {
+		return nil, fmt.Errorf("%w: %d issues, first %s", ErrInvalidHTML, len(issues), issues[0])
+	}
+	return html, nil
+}
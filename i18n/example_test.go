@@ -0,0 +1,120 @@
+package i18n_test
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"os"
+	"testing/fstest"
+	"time"
+
+	"golang.org/x/text/language"
+
+	"impractical.co/temple"
+	"impractical.co/temple/i18n"
+)
+
+func Example() {
+	published := time.Date(2024, time.March, 9, 12, 0, 0, 0, time.UTC)
+	for _, locale := range []language.Tag{language.AmericanEnglish, language.BritishEnglish, language.German} {
+		price, err := i18n.FormatCurrency(locale, 1234.5, "EUR")
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(i18n.FormatDate(locale, published), i18n.FormatNumber(locale, 1234567.891), price)
+	}
+	fmt.Println(i18n.RelativeTime(language.English, published, published.Add(3*time.Hour)))
+	fmt.Println(i18n.RelativeTime(language.English, published, published.Add(-26*time.Hour)))
+	fmt.Println(i18n.FormatDate(language.French, published), "/", i18n.RelativeTime(language.French, published, published.Add(3*time.Hour)))
+	fmt.Println(i18n.FormatDate(language.Spanish, published), "/", i18n.RelativeTime(language.Spanish, published, published.Add(-time.Hour)))
+
+	//Output:
+	// Mar 9, 2024 1,234,567.891 € 1,234.50
+	// 9 Mar 2024 1,234,567.891 € 1,234.50
+	// 9. März 2024 1.234.567,891 € 1.234,50
+	// 3 hours ago
+	// in 1 day
+	// 9 mars 2024 / il y a 3 heures
+	// 9 mar 2024 / dentro de 1 hora
+}
+
+type Site struct {
+	*temple.CachedSite
+}
+
+func (Site) ContextFuncMap(ctx context.Context) template.FuncMap {
+	return i18n.Funcs(ctx)
+}
+
+type ReceiptPage struct {
+	Total float64
+}
+
+func (ReceiptPage) Templates(_ context.Context) []string {
+	return []string{"receipt.html.tmpl"}
+}
+
+func (ReceiptPage) Key(_ context.Context) string {
+	return "receipt.html.tmpl"
+}
+
+func (ReceiptPage) ExecutedTemplate(_ context.Context) string {
+	return "receipt.html.tmpl"
+}
+
+func ExampleFuncs() {
+	templates := fstest.MapFS{
+		"receipt.html.tmpl": {Data: []byte(`Total: {{ formatCurrency .Page.Total "USD" }}`)},
+	}
+	site := Site{CachedSite: temple.NewCachedSite(templates)}
+
+	// usually middleware would set the locale based on the request
+	ctx := i18n.WithLocale(context.Background(), language.AmericanEnglish)
+	temple.Render(ctx, os.Stdout, site, ReceiptPage{Total: 4321})
+
+	//Output:
+	// Total: $ 4,321.00
+}
+
+type EventPage struct {
+	Starts i18n.Time
+}
+
+func (EventPage) Templates(_ context.Context) []string {
+	return []string{"event.html.tmpl"}
+}
+
+func (e EventPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{e.Starts}
+}
+
+func (EventPage) Key(_ context.Context) string {
+	return "event.html.tmpl"
+}
+
+func (EventPage) ExecutedTemplate(_ context.Context) string {
+	return "event.html.tmpl"
+}
+
+func ExampleTime() {
+	templates := fstest.MapFS{
+		"event.html.tmpl": {Data: []byte(`Starts {{ template "i18n/time.html.tmpl" .Page.Starts }}`)},
+	}
+	site := Site{CachedSite: temple.NewCachedSite(templates)}
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		panic(err)
+	}
+	// usually middleware would set the time zone based on the user's
+	// preferences
+	ctx := i18n.WithTimeZone(context.Background(), tokyo)
+
+	starts := time.Date(2024, time.March, 9, 18, 30, 0, 0, time.UTC)
+	temple.Render(ctx, os.Stdout, site, EventPage{
+		Starts: i18n.Time{Time: starts, Layout: "Jan 2 15:04 MST"},
+	})
+
+	//Output:
+	// Starts <time datetime="2024-03-09T18:30:00Z">Mar 10 03:30 JST</time>
+}
@@ -0,0 +1,153 @@
+// Package i18n provides locale-aware template functions for formatting
+// dates, numbers, currency amounts, and relative times, so every Site doesn't
+// need to reimplement them.
+//
+// The locale used is stored in the context.Context by WithLocale, usually in
+// middleware that inspects the request. To make the functions available to
+// templates, a Site should implement temple.ContextFuncMapExtender by
+// returning the output of Funcs:
+//
+//	func (s MySite) ContextFuncMap(ctx context.Context) template.FuncMap {
+//		return i18n.Funcs(ctx)
+//	}
+//
+// Templates can then use:
+//
+//	{{ formatDate .Page.Published }}
+//	{{ formatNumber .Page.Views }}
+//	{{ formatCurrency .Page.Price "USD" }}
+//	{{ relativeTime .Page.Updated }}
+//
+// Times can be displayed in the user's time zone, stored in the
+// context.Context by WithTimeZone, using the localtime function, or by
+// rendering them with the Time Component, which includes a machine-readable
+// version of the time for browsers and scripts. The Time Component's template
+// uses the functions from Funcs, so it can only be used by Sites that
+// register them.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"time"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// DefaultLocale is the locale used when none has been set with WithLocale.
+var DefaultLocale = language.AmericanEnglish
+
+type localeCtxKey struct{}
+
+// WithLocale returns a context.Context that will cause the functions returned
+// by Funcs to format values for the passed locale.
+func WithLocale(ctx context.Context, locale language.Tag) context.Context {
+	return context.WithValue(ctx, localeCtxKey{}, locale)
+}
+
+// Locale returns the locale stored in the context.Context by WithLocale, or
+// DefaultLocale if there isn't one.
+func Locale(ctx context.Context) language.Tag {
+	locale, ok := ctx.Value(localeCtxKey{}).(language.Tag)
+	if !ok {
+		return DefaultLocale
+	}
+	return locale
+}
+
+// Funcs returns a template.FuncMap of formatting functions that use the
+// locale and time zone stored in the passed context.Context:
+//
+//   - formatDate formats a time.Time as a date, like "Jan 2, 2006",
+//     "2 Jan 2006", or "2. Jan. 2006".
+//   - formatNumber formats a number with the locale's digit grouping and
+//     decimal separator, like "1,234.5" or "1.234,5".
+//   - formatCurrency formats a number as an amount of the currency with the
+//     passed ISO 4217 code, like "$ 1,234.50".
+//   - relativeTime formats a time.Time relative to now, like "3 hours ago",
+//     "in 2 days", or "vor 3 Stunden".
+//   - localtime converts a time.Time to the time zone stored in the
+//     context.Context by WithTimeZone.
+//   - inTZ converts a time.Time to the time zone with the passed IANA name:
+//     {{ inTZ "Europe/Paris" .Page.Starts }}
+func Funcs(ctx context.Context) template.FuncMap {
+	locale := Locale(ctx)
+	tz := TimeZone(ctx)
+	return template.FuncMap{
+		"formatDate": func(t time.Time) string {
+			return FormatDate(locale, t)
+		},
+		"formatNumber": func(n any) string {
+			return FormatNumber(locale, n)
+		},
+		"formatCurrency": func(amount any, code string) (string, error) {
+			return FormatCurrency(locale, amount, code)
+		},
+		"relativeTime": func(t time.Time) string {
+			return RelativeTime(locale, t, time.Now())
+		},
+		"localtime": func(t time.Time) time.Time {
+			return t.In(tz)
+		},
+		"inTZ": inTZ,
+	}
+}
+
+// FormatDate formats `t` as a date, in the conventional format for the
+// locale, with the month's name in the locale's language. English, French,
+// German, and Spanish month names are supported; other languages fall back on
+// English month names. The month only comes first for English in regions
+// that write it that way, like the United States, so en-US dates look like
+// "Mar 9, 2024", but fr-CA and es-US dates don't.
+func FormatDate(locale language.Tag, t time.Time) string {
+	return formatDate(locale, t)
+}
+
+// FormatNumber formats `n` using the digit grouping and decimal separator of
+// the locale.
+func FormatNumber(locale language.Tag, n any) string {
+	return message.NewPrinter(locale).Sprint(number.Decimal(n))
+}
+
+// FormatCurrency formats `amount` as an amount of the currency identified by
+// the ISO 4217 code, using the conventions of the locale.
+func FormatCurrency(locale language.Tag, amount any, code string) (string, error) {
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return "", fmt.Errorf("error parsing currency %q: %w", code, err)
+	}
+	return message.NewPrinter(locale).Sprint(currency.Symbol(unit.Amount(amount))), nil
+}
+
+// RelativeTime formats `t` relative to `now`, like "3 hours ago" or "in 2
+// days", in the locale's language. Times within a minute of `now` are
+// formatted as "just now". English, French, German, and Spanish are
+// supported; other languages fall back on English.
+func RelativeTime(locale language.Tag, t, now time.Time) string {
+	diff := now.Sub(t)
+	future := diff < 0
+	if future {
+		diff = -diff
+	}
+	var amount int
+	var unit timeUnit
+	switch {
+	case diff < time.Minute:
+		return dataFor(locale).justNow
+	case diff < time.Hour:
+		amount, unit = int(diff/time.Minute), minuteUnit
+	case diff < 24*time.Hour:
+		amount, unit = int(diff/time.Hour), hourUnit
+	case diff < 30*24*time.Hour:
+		amount, unit = int(diff/(24*time.Hour)), dayUnit
+	case diff < 365*24*time.Hour:
+		amount, unit = int(diff/(30*24*time.Hour)), monthUnit
+	default:
+		amount, unit = int(diff/(365*24*time.Hour)), yearUnit
+	}
+	return formatRelative(locale, amount, unit, future)
+}
@@ -0,0 +1,144 @@
+package i18n_test
+
+import (
+	"context"
+	"html/template"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"golang.org/x/text/language"
+
+	"impractical.co/temple"
+	"impractical.co/temple/i18n"
+)
+
+func TestFormatDate(t *testing.T) {
+	t.Parallel()
+
+	date := time.Date(2024, time.January, 5, 12, 0, 0, 0, time.UTC)
+	cases := map[string]string{
+		"en":    "Jan 5, 2024",
+		"en-US": "Jan 5, 2024",
+		"en-CA": "Jan 5, 2024",
+		"en-PH": "Jan 5, 2024",
+		"en-GB": "5 Jan 2024",
+		"en-AU": "5 Jan 2024",
+		"fr":    "5 janv. 2024",
+		"fr-CA": "5 janv. 2024",
+		"es":    "5 ene 2024",
+		"es-US": "5 ene 2024",
+		"de":    "5. Jan. 2024",
+		"ja-US": "5 Jan 2024",
+	}
+
+	for tag, want := range cases {
+		t.Run(tag, func(t *testing.T) {
+			t.Parallel()
+			if got := i18n.FormatDate(language.MustParse(tag), date); got != want {
+				t.Errorf("expected %q, got %q", want, got)
+			}
+		})
+	}
+}
+
+func TestInTZ(t *testing.T) {
+	t.Parallel()
+
+	inTZ, ok := i18n.Funcs(context.Background())["inTZ"].(func(string, time.Time) (time.Time, error))
+	if !ok {
+		t.Fatal("inTZ has an unexpected type")
+	}
+	instant := time.Date(2024, time.January, 5, 12, 0, 0, 0, time.UTC)
+	cases := map[string]struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		"valid": {
+			name: "Asia/Tokyo",
+			want: "2024-01-05T21:00:00+09:00",
+		},
+		"invalid": {
+			name:    "Not/A_Zone",
+			wantErr: true,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// convert twice, so the second conversion uses the
+			// cached location, if there is one
+			for range 2 {
+				got, err := inTZ(test.name, instant)
+				if test.wantErr {
+					if err == nil {
+						t.Errorf("expected an error, got %s", got)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if got.Format(time.RFC3339) != test.want {
+					t.Errorf("expected %s, got %s", test.want, got.Format(time.RFC3339))
+				}
+			}
+		})
+	}
+}
+
+type timePage struct {
+	Starts i18n.Time
+}
+
+func (timePage) Templates(_ context.Context) []string {
+	return []string{"time.html.tmpl"}
+}
+
+func (p timePage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{p.Starts}
+}
+
+func (timePage) Key(_ context.Context) string {
+	return "time.html.tmpl"
+}
+
+func (timePage) ExecutedTemplate(_ context.Context) string {
+	return "time.html.tmpl"
+}
+
+// funcsSite registers the functions from i18n.Funcs.
+type funcsSite struct {
+	*temple.CachedSite
+}
+
+func (funcsSite) ContextFuncMap(ctx context.Context) template.FuncMap {
+	return i18n.Funcs(ctx)
+}
+
+func TestTimeNeedsFuncs(t *testing.T) {
+	t.Parallel()
+
+	templates := fstest.MapFS{
+		"time.html.tmpl": {Data: []byte(`{{ template "i18n/time.html.tmpl" .Page.Starts }}`)},
+	}
+	page := timePage{Starts: i18n.Time{Time: time.Date(2024, time.January, 5, 12, 0, 0, 0, time.UTC)}}
+
+	var out strings.Builder
+	result := temple.Render(context.Background(), &out, funcsSite{CachedSite: temple.NewCachedSite(templates)}, page)
+	if result.Err != nil {
+		t.Fatalf("unexpected error: %s", result.Err)
+	}
+	if want := `<time datetime="2024-01-05T12:00:00Z">Jan 5, 2024</time>`; out.String() != want {
+		t.Errorf("expected %q, got %q", want, out.String())
+	}
+
+	out.Reset()
+	result = temple.Render(context.Background(), &out, temple.NewCachedSite(templates), page)
+	if result.Err == nil || !strings.Contains(result.Err.Error(), `"localtime" not defined`) {
+		t.Errorf("expected an error about localtime not being defined, got %v", result.Err)
+	}
+}
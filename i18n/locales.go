@@ -0,0 +1,158 @@
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// timeUnit is a unit relative times can be expressed in.
+type timeUnit int
+
+const (
+	minuteUnit timeUnit = iota
+	hourUnit
+	dayUnit
+	monthUnit
+	yearUnit
+)
+
+// localeData holds the words and formats needed to format dates and relative
+// times for a language.
+type localeData struct {
+	// months are the abbreviated names of the months, starting with
+	// January.
+	months [12]string
+
+	// date formats a day, abbreviated month name, and year as a date.
+	date func(day int, month string, year int) string
+
+	// units are the singular and plural forms of each timeUnit.
+	units [5][2]string
+
+	// justNow is used for times within a minute of now.
+	justNow string
+
+	// past and future are format strings for relative times, with a
+	// single %s for the amount and unit, like "3 hours".
+	past   string
+	future string
+}
+
+var locales = map[string]localeData{
+	"en": {
+		months: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		date: func(day int, month string, year int) string {
+			return fmt.Sprintf("%d %s %d", day, month, year)
+		},
+		units: [5][2]string{
+			{"minute", "minutes"},
+			{"hour", "hours"},
+			{"day", "days"},
+			{"month", "months"},
+			{"year", "years"},
+		},
+		justNow: "just now",
+		past:    "%s ago",
+		future:  "in %s",
+	},
+	"de": {
+		months: [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		date: func(day int, month string, year int) string {
+			return fmt.Sprintf("%d. %s %d", day, month, year)
+		},
+		units: [5][2]string{
+			{"Minute", "Minuten"},
+			{"Stunde", "Stunden"},
+			{"Tag", "Tagen"},
+			{"Monat", "Monaten"},
+			{"Jahr", "Jahren"},
+		},
+		justNow: "gerade eben",
+		past:    "vor %s",
+		future:  "in %s",
+	},
+	"fr": {
+		months: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		date: func(day int, month string, year int) string {
+			return fmt.Sprintf("%d %s %d", day, month, year)
+		},
+		units: [5][2]string{
+			{"minute", "minutes"},
+			{"heure", "heures"},
+			{"jour", "jours"},
+			{"mois", "mois"},
+			{"an", "ans"},
+		},
+		justNow: "à l’instant",
+		past:    "il y a %s",
+		future:  "dans %s",
+	},
+	"es": {
+		months: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		date: func(day int, month string, year int) string {
+			return fmt.Sprintf("%d %s %d", day, month, year)
+		},
+		units: [5][2]string{
+			{"minuto", "minutos"},
+			{"hora", "horas"},
+			{"día", "días"},
+			{"mes", "meses"},
+			{"año", "años"},
+		},
+		justNow: "ahora mismo",
+		past:    "hace %s",
+		future:  "dentro de %s",
+	},
+}
+
+// monthFirstLocales are the languages and regions that conventionally write
+// dates with the month before the day. It's keyed on both, as it's a
+// convention of the language as it's written in a region: English in the
+// United States puts the month first, but Spanish there doesn't, and neither
+// does French in Canada.
+var monthFirstLocales = map[string]struct{}{
+	"en-US": {},
+	"en-PH": {},
+	"en-CA": {},
+}
+
+// dataFor returns the localeData for the locale's language, falling back on
+// English for languages without localeData.
+func dataFor(locale language.Tag) localeData {
+	base, _ := locale.Base()
+	data, ok := locales[base.String()]
+	if !ok {
+		return locales["en"]
+	}
+	return data
+}
+
+// formatDate formats `t` as a date for the locale.
+func formatDate(locale language.Tag, t time.Time) string {
+	data := dataFor(locale)
+	month := data.months[t.Month()-1]
+	base, _ := locale.Base()
+	region, _ := locale.Region()
+	if _, ok := monthFirstLocales[base.String()+"-"+region.String()]; ok {
+		return fmt.Sprintf("%s %d, %d", month, t.Day(), t.Year())
+	}
+	return data.date(t.Day(), month, t.Year())
+}
+
+// formatRelative formats an amount of a timeUnit relative to now for the
+// locale.
+func formatRelative(locale language.Tag, amount int, unit timeUnit, future bool) string {
+	data := dataFor(locale)
+	form := data.units[unit][1]
+	if amount == 1 {
+		form = data.units[unit][0]
+	}
+	phrase := strconv.Itoa(amount) + " " + form
+	if future {
+		return fmt.Sprintf(data.future, phrase)
+	}
+	return fmt.Sprintf(data.past, phrase)
+}
@@ -0,0 +1,8 @@
+package i18n
+
+import "impractical.co/temple"
+
+// Package returns the temple.ComponentPackage for Time.
+func Package() (temple.ComponentPackage, error) {
+	return temple.NewComponentPackage("i18n", templates, "templates/i18n", "", Time{})
+}
@@ -0,0 +1,97 @@
+package i18n
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sync"
+	"time"
+
+	"impractical.co/temple"
+)
+
+type timeZoneCtxKey struct{}
+
+// WithTimeZone returns a context.Context that will cause the localtime
+// template function returned by Funcs to convert times to the passed
+// time.Location, usually the time zone of the user making the request.
+func WithTimeZone(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, timeZoneCtxKey{}, loc)
+}
+
+// TimeZone returns the time.Location stored in the context.Context by
+// WithTimeZone, or time.UTC if there isn't one.
+func TimeZone(ctx context.Context) *time.Location {
+	loc, ok := ctx.Value(timeZoneCtxKey{}).(*time.Location)
+	if !ok || loc == nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// locations caches the time.Locations loaded by inTZ, by name, as
+// time.LoadLocation reads and parses the time zone database every time it's
+// called. Only time zones that load successfully are cached, so names from
+// user input can't grow it past the size of the database.
+var locations sync.Map
+
+// inTZ converts the time.Time to the time zone with the passed IANA name,
+// like "America/New_York".
+func inTZ(name string, t time.Time) (time.Time, error) {
+	if loc, ok := locations.Load(name); ok {
+		return t.In(loc.(*time.Location)), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error loading time zone %q: %w", name, err)
+	}
+	locations.Store(name, loc)
+	return t.In(loc), nil
+}
+
+//go:embed templates
+var templates embed.FS
+
+var (
+	_ temple.Component           = Time{}
+	_ temple.TemplateDirProvider = Time{}
+)
+
+// Time is a Component that renders a <time> element, with a machine-readable
+// datetime attribute and the time formatted for display in the user's time
+// zone and locale. It can be rendered with:
+//
+//	{{ template "i18n/time.html.tmpl" .Page.Published }}
+//
+// Its template calls the localtime and formatDate functions, but Time doesn't
+// supply them, as they depend on the locale and time zone of the request: the
+// Site must register them by returning the output of Funcs from its
+// ContextFuncMap method, as shown in the package documentation. Otherwise,
+// parsing the page's templates fails, with an error saying the functions
+// aren't defined.
+type Time struct {
+	// Time is the time to render.
+	Time time.Time
+
+	// Layout is the layout, as understood by time.Time.Format, used to
+	// display the time. If empty, the time is displayed as a date using
+	// formatDate.
+	Layout string
+}
+
+// Templates returns the template needed to render the Time.
+func (Time) Templates(_ context.Context) []string {
+	return []string{"i18n/time.html.tmpl"}
+}
+
+// TemplateDir returns the fs.FS containing the Time's template.
+func (Time) TemplateDir(_ context.Context) fs.FS {
+	return temple.SubFS(templates, "templates")
+}
+
+// Datetime returns the time in the format used by the datetime attribute of
+// the <time> element.
+func (t Time) Datetime() string {
+	return t.Time.Format(time.RFC3339)
+}
@@ -0,0 +1,105 @@
+package temple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+var (
+	// ErrNoEntryTemplate is returned when the component template
+	// function is passed a Component that doesn't implement
+	// EntryTemplater.
+	ErrNoEntryTemplate = errors.New("component doesn't implement EntryTemplater")
+
+	// errComponentFuncUnbound is returned when the component template
+	// function is called on a template set it hasn't been bound to,
+	// which should never happen.
+	errComponentFuncUnbound = errors.New("component function called outside of a render")
+)
+
+// EntryTemplater is an interface that Components can fulfill to be rendered
+// inline by other Components, using the component template function:
+//
+//	{{ component .Page.Sidebar }}
+//
+// The component function executes the template named by EntryTemplate, with
+// an InlineComponentData holding the Component as its data, and includes the
+// output where the function was called. Any arguments after the Component are
+// available as .Data; if there's only one, it's used as-is.
+//
+// The Component still needs to be in the page's tree of Components, usually
+// by being in the UseComponents output of the Component rendering it, so its
+// templates get parsed and its resources included in the page.
+type EntryTemplater interface {
+	// EntryTemplate returns the name of the template to execute when the
+	// Component is rendered inline.
+	EntryTemplate(context.Context) string
+}
+
+// InlineComponentData is the data passed to the entry template of a Component
+// rendered with the component template function.
+type InlineComponentData struct {
+	// Site is the Site the page is being rendered by.
+	Site Site
+
+	// Component is the Component being rendered.
+	Component Component
+
+	// Data is any additional arguments passed to the component template
+	// function after the Component. If there's only one, it's used
+	// as-is; if there's more than one, they're a []any.
+	Data any
+
+	// Request is the request-scoped data embedded in the context.Context
+	// passed to Render using WithRequestData, if any.
+	Request any
+}
+
+// componentFunc returns the component template function, executing entry
+// templates from tmpl. Components can render each other inline, so it gives
+// up once they're nested more than maxComponentDepth deep.
+func componentFunc(ctx context.Context, site Site, tmpl *template.Template) func(Component, ...any) (template.HTML, error) {
+	var depth int
+	return func(comp Component, args ...any) (template.HTML, error) {
+		entry, ok := comp.(EntryTemplater)
+		if !ok {
+			return "", fmt.Errorf("error rendering %T: %w", comp, ErrNoEntryTemplate)
+		}
+		if depth >= maxComponentDepth {
+			return "", fmt.Errorf("error rendering %T: %w", comp, ErrComponentTooDeep)
+		}
+		depth++
+		defer func() {
+			depth--
+		}()
+
+		data := InlineComponentData{
+			Site:      site,
+			Component: comp,
+			Request:   RequestData(ctx),
+		}
+		switch len(args) {
+		case 0:
+		case 1:
+			data.Data = args[0]
+		default:
+			data.Data = args
+		}
+		name := entry.EntryTemplate(ctx)
+		var out strings.Builder
+		err := tmpl.ExecuteTemplate(&out, name, data)
+		if err != nil {
+			return "", fmt.Errorf("error executing template %q for %T: %w", name, comp, err)
+		}
+		return template.HTML(out.String()), nil // #nosec G203
+	}
+}
+
+// unboundComponentFunc is a placeholder for the component template function,
+// used when parsing templates, before there's a template set to bind it to.
+func unboundComponentFunc(Component, ...any) (template.HTML, error) {
+	return "", errComponentFuncUnbound
+}
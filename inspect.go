@@ -0,0 +1,98 @@
+package temple
+
+import (
+	"context"
+	"maps"
+	"slices"
+)
+
+// InspectReport describes how a page would be rendered: the Components it
+// uses, the templates they need, and the resources they include, in the order
+// Render would use them. It can be serialized as JSON, for tools, tests, and
+// debug endpoints.
+type InspectReport struct {
+	// Key is the output of the page's Key method.
+	Key string `json:"key"`
+
+	// ExecutedTemplate is the output of the page's ExecutedTemplate
+	// method.
+	ExecutedTemplate string `json:"executedTemplate"`
+
+	// Components are the page and every Component it uses, in the order
+	// they're resolved.
+	Components []DebugComponent `json:"components"`
+
+	// Templates are the paths of the templates that would be parsed for
+	// the page, in the order they'd be parsed.
+	Templates []string `json:"templates"`
+
+	// LinkedCSS are the URLs of the stylesheets linked to by the page, in
+	// the order they'd be linked.
+	LinkedCSS []string `json:"linkedCSS"`
+
+	// CriticalCSS are the URLs of the critical stylesheets inlined in the
+	// page, in the order they'd be inlined.
+	CriticalCSS []string `json:"criticalCSS"`
+
+	// LinkedJS are the URLs of the scripts linked to by the page, in the
+	// order they'd be linked.
+	LinkedJS []string `json:"linkedJS"`
+
+	// IdleJS are the URLs of the scripts loaded once the browser is idle,
+	// in the order they'd be loaded.
+	IdleJS []string `json:"idleJS"`
+
+	// ImportMap is the page's merged import map.
+	ImportMap JSImportMap `json:"importMap"`
+
+	// JSONData are the IDs of the JSONData embedded in the page, in the
+	// order they'd be embedded.
+	JSONData []string `json:"jsonData"`
+
+	// SurrogateKeys are the surrogate keys the page would be tagged with.
+	SurrogateKeys []string `json:"surrogateKeys"`
+
+	// Funcs are the names of every function available to the page's
+	// templates, sorted alphabetically.
+	Funcs []string `json:"funcs"`
+}
+
+// Inspect returns an InspectReport describing how the page would be rendered
+// by the Site, without parsing or executing any templates. It returns an
+// error if the page's resources can't be collected, in which case Render
+// would fail too.
+func Inspect[SiteType Site, PageType Renderable](ctx context.Context, site SiteType, page PageType) (InspectReport, error) {
+	ctx = withPublished(ctx)
+	ctx = withTheme(ctx, site)
+	components, err := getRecursiveComponents(ctx, page)
+	if err != nil {
+		return InspectReport{}, err
+	}
+	data, err := collectRenderData(ctx, site, page, components)
+	if err != nil {
+		return InspectReport{}, err
+	}
+	funcs, err := getComponentFuncMap(ctx, site, components, nil, false)
+	if err != nil {
+		return InspectReport{}, err
+	}
+	funcs = mergeFuncMaps(funcs, contextFuncs(ctx, site))
+
+	report := InspectReport{
+		Key:              page.Key(ctx),
+		ExecutedTemplate: page.ExecutedTemplate(ctx),
+		Components:       debugComponentTree(ctx, page, 0),
+		Templates:        templatePathStrings(getComponentTemplatePaths(ctx, site, components)),
+		LinkedCSS:        data.LinkedCSS,
+		CriticalCSS:      data.PreloadedCSS,
+		LinkedJS:         data.LinkedJS,
+		IdleJS:           data.IdleJS,
+		ImportMap:        data.ImportMap,
+		SurrogateKeys:    getComponentSurrogateKeys(ctx, components),
+		Funcs:            slices.Sorted(maps.Keys(funcs)),
+	}
+	for _, d := range data.JSONData {
+		report.JSONData = append(report.JSONData, d.ID)
+	}
+	return report, nil
+}
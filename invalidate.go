@@ -0,0 +1,78 @@
+package temple
+
+import (
+	"context"
+	"slices"
+)
+
+// TemplateCacheInvalidator is an optional interface for TemplateCachers.
+// Those fulfilling it can have the templates cached for some keys removed
+// from their cache, so they'll be parsed again the next time they're
+// rendered.
+type TemplateCacheInvalidator interface {
+	// InvalidateCachedTemplates removes the templates cached for each of
+	// the keys.
+	InvalidateCachedTemplates(ctx context.Context, keys ...string)
+}
+
+// GraphCacheInvalidator is an optional interface for GraphCachers. Those
+// fulfilling it can have the ComponentGraphs cached for some keys removed
+// from their cache, so they'll be resolved again the next time they're
+// rendered.
+type GraphCacheInvalidator interface {
+	// InvalidateCachedGraphs removes the ComponentGraphs cached for each
+	// of the keys.
+	InvalidateCachedGraphs(ctx context.Context, keys ...string)
+}
+
+// CriticalCSSCacheInvalidator is an optional interface for
+// CriticalCSSCachers. Those fulfilling it can have the contents cached for
+// some critical stylesheets removed from their cache, so they'll be read
+// again the next time they're rendered.
+type CriticalCSSCacheInvalidator interface {
+	// InvalidateCachedCriticalCSS removes the contents cached for each of
+	// the keys.
+	InvalidateCachedCriticalCSS(ctx context.Context, keys ...string)
+}
+
+// InvalidateTemplates removes everything the Site has cached for the pages
+// that use any of the templates at paths, according to the TemplateIndex, so
+// changes to those templates will be picked up the next time the pages are
+// rendered. Pages that don't use the templates keep their caches. It's meant
+// to be called when templates change, like when a file watcher notices an
+// edit during development.
+//
+// The cached templates are invalidated if the Site is a
+// TemplateCacheInvalidator, any templates recently found to be missing are
+// looked for again, and the cached ComponentGraphs are invalidated if
+// the Site is a GraphCacheInvalidator. Any critical stylesheets at paths are
+// invalidated if the Site is a CriticalCSSCacheInvalidator. If the Site is a
+// Themer, the caches for every one of its Themes are invalidated. The keys of the affected pages
+// are returned, sorted alphabetically, so any caches of the pages' output can
+// be purged too.
+func InvalidateTemplates(ctx context.Context, site Site, index TemplateIndex, paths ...string) []string {
+	if cache, ok := site.(CriticalCSSCacheInvalidator); ok {
+		cache.InvalidateCachedCriticalCSS(ctx, themedKeys(ctx, site, slices.Clone(paths))...)
+	}
+	var keys []string
+	for _, path := range paths {
+		keys = append(keys, index.Keys(path)...)
+	}
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+	if len(keys) < 1 {
+		return nil
+	}
+	cacheKeys := themedKeys(ctx, site, slices.Clone(keys))
+	if cache, ok := site.(TemplateCacheInvalidator); ok {
+		cache.InvalidateCachedTemplates(ctx, cacheKeys...)
+	}
+	if cache, ok := site.(TemplateCacher); ok {
+		forgetMissingTemplates(cache, cacheKeys...)
+	}
+	if cache, ok := site.(GraphCacheInvalidator); ok {
+		cache.InvalidateCachedGraphs(ctx, cacheKeys...)
+	}
+	logger(ctx).DebugContext(ctx, "invalidated cached templates", "paths", paths, "keys", keys)
+	return keys
+}
@@ -0,0 +1,190 @@
+package temple
+
+import (
+	"fmt"
+	"html/template"
+	"iter"
+	"math"
+	"reflect"
+)
+
+// SeqPage is a single page of items from an iter.Seq, as returned by
+// PaginateSeq.
+type SeqPage[T any] struct {
+	// Items are the items on the page.
+	Items []T
+
+	// Number is the 1-indexed number of the page.
+	Number int
+
+	// HasPrev is true if there's a page before this one.
+	HasPrev bool
+
+	// HasNext is true if there's a page after this one.
+	HasNext bool
+}
+
+// seqPageCapacity is the most items PaginateSeq allocates room for up front,
+// so a huge perPage doesn't allocate a huge slice for a short sequence.
+const seqPageCapacity = 128
+
+// PaginateSeq returns the 1-indexed page of `perPage` items from `seq`. Only
+// the items on the requested page are held in memory; items before it are
+// skipped as they're produced, and production stops once the page is full and
+// it's known whether there's another page.
+//
+// Pages less than 1 are treated as page 1, and perPage values less than 1 are
+// treated as 1.
+func PaginateSeq[T any](seq iter.Seq[T], page, perPage int) SeqPage[T] {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 1
+	}
+	result := SeqPage[T]{
+		Number:  page,
+		HasPrev: page > 1,
+		Items:   make([]T, 0, min(perPage, seqPageCapacity)),
+	}
+	// pages too far in to count to can't have any items
+	if page-1 > math.MaxInt/perPage {
+		return result
+	}
+	skip := (page - 1) * perPage
+	seen := 0
+	for item := range seq {
+		if seen < skip {
+			seen++
+			continue
+		}
+		if len(result.Items) >= perPage {
+			result.HasNext = true
+			break
+		}
+		result.Items = append(result.Items, item)
+	}
+	return result
+}
+
+// SeqFromNext returns an iter.Seq that yields values from `next` until it
+// returns false. This is useful for adapting cursors, like the rows returned
+// by a database query, so templates can range over them without loading them
+// all into a slice first.
+func SeqFromNext[T any](next func() (T, bool)) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			item, ok := next()
+			if !ok {
+				return
+			}
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// IterFuncs returns a template.FuncMap of functions for working with
+// sequences in templates. A Site or Component can include them in the output
+// of its FuncMap method.
+//
+// The functions accept slices, arrays, and iter.Seq functions:
+//
+//   - take: {{ range take 10 .Page.Rows }} yields at most the first 10
+//     items.
+//   - skip: {{ range skip 10 .Page.Rows }} yields everything after the
+//     first 10 items.
+//   - collect: {{ $rows := collect .Page.Rows }} gathers every item into a
+//     slice.
+//
+// take and skip return an iter.Seq, which templates can range over directly
+// when built with Go 1.24 or later. For earlier versions, pass the result to
+// collect before ranging over it.
+func IterFuncs() template.FuncMap {
+	return template.FuncMap{
+		"take":    takeSeq,
+		"skip":    skipSeq,
+		"collect": collectSeq,
+	}
+}
+
+// toSeq converts a slice, array, or iter.Seq function into an iter.Seq[any].
+func toSeq(v any) (iter.Seq[any], error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		return func(yield func(any) bool) {
+			for i := 0; i < rv.Len(); i++ {
+				if !yield(rv.Index(i).Interface()) {
+					return
+				}
+			}
+		}, nil
+	case reflect.Func:
+		if !rv.Type().CanSeq() {
+			break
+		}
+		return func(yield func(any) bool) {
+			for item := range rv.Seq() {
+				if !yield(item.Interface()) {
+					return
+				}
+			}
+		}, nil
+	}
+	return nil, fmt.Errorf("can't iterate over %T", v)
+}
+
+func takeSeq(n int, v any) (iter.Seq[any], error) {
+	seq, err := toSeq(v)
+	if err != nil {
+		return nil, err
+	}
+	return func(yield func(any) bool) {
+		if n < 1 {
+			return
+		}
+		taken := 0
+		for item := range seq {
+			if !yield(item) {
+				return
+			}
+			taken++
+			if taken >= n {
+				return
+			}
+		}
+	}, nil
+}
+
+func skipSeq(n int, v any) (iter.Seq[any], error) {
+	seq, err := toSeq(v)
+	if err != nil {
+		return nil, err
+	}
+	return func(yield func(any) bool) {
+		skipped := 0
+		for item := range seq {
+			if skipped < n {
+				skipped++
+				continue
+			}
+			if !yield(item) {
+				return
+			}
+		}
+	}, nil
+}
+
+func collectSeq(v any) ([]any, error) {
+	seq, err := toSeq(v)
+	if err != nil {
+		return nil, err
+	}
+	var results []any
+	for item := range seq {
+		results = append(results, item)
+	}
+	return results, nil
+}
@@ -0,0 +1,66 @@
+package temple_test
+
+import (
+	"iter"
+	"math"
+	"slices"
+	"testing"
+
+	"impractical.co/temple"
+)
+
+func TestPaginateSeq(t *testing.T) {
+	t.Parallel()
+
+	numbers := func(n int) iter.Seq[int] {
+		return func(yield func(int) bool) {
+			for i := 1; i <= n; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+		}
+	}
+
+	cases := map[string]struct {
+		items    int
+		page     int
+		perPage  int
+		want     []int
+		wantNum  int
+		wantPrev bool
+		wantNext bool
+	}{
+		"first-page":         {items: 5, page: 1, perPage: 2, want: []int{1, 2}, wantNum: 1, wantNext: true},
+		"middle-page":        {items: 5, page: 2, perPage: 2, want: []int{3, 4}, wantNum: 2, wantPrev: true, wantNext: true},
+		"last-page":          {items: 5, page: 3, perPage: 2, want: []int{5}, wantNum: 3, wantPrev: true},
+		"exactly-full":       {items: 4, page: 2, perPage: 2, want: []int{3, 4}, wantNum: 2, wantPrev: true},
+		"past-the-end":       {items: 5, page: 4, perPage: 2, want: []int{}, wantNum: 4, wantPrev: true},
+		"empty":              {items: 0, page: 1, perPage: 2, want: []int{}, wantNum: 1},
+		"zero-page":          {items: 5, page: 0, perPage: 2, want: []int{1, 2}, wantNum: 1, wantNext: true},
+		"negative-page":      {items: 5, page: -3, perPage: 2, want: []int{1, 2}, wantNum: 1, wantNext: true},
+		"zero-per-page":      {items: 3, page: 2, perPage: 0, want: []int{2}, wantNum: 2, wantPrev: true, wantNext: true},
+		"negative-per-page":  {items: 1, page: 1, perPage: -1, want: []int{1}, wantNum: 1},
+		"huge-per-page":      {items: 3, page: 1, perPage: math.MaxInt, want: []int{1, 2, 3}, wantNum: 1},
+		"overflowing-offset": {items: 3, page: math.MaxInt, perPage: 2, want: []int{}, wantNum: math.MaxInt, wantPrev: true},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := temple.PaginateSeq(numbers(test.items), test.page, test.perPage)
+			if !slices.Equal(got.Items, test.want) {
+				t.Errorf("expected items %v, got %v", test.want, got.Items)
+			}
+			if got.Number != test.wantNum {
+				t.Errorf("expected page number %d, got %d", test.wantNum, got.Number)
+			}
+			if got.HasPrev != test.wantPrev {
+				t.Errorf("expected HasPrev %v, got %v", test.wantPrev, got.HasPrev)
+			}
+			if got.HasNext != test.wantNext {
+				t.Errorf("expected HasNext %v, got %v", test.wantNext, got.HasNext)
+			}
+		})
+	}
+}
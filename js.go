@@ -3,9 +3,17 @@ package temple
 import (
 	"context"
 	"crypto/sha256"
-	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
+	"strings"
+)
+
+var (
+	// ErrImportMapConflict is returned when two Components map the same
+	// module specifier to different URLs in their import maps.
+	ErrImportMapConflict = errors.New("conflicting import map entries")
 )
 
 // JSEmbedder is an interface that Components can fulfill to include some
@@ -30,32 +38,87 @@ type JSLinker interface {
 	LinkJS(context.Context) []string
 }
 
-func getComponentJSEmbeds(ctx context.Context, component Component) template.JS {
-	var results template.JS
-	seen := map[string]struct{}{}
-	components := getRecursiveComponents(ctx, component)
+func getComponentJSEmbeds(ctx context.Context, components []Component) template.JS {
+	var results strings.Builder
+	seen := map[[sha256.Size]byte]struct{}{}
 	for _, comp := range components {
 		embed, ok := comp.(JSEmbedder)
 		if !ok {
 			continue
 		}
 		script := embed.EmbedJS(ctx)
-		checksum := hex.EncodeToString(sha256.New().Sum([]byte(script)))
+		checksum := sha256.Sum256([]byte(script))
 		if _, ok := seen[checksum]; ok {
 			continue
 		}
 		seen[checksum] = struct{}{}
-		results += template.JS(fmt.Sprintf(`
+		fmt.Fprintf(&results, `
 /* embedded JavaScript from %T */
-%s`, comp, script)) // #nosec G203
+%s`, comp, script)
 	}
-	return results
+	return template.JS(results.String()) // #nosec G203
 }
 
-func getComponentJSLinks(ctx context.Context, component Component) []string {
+// EmbeddedJS returns the JavaScript the Component and every Component it uses
+// would embed in a page, merged exactly as it would be made available to the
+// template as .EmbeddedJS, but without rendering anything. It's useful for
+// running a page's JavaScript through a linter or parser in tests, to catch
+// scripts broken by the values interpolated into them before they reach
+// browsers. It returns an error if the Component's Components can't be
+// resolved, in which case Render would fail too.
+func EmbeddedJS(ctx context.Context, component Component) (template.JS, error) {
+	components, err := getRecursiveComponents(ctx, component)
+	if err != nil {
+		return "", err
+	}
+	return getComponentJSEmbeds(ctx, components), nil
+}
+
+// JSLoadStrategy controls when a script linked to by a JSResourceLinker is
+// loaded.
+type JSLoadStrategy string
+
+const (
+	// JSLoadDefault loads the script like any other linked script. It's
+	// made available to the template as .LinkedJS.
+	JSLoadDefault JSLoadStrategy = ""
+
+	// JSLoadIdle defers loading the script until the browser is idle, or
+	// the user first interacts with the page, whichever comes first. It's
+	// the usual way to keep third-party scripts, like chat widgets or
+	// analytics, from competing with the page for bandwidth and the main
+	// thread. These scripts are made available to the template as
+	// .IdleJS, and are loaded by the script rendered by .IdleJSLoader.
+	JSLoadIdle JSLoadStrategy = "idle"
+)
+
+// JSLink is a script that a Component links to, with extra information about
+// how it should be loaded.
+type JSLink struct {
+	// URL is the URL browsers should load the script from.
+	URL string
+
+	// Strategy is when the script should be loaded.
+	Strategy JSLoadStrategy
+}
+
+// JSResourceLinker is an interface that Components can fulfill to include
+// some JavaScript that should be loaded separately from the HTML document,
+// but that needs more control over when it's loaded than JSLinker offers.
+// Scripts using the JSLoadDefault strategy are made available to the template
+// as .LinkedJS, alongside the output of JSLinker.
+type JSResourceLinker interface {
+	// LinkJSResources returns a list of scripts that should be loaded
+	// by the output HTML.
+	//
+	// If this Component embeds any other Components, it should include
+	// their LinkJSResources output in its own LinkJSResources output.
+	LinkJSResources(context.Context) []JSLink
+}
+
+func getComponentJSLinks(ctx context.Context, components []Component) []string {
 	var results []string
 	seen := map[string]struct{}{}
-	components := getRecursiveComponents(ctx, component)
 	for _, comp := range components {
 		link, ok := comp.(JSLinker)
 		if !ok {
@@ -70,5 +133,128 @@ func getComponentJSLinks(ctx context.Context, component Component) []string {
 			seen[source] = struct{}{}
 		}
 	}
+	for _, comp := range components {
+		link, ok := comp.(JSResourceLinker)
+		if !ok {
+			continue
+		}
+		for _, js := range link.LinkJSResources(ctx) {
+			if js.Strategy != JSLoadDefault {
+				continue
+			}
+			if _, ok := seen[js.URL]; ok {
+				continue
+			}
+			results = append(results, js.URL)
+			seen[js.URL] = struct{}{}
+		}
+	}
+	return results
+}
+
+// getComponentIdleJS returns the URLs of the scripts the Components link to
+// using the JSLoadIdle strategy, leaving out any that are in `linked`, as
+// they're already being loaded normally.
+func getComponentIdleJS(ctx context.Context, components []Component, linked []string) []string {
+	var results []string
+	seen := map[string]struct{}{}
+	for _, url := range linked {
+		seen[url] = struct{}{}
+	}
+	for _, comp := range components {
+		link, ok := comp.(JSResourceLinker)
+		if !ok {
+			continue
+		}
+		for _, js := range link.LinkJSResources(ctx) {
+			if js.Strategy != JSLoadIdle {
+				continue
+			}
+			if _, ok := seen[js.URL]; ok {
+				continue
+			}
+			results = append(results, js.URL)
+			seen[js.URL] = struct{}{}
+		}
+	}
 	return results
 }
+
+// idleJSLoader is the script that loads the page's idle scripts, once the
+// browser is idle or the user first interacts with the page. The %s is
+// replaced by a JSON array of the scripts' URLs.
+const idleJSLoader = `<script>(function(){var urls=%s,loaded=false,events=["pointerdown","keydown","touchstart","scroll"];function load(){if(loaded){return}loaded=true;events.forEach(function(e){removeEventListener(e,load)});urls.forEach(function(u){var s=document.createElement("script");s.src=u;s.async=true;document.body.appendChild(s)})}events.forEach(function(e){addEventListener(e,load,{once:true,passive:true})});if("requestIdleCallback" in window){requestIdleCallback(load,{timeout:5000})}else{addEventListener("load",function(){setTimeout(load,1)})}})()</script>`
+
+// idleJSLoaderTag returns a <script> element that loads the URLs once the
+// browser is idle or the user first interacts with the page, or nothing if
+// there are no URLs.
+func idleJSLoaderTag(urls []string) (template.HTML, error) {
+	if len(urls) < 1 {
+		return "", nil
+	}
+	contents, err := json.Marshal(urls)
+	if err != nil {
+		return "", fmt.Errorf("error encoding idle scripts: %w", err)
+	}
+	// json.Marshal escapes <, >, and & so the contents can't close the
+	// script element early
+	return template.HTML(fmt.Sprintf(idleJSLoader, contents)), nil // #nosec G203
+}
+
+// JSImportMap maps JavaScript module specifiers to the URLs they should be
+// loaded from, as in the "imports" of an import map.
+type JSImportMap map[string]string
+
+// JSImportMapper is an interface that Components can fulfill to contribute to
+// the page's import map. The import maps of every Component on the page are
+// merged into a single import map, which will be made available to the
+// template as .ImportMap.
+type JSImportMapper interface {
+	// ImportMap returns the module specifiers this Component relies on
+	// and the URLs they should be loaded from.
+	//
+	// Components can map the same specifier, as long as they map it to
+	// the same URL. If two Components map a specifier to different URLs,
+	// rendering will fail with an ErrImportMapConflict error.
+	ImportMap(context.Context) JSImportMap
+}
+
+// tag returns a <script type="importmap"> element containing the JSImportMap,
+// or an empty string if there's nothing in the JSImportMap.
+func (j JSImportMap) tag() (template.HTML, error) {
+	if len(j) < 1 {
+		return "", nil
+	}
+	contents, err := json.Marshal(map[string]JSImportMap{"imports": j})
+	if err != nil {
+		return "", fmt.Errorf("error encoding import map: %w", err)
+	}
+	// json.Marshal escapes <, >, and & so the contents can't close the
+	// script element early
+	return template.HTML(`<script type="importmap">` + string(contents) + `</script>`), nil // #nosec G203
+}
+
+func getComponentImportMap(ctx context.Context, components []Component) (JSImportMap, error) {
+	results := JSImportMap{}
+	sources := map[string]Component{}
+	for _, comp := range components {
+		mapper, ok := comp.(JSImportMapper)
+		if !ok {
+			continue
+		}
+		for specifier, url := range mapper.ImportMap(ctx) {
+			existing, ok := results[specifier]
+			if ok && existing != url {
+				return nil, ResourceRenderError{
+					Key:       specifier,
+					Kind:      ResourceKindImportMap,
+					Component: comp,
+					Err:       fmt.Errorf("%w: %T maps it to %q, %T maps it to %q", ErrImportMapConflict, sources[specifier], existing, comp, url),
+				}
+			}
+			results[specifier] = url
+			sources[specifier] = comp
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,132 @@
+package temple
+
+import (
+	"context"
+	"html/template"
+	"strings"
+	"text/template/parse"
+)
+
+// Slot is a named region of a layout that pages fill in by defining a
+// template with the Slot's name, like {{ define "sidebar" }}.
+type Slot struct {
+	// Name is the name of the template that fills the Slot.
+	Name string
+
+	// Required marks the Slot as one every page using the layout must
+	// fill in. Render returns a MissingSlotsError for pages that don't.
+	Required bool
+}
+
+// SlotDeclarer is an interface that Components can fulfill to declare the
+// Slots they expect the pages using them to fill in. Layout implements it,
+// and is usually what should be used, but Components that need to decide
+// their Slots dynamically can implement it themselves.
+//
+// Every required Slot is checked when the page's templates are parsed. A
+// Slot counts as filled if a template with its name is defined and isn't
+// empty, so layouts should use empty blocks, like
+// {{ block "body" . }}{{ end }}, for required Slots rather than blocks with
+// default contents.
+type SlotDeclarer interface {
+	// DeclareSlots returns the Slots the Component expects pages using it
+	// to fill in.
+	DeclareSlots(context.Context) []Slot
+}
+
+var _ Component = Layout{}
+var _ ComponentUser = Layout{}
+var _ SlotDeclarer = Layout{}
+
+// Layout is a Component for a base template that pages fill in the Slots of.
+// Pages should include the Layout in their UseComponents output and return
+// its BaseTemplate from their ExecutedTemplate method.
+//
+// Layouts can be nested by setting their Parent. A nested Layout fills in
+// its Parent's Slots, usually wrapping Slots of its own, and pages using it
+// only need to fill in the nested Layout's Slots:
+//
+//	{{ define "body" }}<div class="marketing">{{ block "content" . }}{{ end }}</div>{{ end }}
+//
+// As templates are parsed starting with the page, Layouts with a Parent
+// should declare their Slots with empty blocks, so they don't replace the
+// definitions of the page.
+type Layout struct {
+	// Path is the path to the layout's template. If the Layout has no
+	// Parent, it's also the name of the template that gets executed.
+	Path string
+
+	// Slots are the Slots the layout's template expects pages to fill in.
+	Slots []Slot
+
+	// Parent is the Layout this Layout is nested in, if any.
+	Parent *Layout
+}
+
+// Templates returns the Layout's Path.
+func (l Layout) Templates(_ context.Context) []string {
+	return []string{l.Path}
+}
+
+// DeclareSlots returns the Layout's Slots.
+func (l Layout) DeclareSlots(_ context.Context) []Slot {
+	return l.Slots
+}
+
+// UseComponents returns the Layout's Parent, if it has one, so its templates
+// are parsed and its Slots checked too.
+func (l Layout) UseComponents(_ context.Context) []Component {
+	if l.Parent == nil {
+		return nil
+	}
+	return []Component{*l.Parent}
+}
+
+// BaseTemplate returns the name of the template at the root of the Layout's
+// chain of Parents, for use in the ExecutedTemplate method of pages using the
+// Layout.
+func (l Layout) BaseTemplate() string {
+	root := l
+	for i := 0; root.Parent != nil && i < maxComponentDepth; i++ {
+		root = *root.Parent
+	}
+	return root.Path
+}
+
+// checkSlots returns a MissingSlotsError for the first Component whose
+// required Slots aren't all filled in by the parsed templates.
+func checkSlots(ctx context.Context, tmpl *template.Template, components []Component) error {
+	for _, comp := range components {
+		declarer, ok := comp.(SlotDeclarer)
+		if !ok {
+			continue
+		}
+		var missing []string
+		for _, slot := range declarer.DeclareSlots(ctx) {
+			if !slot.Required || slotFilled(tmpl, slot.Name) {
+				continue
+			}
+			missing = append(missing, slot.Name)
+		}
+		if len(missing) > 0 {
+			return MissingSlotsError{Component: comp, Slots: missing}
+		}
+	}
+	return nil
+}
+
+// slotFilled returns true if tmpl has a template defined with the name that
+// has more than whitespace in it.
+func slotFilled(tmpl *template.Template, name string) bool {
+	slot := tmpl.Lookup(name)
+	if slot == nil || slot.Tree == nil || slot.Tree.Root == nil {
+		return false
+	}
+	for _, node := range slot.Tree.Root.Nodes {
+		text, ok := node.(*parse.TextNode)
+		if !ok || strings.TrimSpace(string(text.Text)) != "" {
+			return true
+		}
+	}
+	return false
+}
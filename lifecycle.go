@@ -0,0 +1,158 @@
+package temple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrAlreadyStarted is returned when a Lifecycle is started while
+	// it's already running.
+	ErrAlreadyStarted = errors.New("already started")
+)
+
+// Service is a background part of a Site, like a template watcher or a cache
+// warmer, that needs to be started before the Site serves requests and
+// stopped when the server shuts down.
+type Service interface {
+	// Start starts the Service. It should return once the Service is
+	// running, leaving any long-running work in the background.
+	Start(ctx context.Context) error
+
+	// Shutdown stops the Service, returning once it has stopped or the
+	// context.Context is done, whichever comes first.
+	Shutdown(ctx context.Context) error
+}
+
+var _ Service = &Lifecycle{}
+
+// Lifecycle starts and stops a Site's Services together. Embed it in a Site
+// so servers can start everything the Site needs with Start and stop it with
+// Shutdown, usually when they receive SIGTERM. A Lifecycle is itself a
+// Service, so Lifecycles can be nested. Its zero value is ready to use, and it
+// can safely be used by multiple goroutines.
+type Lifecycle struct {
+	services []Service
+	started  []Service
+	running  bool
+	mu       sync.Mutex
+}
+
+// Register adds Services to the Lifecycle. Services are started in the order
+// they're registered, and shut down in the reverse order. Services registered
+// while the Lifecycle is running are started the next time it's started.
+func (l *Lifecycle) Register(services ...Service) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.services = append(l.services, services...)
+}
+
+// Start starts every registered Service, in order. If one fails to start,
+// the ones already started are shut down and the error is returned.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.running {
+		return ErrAlreadyStarted
+	}
+	for _, service := range l.services {
+		err := service.Start(ctx)
+		if err != nil {
+			err = fmt.Errorf("error starting %T: %w", service, err)
+			return errors.Join(err, l.shutdown(ctx))
+		}
+		l.started = append(l.started, service)
+	}
+	l.running = true
+	return nil
+}
+
+// Shutdown shuts down every Service that was started, in the reverse of the
+// order they were started in. Every Service is shut down even if some return
+// errors, which are all returned together. Calling Shutdown when the
+// Lifecycle isn't running does nothing.
+func (l *Lifecycle) Shutdown(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.running = false
+	return l.shutdown(ctx)
+}
+
+// shutdown shuts down the started Services. It must be called with l.mu
+// held.
+func (l *Lifecycle) shutdown(ctx context.Context) error {
+	var errs []error
+	for i := len(l.started) - 1; i >= 0; i-- {
+		err := l.started[i].Shutdown(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error shutting down %T: %w", l.started[i], err))
+		}
+	}
+	l.started = nil
+	return errors.Join(errs...)
+}
+
+var _ Service = &BackgroundService{}
+
+// BackgroundService is a Service that runs a function in its own goroutine
+// until it's shut down. It's the simplest way to turn a long-running loop,
+// like polling for changed templates, into a Service. It can safely be used by
+// multiple goroutines.
+type BackgroundService struct {
+	// Run is called in a new goroutine when the BackgroundService is
+	// started. The context.Context it's passed is canceled when the
+	// BackgroundService is shut down, and Run should return soon after.
+	Run func(ctx context.Context) error
+
+	cancel context.CancelFunc
+	done   chan error
+	mu     sync.Mutex
+}
+
+// Start calls Run in a new goroutine. The goroutine's context.Context
+// isn't canceled when the context.Context passed to Start is, only when the
+// BackgroundService is shut down, but it keeps the passed context.Context's
+// values.
+func (b *BackgroundService) Start(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.done != nil {
+		return ErrAlreadyStarted
+	}
+	ctx, b.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	b.done = make(chan error, 1)
+	go func(done chan<- error) {
+		done <- b.Run(ctx)
+	}(b.done)
+	return nil
+}
+
+// Shutdown cancels Run's context.Context and waits for it to return,
+// returning its error. If ctx is done first, ctx's error is returned
+// instead. Errors caused by Run's context.Context being canceled aren't
+// returned.
+//
+// Either way, the BackgroundService can be started again once Shutdown has
+// been called, even if ctx was done before Run returned. In that case, the
+// new call to Run may overlap with the end of the previous one.
+func (b *BackgroundService) Shutdown(ctx context.Context) error {
+	b.mu.Lock()
+	cancel, done := b.cancel, b.done
+	b.cancel, b.done = nil, nil
+	b.mu.Unlock()
+	if done == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case err := <-done:
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("error waiting for background service to stop: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,86 @@
+package temple_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"impractical.co/temple"
+)
+
+func TestBackgroundServiceRestartAfterTimeout(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	var runs int
+	var mu sync.Mutex
+	service := &temple.BackgroundService{
+		Run: func(ctx context.Context) error {
+			mu.Lock()
+			runs++
+			first := runs == 1
+			mu.Unlock()
+			if first {
+				started <- struct{}{}
+			}
+			<-ctx.Done()
+			if first {
+				// the first run ignores being canceled, so
+				// Shutdown times out waiting for it
+				<-release
+			}
+			return ctx.Err()
+		},
+	}
+	defer close(release)
+
+	err := service.Start(context.Background())
+	if err != nil {
+		t.Fatalf("error starting: %s", err)
+	}
+	<-started
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = service.Shutdown(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected Shutdown to time out, got %v", err)
+	}
+
+	err = service.Start(context.Background())
+	if err != nil {
+		t.Fatalf("error restarting after Shutdown timed out: %s", err)
+	}
+	err = service.Shutdown(context.Background())
+	if err != nil {
+		t.Errorf("error shutting down: %s", err)
+	}
+}
+
+func TestBackgroundServiceConcurrent(t *testing.T) {
+	t.Parallel()
+
+	service := &temple.BackgroundService{
+		Run: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := service.Start(context.Background())
+			if err != nil && !errors.Is(err, temple.ErrAlreadyStarted) {
+				t.Errorf("error starting: %s", err)
+			}
+			err = service.Shutdown(context.Background())
+			if err != nil {
+				t.Errorf("error shutting down: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
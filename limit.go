@@ -0,0 +1,131 @@
+package temple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	// ErrRenderQueueTimeout is returned when a page waits longer than its
+	// RenderLimiter's timeout for a chance to render.
+	ErrRenderQueueTimeout = errors.New("timed out waiting to render")
+)
+
+// RenderLimiter limits how many pages can render at once. Renders beyond the
+// limit wait in a queue until another render finishes, giving up after a
+// timeout. This keeps a burst of requests for memory-heavy pages from
+// overwhelming a small instance. A RenderLimiter must be instantiated through
+// NewRenderLimiter, its empty value is not usable. It can safely be used by
+// multiple goroutines.
+type RenderLimiter struct {
+	slots   chan struct{}
+	timeout time.Duration
+}
+
+// NewRenderLimiter returns a RenderLimiter that lets up to `limit` pages render
+// at once. Other renders wait for up to `timeout` before failing with
+// ErrRenderQueueTimeout; if timeout is 0 or less, they wait until their
+// context.Context is canceled. If limit is less than 1, it's treated as 1.
+func NewRenderLimiter(limit int, timeout time.Duration) *RenderLimiter {
+	return &RenderLimiter{
+		slots:   make(chan struct{}, max(limit, 1)),
+		timeout: timeout,
+	}
+}
+
+// acquire waits for a slot to render in, returning how long it waited. If it
+// returns a nil error, release must be called once the render is done.
+func (l *RenderLimiter) acquire(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	// don't bother setting up a timer if there's a slot free
+	select {
+	case l.slots <- struct{}{}:
+		return 0, nil
+	default:
+	}
+	var timeout <-chan time.Time
+	if l.timeout > 0 {
+		timer := time.NewTimer(l.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return time.Since(start), nil
+	case <-timeout:
+		return time.Since(start), fmt.Errorf("%w after %s", ErrRenderQueueTimeout, l.timeout)
+	case <-ctx.Done():
+		return time.Since(start), fmt.Errorf("error waiting to render: %w", ctx.Err())
+	}
+}
+
+// release frees the slot taken by acquire.
+func (l *RenderLimiter) release() {
+	<-l.slots
+}
+
+// RenderLimitProvider is an interface that Sites and Renderables can fulfill
+// to limit how many pages can render at once. A Site's RenderLimiter applies
+// to every page rendered for it, and a Renderable's RenderLimiter applies to
+// every page that returns the same RenderLimiter, so memory-heavy pages can
+// be limited more strictly than others. If both supply a RenderLimiter, the
+// page waits for the Renderable's first, then the Site's. Server error pages
+// are never limited.
+type RenderLimitProvider interface {
+	// RenderLimiter returns the RenderLimiter to wait for before
+	// rendering, or nil if rendering shouldn't be limited.
+	RenderLimiter(ctx context.Context) *RenderLimiter
+}
+
+// acquireRenderLimits waits for the RenderLimiters of the page and the Site,
+// if they have them, recording how long it waited in the RenderResult. It
+// returns a function that releases them, which must be called once the page
+// is done rendering, even if it returns an error.
+func acquireRenderLimits(ctx context.Context, site Site, page Renderable, result *RenderResult) (func(), error) {
+	var limiters []*RenderLimiter
+	if provider, ok := page.(RenderLimitProvider); ok {
+		if limiter := provider.RenderLimiter(ctx); limiter != nil {
+			limiters = append(limiters, limiter)
+		}
+	}
+	if provider, ok := site.(RenderLimitProvider); ok {
+		if limiter := provider.RenderLimiter(ctx); limiter != nil {
+			limiters = append(limiters, limiter)
+		}
+	}
+	var acquired []*RenderLimiter
+	release := func() {
+		for _, limiter := range acquired {
+			limiter.release()
+		}
+	}
+	if len(limiters) < 1 {
+		return release, nil
+	}
+	for _, limiter := range limiters {
+		wait, err := limiter.acquire(ctx)
+		result.QueueWait += wait
+		if err != nil {
+			recordQueueWait(ctx, *result)
+			return release, err
+		}
+		acquired = append(acquired, limiter)
+	}
+	recordQueueWait(ctx, *result)
+	return release, nil
+}
+
+// recordQueueWait records how long the page in the RenderResult waited for
+// its RenderLimiters.
+func recordQueueWait(ctx context.Context, result RenderResult) {
+	metrics := getRenderMetrics()
+	// the instrument is nil if it couldn't be created
+	if metrics.queueWait == nil {
+		return
+	}
+	metrics.queueWait.Record(ctx, result.QueueWait.Seconds(), metric.WithAttributes(pageKeyAttr.String(result.Key)))
+}
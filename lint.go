@@ -0,0 +1,280 @@
+package temple
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"text/template/parse"
+)
+
+// LintIssueKind describes what's wrong with a template found by Lint.
+type LintIssueKind string
+
+const (
+	// LintMissingTemplate is the LintIssueKind for a path returned by a
+	// Component's Templates method that doesn't match any files.
+	LintMissingTemplate LintIssueKind = "missing-template"
+
+	// LintParseError is the LintIssueKind for a template file that can't
+	// be read or parsed.
+	LintParseError LintIssueKind = "parse-error"
+
+	// LintUndefinedTemplate is the LintIssueKind for a template file that
+	// executes a template, using {{ template }}, that isn't defined by
+	// any of the templates it's parsed with.
+	LintUndefinedTemplate LintIssueKind = "undefined-template"
+
+	// LintUnusedTemplate is the LintIssueKind for a template file that
+	// nothing uses.
+	LintUnusedTemplate LintIssueKind = "unused-template"
+)
+
+// LintIssue is a problem with a template found by Lint or LintFS.
+type LintIssue struct {
+	// Kind is what's wrong.
+	Kind LintIssueKind
+
+	// Path is the path of the template file, or, for
+	// LintMissingTemplate, the path that doesn't match any files.
+	Path string
+
+	// Name is the name of the undefined template, for
+	// LintUndefinedTemplate.
+	Name string
+
+	// Component is the Component whose Templates method returned the
+	// Path, if it's known.
+	Component Component
+
+	// Err is the underlying error, for LintParseError.
+	Err error
+}
+
+// String describes the LintIssue in a single line.
+func (l LintIssue) String() string {
+	var msg string
+	switch l.Kind {
+	case LintMissingTemplate:
+		msg = "doesn't match any template files"
+	case LintParseError:
+		msg = fmt.Sprintf("can't be parsed: %s", l.Err)
+	case LintUndefinedTemplate:
+		msg = fmt.Sprintf("executes undefined template %q", l.Name)
+	case LintUnusedTemplate:
+		msg = "isn't used"
+	default:
+		msg = string(l.Kind)
+	}
+	if l.Component != nil {
+		return fmt.Sprintf("%s: %s (used by %T)", l.Path, msg, l.Component)
+	}
+	return fmt.Sprintf("%s: %s", l.Path, msg)
+}
+
+// lintTemplateExt is the extension of the files LintFS and Lint consider to
+// be templates when looking for unused ones.
+const lintTemplateExt = ".tmpl"
+
+// Lint checks the templates used by the pages, and the Components they use,
+// for problems that would otherwise only show up when the pages are
+// rendered, or not at all:
+//
+//   - paths returned by Templates methods that don't match any files
+//   - template files that can't be parsed
+//   - {{ template }} actions executing templates the page doesn't define
+//   - .tmpl files in the Site's TemplateDir that no page uses
+//
+// Like DebugHandler, Lint can't discover a Site's pages on its own, so every
+// page should be passed to it, usually with zero values for any data, or
+// templates will be reported as unused. Functions aren't checked, as they
+// can differ between renders, so templates using undefined functions will
+// still fail to render.
+//
+// The LintIssues are returned sorted by Path. An error is only returned if a
+// page's Components can't be resolved, or the Site's TemplateDir can't be
+// listed.
+func Lint[SiteType Site](ctx context.Context, site SiteType, pages ...Renderable) ([]LintIssue, error) {
+	var issues []LintIssue
+	used := map[string]struct{}{}
+	siteDir := site.TemplateDir(ctx)
+	for _, page := range pages {
+		components, err := getRecursiveComponents(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		var files []templatePath
+		for _, pattern := range getComponentTemplatePaths(ctx, site, components) {
+			matches, err := fs.Glob(pattern.dir, pattern.path)
+			if err != nil || len(matches) < 1 {
+				issues = append(issues, LintIssue{Kind: LintMissingTemplate, Path: pattern.path, Component: pattern.component})
+				continue
+			}
+			for _, match := range matches {
+				if _, ownDir := pattern.component.(TemplateDirProvider); !ownDir {
+					used[match] = struct{}{}
+				}
+				files = append(files, templatePath{dir: pattern.dir, path: match, component: pattern.component, delims: pattern.delims})
+			}
+		}
+		issues = append(issues, lintTemplateSet(files)...)
+	}
+	unused, err := unusedTemplates(siteDir, func(file string) bool {
+		_, ok := used[file]
+		return ok
+	})
+	if err != nil {
+		return nil, err
+	}
+	issues = append(issues, unused...)
+	return sortLintIssues(issues), nil
+}
+
+// LintFS checks every .tmpl file in the fs.FS for problems, without needing
+// a Site, by treating them as if they were all parsed together. It reports
+// files that can't be parsed, {{ template }} actions executing templates
+// none of the files define, and files that aren't used: that don't match any
+// of the `used` patterns, which use the syntax of path.Match, and aren't
+// executed by any of the other files. If `used` is nil, unused files aren't
+// reported. The delimiters are the template delimiters to use; if they're
+// empty, the defaults are used.
+//
+// LintFS is less accurate than Lint, which knows which templates are parsed
+// together, but is useful for tools, like the temple command, that can't
+// load the Site.
+func LintFS(dir fs.FS, used []string, leftDelim, rightDelim string) ([]LintIssue, error) {
+	var files []templatePath
+	err := fs.WalkDir(dir, ".", func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || path.Ext(file) != lintTemplateExt {
+			return nil
+		}
+		files = append(files, templatePath{dir: dir, path: file, delims: [2]string{leftDelim, rightDelim}})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing templates: %w", err)
+	}
+	issues := lintTemplateSet(files)
+	if used == nil {
+		return sortLintIssues(issues), nil
+	}
+	executed := map[string]struct{}{}
+	for _, file := range files {
+		trees, err := parseTemplateFile(file)
+		if err != nil {
+			continue
+		}
+		for _, tree := range trees {
+			executedTemplates(tree.Root, executed)
+		}
+	}
+	unused, err := unusedTemplates(dir, func(file string) bool {
+		if _, ok := executed[file]; ok {
+			return true
+		}
+		for _, pattern := range used {
+			if ok, _ := path.Match(pattern, file); ok {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	issues = append(issues, unused...)
+	return sortLintIssues(issues), nil
+}
+
+// parseTemplateFile parses the template file, returning the trees for every
+// template it defines, including itself, without checking its functions.
+func parseTemplateFile(file templatePath) (map[string]*parse.Tree, error) {
+	contents, err := fs.ReadFile(file.dir, file.path)
+	if err != nil {
+		return nil, err
+	}
+	trees := map[string]*parse.Tree{}
+	tree := parse.New(file.path)
+	tree.Mode = parse.SkipFuncCheck
+	_, err = tree.Parse(string(contents), file.delims[0], file.delims[1], trees)
+	if err != nil {
+		return nil, err
+	}
+	// an empty file doesn't add itself to trees, but it can still be
+	// executed
+	if _, ok := trees[file.path]; !ok {
+		trees[file.path] = tree
+	}
+	return trees, nil
+}
+
+// lintTemplateSet reports the files that can't be parsed, and the templates
+// executed by the files that none of them define.
+func lintTemplateSet(files []templatePath) []LintIssue {
+	var issues []LintIssue
+	defined := map[string]struct{}{}
+	executed := map[string]map[string]struct{}{}
+	for _, file := range files {
+		trees, err := parseTemplateFile(file)
+		if err != nil {
+			issues = append(issues, LintIssue{Kind: LintParseError, Path: file.path, Component: file.component, Err: err})
+			continue
+		}
+		names := map[string]struct{}{}
+		for name, tree := range trees {
+			defined[name] = struct{}{}
+			executedTemplates(tree.Root, names)
+		}
+		executed[file.path] = names
+	}
+	for _, file := range files {
+		for name := range executed[file.path] {
+			if _, ok := defined[name]; ok {
+				continue
+			}
+			issues = append(issues, LintIssue{Kind: LintUndefinedTemplate, Path: file.path, Name: name, Component: file.component})
+		}
+	}
+	return issues
+}
+
+// unusedTemplates reports the template files in dir that `used` returns
+// false for.
+func unusedTemplates(dir fs.FS, used func(string) bool) ([]LintIssue, error) {
+	var issues []LintIssue
+	err := fs.WalkDir(dir, ".", func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || path.Ext(file) != lintTemplateExt || used(file) {
+			return nil
+		}
+		issues = append(issues, LintIssue{Kind: LintUnusedTemplate, Path: file})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing templates: %w", err)
+	}
+	return issues, nil
+}
+
+// sortLintIssues sorts the issues by Path, Kind, and Name, and removes any
+// duplicates, like the same template being reported for more than one page.
+func sortLintIssues(issues []LintIssue) []LintIssue {
+	slices.SortStableFunc(issues, func(a, b LintIssue) int {
+		return cmp.Or(
+			strings.Compare(a.Path, b.Path),
+			strings.Compare(string(a.Kind), string(b.Kind)),
+			strings.Compare(a.Name, b.Name),
+		)
+	})
+	return slices.CompactFunc(issues, func(a, b LintIssue) bool {
+		return a.Path == b.Path && a.Kind == b.Kind && a.Name == b.Name
+	})
+}
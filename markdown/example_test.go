@@ -0,0 +1,81 @@
+package markdown_test
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"os"
+	"strings"
+	"testing/fstest"
+
+	"impractical.co/temple"
+	"impractical.co/temple/cache"
+	"impractical.co/temple/markdown"
+)
+
+type PostPage struct {
+	Post markdown.Document
+}
+
+func (PostPage) Templates(_ context.Context) []string {
+	return []string{"post.html.tmpl"}
+}
+
+func (p PostPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{p.Post}
+}
+
+func (PostPage) Key(_ context.Context) string {
+	return "post.html.tmpl"
+}
+
+func (PostPage) ExecutedTemplate(_ context.Context) string {
+	return "post.html.tmpl"
+}
+
+// paragraphs is a stand-in for a real Markdown parser, like goldmark, that
+// only knows about paragraphs
+var paragraphs = markdown.RendererFunc(func(_ context.Context, src []byte) ([]byte, error) {
+	var out strings.Builder
+	for _, para := range strings.Split(strings.TrimSpace(string(src)), "\n\n") {
+		out.WriteString("<p>" + html.EscapeString(para) + "</p>")
+	}
+	return []byte(out.String()), nil
+})
+
+func Example() {
+	templates := fstest.MapFS{
+		"post.html.tmpl": {Data: []byte(`<h1>{{ .Page.Post.Title }}</h1>
+<p>Tagged {{ range .Page.Post.Strings "tags" }}#{{ . }} {{ end }}</p>
+{{ template "markdown/document.html.tmpl" .Page.Post }}`)},
+		"posts/hello.md": {Data: []byte(`---
+title: "Hello, world"
+tags:
+  - go
+  - templates
+---
+This is my first post.
+
+It has two paragraphs.
+`)},
+	}
+	site := temple.NewCachedSite(templates)
+	ctx := context.Background()
+
+	post, err := markdown.Markdown{
+		Path:      "posts/hello.md",
+		Renderer:  paragraphs,
+		Sanitizer: markdown.Trusted{},
+		Cache:     &cache.Memory{},
+	}.Load(ctx, site)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	temple.Render(ctx, os.Stdout, site, PostPage{Post: post})
+
+	//Output:
+	// <h1>Hello, world</h1>
+	// <p>Tagged #go #templates </p>
+	// <article class="markdown"><p>This is my first post.</p><p>It has two paragraphs.</p></article>
+}
@@ -0,0 +1,119 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// frontmatterDelim is the line that starts and ends a document's
+// frontmatter.
+const frontmatterDelim = "---"
+
+// splitFrontmatter separates the frontmatter at the start of the source, if
+// there is any, from the Markdown body that follows it.
+func splitFrontmatter(src []byte) ([]byte, []byte, bool) {
+	src = bytes.TrimPrefix(src, []byte("\ufeff"))
+	first, rest, ok := cutLine(src)
+	if !ok || strings.TrimSpace(string(first)) != frontmatterDelim {
+		return nil, src, false
+	}
+	var front []byte
+	for len(rest) > 0 {
+		var line []byte
+		line, rest, _ = cutLine(rest)
+		if strings.TrimSpace(string(line)) == frontmatterDelim {
+			return front, rest, true
+		}
+		front = append(front, line...)
+		front = append(front, '\n')
+	}
+	// an unterminated block isn't frontmatter
+	return nil, src, false
+}
+
+// cutLine returns the first line of src, without its line ending, and the
+// rest of src. It returns false if src is empty.
+func cutLine(src []byte) ([]byte, []byte, bool) {
+	if len(src) < 1 {
+		return nil, nil, false
+	}
+	line, rest, found := bytes.Cut(src, []byte("\n"))
+	if !found {
+		rest = nil
+	}
+	return bytes.TrimSuffix(line, []byte("\r")), rest, true
+}
+
+// parseFrontmatter parses a simple subset of YAML: one "key: value" pair per
+// line, where values are strings, optionally quoted; integers; booleans; or
+// lists of strings, either in square brackets, like [go, templates], or as a
+// block of "- item" lines after a key with no value. Blank lines and lines
+// starting with # are ignored. Nested maps, multi-line strings, and the rest
+// of YAML aren't supported.
+func parseFrontmatter(src []byte) (map[string]any, error) {
+	results := map[string]any{}
+	// listKey is the key whose block list items are being read, if any
+	var listKey string
+	for num, line := range strings.Split(string(src), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if item, ok := strings.CutPrefix(trimmed, "-"); ok && (item == "" || item[0] == ' ' || item[0] == '\t') {
+			if listKey == "" {
+				return nil, fmt.Errorf("line %d: list item %q isn't part of a list", num+1, trimmed)
+			}
+			items, _ := results[listKey].([]string)
+			results[listKey] = append(items, unquote(strings.TrimSpace(item)))
+			continue
+		}
+		key, value, ok := strings.Cut(trimmed, ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected \"key: value\", got %q", num+1, trimmed)
+		}
+		value = strings.TrimSpace(value)
+		listKey = ""
+		if value == "" {
+			// the value may be a block list on the following lines
+			listKey = key
+		}
+		results[key] = parseFrontmatterValue(value)
+	}
+	return results, nil
+}
+
+// parseFrontmatterValue converts a frontmatter value to a string, int, bool,
+// or []string.
+func parseFrontmatterValue(value string) any {
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		var items []string
+		for _, item := range strings.Split(value[1:len(value)-1], ",") {
+			item = unquote(strings.TrimSpace(item))
+			if item != "" {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	if unquoted := unquote(value); unquoted != value {
+		return unquoted
+	}
+	if i, err := strconv.Atoi(value); err == nil {
+		return i
+	}
+	if b, err := strconv.ParseBool(value); err == nil {
+		return b
+	}
+	return value
+}
+
+// unquote removes matching single or double quotes from around the value.
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
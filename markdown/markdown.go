@@ -0,0 +1,229 @@
+// Package markdown provides temple Components for rendering Markdown files,
+// with frontmatter, as sanitized HTML.
+//
+// temple doesn't include a Markdown parser. Any parser can be used by
+// wrapping it in a Renderer; for goldmark, that looks like:
+//
+//	renderer := markdown.RendererFunc(func(_ context.Context, src []byte) ([]byte, error) {
+//		var buf bytes.Buffer
+//		err := goldmark.Convert(src, &buf)
+//		return buf.Bytes(), err
+//	})
+//
+// A Markdown loads a file, and its Load method returns a Document that can be
+// included in a page's UseComponents output, with its frontmatter available
+// to templates as .Frontmatter and its contents rendered by executing its
+// template:
+//
+//	<h1>{{ .Page.Post.Title }}</h1>
+//	{{ template "markdown/document.html.tmpl" .Page.Post }}
+package markdown
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+
+	"impractical.co/temple"
+	"impractical.co/temple/cache"
+)
+
+//go:embed templates
+var templates embed.FS
+
+var (
+	// ErrNoRenderer is returned when a Markdown without a Renderer is
+	// loaded.
+	ErrNoRenderer = errors.New("no markdown renderer configured")
+
+	// ErrNoSanitizer is returned when a Markdown without a Sanitizer is
+	// loaded.
+	ErrNoSanitizer = errors.New("no markdown sanitizer configured")
+)
+
+// Renderer converts Markdown to HTML.
+type Renderer interface {
+	// RenderMarkdown returns the HTML for the Markdown source.
+	RenderMarkdown(ctx context.Context, src []byte) ([]byte, error)
+}
+
+// RendererFunc is a function that fulfills the Renderer interface.
+type RendererFunc func(ctx context.Context, src []byte) ([]byte, error)
+
+// RenderMarkdown calls the RendererFunc.
+func (fn RendererFunc) RenderMarkdown(ctx context.Context, src []byte) ([]byte, error) {
+	return fn(ctx, src)
+}
+
+var _ temple.Sanitizer = Trusted{}
+
+// Trusted is a temple.Sanitizer that doesn't remove anything. It should only
+// be used for Markdown the Site's authors wrote themselves, never for
+// user-generated content.
+type Trusted struct{}
+
+// Sanitize returns the HTML unchanged.
+func (Trusted) Sanitize(s string) string {
+	return s
+}
+
+// Markdown describes a Markdown file to load, and how to render it.
+type Markdown struct {
+	// Path is the path to the Markdown file within the fs.FS it's loaded
+	// from.
+	Path string
+
+	// Renderer converts the Markdown to HTML. It's required.
+	Renderer Renderer
+
+	// Sanitizer cleans the HTML the Renderer outputs. It's required, as
+	// what's safe depends on who wrote the Markdown: temple.BasicSanitizer
+	// suits short user-generated content, but removes headings, images,
+	// and tables, so documentation Sites will usually want a more
+	// permissive policy, or Trusted for Markdown they wrote themselves.
+	Sanitizer temple.Sanitizer
+
+	// Cache stores the sanitized HTML, keyed by a hash of the Markdown and
+	// the types of the Renderer and Sanitizer, so unchanged files aren't
+	// rendered again. If nil, files are rendered every time they're
+	// loaded.
+	Cache cache.Backend
+
+	// CacheVersion is included in the Cache's keys. Markdowns sharing a
+	// Cache whose Renderers or Sanitizers have the same types but are
+	// configured differently, like two bluemonday policies, need to use
+	// different CacheVersions, and changing it discards everything cached
+	// before.
+	CacheVersion string
+}
+
+// Load reads the Markdown file from the Site's TemplateDir and renders it.
+func (m Markdown) Load(ctx context.Context, site temple.Site) (Document, error) {
+	return m.LoadFS(ctx, site.TemplateDir(ctx))
+}
+
+// LoadFS reads the Markdown file from the fs.FS and renders it.
+func (m Markdown) LoadFS(ctx context.Context, dir fs.FS) (Document, error) {
+	src, err := fs.ReadFile(dir, m.Path)
+	if err != nil {
+		return Document{}, fmt.Errorf("error reading markdown %q: %w", m.Path, err)
+	}
+	return m.Parse(ctx, src)
+}
+
+// Parse renders the Markdown source, with any frontmatter, as if it had been
+// loaded from Path.
+func (m Markdown) Parse(ctx context.Context, src []byte) (Document, error) {
+	doc := Document{Path: m.Path, Frontmatter: map[string]any{}}
+	front, body, ok := splitFrontmatter(src)
+	if ok {
+		parsed, err := parseFrontmatter(front)
+		if err != nil {
+			return Document{}, fmt.Errorf("error parsing frontmatter of %q: %w", m.Path, err)
+		}
+		doc.Frontmatter = parsed
+	}
+	html, err := m.render(ctx, body)
+	if err != nil {
+		return Document{}, err
+	}
+	doc.Body = html
+	return doc, nil
+}
+
+// render converts the Markdown body to sanitized HTML, using the Cache if
+// there is one.
+func (m Markdown) render(ctx context.Context, body []byte) (template.HTML, error) {
+	if m.Renderer == nil {
+		return "", fmt.Errorf("error rendering markdown %q: %w", m.Path, ErrNoRenderer)
+	}
+	if m.Sanitizer == nil {
+		return "", fmt.Errorf("error rendering markdown %q: %w", m.Path, ErrNoSanitizer)
+	}
+	key := m.cacheKey(body)
+	if m.Cache != nil {
+		cached, err := m.Cache.Get(ctx, key)
+		if err == nil {
+			return template.HTML(cached), nil // #nosec G203
+		}
+	}
+	rendered, err := m.Renderer.RenderMarkdown(ctx, body)
+	if err != nil {
+		return "", fmt.Errorf("error rendering markdown %q: %w", m.Path, err)
+	}
+	html := m.Sanitizer.Sanitize(string(rendered))
+	if m.Cache != nil {
+		// caching is best-effort, the Document is still usable
+		_ = m.Cache.Set(ctx, key, []byte(html), 0)
+	}
+	return template.HTML(html), nil // #nosec G203
+}
+
+// cacheKey returns the key the HTML for the Markdown body is cached under,
+// which changes if the body, the types of the Renderer or Sanitizer, or the
+// CacheVersion do.
+func (m Markdown) cacheKey(body []byte) string {
+	hash := sha256.New()
+	// the lengths are included so the parts can't run together
+	fmt.Fprintf(hash, "%d:%s", len(m.CacheVersion), m.CacheVersion)
+	renderer, sanitizer := fmt.Sprintf("%T", m.Renderer), fmt.Sprintf("%T", m.Sanitizer)
+	fmt.Fprintf(hash, "%d:%s%d:%s", len(renderer), renderer, len(sanitizer), sanitizer)
+	hash.Write(body)
+	return "temple/markdown:" + hex.EncodeToString(hash.Sum(nil))
+}
+
+var (
+	_ temple.Component           = Document{}
+	_ temple.TemplateDirProvider = Document{}
+)
+
+// Document is a rendered Markdown file. It's a Component, so it can be
+// included in a page's UseComponents output, and its template renders its
+// Body in an <article> element:
+//
+//	{{ template "markdown/document.html.tmpl" .Page.Post }}
+type Document struct {
+	// Path is the path the Markdown was loaded from.
+	Path string
+
+	// Frontmatter holds the values from the document's frontmatter. Its
+	// values are strings, ints, bools, or, for lists, []strings.
+	Frontmatter map[string]any
+
+	// Body is the sanitized HTML the Markdown was rendered to.
+	Body template.HTML
+}
+
+// Templates returns the template needed to render the Document.
+func (Document) Templates(_ context.Context) []string {
+	return []string{"markdown/document.html.tmpl"}
+}
+
+// TemplateDir returns the fs.FS containing the Document's template.
+func (Document) TemplateDir(_ context.Context) fs.FS {
+	return temple.SubFS(templates, "templates")
+}
+
+// String returns the frontmatter value for the key, if it's a string, or an
+// empty string if it isn't.
+func (d Document) String(key string) string {
+	val, _ := d.Frontmatter[key].(string)
+	return val
+}
+
+// Strings returns the frontmatter value for the key, if it's a list, or nil
+// if it isn't.
+func (d Document) Strings(key string) []string {
+	val, _ := d.Frontmatter[key].([]string)
+	return val
+}
+
+// Title returns the "title" value from the Document's frontmatter.
+func (d Document) Title() string {
+	return d.String("title")
+}
@@ -0,0 +1,122 @@
+package markdown_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"impractical.co/temple"
+	"impractical.co/temple/cache"
+	"impractical.co/temple/markdown"
+)
+
+func TestMarkdownFrontmatter(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		src     string
+		want    map[string]any
+		wantErr bool
+	}{
+		"inline-values": {
+			src:  "---\ntitle: \"Hello: world\"\ncount: 3\ndraft: true\ntags: [go, 'templates']\n---\n",
+			want: map[string]any{"title": "Hello: world", "count": 3, "draft": true, "tags": []string{"go", "templates"}},
+		},
+		"block-list": {
+			src:  "---\ntags:\n  - go\n  - \"templates\"\n# a comment\ntitle: Hello\n---\n",
+			want: map[string]any{"tags": []string{"go", "templates"}, "title": "Hello"},
+		},
+		"empty-value": {
+			src:  "---\nsummary:\ntitle: Hello\n---\n",
+			want: map[string]any{"summary": "", "title": "Hello"},
+		},
+		"negative-number": {
+			src:  "---\noffset: -3\n---\n",
+			want: map[string]any{"offset": -3},
+		},
+		"stray-list-item": {
+			src:     "---\ntitle: Hello\n- go\n---\n",
+			wantErr: true,
+		},
+		"no-frontmatter": {
+			src:  "Just a body.\n",
+			want: map[string]any{},
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			doc, err := markdown.Markdown{Renderer: paragraphs, Sanitizer: markdown.Trusted{}}.Parse(context.Background(), []byte(test.src))
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got frontmatter %v", doc.Frontmatter)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(doc.Frontmatter, test.want) {
+				t.Errorf("expected frontmatter %#v, got %#v", test.want, doc.Frontmatter)
+			}
+		})
+	}
+}
+
+func TestMarkdownNoSanitizer(t *testing.T) {
+	t.Parallel()
+
+	_, err := markdown.Markdown{Renderer: paragraphs}.Parse(context.Background(), []byte("Hello"))
+	if !errors.Is(err, markdown.ErrNoSanitizer) {
+		t.Errorf("expected ErrNoSanitizer, got %v", err)
+	}
+}
+
+func TestMarkdownCacheKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	backend := &cache.Memory{}
+	src := []byte(`<script>alert("hi")</script>`)
+	raw := markdown.RendererFunc(func(_ context.Context, src []byte) ([]byte, error) {
+		return src, nil
+	})
+
+	trusted, err := markdown.Markdown{Renderer: raw, Sanitizer: markdown.Trusted{}, Cache: backend}.Parse(ctx, src)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(trusted.Body) != string(src) {
+		t.Errorf("expected trusted body %q, got %q", src, trusted.Body)
+	}
+
+	// the same Markdown with a stricter Sanitizer can't be served the
+	// trusted HTML from the cache
+	basic, err := markdown.Markdown{Renderer: raw, Sanitizer: temple.BasicSanitizer{}, Cache: backend}.Parse(ctx, src)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if basic.Body != "" {
+		t.Errorf("expected sanitized body to be empty, got %q", basic.Body)
+	}
+
+	// Sanitizers of the same type are told apart by their CacheVersion
+	for _, version := range []string{"v1", "v2"} {
+		sanitizer := sanitizerFunc(func(string) string { return version })
+		doc, err := markdown.Markdown{Renderer: raw, Sanitizer: sanitizer, Cache: backend, CacheVersion: version}.Parse(ctx, src)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if string(doc.Body) != version {
+			t.Errorf("expected body %q, got %q", version, doc.Body)
+		}
+	}
+}
+
+type sanitizerFunc func(string) string
+
+func (fn sanitizerFunc) Sanitize(s string) string {
+	return fn(s)
+}
@@ -0,0 +1,8 @@
+package markdown
+
+import "impractical.co/temple"
+
+// Package returns the temple.ComponentPackage for Document.
+func Package() (temple.ComponentPackage, error) {
+	return temple.NewComponentPackage("markdown", templates, "templates/markdown", "", Document{})
+}
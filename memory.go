@@ -0,0 +1,199 @@
+package temple
+
+import (
+	"context"
+	"html/template"
+	"reflect"
+	"text/template/parse"
+	"unsafe"
+)
+
+// estimatedNodeSize is roughly how many bytes a node in a template's parse
+// tree takes up, not counting any text it holds. Nodes vary in size, so this
+// is an average of the common ones.
+const estimatedNodeSize = 96
+
+// TemplateCacheStats describes the templates held in a template cache, and
+// estimates how much memory they use, for capacity planning.
+type TemplateCacheStats struct {
+	// Keys is the number of page keys with cached templates.
+	Keys int `json:"keys"`
+
+	// Templates is the number of named templates cached across every
+	// key, including templates defined with {{ define }}.
+	Templates int `json:"templates"`
+
+	// Nodes is the number of nodes in the parse trees of every cached
+	// template.
+	Nodes int `json:"nodes"`
+
+	// TextBytes is the number of bytes of literal text in the cached
+	// templates, which is counted exactly.
+	TextBytes int64 `json:"textBytes"`
+
+	// EstimatedBytes is an estimate of how much memory the cached
+	// templates use, based on the number of nodes and TextBytes. It
+	// doesn't include the memory used by the functions in their
+	// FuncMaps.
+	EstimatedBytes int64 `json:"estimatedBytes"`
+}
+
+// add includes the template and every template associated with it in the
+// TemplateCacheStats.
+func (s *TemplateCacheStats) add(tmpl *template.Template) {
+	s.Keys++
+	for _, t := range tmpl.Templates() {
+		s.Templates++
+		if t.Tree == nil || t.Tree.Root == nil {
+			continue
+		}
+		nodes, text := countNodes(t.Tree.Root)
+		s.Nodes += nodes
+		s.TextBytes += text
+	}
+	s.EstimatedBytes = int64(s.Nodes)*estimatedNodeSize + s.TextBytes
+}
+
+// countNodes returns the number of nodes in the parse tree rooted at node,
+// and the number of bytes of literal text they hold.
+func countNodes(node parse.Node) (int, int64) {
+	if node == nil {
+		return 0, 0
+	}
+	nodes, text := 1, int64(0)
+	addAll := func(children ...parse.Node) {
+		for _, child := range children {
+			n, t := countNodes(child)
+			nodes += n
+			text += t
+		}
+	}
+	switch n := node.(type) {
+	case *parse.TextNode:
+		text += int64(len(n.Text))
+	case *parse.StringNode:
+		text += int64(len(n.Text))
+	case *parse.ListNode:
+		for _, child := range n.Nodes {
+			addAll(child)
+		}
+	case *parse.ActionNode:
+		addAll(pipeNode(n.Pipe))
+	case *parse.TemplateNode:
+		addAll(pipeNode(n.Pipe))
+	case *parse.IfNode:
+		addAll(branchNodes(n.BranchNode)...)
+	case *parse.RangeNode:
+		addAll(branchNodes(n.BranchNode)...)
+	case *parse.WithNode:
+		addAll(branchNodes(n.BranchNode)...)
+	case *parse.PipeNode:
+		for _, decl := range n.Decl {
+			addAll(decl)
+		}
+		for _, cmd := range n.Cmds {
+			addAll(cmd)
+		}
+	case *parse.CommandNode:
+		addAll(n.Args...)
+	}
+	return nodes, text
+}
+
+// pipeNode returns the PipeNode as a parse.Node, or nil if it's nil, so nil
+// PipeNodes aren't counted.
+func pipeNode(pipe *parse.PipeNode) parse.Node {
+	if pipe == nil {
+		return nil
+	}
+	return pipe
+}
+
+// branchNodes returns the children of an if, range, or with action.
+func branchNodes(branch parse.BranchNode) []parse.Node {
+	children := []parse.Node{pipeNode(branch.Pipe)}
+	if branch.List != nil {
+		children = append(children, branch.List)
+	}
+	if branch.ElseList != nil {
+		children = append(children, branch.ElseList)
+	}
+	return children
+}
+
+// GraphCacheStats describes the ComponentGraphs held in a GraphCacher, and
+// estimates how much memory they use, for capacity planning.
+type GraphCacheStats struct {
+	// Keys is the number of page keys with cached ComponentGraphs.
+	Keys int `json:"keys"`
+
+	// Components is the number of Components cached across every key.
+	Components int `json:"components"`
+
+	// EstimatedBytes is an estimate of how much memory the cached
+	// ComponentGraphs use. It counts the values of the Components
+	// themselves, but not any memory they point to, like the contents
+	// of slices, maps, or strings.
+	EstimatedBytes int64 `json:"estimatedBytes"`
+}
+
+// add includes the ComponentGraph in the GraphCacheStats.
+func (s *GraphCacheStats) add(graph *ComponentGraph) {
+	s.Keys++
+	s.Components += len(graph.components)
+	s.EstimatedBytes += int64(unsafe.Sizeof(ComponentGraph{}))
+	for _, comp := range graph.components {
+		s.EstimatedBytes += int64(unsafe.Sizeof(comp)) + int64(reflect.TypeOf(comp).Size())
+	}
+}
+
+// TemplateCacheStatser is an optional interface for TemplateCachers. Those
+// fulfilling it can report on the templates they hold, for CacheMemory.
+type TemplateCacheStatser interface {
+	// TemplateCacheStats returns the TemplateCacheStats for the
+	// templates currently cached.
+	TemplateCacheStats(ctx context.Context) TemplateCacheStats
+}
+
+// GraphCacheStatser is an optional interface for GraphCachers. Those
+// fulfilling it can report on the ComponentGraphs they hold, for
+// CacheMemory.
+type GraphCacheStatser interface {
+	// GraphCacheStats returns the GraphCacheStats for the
+	// ComponentGraphs currently cached.
+	GraphCacheStats(ctx context.Context) GraphCacheStats
+}
+
+// CacheMemoryReport describes the caches of a Site, and estimates how much
+// memory they use, for capacity planning. It can be serialized as JSON.
+type CacheMemoryReport struct {
+	// Templates describes the Site's template cache, if it's a
+	// TemplateCacheStatser.
+	Templates *TemplateCacheStats `json:"templates,omitempty"`
+
+	// Graphs describes the Site's ComponentGraph cache, if it's a
+	// GraphCacheStatser.
+	Graphs *GraphCacheStats `json:"graphs,omitempty"`
+
+	// EstimatedBytes is the total estimated memory used by the Site's
+	// caches.
+	EstimatedBytes int64 `json:"estimatedBytes"`
+}
+
+// CacheMemory returns a CacheMemoryReport for the Site's caches. Only caches
+// that can report on themselves, by implementing TemplateCacheStatser or
+// GraphCacheStatser, are included; CachedSite and GraphCache both do.
+func CacheMemory(ctx context.Context, site Site) CacheMemoryReport {
+	var report CacheMemoryReport
+	if statser, ok := site.(TemplateCacheStatser); ok {
+		stats := statser.TemplateCacheStats(ctx)
+		report.Templates = &stats
+		report.EstimatedBytes += stats.EstimatedBytes
+	}
+	if statser, ok := site.(GraphCacheStatser); ok {
+		stats := statser.GraphCacheStats(ctx)
+		report.Graphs = &stats
+		report.EstimatedBytes += stats.EstimatedBytes
+	}
+	return report
+}
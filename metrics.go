@@ -0,0 +1,103 @@
+package temple
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// pageKeyAttr is the metric attribute holding the Key of the page
+	// that was rendered.
+	pageKeyAttr = attribute.Key("temple.page.key")
+
+	// cacheResultAttr is the metric attribute recording whether a
+	// template cache lookup was a "hit" or a "miss".
+	cacheResultAttr = attribute.Key("temple.cache.result")
+)
+
+// renderMetrics holds the OpenTelemetry instruments Render records to.
+type renderMetrics struct {
+	duration     metric.Float64Histogram
+	errors       metric.Int64Counter
+	bytes        metric.Int64Counter
+	cacheLookups metric.Int64Counter
+	queueWait    metric.Float64Histogram
+}
+
+// getRenderMetrics returns the instruments Render records to, creating them
+// using the global MeterProvider the first time it's called. The global
+// MeterProvider forwards to whatever MeterProvider is set later, so the
+// instruments only need to be created once.
+var getRenderMetrics = sync.OnceValue(func() renderMetrics {
+	meter := otel.GetMeterProvider().Meter("impractical.co/temple")
+	var metrics renderMetrics
+	var err error
+	metrics.duration, err = meter.Float64Histogram("temple.render.duration",
+		metric.WithDescription("How long it took to render a page, including any server error page."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	metrics.errors, err = meter.Int64Counter("temple.render.errors",
+		metric.WithDescription("The number of pages that failed to render."),
+		metric.WithUnit("{error}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	metrics.bytes, err = meter.Int64Counter("temple.render.written",
+		metric.WithDescription("The number of bytes of rendered pages written."),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	metrics.cacheLookups, err = meter.Int64Counter("temple.template.cache.lookups",
+		metric.WithDescription("The number of times a page's templates were looked up in the Site's TemplateCacher, by whether they were found."),
+		metric.WithUnit("{lookup}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	metrics.queueWait, err = meter.Float64Histogram("temple.render.queue_wait",
+		metric.WithDescription("How long a page waited for its RenderLimiters before rendering."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	return metrics
+})
+
+// recordRenderMetrics records the RenderResult to the OpenTelemetry
+// instruments for Render. Template cache lookups are only recorded if the
+// page's templates were looked up in the Site's TemplateCacher.
+func recordRenderMetrics(ctx context.Context, result RenderResult) {
+	metrics := getRenderMetrics()
+	attrs := metric.WithAttributes(pageKeyAttr.String(result.Key))
+	// the instruments are nil if they couldn't be created
+	if metrics.duration != nil {
+		metrics.duration.Record(ctx, result.Duration.Seconds(), attrs)
+	}
+	if metrics.bytes != nil {
+		metrics.bytes.Add(ctx, result.BytesWritten, attrs)
+	}
+	if metrics.errors != nil && result.Err != nil {
+		metrics.errors.Add(ctx, 1, attrs)
+	}
+	if result.lookedUpTemplate && metrics.cacheLookups != nil {
+		cacheResult := "miss"
+		if result.CachedTemplate {
+			cacheResult = "hit"
+		}
+		metrics.cacheLookups.Add(ctx, 1, metric.WithAttributes(
+			pageKeyAttr.String(result.Key),
+			cacheResultAttr.String(cacheResult),
+		))
+	}
+}
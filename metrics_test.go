@@ -0,0 +1,166 @@
+package temple_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	"impractical.co/temple"
+)
+
+// lookupRecorder is a metric.MeterProvider that records the cache result of
+// every temple.template.cache.lookups measurement, by page key, and ignores
+// everything else.
+type lookupRecorder struct {
+	noop.MeterProvider
+	mu      sync.Mutex
+	lookups map[string][]string
+}
+
+func (l *lookupRecorder) Meter(_ string, _ ...metric.MeterOption) metric.Meter {
+	return lookupMeter{recorder: l}
+}
+
+// globalLookupRecorder returns the lookupRecorder set as the global
+// MeterProvider. It's only set once, as temple's instruments keep using the
+// first MeterProvider set, even if the test is run more than once.
+var globalLookupRecorder = sync.OnceValue(func() *lookupRecorder {
+	recorder := &lookupRecorder{lookups: map[string][]string{}}
+	otel.SetMeterProvider(recorder)
+	return recorder
+})
+
+func (l *lookupRecorder) reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.lookups, key)
+}
+
+func (l *lookupRecorder) get(key string) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.lookups[key])
+}
+
+type lookupMeter struct {
+	noop.Meter
+	recorder *lookupRecorder
+}
+
+func (m lookupMeter) Int64Counter(name string, _ ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	if name != "temple.template.cache.lookups" {
+		return noop.Int64Counter{}, nil
+	}
+	return lookupCounter{recorder: m.recorder}, nil
+}
+
+type lookupCounter struct {
+	noop.Int64Counter
+	recorder *lookupRecorder
+}
+
+func (c lookupCounter) Add(_ context.Context, _ int64, opts ...metric.AddOption) {
+	attrs := metric.NewAddConfig(opts).Attributes()
+	key, _ := attrs.Value(attribute.Key("temple.page.key"))
+	result, _ := attrs.Value(attribute.Key("temple.cache.result"))
+	c.recorder.mu.Lock()
+	defer c.recorder.mu.Unlock()
+	c.recorder.lookups[key.AsString()] = append(c.recorder.lookups[key.AsString()], result.AsString())
+}
+
+type metricsPage struct {
+	key      string
+	redirect bool
+	fail     bool
+}
+
+func (metricsPage) Templates(_ context.Context) []string {
+	return []string{"metrics.html.tmpl"}
+}
+
+func (p metricsPage) Key(_ context.Context) string {
+	return p.key
+}
+
+func (metricsPage) ExecutedTemplate(_ context.Context) string {
+	return "metrics.html.tmpl"
+}
+
+func (p metricsPage) Respond(_ context.Context) (temple.Response, error) {
+	if p.fail {
+		return temple.Response{}, errors.New("broken page")
+	}
+	if p.redirect {
+		return temple.Response{Redirect: "/elsewhere"}, nil
+	}
+	return temple.Response{}, nil
+}
+
+// uncachedSite is a Site that isn't a TemplateCacher.
+type uncachedSite struct {
+	fs fstest.MapFS
+}
+
+func (s uncachedSite) TemplateDir(_ context.Context) fs.FS {
+	return s.fs
+}
+
+func TestRenderMetricsCacheLookups(t *testing.T) {
+	// the global MeterProvider is shared, so this can't run in parallel
+	// with other tests that set it
+	recorder := globalLookupRecorder()
+
+	templates := fstest.MapFS{
+		"metrics.html.tmpl": {Data: []byte(`<p>metrics</p>`)},
+	}
+	cases := map[string]struct {
+		site    temple.Site
+		page    metricsPage
+		renders int
+		want    []string
+	}{
+		"cached": {
+			site:    temple.NewCachedSite(templates),
+			page:    metricsPage{key: "metrics-cached"},
+			renders: 2,
+			want:    []string{"miss", "hit"},
+		},
+		"redirected": {
+			site:    temple.NewCachedSite(templates),
+			page:    metricsPage{key: "metrics-redirected", redirect: true},
+			renders: 2,
+		},
+		"failed-before-templates": {
+			site:    temple.NewCachedSite(templates),
+			page:    metricsPage{key: "metrics-failed", fail: true},
+			renders: 2,
+		},
+		"not-a-template-cacher": {
+			site:    uncachedSite{fs: templates},
+			page:    metricsPage{key: "metrics-uncached"},
+			renders: 2,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			recorder.reset(test.page.key)
+			for range test.renders {
+				temple.Render(context.Background(), httptest.NewRecorder(), test.site, test.page)
+			}
+			if got := recorder.get(test.page.key); !slices.Equal(got, test.want) {
+				t.Errorf("expected cache lookups %q, got %q", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package temple
+
+import (
+	"bytes"
+	"context"
+	"slices"
+
+	xhtml "golang.org/x/net/html"
+)
+
+var (
+	// whitespaceInsensitiveElements are the elements whitespace before
+	// and after is never rendered next to, as they start or end a line
+	// or don't render at all, so it can be removed without changing how
+	// the page is displayed, unless a stylesheet changes their display.
+	whitespaceInsensitiveElements = []string{
+		"address", "article", "aside", "base", "blockquote", "body", "br", "caption", "col", "colgroup", "dd", "details", "div", "dl", "dt",
+		"fieldset", "figcaption", "figure", "footer", "form", "h1", "h2", "h3", "h4", "h5", "h6", "head", "header", "hgroup", "hr", "html",
+		"li", "link", "main", "menu", "meta", "nav", "ol", "optgroup", "option", "p", "pre", "section", "summary", "table", "tbody", "td",
+		"tfoot", "th", "thead", "title", "tr", "ul",
+	}
+
+	// preformattedElements are the elements whose whitespace is
+	// rendered as-is.
+	preformattedElements = []string{"pre", "textarea"}
+)
+
+// preserveWhitespaceAttr is the attribute that stops Minifier from changing
+// the contents of the element it's on.
+const preserveWhitespaceAttr = "data-temple-preserve-whitespace"
+
+var _ OutputFilter = Minifier{}
+
+// Minifier is an OutputFilter that makes pages smaller without changing how
+// they're displayed:
+//
+//   - Runs of whitespace in text are collapsed to a single space, except
+//     inside <pre>, <textarea>, <script>, and <style> elements.
+//   - Whitespace next to elements that always start a new line or aren't
+//     displayed, like <div>, <li>, and <meta>, is removed.
+//   - Comments are removed, unless they start with "!", like
+//     <!--! license -->, or are conditional comments, like <!--[if IE]>.
+//     html/template removes comments from templates, so these only come
+//     from template.HTML values.
+//
+// Tags and attributes are left exactly as they were rendered, as are the
+// contents of scripts and stylesheets, so it's safe to use with any output.
+// Pages that use CSS to display block elements inline, or with white-space:
+// pre, may be displayed differently. Elements whose whitespace matters can be
+// marked with the data-temple-preserve-whitespace attribute, like
+// <ul class="inline" data-temple-preserve-whitespace>, and everything inside
+// them is left exactly as it was rendered.
+//
+// It's meant to be used in production:
+//
+//	temple.Render(ctx, w, site, page, temple.WithOutputFilters(temple.Minifier{}))
+type Minifier struct {
+	// KeepComments stops comments from being removed.
+	KeepComments bool
+}
+
+// FilterOutput returns the minified HTML.
+func (m Minifier) FilterOutput(_ context.Context, html []byte) ([]byte, error) {
+	return m.Minify(html), nil
+}
+
+// Minify returns the minified HTML.
+func (m Minifier) Minify(html []byte) []byte {
+	out := make([]byte, 0, len(html))
+	// whether whitespace was seen since the last thing written, and
+	// whether the last thing written means it isn't needed
+	var pending, dropPending = false, true
+	var preformatted int
+	var rawText string
+	// preserved is the name of the outermost element with the
+	// preserveWhitespaceAttr attribute the tokenizer is in, and
+	// preservedDepth is how many elements with that name it's in
+	var preserved string
+	var preservedDepth int
+	tokenizer := xhtml.NewTokenizer(bytes.NewReader(html))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == xhtml.ErrorToken {
+			break
+		}
+		raw := tokenizer.Raw()
+		switch tokenType {
+		case xhtml.TextToken:
+			if preformatted > 0 || rawText != "" || preserved != "" {
+				if pending && !dropPending {
+					out = append(out, ' ')
+				}
+				out = append(out, raw...)
+				pending, dropPending = false, false
+				continue
+			}
+			text := bytes.TrimLeft(raw, htmlWhitespace)
+			if len(text) < len(raw) {
+				pending = true
+			}
+			if len(text) < 1 {
+				continue
+			}
+			if pending && !dropPending {
+				out = append(out, ' ')
+			}
+			trimmed := bytes.TrimRight(text, htmlWhitespace)
+			out = appendCollapsedWhitespace(out, trimmed)
+			pending, dropPending = len(trimmed) < len(text), false
+		case xhtml.StartTagToken, xhtml.EndTagToken, xhtml.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			tag := string(name)
+			if tokenType == xhtml.StartTagToken && !slices.Contains(voidElements, tag) {
+				switch {
+				case preserved == "" && hasAttr && hasAttribute(tokenizer, preserveWhitespaceAttr):
+					preserved, preservedDepth = tag, 1
+				case tag == preserved:
+					preservedDepth++
+				}
+			}
+			if tokenType == xhtml.EndTagToken && tag == preserved {
+				preservedDepth--
+				if preservedDepth < 1 {
+					preserved = ""
+				}
+			}
+			insensitive := slices.Contains(whitespaceInsensitiveElements, tag)
+			if pending && !dropPending && !insensitive {
+				out = append(out, ' ')
+			}
+			out = append(out, raw...)
+			pending, dropPending = false, insensitive
+			switch {
+			case tokenType == xhtml.StartTagToken && slices.Contains(preformattedElements, tag):
+				preformatted++
+			case tokenType == xhtml.EndTagToken && slices.Contains(preformattedElements, tag) && preformatted > 0:
+				preformatted--
+			case tokenType == xhtml.StartTagToken && (tag == "script" || tag == "style"):
+				rawText = tag
+			case tokenType == xhtml.EndTagToken && tag == rawText:
+				rawText = ""
+			}
+		case xhtml.CommentToken:
+			if !m.KeepComments && !keptComment(raw) {
+				continue
+			}
+			if pending && !dropPending {
+				out = append(out, ' ')
+			}
+			out = append(out, raw...)
+			pending, dropPending = false, false
+		case xhtml.DoctypeToken:
+			out = append(out, raw...)
+			pending, dropPending = false, true
+		}
+	}
+	return out
+}
+
+// hasAttribute returns true if the tag the tokenizer is at has the attribute.
+// It consumes the tag's attributes, so it has to be called after TagName, and
+// only once per tag.
+func hasAttribute(tokenizer *xhtml.Tokenizer, name string) bool {
+	for {
+		key, _, more := tokenizer.TagAttr()
+		if string(key) == name {
+			return true
+		}
+		if !more {
+			return false
+		}
+	}
+}
+
+// htmlWhitespace is the characters HTML treats as whitespace.
+const htmlWhitespace = " \t\n\f\r"
+
+// appendCollapsedWhitespace appends text to out with each run of whitespace
+// in it replaced with a single space.
+func appendCollapsedWhitespace(out, text []byte) []byte {
+	space := false
+	for _, b := range text {
+		if bytes.IndexByte([]byte(htmlWhitespace), b) >= 0 {
+			space = true
+			continue
+		}
+		if space {
+			out = append(out, ' ')
+			space = false
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+// keptComment returns true if the raw comment should survive minification,
+// because it starts with "!" or is a conditional comment.
+func keptComment(raw []byte) bool {
+	return bytes.HasPrefix(raw, []byte("<!--!")) || bytes.HasPrefix(raw, []byte("<!--[if")) || bytes.HasPrefix(raw, []byte("<!--<![endif]"))
+}
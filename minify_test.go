@@ -0,0 +1,46 @@
+package temple_test
+
+import (
+	"testing"
+
+	"impractical.co/temple"
+)
+
+func TestMinifierPreserveWhitespace(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		html string
+		want string
+	}{
+		"not-preserved": {
+			html: "<ul class=\"inline\">\n\t<li>a</li>\n\t<li>b</li>\n</ul>",
+			want: `<ul class="inline"><li>a</li><li>b</li></ul>`,
+		},
+		"preserved": {
+			html: "<div>\n<ul class=\"inline\" data-temple-preserve-whitespace>\n\t<li>a</li>\n\t<li>b  c</li>\n</ul>\n</div>",
+			want: "<div><ul class=\"inline\" data-temple-preserve-whitespace>\n\t<li>a</li>\n\t<li>b  c</li>\n</ul></div>",
+		},
+		"nested-same-element": {
+			html: "<div data-temple-preserve-whitespace>\n<div> a </div>\n</div>\n<div>  b  </div>",
+			want: "<div data-temple-preserve-whitespace>\n<div> a </div>\n</div><div>b</div>",
+		},
+		"other-attributes-first": {
+			html: "<p class=\"x\" data-temple-preserve-whitespace id=\"y\">a   b</p><p>c   d</p>",
+			want: "<p class=\"x\" data-temple-preserve-whitespace id=\"y\">a   b</p><p>c d</p>",
+		},
+		"void-element": {
+			html: "<br data-temple-preserve-whitespace><p>a   b</p>",
+			want: "<br data-temple-preserve-whitespace><p>a b</p>",
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := string(temple.Minifier{}.Minify([]byte(test.html))); got != test.want {
+				t.Errorf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
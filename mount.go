@@ -0,0 +1,240 @@
+package temple
+
+import (
+	"io"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	_ fs.FS        = MountFS{}
+	_ fs.ReadDirFS = MountFS{}
+)
+
+// MountFS is an fs.FS made of other fs.FSes, each mounted under a prefix, so
+// templates from several sources, like a Site's own templates and those of a
+// vendored Component library, can be used together without their paths
+// colliding. Keys are the prefixes the fs.FSes are mounted under, like
+// "vendor" or "vendor/forms", without leading or trailing slashes; the fs.FS
+// mounted under "", if any, holds everything that isn't under another
+// prefix.
+//
+// Paths are resolved using the longest matching prefix, so a file at
+// "vendor/forms/input.html.tmpl" is opened as "input.html.tmpl" in the fs.FS
+// mounted under "vendor/forms", if there is one, even if the fs.FS mounted
+// under "vendor" or "" has a file at that path. Directories containing mount
+// points list them alongside their own entries, so fs.Glob and fs.WalkDir
+// work across mounts.
+type MountFS map[string]fs.FS
+
+// WithMount is a SiteOption that mounts the fs.FS under the prefix in the
+// CachedSite's TemplateDir, using a MountFS with the fs.FS passed to
+// NewCachedSite mounted under "". Templates in it can then be referred to by
+// Components as the prefix followed by their path in the fs.FS:
+//
+//	site := temple.NewCachedSite(templates, temple.WithMount("vendor/forms", forms.Templates))
+//
+// It can be used more than once, to mount more than one fs.FS. Mounting an
+// fs.FS under a prefix that's already mounted replaces it.
+func WithMount(prefix string, dir fs.FS) SiteOption {
+	return func(s *CachedSite) {
+		mounts := MountFS{}
+		if existing, ok := s.templateDir.(MountFS); ok {
+			maps.Copy(mounts, existing)
+		} else if s.templateDir != nil {
+			mounts[""] = s.templateDir
+		}
+		mounts[cleanMountPrefix(prefix)] = dir
+		s.templateDir = mounts
+	}
+}
+
+// cleanMountPrefix returns the prefix without leading or trailing slashes,
+// or "" for the root.
+func cleanMountPrefix(prefix string) string {
+	prefix = path.Clean(strings.Trim(prefix, "/"))
+	if prefix == "." {
+		return ""
+	}
+	return prefix
+}
+
+// resolve returns the fs.FS the path is in, and the path within it, using
+// the longest matching prefix.
+func (m MountFS) resolve(name string) (fs.FS, string, bool) {
+	var best string
+	var found bool
+	for prefix := range m {
+		matches := prefix == "" || prefix == name || strings.HasPrefix(name, prefix+"/")
+		if matches && (!found || len(prefix) > len(best)) {
+			best, found = prefix, true
+		}
+	}
+	if !found {
+		return nil, "", false
+	}
+	if best == "" {
+		return m[best], name, true
+	}
+	if best == name {
+		return m[best], ".", true
+	}
+	return m[best], strings.TrimPrefix(name, best+"/"), true
+}
+
+// mountPoints returns the names of the entries in the directory that are
+// mount points, or lead to them.
+func (m MountFS) mountPoints(dir string) []string {
+	var results []string
+	for prefix := range m {
+		var rest string
+		switch {
+		case prefix == "" || prefix == dir:
+			continue
+		case dir == ".":
+			rest = prefix
+		case strings.HasPrefix(prefix, dir+"/"):
+			rest = strings.TrimPrefix(prefix, dir+"/")
+		default:
+			continue
+		}
+		child, _, _ := strings.Cut(rest, "/")
+		if !slices.Contains(results, child) {
+			results = append(results, child)
+		}
+	}
+	return results
+}
+
+// Open opens the named file in the fs.FS it's mounted in.
+func (m MountFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	dir, sub, ok := m.resolve(name)
+	// directories with mount points in them, and the mount points
+	// themselves, which would be named "." by the fs.FS mounted there
+	if len(m.mountPoints(name)) > 0 || (ok && sub == "." && name != ".") {
+		return &mountDir{fsys: m, name: name}, nil
+	}
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return dir.Open(sub)
+}
+
+// ReadDir reads the named directory in the fs.FS it's mounted in, along with
+// any mount points in it, sorted by filename.
+func (m MountFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	mountPoints := m.mountPoints(name)
+	var entries []fs.DirEntry
+	if dir, sub, ok := m.resolve(name); ok {
+		var err error
+		entries, err = fs.ReadDir(dir, sub)
+		if err != nil && len(mountPoints) < 1 {
+			return nil, err
+		}
+	} else if len(mountPoints) < 1 {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	// mount points hide anything at the same path in the fs.FS they're
+	// in
+	entries = slices.DeleteFunc(entries, func(entry fs.DirEntry) bool {
+		return slices.Contains(mountPoints, entry.Name())
+	})
+	for _, point := range mountPoints {
+		entries = append(entries, fs.FileInfoToDirEntry(mountDirInfo{name: point}))
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries, nil
+}
+
+// mountDir is a directory in a MountFS that contains mount points.
+type mountDir struct {
+	fsys    MountFS
+	name    string
+	entries []fs.DirEntry
+	read    bool
+}
+
+// Stat returns the directory's fs.FileInfo.
+func (d *mountDir) Stat() (fs.FileInfo, error) {
+	return mountDirInfo{name: path.Base(d.name)}, nil
+}
+
+// Read returns an error, as directories can't be read.
+func (d *mountDir) Read(_ []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: fs.ErrInvalid}
+}
+
+// Close does nothing.
+func (d *mountDir) Close() error {
+	return nil
+}
+
+// ReadDir returns the directory's entries, as fs.ReadDirFile describes.
+func (d *mountDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.read {
+		entries, err := d.fsys.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries, d.read = entries, true
+	}
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) < 1 {
+		return nil, io.EOF
+	}
+	n = min(n, len(d.entries))
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
+
+// mountDirInfo is the fs.FileInfo of a mountDir.
+type mountDirInfo struct {
+	name string
+}
+
+// Name returns the directory's name.
+func (i mountDirInfo) Name() string {
+	return i.name
+}
+
+// Size returns 0.
+func (mountDirInfo) Size() int64 {
+	return 0
+}
+
+// Mode returns a read-only directory mode.
+func (mountDirInfo) Mode() fs.FileMode {
+	return fs.ModeDir | 0o555
+}
+
+// ModTime returns the zero time.Time.
+func (mountDirInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+// IsDir returns true.
+func (mountDirInfo) IsDir() bool {
+	return true
+}
+
+// Sys returns nil.
+func (mountDirInfo) Sys() any {
+	return nil
+}
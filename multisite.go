@@ -0,0 +1,117 @@
+package temple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrUnknownSite is returned by SiteResolvers when there's no Site
+	// for a request.
+	ErrUnknownSite = errors.New("no site for request")
+)
+
+// SiteResolver chooses the Site to serve a request with, so one server can
+// serve several Sites, each with its own templates, configuration, and
+// caches, like a white-label product serving each customer on their own
+// domain.
+//
+// Sites returned by a SiteResolver should be long-lived, not created for each
+// request, so their caches are reused. Each Site's caches are its own, so
+// Sites don't need to worry about their pages' Keys colliding.
+type SiteResolver[SiteType Site] interface {
+	// ResolveSite returns the Site for the request. It should return an
+	// error wrapping ErrUnknownSite if there isn't one.
+	ResolveSite(r *http.Request) (SiteType, error)
+}
+
+// SiteResolverFunc is a function that fulfills the SiteResolver interface.
+type SiteResolverFunc[SiteType Site] func(r *http.Request) (SiteType, error)
+
+// ResolveSite calls the SiteResolverFunc.
+func (fn SiteResolverFunc[SiteType]) ResolveSite(r *http.Request) (SiteType, error) {
+	return fn(r)
+}
+
+// HostSites is a SiteResolver that chooses Sites by the host the request was
+// made to, ignoring the port. Keys are lowercase hostnames, like
+// "shop.example.com", or wildcards matching any subdomain of a domain, like
+// "*.example.com", which are only used if no hostname matches exactly. More
+// specific wildcards are preferred.
+type HostSites[SiteType Site] map[string]SiteType
+
+// ResolveSite returns the Site for the request's host, or an error wrapping
+// ErrUnknownSite if there isn't one.
+func (h HostSites[SiteType]) ResolveSite(r *http.Request) (SiteType, error) {
+	host := r.Host
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if site, ok := h[host]; ok {
+		return site, nil
+	}
+	for domain := host; ; {
+		_, parent, ok := strings.Cut(domain, ".")
+		if !ok {
+			break
+		}
+		if site, ok := h["*."+parent]; ok {
+			return site, nil
+		}
+		domain = parent
+	}
+	var zero SiteType
+	return zero, fmt.Errorf("%w: host %q", ErrUnknownSite, host)
+}
+
+type siteCtxKey struct{}
+
+// SiteMiddleware returns a middleware that uses the SiteResolver to choose
+// the Site for each request, and stores it in the request's context.Context,
+// where handlers can retrieve it with SiteFromContext:
+//
+//	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+//		site, _ := temple.SiteFromContext[*MySite](r.Context())
+//		temple.Render(r.Context(), w, site, HomePage{})
+//	})
+//
+// Requests the SiteResolver returns an error wrapping ErrUnknownSite for get a
+// 404 Not Found response; other errors get a 500 Internal Server Error
+// response and are logged.
+func SiteMiddleware[SiteType Site](resolver SiteResolver[SiteType]) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			site, err := resolver.ResolveSite(r)
+			if errors.Is(err, ErrUnknownSite) {
+				logger(ctx).DebugContext(ctx, "no site for request", "host", r.Host, "error", err)
+				http.Error(w, "Not found.", http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				logger(ctx).ErrorContext(ctx, "error resolving site", "host", r.Host, "error", err)
+				http.Error(w, "Server error.", http.StatusInternalServerError)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithSite(ctx, site)))
+		})
+	}
+}
+
+// WithSite returns a context.Context holding the Site, for SiteFromContext to
+// retrieve.
+func WithSite[SiteType Site](ctx context.Context, site SiteType) context.Context {
+	return context.WithValue(ctx, siteCtxKey{}, site)
+}
+
+// SiteFromContext returns the Site stored in the context.Context by
+// SiteMiddleware or WithSite, and whether there was one of the requested type.
+func SiteFromContext[SiteType Site](ctx context.Context) (SiteType, bool) {
+	site, ok := ctx.Value(siteCtxKey{}).(SiteType)
+	return site, ok
+}
@@ -0,0 +1,74 @@
+package nav_test
+
+import (
+	"context"
+	"os"
+	"testing/fstest"
+
+	"impractical.co/temple"
+	"impractical.co/temple/nav"
+)
+
+type ArticlePage struct {
+	Menu        nav.NavMenu
+	Breadcrumbs nav.Breadcrumbs
+}
+
+func (ArticlePage) Templates(_ context.Context) []string {
+	return []string{"article.html.tmpl"}
+}
+
+func (a ArticlePage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{a.Menu, a.Breadcrumbs}
+}
+
+func (ArticlePage) Key(_ context.Context) string {
+	return "article.html.tmpl"
+}
+
+func (ArticlePage) ExecutedTemplate(_ context.Context) string {
+	return "article.html.tmpl"
+}
+
+func Example() {
+	templates := fstest.MapFS{
+		"article.html.tmpl": {Data: []byte(`{{ .JSONDataTags }}{{ template "nav/menu.html.tmpl" .Page.Menu }}
+{{ template "nav/breadcrumbs.html.tmpl" .Page.Breadcrumbs }}`)},
+	}
+	site := temple.NewCachedSite(templates)
+
+	page := ArticlePage{
+		Menu: nav.NavMenu{
+			Items: []nav.Item{
+				{Label: "Home", URL: "/"},
+				{Label: "Blog", URL: "/blog/"},
+			},
+			Current: "/blog/",
+		},
+		Breadcrumbs: nav.Breadcrumbs{
+			Items: []nav.Item{
+				{Label: "Home", URL: "https://example.com/"},
+				{Label: "Blog", URL: "https://example.com/blog/"},
+				{Label: "Hello, World", URL: "https://example.com/blog/hello"},
+			},
+			StructuredData: true,
+		},
+	}
+	temple.Render(context.Background(), os.Stdout, site, page)
+
+	//Output:
+	// <script type="application/ld+json" id="temple-nav-breadcrumbs">{"@context":"https://schema.org","@type":"BreadcrumbList","itemListElement":[{"@type":"ListItem","position":1,"name":"Home","item":"https://example.com/"},{"@type":"ListItem","position":2,"name":"Blog","item":"https://example.com/blog/"},{"@type":"ListItem","position":3,"name":"Hello, World","item":"https://example.com/blog/hello"}]}</script>
+	// <nav class="temple-nav-menu" aria-label="Main">
+	// 	<ul>
+	// 		<li><a href="/">Home</a></li>
+	// 		<li class="temple-nav-active"><a href="/blog/" aria-current="page">Blog</a></li>
+	// 	</ul>
+	// </nav>
+	// <nav class="temple-nav-breadcrumbs" aria-label="Breadcrumb">
+	// 	<ol>
+	// 		<li><a href="https://example.com/">Home</a></li>
+	// 		<li><a href="https://example.com/blog/">Blog</a></li>
+	// 		<li><span aria-current="page">Hello, World</span></li>
+	// 	</ol>
+	// </nav>
+}
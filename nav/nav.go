@@ -0,0 +1,189 @@
+// Package nav provides temple Components for helping users find their way
+// around a site: Breadcrumbs, showing where the current page sits in the
+// site's hierarchy, and NavMenu, a list of links with the current one
+// highlighted.
+//
+// Both Components supply their own templates and embed the CSS they need, so
+// all that's required to use them is to include them in the UseComponents
+// output of a Component and execute their templates:
+//
+//	{{ template "nav/breadcrumbs.html.tmpl" .Page.Breadcrumbs }}
+//	{{ template "nav/menu.html.tmpl" .Page.Menu }}
+package nav
+
+import (
+	"context"
+	"embed"
+	"html/template"
+	"io/fs"
+
+	"impractical.co/temple"
+)
+
+//go:embed templates
+var templates embed.FS
+
+const css = `
+.temple-nav-breadcrumbs ol { list-style: none; margin: 0; padding: 0; }
+.temple-nav-breadcrumbs li { display: inline; }
+.temple-nav-breadcrumbs li + li::before { content: "/"; padding: 0 0.5em; }
+.temple-nav-menu ul { list-style: none; margin: 0; padding: 0; }
+.temple-nav-menu .temple-nav-active a { font-weight: bold; }
+`
+
+// Item is a single link in Breadcrumbs or a NavMenu.
+type Item struct {
+	// Label is the text of the link.
+	Label string
+
+	// URL is where the link points to.
+	URL string
+
+	// Active marks the Item as the current page.
+	Active bool
+}
+
+var (
+	_ temple.Component           = Breadcrumbs{}
+	_ temple.TemplateDirProvider = Breadcrumbs{}
+	_ temple.CSSEmbedder         = Breadcrumbs{}
+	_ temple.JSONDataEmbedder    = Breadcrumbs{}
+)
+
+// Breadcrumbs is a Component that renders the path from the root of the site
+// to the current page. The last Item is presumed to be the current page.
+type Breadcrumbs struct {
+	// Items are the pages leading to the current page, starting with the
+	// root of the site and ending with the current page.
+	Items []Item
+
+	// Label is the accessible name of the <nav> element. Defaults to
+	// "Breadcrumb".
+	Label string
+
+	// StructuredData embeds the Items as a schema.org BreadcrumbList in
+	// JSON-LD, so search engines can display them. The Items' URLs should
+	// be absolute for search engines to use them.
+	StructuredData bool
+}
+
+// Templates returns the template needed to render the Breadcrumbs.
+func (Breadcrumbs) Templates(_ context.Context) []string {
+	return []string{"nav/breadcrumbs.html.tmpl"}
+}
+
+// TemplateDir returns the fs.FS containing the Breadcrumbs' template.
+func (Breadcrumbs) TemplateDir(_ context.Context) fs.FS {
+	return temple.SubFS(templates, "templates")
+}
+
+// EmbedCSS returns the CSS used to style navigation Components.
+func (Breadcrumbs) EmbedCSS(_ context.Context) template.CSS {
+	return css
+}
+
+// EmbedJSONData returns the Items as a schema.org BreadcrumbList, if
+// StructuredData is true.
+func (b Breadcrumbs) EmbedJSONData(_ context.Context) []temple.JSONData {
+	if !b.StructuredData || len(b.Items) < 1 {
+		return nil
+	}
+	type listItem struct {
+		Type     string `json:"@type"`
+		Position int    `json:"position"`
+		Name     string `json:"name"`
+		Item     string `json:"item,omitempty"`
+	}
+	items := make([]listItem, 0, len(b.Items))
+	for pos, item := range b.Items {
+		items = append(items, listItem{
+			Type:     "ListItem",
+			Position: pos + 1,
+			Name:     item.Label,
+			Item:     item.URL,
+		})
+	}
+	return []temple.JSONData{{
+		ID:   "temple-nav-breadcrumbs",
+		Type: "application/ld+json",
+		Value: map[string]any{
+			"@context":        "https://schema.org",
+			"@type":           "BreadcrumbList",
+			"itemListElement": items,
+		},
+	}}
+}
+
+// AriaLabel returns the accessible name of the <nav> element.
+func (b Breadcrumbs) AriaLabel() string {
+	if b.Label == "" {
+		return "Breadcrumb"
+	}
+	return b.Label
+}
+
+// IsCurrent returns true if the Item at the passed index is the current page:
+// either it's marked Active, or it's the last Item and no Item is marked
+// Active.
+func (b Breadcrumbs) IsCurrent(index int) bool {
+	anyActive := false
+	for i, item := range b.Items {
+		if item.Active {
+			if i == index {
+				return true
+			}
+			anyActive = true
+		}
+	}
+	return !anyActive && index == len(b.Items)-1
+}
+
+var (
+	_ temple.Component           = NavMenu{}
+	_ temple.TemplateDirProvider = NavMenu{}
+	_ temple.CSSEmbedder         = NavMenu{}
+)
+
+// NavMenu is a Component that renders a list of links, highlighting the one
+// for the current page.
+type NavMenu struct {
+	// Items are the links in the NavMenu.
+	Items []Item
+
+	// Current is the URL of the current page. Any Item with this URL is
+	// highlighted, as is any Item marked Active.
+	Current string
+
+	// Label is the accessible name of the <nav> element. Defaults to
+	// "Main".
+	Label string
+}
+
+// Templates returns the template needed to render the NavMenu.
+func (NavMenu) Templates(_ context.Context) []string {
+	return []string{"nav/menu.html.tmpl"}
+}
+
+// TemplateDir returns the fs.FS containing the NavMenu's template.
+func (NavMenu) TemplateDir(_ context.Context) fs.FS {
+	return temple.SubFS(templates, "templates")
+}
+
+// EmbedCSS returns the CSS used to style navigation Components.
+func (NavMenu) EmbedCSS(_ context.Context) template.CSS {
+	return css
+}
+
+// AriaLabel returns the accessible name of the <nav> element.
+func (n NavMenu) AriaLabel() string {
+	if n.Label == "" {
+		return "Main"
+	}
+	return n.Label
+}
+
+// IsActive returns true if the passed Item should be highlighted as the
+// current page.
+func (n NavMenu) IsActive(item Item) bool {
+	return item.Active || (n.Current != "" && item.URL == n.Current)
+}
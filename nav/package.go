@@ -0,0 +1,8 @@
+package nav
+
+import "impractical.co/temple"
+
+// Package returns the temple.ComponentPackage for Breadcrumbs and NavMenu.
+func Package() (temple.ComponentPackage, error) {
+	return temple.NewComponentPackage("nav", templates, "templates/nav", "", Breadcrumbs{}, NavMenu{})
+}
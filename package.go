@@ -0,0 +1,199 @@
+package temple
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// PackageAssetsPath is the URL path PackageAssetsHandler serves the assets of
+// ComponentPackages under, with each package's assets under its name.
+const PackageAssetsPath = "/_temple/packages/"
+
+// ComponentPackage describes a library of Components that brings its own
+// templates and static assets, so it can be registered with a Site in one
+// step, instead of the Site needing to know where each of its files are.
+//
+// A package's templates are mounted under its name in the Site's
+// TemplateDir, so its Components should refer to them with the name as a
+// prefix, like "forms/input.html.tmpl" for a package named "forms" with an
+// "input.html.tmpl" template. Its assets are served by PackageAssetsHandler,
+// and its Components should link to them using PackageAssetURL. Packages
+// usually keep both in an embed.FS, and use NewComponentPackage instead of
+// implementing ComponentPackage themselves:
+//
+//	//go:embed templates assets
+//	var files embed.FS
+//
+//	func Package() (temple.ComponentPackage, error) {
+//		return temple.NewComponentPackage("forms", files, "templates/forms", "assets", Input{}, Form{})
+//	}
+//
+// Packages should be named after their Go package, to avoid collisions.
+type ComponentPackage interface {
+	// PackageName returns the name of the package, which its
+	// templates are mounted under and its assets are served under.
+	PackageName() string
+
+	// PackageTemplates returns the package's templates.
+	PackageTemplates() fs.FS
+
+	// PackageAssets returns the package's static assets, like CSS and
+	// JavaScript files, or nil if it has none.
+	PackageAssets() fs.FS
+
+	// PackageComponents returns an instance of each Component in the
+	// package, so tools can discover them.
+	PackageComponents() []Component
+}
+
+// SubFS returns the fs.FS corresponding to the subtree rooted at dir in fsys,
+// like fs.Sub. fs.Sub only fails if dir isn't a valid path; instead of
+// returning that error, SubFS returns an fs.FS that fails to open anything
+// with it, so it can be used in methods that can't return an error, like
+// TemplateDir, and Render reports the error when it reads the templates:
+//
+//	//go:embed templates
+//	var templates embed.FS
+//
+//	func (Input) TemplateDir(_ context.Context) fs.FS {
+//		return temple.SubFS(templates, "templates")
+//	}
+func SubFS(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		return errFS{err: err}
+	}
+	return sub
+}
+
+// errFS is an fs.FS that fails to open anything.
+type errFS struct {
+	err error
+}
+
+func (e errFS) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: e.err}
+}
+
+// componentPackage is the ComponentPackage returned by NewComponentPackage.
+type componentPackage struct {
+	name       string
+	templates  fs.FS
+	assets     fs.FS
+	components []Component
+}
+
+func (c componentPackage) PackageName() string {
+	return c.name
+}
+
+func (c componentPackage) PackageTemplates() fs.FS {
+	return c.templates
+}
+
+func (c componentPackage) PackageAssets() fs.FS {
+	return c.assets
+}
+
+func (c componentPackage) PackageComponents() []Component {
+	return c.components
+}
+
+// NewComponentPackage returns a ComponentPackage named name, for the
+// Components passed, whose templates are in the templatesDir directory of
+// files. If assetsDir isn't empty, the package's assets are in that directory
+// of files; otherwise, it has none. It returns an error if either directory
+// isn't a valid path.
+func NewComponentPackage(name string, files fs.FS, templatesDir, assetsDir string, components ...Component) (ComponentPackage, error) {
+	templates, err := fs.Sub(files, templatesDir)
+	if err != nil {
+		return nil, fmt.Errorf("error finding templates of package %q: %w", name, err)
+	}
+	pkg := componentPackage{name: name, templates: templates, components: components}
+	if assetsDir != "" {
+		pkg.assets, err = fs.Sub(files, assetsDir)
+		if err != nil {
+			return nil, fmt.Errorf("error finding assets of package %q: %w", name, err)
+		}
+	}
+	return pkg, nil
+}
+
+// Packager is an interface that Sites can fulfill to list the
+// ComponentPackages registered with them. CachedSite implements it, listing
+// the packages registered with WithPackages.
+type Packager interface {
+	// Packages returns the ComponentPackages registered with the Site.
+	Packages(ctx context.Context) []ComponentPackage
+}
+
+// WithPackages is a SiteOption that registers ComponentPackages with the
+// CachedSite, mounting each package's templates under its name, as WithMount
+// does, and listing it in the output of Packages, so PackageAssetsHandler
+// serves its assets. It can be used more than once.
+func WithPackages(packages ...ComponentPackage) SiteOption {
+	return func(s *CachedSite) {
+		for _, pkg := range packages {
+			WithMount(pkg.PackageName(), pkg.PackageTemplates())(s)
+			s.packages = append(s.packages, pkg)
+		}
+	}
+}
+
+// Packages returns the ComponentPackages registered with the CachedSite
+// using WithPackages, in the order they were registered.
+func (s *CachedSite) Packages(_ context.Context) []ComponentPackage {
+	return s.packages
+}
+
+// PackageAssetURL returns the URL PackageAssetsHandler serves the asset at
+// assetPath in the ComponentPackage named packageName at, for use by the
+// package's Components:
+//
+//	func (Input) LinkCSS(_ context.Context) []string {
+//		return []string{temple.PackageAssetURL("forms", "forms.css")}
+//	}
+func PackageAssetURL(packageName, assetPath string) string {
+	return PackageAssetsPath + cleanMountPrefix(packageName) + "/" + strings.TrimPrefix(path.Clean("/"+assetPath), "/")
+}
+
+// PackageAssetsHandler returns an http.Handler serving the assets of the
+// ComponentPackages registered with the Site at the URLs returned by
+// PackageAssetURL, if the Site implements Packager. It should be registered
+// for PackageAssetsPath:
+//
+//	mux.Handle(temple.PackageAssetsPath, temple.PackageAssetsHandler(site))
+//
+// Requests for assets that don't exist, or for packages that aren't
+// registered, get a 404 Not Found response.
+func PackageAssetsHandler(site Site) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		packager, ok := site.(Packager)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		assets := MountFS{}
+		for _, pkg := range packager.Packages(r.Context()) {
+			if dir := pkg.PackageAssets(); dir != nil {
+				assets[cleanMountPrefix(pkg.PackageName())] = dir
+			}
+		}
+		rest, ok := strings.CutPrefix(r.URL.Path, PackageAssetsPath)
+		// only serve files that are in a package, never directory
+		// listings
+		if !ok || len(assets) < 1 || strings.HasSuffix(rest, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		if _, _, ok := assets.resolve(rest); !ok {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeFileFS(w, r, assets, rest)
+	})
+}
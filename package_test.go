@@ -0,0 +1,111 @@
+package temple_test
+
+import (
+	"context"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"impractical.co/temple"
+	"impractical.co/temple/avatar"
+	"impractical.co/temple/chart"
+	"impractical.co/temple/forms"
+	"impractical.co/temple/highlight"
+	"impractical.co/temple/i18n"
+	"impractical.co/temple/markdown"
+	"impractical.co/temple/nav"
+	"impractical.co/temple/progress"
+	"impractical.co/temple/qr"
+)
+
+func TestComponentPackages(t *testing.T) {
+	t.Parallel()
+
+	packages := []func() (temple.ComponentPackage, error){
+		avatar.Package,
+		chart.Package,
+		forms.Package,
+		highlight.Package,
+		i18n.Package,
+		markdown.Package,
+		nav.Package,
+		progress.Package,
+		qr.Package,
+	}
+
+	for _, newPackage := range packages {
+		pkg, err := newPackage()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		t.Run(pkg.PackageName(), func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			site := temple.NewCachedSite(fstest.MapFS{}, temple.WithPackages(pkg))
+			if len(pkg.PackageComponents()) < 1 {
+				t.Error("expected the package to list its Components")
+			}
+			for _, comp := range pkg.PackageComponents() {
+				provider, ok := comp.(temple.TemplateDirProvider)
+				if !ok {
+					t.Errorf("expected %T to supply its own templates", comp)
+					continue
+				}
+				for _, path := range comp.Templates(ctx) {
+					if !strings.HasPrefix(path, pkg.PackageName()+"/") {
+						t.Errorf("expected template %q of %T to be under %q", path, comp, pkg.PackageName()+"/")
+					}
+					// the templates mounted in the Site should be
+					// the ones the Component supplies itself
+					mounted, err := fs.ReadFile(site.TemplateDir(ctx), path)
+					if err != nil {
+						t.Errorf("error reading template %q of %T from the Site: %s", path, comp, err)
+						continue
+					}
+					own, err := fs.ReadFile(provider.TemplateDir(ctx), path)
+					if err != nil {
+						t.Errorf("error reading template %q of %T: %s", path, comp, err)
+						continue
+					}
+					if string(mounted) != string(own) {
+						t.Errorf("template %q of %T mounted in the Site differs from the Component's", path, comp)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSubFS(t *testing.T) {
+	t.Parallel()
+
+	dir := temple.SubFS(fstest.MapFS{"templates/a.html.tmpl": {Data: []byte("a")}}, "templates")
+	contents, err := fs.ReadFile(dir, "a.html.tmpl")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(contents) != "a" {
+		t.Errorf("expected %q, got %q", "a", contents)
+	}
+
+	// invalid paths are reported when the templates are read
+	_, err = fs.ReadFile(temple.SubFS(fstest.MapFS{}, "../templates"), "a.html.tmpl")
+	if err == nil {
+		t.Error("expected an error reading from an invalid directory")
+	}
+}
+
+func TestNewComponentPackageInvalidDir(t *testing.T) {
+	t.Parallel()
+
+	for name, dirs := range map[string][2]string{
+		"templates": {"../templates", ""},
+		"assets":    {"templates", "/assets"},
+	} {
+		_, err := temple.NewComponentPackage("invalid", fstest.MapFS{}, dirs[0], dirs[1])
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
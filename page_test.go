@@ -0,0 +1,93 @@
+package temple_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"impractical.co/temple"
+)
+
+// testPage is the Renderable the render tests use. It executes its template,
+// page.html.tmpl unless one is set, and fulfills the optional interfaces a
+// page can, without affecting the render when their fields aren't set.
+type testPage struct {
+	template string
+
+	// Rows are available to the template as .Page.Rows.
+	Rows []string
+
+	// fail makes Check return an error, and failAt makes CheckRow return
+	// an error for every row from failAt on, if it isn't 0.
+	fail   bool
+	failAt int
+
+	headers    http.Header
+	policy     temple.CacheControl
+	response   temple.Response
+	components []temple.Component
+	keys       []string
+}
+
+func (p testPage) name() string {
+	if p.template == "" {
+		return "page.html.tmpl"
+	}
+	return p.template
+}
+
+func (p testPage) Templates(_ context.Context) []string {
+	return []string{p.name()}
+}
+
+func (p testPage) Key(_ context.Context) string {
+	return p.name()
+}
+
+func (p testPage) ExecutedTemplate(_ context.Context) string {
+	return p.name()
+}
+
+func (p testPage) Headers(_ context.Context) http.Header {
+	return p.headers
+}
+
+func (p testPage) CacheControl(_ context.Context) temple.CacheControl {
+	return p.policy
+}
+
+func (p testPage) Respond(_ context.Context) (temple.Response, error) {
+	return p.response, nil
+}
+
+func (p testPage) UseComponents(_ context.Context) []temple.Component {
+	return p.components
+}
+
+func (p testPage) SurrogateKeys(_ context.Context) []string {
+	return p.keys
+}
+
+func (p testPage) Check() (string, error) {
+	if p.fail {
+		return "", errors.New("broken page")
+	}
+	return "ok", nil
+}
+
+func (p testPage) CheckRow(row int) (string, error) {
+	if p.failAt > 0 && row >= p.failAt {
+		return "", errors.New("broken row")
+	}
+	return "", nil
+}
+
+// errorPageSite is a Site that renders errorPage when a page fails to render.
+type errorPageSite struct {
+	*temple.CachedSite
+	errorPage temple.Renderable
+}
+
+func (s errorPageSite) ServerErrorPage(_ context.Context) temple.Renderable {
+	return s.errorPage
+}
@@ -0,0 +1,151 @@
+package temple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// parseCallKey identifies the templates being parsed for a key of a
+// TemplateCacher.
+type parseCallKey struct {
+	cache TemplateCacher
+	key   string
+}
+
+// parseCall is an in-progress parse of the templates for a parseCallKey.
+type parseCall struct {
+	done chan struct{}
+	tmpl *template.Template
+	err  error
+}
+
+// missingTemplateTTL is how long parseOnce remembers that a key's templates
+// couldn't be found, before looking for them again.
+const missingTemplateTTL = 5 * time.Second
+
+// missingTemplates is an error parsing a key's templates because some of them
+// couldn't be found, and when parseOnce should look for them again.
+type missingTemplates struct {
+	err   error
+	retry time.Time
+}
+
+var (
+	parseCalls   = map[parseCallKey]*parseCall{}
+	parseMissing = map[parseCallKey]missingTemplates{}
+	// parseMissingSwept is when expired entries were last removed from
+	// parseMissing
+	parseMissingSwept time.Time
+	parseCallsMu      sync.Mutex
+)
+
+// parseOnce calls parse, unless another goroutine is already calling parse for
+// the same key of the same TemplateCacher, in which case it waits for that
+// call to finish and returns its results instead. This keeps a burst of
+// requests for a page whose templates aren't cached yet from all parsing
+// them at once. It returns true if the results came from another goroutine.
+//
+// If parse fails because some of the templates are missing, the error is
+// remembered for a few seconds, and returned without calling parse again, so
+// a page with a bad template path doesn't hit the filesystem on every
+// request. Calling forgetMissingTemplates for the key, which
+// InvalidateTemplates does, makes the next call look for them again. Missing
+// templates aren't remembered for TemplateCachers that are
+// DevelopmentReporters in development, so templates added while developing
+// show up immediately.
+//
+// TemplateCachers that can't be compared can't be told apart, so each call
+// for them parses independently.
+func parseOnce(ctx context.Context, cache TemplateCacher, key string, parse func() (*template.Template, error)) (*template.Template, bool, error) {
+	if !reflect.ValueOf(cache).Comparable() {
+		tmpl, err := parse()
+		return tmpl, false, err
+	}
+	dev, ok := cache.(DevelopmentReporter)
+	rememberMissing := !ok || !dev.InDevelopment(ctx)
+	callKey := parseCallKey{cache: cache, key: key}
+	parseCallsMu.Lock()
+	if missing, ok := parseMissing[callKey]; ok {
+		if time.Now().Before(missing.retry) {
+			parseCallsMu.Unlock()
+			return nil, false, missing.err
+		}
+		delete(parseMissing, callKey)
+	}
+	if call, ok := parseCalls[callKey]; ok {
+		parseCallsMu.Unlock()
+		select {
+		case <-call.done:
+			return call.tmpl, true, call.err
+		case <-ctx.Done():
+			return nil, true, fmt.Errorf("error waiting for templates to be parsed: %w", ctx.Err())
+		}
+	}
+	call := &parseCall{
+		done: make(chan struct{}),
+		// in case parse panics, don't let the goroutines waiting for
+		// it think it succeeded
+		err: fmt.Errorf("templates for %q weren't parsed", key),
+	}
+	parseCalls[callKey] = call
+	parseCallsMu.Unlock()
+
+	defer func() {
+		parseCallsMu.Lock()
+		delete(parseCalls, callKey)
+		if rememberMissing && isMissingTemplate(call.err) {
+			now := time.Now()
+			sweepMissingTemplates(now)
+			parseMissing[callKey] = missingTemplates{
+				err:   call.err,
+				retry: now.Add(missingTemplateTTL),
+			}
+		}
+		parseCallsMu.Unlock()
+		close(call.done)
+	}()
+	call.tmpl, call.err = parse()
+	return call.tmpl, false, call.err
+}
+
+// isMissingTemplate returns true if the error was caused by a template that
+// couldn't be found.
+func isMissingTemplate(err error) bool {
+	return errors.Is(err, ErrTemplatePatternMatchesNoFiles) || errors.Is(err, fs.ErrNotExist)
+}
+
+// sweepMissingTemplates removes the expired entries from parseMissing, so keys
+// that are never rendered again, and the TemplateCachers they refer to, aren't
+// kept forever. It only checks the entries once every missingTemplateTTL, so
+// adding an entry stays cheap, and parseMissing never holds more than the
+// entries added in the last two TTLs. parseCallsMu must be held.
+func sweepMissingTemplates(now time.Time) {
+	if now.Sub(parseMissingSwept) < missingTemplateTTL {
+		return
+	}
+	parseMissingSwept = now
+	for key, missing := range parseMissing {
+		if !now.Before(missing.retry) {
+			delete(parseMissing, key)
+		}
+	}
+}
+
+// forgetMissingTemplates makes parseOnce look for the templates of the keys
+// again the next time they're parsed, even if they were missing recently.
+func forgetMissingTemplates(cache TemplateCacher, keys ...string) {
+	if !reflect.ValueOf(cache).Comparable() {
+		return
+	}
+	parseCallsMu.Lock()
+	defer parseCallsMu.Unlock()
+	for _, key := range keys {
+		delete(parseMissing, parseCallKey{cache: cache, key: key})
+	}
+}
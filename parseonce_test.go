@@ -0,0 +1,48 @@
+package temple_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"impractical.co/temple"
+)
+
+func TestMissingTemplatesDevelopment(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		opts      []temple.SiteOption
+		wantOpens int32
+	}{
+		"remembered": {
+			wantOpens: 1,
+		},
+		"development": {
+			opts:      []temple.SiteOption{temple.WithDevelopment()},
+			wantOpens: 3,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			templates := CountingFS{
+				FS:    staticFS{"broken.html.tmpl": `Hello`},
+				opens: &atomic.Int32{},
+			}
+			site := temple.NewCachedSite(templates, test.opts...)
+			for range 3 {
+				result := temple.Render(context.Background(), io.Discard, site, BrokenPage{})
+				if !errors.Is(result.Err, temple.ErrTemplatePatternMatchesNoFiles) {
+					t.Fatalf("expected missing template error, got %v", result.Err)
+				}
+			}
+			if got := templates.opens.Load(); got != test.wantOpens {
+				t.Errorf("expected templates to be looked for %d times, got %d", test.wantOpens, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package temple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"slices"
+	"strings"
+	"text/template/parse"
+)
+
+// placedResourcesTemplate is the name of a template added to parsed template
+// sets that call placeResource, listing the URLs of the resources they place,
+// one per line, so they can be left out of LinkedCSS and LinkedJS without
+// walking the templates on every render.
+const placedResourcesTemplate = "temple:placed-resources"
+
+var (
+	// ErrResourceNotDeclared is returned when the placeResource template
+	// function is passed a URL that isn't linked to by any of the
+	// page's Components.
+	ErrResourceNotDeclared = errors.New("resource isn't linked to by any component on the page")
+)
+
+type pageResourcesCtxKey struct{}
+
+// pageResources are the resources linked to by the page being rendered,
+// before any placed resources are removed, for the placeResource template
+// function.
+type pageResources struct {
+	js         []string
+	css        []string
+	priorities map[string]FetchPriority
+}
+
+// withPageResources returns a context.Context that the placeResource template
+// function can find the page's resources in.
+func withPageResources(ctx context.Context, js, css []string, priorities map[string]FetchPriority) context.Context {
+	return context.WithValue(ctx, pageResourcesCtxKey{}, pageResources{js: js, css: css, priorities: priorities})
+}
+
+// placeResourceFunc returns the placeResource template function, which
+// renders a <script> or <link> element for one of the page's linked
+// resources where it's called, instead of wherever LinkedJS or LinkedCSS are
+// rendered. Each resource is only rendered once, no matter how many times
+// it's placed.
+//
+// Resources whose URLs are passed to placeResource as string literals, like
+// {{ placeResource "/js/ads.js" }}, are left out of LinkedJS and LinkedCSS.
+// URLs from variables or fields can't be known until the template is
+// executed, by which point the <head> has usually been rendered, so those
+// resources stay in LinkedJS or LinkedCSS as well.
+func placeResourceFunc(ctx context.Context) func(string) (template.HTML, error) {
+	placed := map[string]struct{}{}
+	return func(url string) (template.HTML, error) {
+		resources, _ := ctx.Value(pageResourcesCtxKey{}).(pageResources)
+		var element string
+		switch {
+		case slices.Contains(resources.js, url):
+			element = `<script src="%s"%s></script>`
+		case slices.Contains(resources.css, url):
+			element = `<link rel="stylesheet" href="%s"%s>`
+		default:
+			return "", fmt.Errorf("error placing %q: %w", url, ErrResourceNotDeclared)
+		}
+		if _, ok := placed[url]; ok {
+			return "", nil
+		}
+		placed[url] = struct{}{}
+		return template.HTML(fmt.Sprintf(element, html.EscapeString(url), fetchPriorityAttr(resources.priorities[url]))), nil // #nosec G203
+	}
+}
+
+// addPlacedResourcesMarker adds a template named placedResourcesTemplate to
+// the template set, listing the URLs passed to placeResource as string
+// literals, if there are any.
+func addPlacedResourcesMarker(tmpl *template.Template) error {
+	var urls []string
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+		placedResources(t.Tree.Root, &urls)
+	}
+	if len(urls) < 1 {
+		return nil
+	}
+	// the URLs are only read back out of the parse tree, so they don't
+	// need escaping, but they mustn't be mistaken for actions
+	text := strings.Join(urls, "\n")
+	_, err := tmpl.New(placedResourcesTemplate).Delims("\x00", "\x00").Parse(text)
+	if err != nil {
+		return fmt.Errorf("error marking placed resources: %w", err)
+	}
+	return nil
+}
+
+// removePlacedResources returns the passed URLs, without any the template set
+// places using placeResource.
+func removePlacedResources(tmpl *template.Template, urls []string) []string {
+	marker := tmpl.Lookup(placedResourcesTemplate)
+	if marker == nil || marker.Tree == nil || marker.Tree.Root == nil {
+		return urls
+	}
+	var placed []string
+	for _, node := range marker.Tree.Root.Nodes {
+		if text, ok := node.(*parse.TextNode); ok {
+			placed = append(placed, strings.Split(string(text.Text), "\n")...)
+		}
+	}
+	return slices.DeleteFunc(slices.Clone(urls), func(url string) bool {
+		return slices.Contains(placed, url)
+	})
+}
+
+// placedResources appends the string literals passed to placeResource in the
+// parse tree rooted at `node` to urls.
+func placedResources(node parse.Node, urls *[]string) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			placedResources(child, urls)
+		}
+	case *parse.ActionNode:
+		placedResources(n.Pipe, urls)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			placedResources(cmd, urls)
+		}
+	case *parse.CommandNode:
+		if len(n.Args) == 2 {
+			ident, isIdent := n.Args[0].(*parse.IdentifierNode)
+			str, isString := n.Args[1].(*parse.StringNode)
+			if isIdent && isString && ident.Ident == "placeResource" && !slices.Contains(*urls, str.Text) {
+				*urls = append(*urls, str.Text)
+			}
+		}
+		for _, arg := range n.Args {
+			placedResources(arg, urls)
+		}
+	case *parse.IfNode:
+		placedResources(n.Pipe, urls)
+		placedResources(n.List, urls)
+		placedResources(n.ElseList, urls)
+	case *parse.RangeNode:
+		placedResources(n.Pipe, urls)
+		placedResources(n.List, urls)
+		placedResources(n.ElseList, urls)
+	case *parse.WithNode:
+		placedResources(n.Pipe, urls)
+		placedResources(n.List, urls)
+		placedResources(n.ElseList, urls)
+	case *parse.TemplateNode:
+		placedResources(n.Pipe, urls)
+	}
+}
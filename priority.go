@@ -0,0 +1,114 @@
+package temple
+
+import (
+	"context"
+	"html"
+	"html/template"
+)
+
+// FetchPriority is a hint to browsers about how important a resource is
+// relative to the page's other resources, used as the value of fetchpriority
+// attributes.
+type FetchPriority string
+
+const (
+	// FetchPriorityHigh marks a resource as more important than other
+	// resources of the same type.
+	FetchPriorityHigh FetchPriority = "high"
+
+	// FetchPriorityLow marks a resource as less important than other
+	// resources of the same type.
+	FetchPriorityLow FetchPriority = "low"
+
+	// FetchPriorityAuto leaves the importance of a resource up to the
+	// browser.
+	FetchPriorityAuto FetchPriority = "auto"
+)
+
+// FetchPriorityPolicier is an optional interface for Sites. Those fulfilling
+// it can set default fetchpriority hints for the resources their pages link
+// to, based on what kind of resource they are and where they appear in the
+// page, rather than on each Component that links to them.
+type FetchPriorityPolicier interface {
+	// FetchPriorityPolicy returns the FetchPriorityPolicy for the page
+	// being rendered with the context.Context.
+	FetchPriorityPolicy(ctx context.Context) FetchPriorityPolicy
+}
+
+// FetchPriorityPolicy sets the fetchpriority of a page's resources by group.
+// Groups that are left empty get no fetchpriority, leaving it up to the
+// browser. The zero value sets no priorities.
+//
+// A common policy, following current web performance guidance, fetches the
+// first stylesheet at a high priority and scripts at a low priority:
+//
+//	temple.FetchPriorityPolicy{
+//		FirstCSS: temple.FetchPriorityHigh,
+//		JS:       temple.FetchPriorityLow,
+//	}
+type FetchPriorityPolicy struct {
+	// PreloadedCSS is the priority of critical stylesheets, which are
+	// preloaded rather than linked.
+	PreloadedCSS FetchPriority
+
+	// FirstCSS is the priority of the first stylesheet in LinkedCSS. If
+	// it's empty, CSS is used.
+	FirstCSS FetchPriority
+
+	// CSS is the priority of the stylesheets in LinkedCSS.
+	CSS FetchPriority
+
+	// FirstJS is the priority of the first script in LinkedJS. If it's
+	// empty, JS is used.
+	FirstJS FetchPriority
+
+	// JS is the priority of the scripts in LinkedJS.
+	JS FetchPriority
+}
+
+// priorities returns the priority of each URL, according to the
+// FetchPriorityPolicy. URLs with no priority aren't included.
+func (p FetchPriorityPolicy) priorities(preloadedCSS, linkedCSS, linkedJS []string) map[string]FetchPriority {
+	results := map[string]FetchPriority{}
+	set := func(urls []string, first, rest FetchPriority) {
+		for pos, url := range urls {
+			priority := rest
+			if pos == 0 && first != "" {
+				priority = first
+			}
+			if priority == "" {
+				continue
+			}
+			if _, ok := results[url]; !ok {
+				results[url] = priority
+			}
+		}
+	}
+	set(preloadedCSS, "", p.PreloadedCSS)
+	set(linkedCSS, p.FirstCSS, p.CSS)
+	set(linkedJS, p.FirstJS, p.JS)
+	if len(results) < 1 {
+		return nil
+	}
+	return results
+}
+
+// getFetchPriorities returns the priority of each of the page's resources,
+// according to the Site's FetchPriorityPolicy, if it's a
+// FetchPriorityPolicier.
+func getFetchPriorities(ctx context.Context, site Site, preloadedCSS, linkedCSS, linkedJS []string) map[string]FetchPriority {
+	policier, ok := site.(FetchPriorityPolicier)
+	if !ok {
+		return nil
+	}
+	return policier.FetchPriorityPolicy(ctx).priorities(preloadedCSS, linkedCSS, linkedJS)
+}
+
+// fetchPriorityAttr returns a fetchpriority attribute, with a leading space,
+// for the priority, or nothing if priority is empty.
+func fetchPriorityAttr(priority FetchPriority) template.HTMLAttr {
+	if priority == "" {
+		return ""
+	}
+	return template.HTMLAttr(` fetchpriority="` + html.EscapeString(string(priority)) + `"`) // #nosec G203
+}
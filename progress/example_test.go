@@ -0,0 +1,98 @@
+package progress_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing/fstest"
+
+	"impractical.co/temple"
+	"impractical.co/temple/progress"
+)
+
+type exportJob struct {
+	rows, total int
+}
+
+func (e exportJob) Status(_ context.Context) (progress.Status, error) {
+	if e.rows >= e.total {
+		return progress.Status{
+			Percent:     100,
+			Done:        true,
+			RedirectURL: "/exports/42/download",
+		}, nil
+	}
+	return progress.Status{
+		Percent: e.rows * 100 / e.total,
+		Message: fmt.Sprintf("Exported %d of %d rows", e.rows, e.total),
+	}, nil
+}
+
+type ExportPage struct {
+	Progress progress.Progress
+}
+
+func (ExportPage) Templates(_ context.Context) []string {
+	return []string{"export.html.tmpl"}
+}
+
+func (e ExportPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{e.Progress}
+}
+
+func (ExportPage) Key(_ context.Context) string {
+	return "export.html.tmpl"
+}
+
+func (ExportPage) ExecutedTemplate(_ context.Context) string {
+	return "export.html.tmpl"
+}
+
+func Example() {
+	templates := fstest.MapFS{
+		"export.html.tmpl": {Data: []byte(`<h1>Exporting</h1>
+{{ template "progress/progress.html.tmpl" .Page.Progress }}`)},
+	}
+	site := temple.NewCachedSite(templates)
+
+	ctx := context.Background()
+	job := exportJob{rows: 300, total: 1200}
+	status, err := job.Status(ctx)
+	if err != nil {
+		panic(err)
+	}
+	temple.Render(ctx, os.Stdout, site, ExportPage{
+		Progress: progress.Progress{
+			Status:  status,
+			PollURL: "/exports/42/progress",
+		},
+	})
+
+	//Output:
+	// <h1>Exporting</h1>
+	// <div class="temple-progress" data-temple-progress data-poll-url="/exports/42/progress" data-interval="2000" role="status" aria-live="polite">
+	// 	<progress value="25" max="100">25%</progress>
+	// 	<p>Exported 300 of 1200 rows</p>
+	// </div>
+}
+
+func ExampleHandler() {
+	site := temple.NewCachedSite(fstest.MapFS{})
+	handler := progress.Handler(site, func(_ *http.Request) (progress.Job, error) {
+		return exportJob{rows: 1200, total: 1200}, nil
+	}, 0)
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/exports/42/progress", nil))
+	fmt.Println(resp.Header().Get("Cache-Control"))
+	fmt.Println(resp.Body.String())
+
+	//Output:
+	// no-store
+	// <div class="temple-progress" data-temple-progress data-redirect-url="/exports/42/download" role="status" aria-live="polite">
+	// 	<progress value="100" max="100">100%</progress>
+	// 	<p><a href="/exports/42/download">Continue</a></p>
+	// </div>
+}
@@ -0,0 +1,8 @@
+package progress
+
+import "impractical.co/temple"
+
+// Package returns the temple.ComponentPackage for Progress and Fragment.
+func Package() (temple.ComponentPackage, error) {
+	return temple.NewComponentPackage("progress", templates, "templates/progress", "", Progress{}, Fragment{})
+}
@@ -0,0 +1,263 @@
+// Package progress provides a temple Component for pages that wait on a
+// long-running background job, like an export or an import. The page renders
+// a Progress Component showing the job's Status, which polls a Handler for
+// updates and redirects the browser once the job is done.
+//
+// A page using the Component needs to include it in its UseComponents output,
+// execute its template, and include its EmbeddedJS:
+//
+//	{{ template "progress/progress.html.tmpl" .Page.Progress }}
+//	<script>{{ .EmbeddedJS }}</script>
+//
+// and the Site needs to serve a Handler at the Progress' PollURL:
+//
+//	mux.Handle("/exports/{id}/progress", progress.Handler(site, lookupExport, 0))
+package progress
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"time"
+
+	"impractical.co/temple"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// defaultInterval is how often a Progress with no Interval set polls for
+// updates.
+const defaultInterval = 2 * time.Second
+
+const css = `
+.temple-progress progress { width: 100%; }
+.temple-progress-error { color: #b00020; }
+`
+
+// js polls the PollURL of every Progress on the page, replacing it with the
+// fragment returned, until the job is done, then follows its redirect.
+const js = `
+(function () {
+	function schedule(el) {
+		var redirect = el.getAttribute("data-redirect-url");
+		if (redirect) {
+			window.location.assign(redirect);
+			return;
+		}
+		var url = el.getAttribute("data-poll-url");
+		if (!url) {
+			return;
+		}
+		var interval = parseInt(el.getAttribute("data-interval"), 10) || 2000;
+		setTimeout(function () {
+			fetch(url, {headers: {"Accept": "text/html"}}).then(function (resp) {
+				if (!resp.ok) {
+					throw new Error(resp.statusText);
+				}
+				return resp.text();
+			}).then(function (html) {
+				var container = document.createElement("div");
+				container.innerHTML = html;
+				var next = container.querySelector("[data-temple-progress]");
+				if (!next) {
+					return;
+				}
+				el.replaceWith(next);
+				schedule(next);
+			}).catch(function () {
+				// try again after the next interval
+				schedule(el);
+			});
+		}, interval);
+	}
+	function start() {
+		document.querySelectorAll("[data-temple-progress]").forEach(schedule);
+	}
+	if (document.readyState === "loading") {
+		document.addEventListener("DOMContentLoaded", start);
+	} else {
+		start();
+	}
+})();
+`
+
+// ErrJobNotFound can be returned by the lookup function passed to Handler to
+// respond with a 404 Not Found.
+var ErrJobNotFound = errors.New("job not found")
+
+// Status is the progress of a job at a point in time.
+type Status struct {
+	// Percent is how much of the job is complete, from 0 to 100.
+	Percent int
+
+	// Indeterminate marks the job's progress as unknown, in which case
+	// Percent is ignored.
+	Indeterminate bool
+
+	// Message describes what the job is doing, like "Exporting 1,200
+	// rows".
+	Message string
+
+	// Done marks the job as finished, successfully or not. Once the job
+	// is done, the Progress stops polling for updates.
+	Done bool
+
+	// Error is a message to display to the user if the job failed. It
+	// should only be set if Done is true.
+	Error string
+
+	// RedirectURL is where to send the browser when the job finishes
+	// successfully, like the page to download an export from.
+	RedirectURL string
+}
+
+// Job is a long-running task whose progress can be displayed using a
+// Progress Component.
+type Job interface {
+	// Status returns the current progress of the job.
+	Status(ctx context.Context) (Status, error)
+}
+
+var (
+	_ temple.Component           = Progress{}
+	_ temple.TemplateDirProvider = Progress{}
+	_ temple.CSSEmbedder         = Progress{}
+	_ temple.JSEmbedder          = Progress{}
+)
+
+// Progress is a Component that renders the Status of a job, polling its
+// PollURL for updates until the job is done.
+type Progress struct {
+	// Status is the job's progress as of the time the page is rendered.
+	Status Status
+
+	// PollURL is the URL of a Handler that renders the job's updated
+	// progress.
+	PollURL string
+
+	// Interval is how often to poll for updates. Defaults to two seconds.
+	Interval time.Duration
+}
+
+// Templates returns the template needed to render the Progress.
+func (Progress) Templates(_ context.Context) []string {
+	return []string{"progress/progress.html.tmpl"}
+}
+
+// TemplateDir returns the fs.FS containing the Progress' template.
+func (Progress) TemplateDir(_ context.Context) fs.FS {
+	return temple.SubFS(templates, "templates")
+}
+
+// EmbedCSS returns the CSS used to style the Progress.
+func (Progress) EmbedCSS(_ context.Context) template.CSS {
+	return css
+}
+
+// EmbedJS returns the JavaScript that polls for updates.
+func (Progress) EmbedJS(_ context.Context) template.JS {
+	return js
+}
+
+// Polling returns true if the job isn't done yet, so the Progress should
+// poll for updates.
+func (p Progress) Polling() bool {
+	return !p.Status.Done && p.PollURL != ""
+}
+
+// IntervalMillis returns how often to poll for updates, in milliseconds.
+func (p Progress) IntervalMillis() int64 {
+	if p.Interval <= 0 {
+		return defaultInterval.Milliseconds()
+	}
+	return p.Interval.Milliseconds()
+}
+
+// RedirectURL returns where to send the browser, which is only set once the
+// job has finished successfully.
+func (p Progress) RedirectURL() string {
+	if !p.Status.Done || p.Status.Error != "" {
+		return ""
+	}
+	return p.Status.RedirectURL
+}
+
+var (
+	_ temple.Renderable  = Fragment{}
+	_ temple.CachePolicy = Fragment{}
+)
+
+// Fragment is a Renderable that renders just a Progress, for polling
+// requests. Handler renders it, but it can also be used directly by Sites
+// that need more control over their polling endpoint.
+type Fragment struct {
+	Progress Progress
+}
+
+// Templates returns the template needed to render the Fragment.
+func (Fragment) Templates(_ context.Context) []string {
+	return []string{"progress/fragment.html.tmpl"}
+}
+
+// TemplateDir returns the fs.FS containing the Fragment's template.
+func (Fragment) TemplateDir(_ context.Context) fs.FS {
+	return temple.SubFS(templates, "templates")
+}
+
+// UseComponents returns the Progress the Fragment renders.
+func (f Fragment) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{f.Progress}
+}
+
+// Key returns the key the Fragment's templates are cached under.
+func (Fragment) Key(_ context.Context) string {
+	return "impractical.co/temple/progress.Fragment"
+}
+
+// ExecutedTemplate returns the template that renders the Fragment.
+func (Fragment) ExecutedTemplate(_ context.Context) string {
+	return "progress/fragment.html.tmpl"
+}
+
+// CacheControl stops the Fragment from being cached, so every poll gets the
+// job's latest progress.
+func (Fragment) CacheControl(_ context.Context) temple.CacheControl {
+	return temple.CacheControl{NoStore: true}
+}
+
+// Handler returns an http.Handler that renders the progress of the Job
+// returned by `lookup` as a Fragment, for Progress Components to poll. The
+// Fragment polls the URL of the request it's rendered for, every `interval`,
+// or every two seconds if `interval` is 0.
+//
+// If `lookup` returns an error wrapping ErrJobNotFound, the Handler responds
+// with a 404 Not Found.
+func Handler[SiteType temple.Site](site SiteType, lookup func(*http.Request) (Job, error), interval time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		job, err := lookup(r)
+		if errors.Is(err, ErrJobNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		var status Status
+		if err == nil {
+			status, err = job.Status(ctx)
+		}
+		if err != nil {
+			http.Error(w, "Server error.", http.StatusInternalServerError)
+			return
+		}
+		temple.Render(ctx, w, site, Fragment{
+			Progress: Progress{
+				Status:   status,
+				PollURL:  r.URL.RequestURI(),
+				Interval: interval,
+			},
+		})
+	})
+}
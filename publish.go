@@ -0,0 +1,100 @@
+package temple
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+)
+
+type publishedCtxKey struct{}
+
+// published holds the values Components have published during a single
+// render.
+type published struct {
+	values map[string][]any
+	mu     sync.Mutex
+}
+
+// withPublished returns a context.Context that Publish can record values in
+// for the duration of a render.
+func withPublished(ctx context.Context) context.Context {
+	return context.WithValue(ctx, publishedCtxKey{}, &published{values: map[string][]any{}})
+}
+
+// Publish records the value under the topic for the rest of the render the
+// context.Context belongs to, so other Components can find out about it with
+// Published. It lets Components coordinate without knowing about each other,
+// like a map Component publishing that a map is on the page so the layout
+// links to the maps script once:
+//
+//	func (m Map) UseComponents(ctx context.Context) []temple.Component {
+//		temple.Publish(ctx, "maps", m.Provider)
+//		return nil
+//	}
+//
+//	func (l Layout) LinkJS(ctx context.Context) []string {
+//		if len(temple.Published(ctx, "maps")) > 0 {
+//			return []string{"/js/maps.js"}
+//		}
+//		return nil
+//	}
+//
+// Values should be published while the tree of Components is being resolved,
+// from UseComponents, and read afterwards, from the methods that supply CSS,
+// JavaScript, and data, or from templates, using the published template
+// function. If the Site is a GraphCacher, values published while resolving
+// the tree are cached with it, and published again when the tree is
+// retrieved from the cache.
+//
+// Publish does nothing if the context.Context doesn't belong to a render.
+func Publish(ctx context.Context, topic string, value any) {
+	pub, ok := ctx.Value(publishedCtxKey{}).(*published)
+	if !ok {
+		return
+	}
+	pub.mu.Lock()
+	defer pub.mu.Unlock()
+	pub.values[topic] = append(pub.values[topic], value)
+}
+
+// Published returns the values published under the topic so far in the
+// render the context.Context belongs to, in the order they were published.
+func Published(ctx context.Context, topic string) []any {
+	pub, ok := ctx.Value(publishedCtxKey{}).(*published)
+	if !ok {
+		return nil
+	}
+	pub.mu.Lock()
+	defer pub.mu.Unlock()
+	return slices.Clone(pub.values[topic])
+}
+
+// snapshotPublished returns a copy of every value published so far in the
+// render the context.Context belongs to.
+func snapshotPublished(ctx context.Context) map[string][]any {
+	pub, ok := ctx.Value(publishedCtxKey{}).(*published)
+	if !ok {
+		return nil
+	}
+	pub.mu.Lock()
+	defer pub.mu.Unlock()
+	if len(pub.values) < 1 {
+		return nil
+	}
+	results := make(map[string][]any, len(pub.values))
+	for topic, values := range pub.values {
+		results[topic] = slices.Clone(values)
+	}
+	return results
+}
+
+// restorePublished publishes the values from a snapshot taken with
+// snapshotPublished in the render the context.Context belongs to.
+func restorePublished(ctx context.Context, values map[string][]any) {
+	for _, topic := range slices.Sorted(maps.Keys(values)) {
+		for _, value := range values[topic] {
+			Publish(ctx, topic, value)
+		}
+	}
+}
@@ -0,0 +1,553 @@
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrContentTooLong is returned when the content passed to DefaultEncoder is
+// too long to fit in a QR code at the requested Level.
+var ErrContentTooLong = errors.New("content too long for a QR code")
+
+// Level is the error correction level of a QR code. Higher levels let the code
+// be read even if more of it is damaged or obscured, at the cost of a larger
+// code. The zero value is LevelMedium.
+type Level int
+
+const (
+	// LevelMedium can recover from about 15% of the code being damaged.
+	LevelMedium Level = iota
+
+	// LevelLow can recover from about 7% of the code being damaged.
+	LevelLow
+
+	// LevelQuartile can recover from about 25% of the code being damaged.
+	LevelQuartile
+
+	// LevelHigh can recover from about 30% of the code being damaged.
+	LevelHigh
+)
+
+// formatBits returns the bits identifying the Level in the QR code's format
+// information.
+func (l Level) formatBits() int {
+	switch l {
+	case LevelLow:
+		return 1
+	case LevelQuartile:
+		return 3
+	case LevelHigh:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// tableIndex returns the index of the Level in eccCodewordsPerBlock and
+// eccBlocks.
+func (l Level) tableIndex() int {
+	switch l {
+	case LevelLow:
+		return 0
+	case LevelQuartile:
+		return 2
+	case LevelHigh:
+		return 3
+	default:
+		return 1
+	}
+}
+
+// eccCodewordsPerBlock is the number of error correction codewords in each
+// block, indexed by Level and version. Version 0 doesn't exist.
+var eccCodewordsPerBlock = [4][41]int{
+	{0, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{0, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+// eccBlocks is the number of error correction blocks, indexed by Level and
+// version. Version 0 doesn't exist.
+var eccBlocks = [4][41]int{
+	{0, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{0, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{0, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{0, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+var _ Encoder = DefaultEncoder{}
+
+// DefaultEncoder is the Encoder used by QR when it has no Encoder set. It
+// encodes content in byte mode, using the smallest version (size) of QR code
+// that fits the content at its Level, and picks the mask that makes the code
+// easiest to scan.
+type DefaultEncoder struct {
+	// Level is the error correction level of the generated codes.
+	// Defaults to LevelMedium.
+	Level Level
+}
+
+// Encode returns the modules of a QR code containing the content.
+func (e DefaultEncoder) Encode(content string) ([][]bool, error) {
+	data := []byte(content)
+	version := 0
+	for v := 1; v <= 40; v++ {
+		countBits := 8
+		if v >= 10 {
+			countBits = 16
+		}
+		if len(data) >= 1<<countBits {
+			continue
+		}
+		if 4+countBits+len(data)*8 <= numDataCodewords(v, e.Level)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("%w: %d bytes", ErrContentTooLong, len(data))
+	}
+
+	code := newSymbol(version, e.Level)
+	code.drawCodewords(code.addErrorCorrection(encodeData(data, version, e.Level)))
+
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		code.applyMask(mask)
+		code.drawFormatBits(mask)
+		penalty := code.penalty()
+		if bestPenalty < 0 || penalty < bestPenalty {
+			best, bestPenalty = mask, penalty
+		}
+		// masks are applied with XOR, so applying it again undoes it
+		code.applyMask(mask)
+	}
+	code.applyMask(best)
+	code.drawFormatBits(best)
+	return code.modules, nil
+}
+
+// numRawDataModules returns the number of modules in a QR code of the passed
+// version that can hold data, after all the function patterns are placed.
+func numRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+// numDataCodewords returns the number of 8-bit codewords of data a QR code of
+// the passed version and Level can hold.
+func numDataCodewords(version int, level Level) int {
+	idx := level.tableIndex()
+	return numRawDataModules(version)/8 - eccCodewordsPerBlock[idx][version]*eccBlocks[idx][version]
+}
+
+// encodeData returns the data codewords of a QR code containing the passed
+// data in byte mode, padded to fill the code.
+func encodeData(data []byte, version int, level Level) []byte {
+	capacity := numDataCodewords(version, level) * 8
+	var bits []bool
+	appendBits := func(val, length int) {
+		for i := length - 1; i >= 0; i-- {
+			bits = append(bits, (val>>i)&1 != 0)
+		}
+	}
+	// byte mode indicator
+	appendBits(0x4, 4)
+	if version < 10 {
+		appendBits(len(data), 8)
+	} else {
+		appendBits(len(data), 16)
+	}
+	for _, b := range data {
+		appendBits(int(b), 8)
+	}
+	// terminator, then pad to a whole number of bytes
+	appendBits(0, min(4, capacity-len(bits)))
+	appendBits(0, (8-len(bits)%8)%8)
+	// alternating pad bytes fill the rest of the capacity
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		appendBits(pad, 8)
+	}
+	result := make([]byte, len(bits)/8)
+	for i, bit := range bits {
+		if bit {
+			result[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return result
+}
+
+// symbol is a QR code being built.
+type symbol struct {
+	version  int
+	level    Level
+	size     int
+	modules  [][]bool
+	function [][]bool
+}
+
+// newSymbol returns a symbol of the passed version and Level with all its
+// function patterns drawn.
+func newSymbol(version int, level Level) *symbol {
+	size := version*4 + 17
+	code := &symbol{
+		version:  version,
+		level:    level,
+		size:     size,
+		modules:  make([][]bool, size),
+		function: make([][]bool, size),
+	}
+	for i := range code.modules {
+		code.modules[i] = make([]bool, size)
+		code.function[i] = make([]bool, size)
+	}
+
+	// timing patterns
+	for i := 0; i < size; i++ {
+		code.setFunction(6, i, i%2 == 0)
+		code.setFunction(i, 6, i%2 == 0)
+	}
+
+	// finder patterns, in three corners
+	code.drawFinder(3, 3)
+	code.drawFinder(size-4, 3)
+	code.drawFinder(3, size-4)
+
+	// alignment patterns, everywhere but where they'd overlap the
+	// finder patterns
+	positions := alignmentPositions(version)
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			code.drawAlignment(x, y)
+		}
+	}
+
+	// reserve the format bits, which depend on the mask, so they're
+	// drawn later
+	code.drawFormatBits(0)
+	code.drawVersion()
+	return code
+}
+
+func (s *symbol) setFunction(x, y int, dark bool) {
+	s.modules[y][x] = dark
+	s.function[y][x] = true
+}
+
+func (s *symbol) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= s.size || yy < 0 || yy >= s.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			s.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (s *symbol) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			s.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// alignmentPositions returns the coordinates of the centers of the alignment
+// patterns, along each axis, for the passed version.
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	numAlign := version/7 + 2
+	step := (version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+	result := make([]int, numAlign)
+	result[0] = 6
+	for i, pos := numAlign-1, version*4+17-7; i >= 1; i, pos = i-1, pos-step {
+		result[i] = pos
+	}
+	return result
+}
+
+// drawFormatBits draws the format information, identifying the Level and
+// mask, in both the places it appears in the code.
+func (s *symbol) drawFormatBits(mask int) {
+	data := s.level.formatBits()<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool {
+		return (bits>>i)&1 != 0
+	}
+
+	// around the top left finder pattern
+	for i := 0; i <= 5; i++ {
+		s.setFunction(8, i, bit(i))
+	}
+	s.setFunction(8, 7, bit(6))
+	s.setFunction(8, 8, bit(7))
+	s.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		s.setFunction(14-i, 8, bit(i))
+	}
+
+	// split between the other two finder patterns
+	for i := 0; i < 8; i++ {
+		s.setFunction(s.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		s.setFunction(8, s.size-15+i, bit(i))
+	}
+	// this module is always dark
+	s.setFunction(8, s.size-8, true)
+}
+
+// drawVersion draws the version information, which only codes of version 7 and
+// up have.
+func (s *symbol) drawVersion() {
+	if s.version < 7 {
+		return
+	}
+	rem := s.version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := s.version<<12 | rem
+	for i := 0; i < 18; i++ {
+		dark := (bits>>i)&1 != 0
+		a, b := s.size-11+i%3, i/3
+		s.setFunction(a, b, dark)
+		s.setFunction(b, a, dark)
+	}
+}
+
+// addErrorCorrection splits the data codewords into blocks, appends the error
+// correction codewords to each block, and interleaves the blocks.
+func (s *symbol) addErrorCorrection(data []byte) []byte {
+	idx := s.level.tableIndex()
+	numBlocks := eccBlocks[idx][s.version]
+	eccLen := eccCodewordsPerBlock[idx][s.version]
+	rawCodewords := numRawDataModules(s.version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := reedSolomonDivisor(eccLen)
+	blocks := make([][]byte, 0, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		length := shortBlockLen - eccLen
+		if i >= numShortBlocks {
+			length++
+		}
+		block := append([]byte{}, data[k:k+length]...)
+		k += length
+		ecc := reedSolomonRemainder(block, divisor)
+		if i < numShortBlocks {
+			// pad short blocks so every block is the same length
+			// while interleaving; the padding is skipped below
+			block = append(block, 0)
+		}
+		blocks = append(blocks, append(block, ecc...))
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortBlockLen-eccLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// drawCodewords draws the codewords into the modules that aren't part of a
+// function pattern, in the zigzag order QR codes use.
+func (s *symbol) drawCodewords(codewords []byte) {
+	i := 0
+	for right := s.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			// skip the vertical timing pattern
+			right = 5
+		}
+		for vert := 0; vert < s.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					// this pair of columns is read upwards
+					y = s.size - 1 - vert
+				}
+				if s.function[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+				s.modules[y][x] = (codewords[i>>3]>>(7-i&7))&1 != 0
+				i++
+			}
+		}
+	}
+}
+
+// applyMask inverts the modules that aren't part of a function pattern
+// according to the passed mask pattern.
+func (s *symbol) applyMask(mask int) {
+	for y := 0; y < s.size; y++ {
+		for x := 0; x < s.size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !s.function[y][x] {
+				s.modules[y][x] = !s.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores how hard the code would be to scan, using the rules from the
+// QR code specification. Lower is better.
+func (s *symbol) penalty() int {
+	result := 0
+	lines := make([][]bool, 0, s.size*2)
+	for y := 0; y < s.size; y++ {
+		lines = append(lines, s.modules[y])
+	}
+	for x := 0; x < s.size; x++ {
+		column := make([]bool, s.size)
+		for y := 0; y < s.size; y++ {
+			column[y] = s.modules[y][x]
+		}
+		lines = append(lines, column)
+	}
+
+	finderLike := [][]bool{
+		{true, false, true, true, true, false, true, false, false, false, false},
+		{false, false, false, false, true, false, true, true, true, false, true},
+	}
+	for _, line := range lines {
+		// runs of five or more modules of the same color
+		run := 1
+		for i := 1; i <= len(line); i++ {
+			if i < len(line) && line[i] == line[i-1] {
+				run++
+				continue
+			}
+			if run >= 5 {
+				result += 3 + run - 5
+			}
+			run = 1
+		}
+		// patterns that look like finder patterns
+		for i := 0; i+11 <= len(line); i++ {
+			for _, pattern := range finderLike {
+				if slices.Equal(line[i:i+11], pattern) {
+					result += 40
+				}
+			}
+		}
+	}
+
+	// 2x2 blocks of the same color
+	dark := 0
+	for y := 0; y < s.size; y++ {
+		for x := 0; x < s.size; x++ {
+			if s.modules[y][x] {
+				dark++
+			}
+			if x+1 < s.size && y+1 < s.size {
+				c := s.modules[y][x]
+				if c == s.modules[y][x+1] && c == s.modules[y+1][x] && c == s.modules[y+1][x+1] {
+					result += 3
+				}
+			}
+		}
+	}
+
+	// the balance of dark and light modules
+	total := s.size * s.size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	result += k * 10
+	return result
+}
+
+// reedSolomonDivisor returns the generator polynomial for Reed-Solomon error
+// correction codes of the passed degree.
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// reedSolomonRemainder returns the Reed-Solomon error correction codewords for
+// the data, using the passed generator polynomial.
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i := range result {
+			result[i] ^= gfMultiply(divisor[i], factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies two elements of GF(2^8), modulo the polynomial QR
+// codes use.
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
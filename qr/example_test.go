@@ -0,0 +1,89 @@
+package qr_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing/fstest"
+
+	"impractical.co/temple"
+	"impractical.co/temple/qr"
+)
+
+type TicketPage struct {
+	QR qr.QR
+}
+
+func (TicketPage) Templates(_ context.Context) []string {
+	return []string{"ticket.html.tmpl"}
+}
+
+func (t TicketPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{t.QR}
+}
+
+func (TicketPage) Key(_ context.Context) string {
+	return "ticket.html.tmpl"
+}
+
+func (TicketPage) ExecutedTemplate(_ context.Context) string {
+	return "ticket.html.tmpl"
+}
+
+func Example() {
+	templates := fstest.MapFS{
+		"ticket.html.tmpl": {Data: []byte(`{{ template "qr/qr.html.tmpl" .Page.QR }}`)},
+	}
+	site := temple.NewCachedSite(templates)
+
+	temple.Render(context.Background(), os.Stdout, site, TicketPage{
+		QR: qr.QR{
+			Content: "A1",
+			Label:   "Ticket A1",
+			Size:    120,
+		},
+	})
+
+	//Output:
+	// <svg class="temple-qr" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 29 29" width="120" height="120" role="img" aria-label="Ticket A1" shape-rendering="crispEdges"><rect width="100%" height="100%" fill="#fff"/><path d="M4 4h7v1h-7zM13 4h1v1h-1zM15 4h2v1h-2zM18 4h7v1h-7zM4 5h1v1h-1zM10 5h1v1h-1zM12 5h1v1h-1zM18 5h1v1h-1zM24 5h1v1h-1zM4 6h1v1h-1zM6 6h3v1h-3zM10 6h1v1h-1zM13 6h3v1h-3zM18 6h1v1h-1zM20 6h3v1h-3zM24 6h1v1h-1zM4 7h1v1h-1zM6 7h3v1h-3zM10 7h1v1h-1zM13 7h1v1h-1zM15 7h2v1h-2zM18 7h1v1h-1zM20 7h3v1h-3zM24 7h1v1h-1zM4 8h1v1h-1zM6 8h3v1h-3zM10 8h1v1h-1zM12 8h2v1h-2zM15 8h2v1h-2zM18 8h1v1h-1zM20 8h3v1h-3zM24 8h1v1h-1zM4 9h1v1h-1zM10 9h1v1h-1zM14 9h2v1h-2zM18 9h1v1h-1zM24 9h1v1h-1zM4 10h7v1h-7zM12 10h1v1h-1zM14 10h1v1h-1zM16 10h1v1h-1zM18 10h7v1h-7zM13 11h1v1h-1zM4 12h1v1h-1zM6 12h1v1h-1zM8 12h1v1h-1zM10 12h1v1h-1zM13 12h2v1h-2zM16 12h1v1h-1zM20 12h1v1h-1zM23 12h1v1h-1zM4 13h2v1h-2zM7 13h1v1h-1zM11 13h1v1h-1zM15 13h1v1h-1zM17 13h1v1h-1zM19 13h1v1h-1zM21 13h1v1h-1zM24 13h1v1h-1zM4 14h5v1h-5zM10 14h2v1h-2zM13 14h1v1h-1zM15 14h1v1h-1zM17 14h3v1h-3zM21 14h2v1h-2zM24 14h1v1h-1zM8 15h1v1h-1zM15 15h3v1h-3zM19 15h3v1h-3zM5 16h2v1h-2zM10 16h4v1h-4zM15 16h1v1h-1zM17 16h3v1h-3zM22 16h1v1h-1zM24 16h1v1h-1zM12 17h1v1h-1zM18 17h1v1h-1zM22 17h2v1h-2zM4 18h7v1h-7zM13 18h2v1h-2zM16 18h1v1h-1zM20 18h1v1h-1zM23 18h2v1h-2zM4 19h1v1h-1zM10 19h1v1h-1zM14 19h1v1h-1zM18 19h1v1h-1zM22 19h3v1h-3zM4 20h1v1h-1zM6 20h3v1h-3zM10 20h1v1h-1zM12 20h3v1h-3zM16 20h1v1h-1zM18 20h1v1h-1zM20 20h1v1h-1zM22 20h1v1h-1zM24 20h1v1h-1zM4 21h1v1h-1zM6 21h3v1h-3zM10 21h1v1h-1zM15 21h1v1h-1zM17 21h1v1h-1zM19 21h1v1h-1zM21 21h1v1h-1zM23 21h1v1h-1zM4 22h1v1h-1zM6 22h3v1h-3zM10 22h1v1h-1zM12 22h1v1h-1zM14 22h2v1h-2zM17 22h3v1h-3zM21 22h2v1h-2zM24 22h1v1h-1zM4 23h1v1h-1zM10 23h1v1h-1zM14 23h4v1h-4zM19 23h3v1h-3zM23 23h1v1h-1zM4 24h7v1h-7zM12 24h2v1h-2zM15 24h1v1h-1zM17 24h3v1h-3zM21 24h4v1h-4z" fill="#000"/></svg>
+}
+
+func ExampleDefaultEncoder() {
+	modules, err := qr.DefaultEncoder{Level: qr.LevelLow}.Encode("temple")
+	if err != nil {
+		panic(err)
+	}
+	for _, row := range modules {
+		for _, dark := range row {
+			if dark {
+				fmt.Print("#")
+			} else {
+				fmt.Print(".")
+			}
+		}
+		fmt.Println()
+	}
+
+	//Output:
+	// #######...#.#.#######
+	// #.....#.#.#.#.#.....#
+	// #.###.#.#.##..#.###.#
+	// #.###.#.....#.#.###.#
+	// #.###.#.#####.#.###.#
+	// #.....#.###...#.....#
+	// #######.#.#.#.#######
+	// ........#............
+	// ##.#..##..###.###.##.
+	// .#..##..#.##.#.#...##
+	// #.#..##.##.#..#####.#
+	// ##.##..###..######.##
+	// ##.#####.###.###...##
+	// ........##...####.###
+	// #######.#..##.#.#.##.
+	// #.....#..##...#....##
+	// #.###.#..#..####.#..#
+	// #.###.#.#....####..##
+	// #.###.#...##.####.#.#
+	// #.....#.#..##..##....
+	// #######.#.#..#.#.#.#.
+}
@@ -0,0 +1,8 @@
+package qr
+
+import "impractical.co/temple"
+
+// Package returns the temple.ComponentPackage for QR.
+func Package() (temple.ComponentPackage, error) {
+	return temple.NewComponentPackage("qr", templates, "templates/qr", "", QR{})
+}
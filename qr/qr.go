@@ -0,0 +1,150 @@
+// Package qr provides a temple Component for rendering QR codes as inline SVG,
+// for things like tickets or setting up two-factor authentication apps.
+//
+// The Component supplies its own template and embeds the CSS it needs, so all
+// that's required to use it is to include it in the UseComponents output of a
+// Component and execute its template:
+//
+//	{{ template "qr/qr.html.tmpl" .Page.QR }}
+package qr
+
+import (
+	"context"
+	"embed"
+	"html/template"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"impractical.co/temple"
+)
+
+//go:embed templates
+var templates embed.FS
+
+const (
+	// defaultSize is the size, in pixels, of a QR with no Size set.
+	defaultSize = 200
+
+	// quietZone is the number of light modules around the code, which
+	// scanners need to find it.
+	quietZone = 4
+)
+
+const css = `
+.temple-qr { display: block; max-width: 100%; height: auto; }
+`
+
+// Encoder turns content into a QR code. DefaultEncoder is used if a QR has no
+// Encoder set; other implementations can be used to support other encoding
+// modes or to reuse a QR code library a project already depends on.
+type Encoder interface {
+	// Encode returns the modules of a QR code containing the content, as
+	// rows of columns, with true for dark modules. The result shouldn't
+	// include the quiet zone around the code.
+	Encode(content string) ([][]bool, error)
+}
+
+var (
+	_ temple.Component           = QR{}
+	_ temple.TemplateDirProvider = QR{}
+	_ temple.CSSEmbedder         = QR{}
+)
+
+// QR is a Component that renders its Content as a QR code, using an inline
+// <svg> element.
+type QR struct {
+	// Content is the text or URL to encode in the QR code.
+	Content string
+
+	// Label is the accessible name of the QR code. Defaults to "QR code".
+	// It's a good idea to also display the Content as text, for people
+	// who can't scan the code.
+	Label string
+
+	// Size is the width and height of the QR code, in pixels. Defaults to
+	// 200.
+	Size int
+
+	// Encoder is used to generate the QR code. Defaults to
+	// DefaultEncoder.
+	Encoder Encoder
+}
+
+// Templates returns the template needed to render the QR.
+func (QR) Templates(_ context.Context) []string {
+	return []string{"qr/qr.html.tmpl"}
+}
+
+// TemplateDir returns the fs.FS containing the QR's template.
+func (QR) TemplateDir(_ context.Context) fs.FS {
+	return temple.SubFS(templates, "templates")
+}
+
+// EmbedCSS returns the CSS used to style QR codes.
+func (QR) EmbedCSS(_ context.Context) template.CSS {
+	return css
+}
+
+// PixelSize returns the width and height of the QR code, in pixels.
+func (q QR) PixelSize() int {
+	if q.Size < 1 {
+		return defaultSize
+	}
+	return q.Size
+}
+
+// AriaLabel returns the accessible name of the QR code.
+func (q QR) AriaLabel() string {
+	if q.Label == "" {
+		return "QR code"
+	}
+	return q.Label
+}
+
+// Code encodes the QR's Content using its Encoder.
+func (q QR) Code() (Code, error) {
+	encoder := q.Encoder
+	if encoder == nil {
+		encoder = DefaultEncoder{}
+	}
+	modules, err := encoder.Encode(q.Content)
+	if err != nil {
+		return Code{}, err
+	}
+	return Code{Modules: modules}, nil
+}
+
+// Code is an encoded QR code.
+type Code struct {
+	// Modules are the modules of the QR code, as rows of columns, with
+	// true for dark modules.
+	Modules [][]bool
+}
+
+// ViewBox returns the size of the code in modules, including the quiet zone
+// around it, for use in the viewBox of an <svg> element.
+func (c Code) ViewBox() int {
+	return len(c.Modules) + quietZone*2
+}
+
+// Path returns SVG path data drawing the dark modules of the code, offset by
+// the quiet zone. Horizontal runs of dark modules are drawn as a single
+// rectangle, to keep the path short.
+func (c Code) Path() string {
+	var path strings.Builder
+	for y, row := range c.Modules {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			path.WriteString("M" + strconv.Itoa(start+quietZone) + " " + strconv.Itoa(y+quietZone))
+			path.WriteString("h" + strconv.Itoa(x-start) + "v1h-" + strconv.Itoa(x-start) + "z")
+		}
+	}
+	return path.String()
+}
@@ -0,0 +1,119 @@
+package temple
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RecentError is a render error recorded by RecentErrors.
+type RecentError struct {
+	// Key is the Key of the page that failed to render.
+	Key string `json:"key"`
+
+	// Error is the error's message.
+	Error string `json:"error"`
+
+	// Time is when the error was recorded.
+	Time time.Time `json:"time"`
+
+	// RequestID identifies the request that rendered the page, if
+	// RecentErrors has a RequestID function.
+	RequestID string `json:"requestID,omitempty"`
+}
+
+// RenderErrorRecorder is an interface that Sites can fulfill to be told about
+// every page that fails to render for them, after the server error page has
+// been rendered. RecentErrors fulfills it, and can be embedded in a Site to
+// keep track of its most recent render errors.
+type RenderErrorRecorder interface {
+	// RecordRenderError is called with the RenderResult of each page
+	// that fails to render.
+	RecordRenderError(ctx context.Context, result RenderResult)
+}
+
+// RecentRenderErrorLister is an interface that Sites can fulfill to list the
+// pages that recently failed to render for them, which DebugHandler serves.
+// RecentErrors fulfills it.
+type RecentRenderErrorLister interface {
+	// RecentRenderErrors returns the recent render errors, newest
+	// first.
+	RecentRenderErrors(ctx context.Context) []RecentError
+}
+
+var (
+	_ RenderErrorRecorder     = &RecentErrors{}
+	_ RecentRenderErrorLister = &RecentErrors{}
+)
+
+// RecentErrors keeps the most recent render errors in memory, for displaying
+// on status pages or through DebugHandler. Embed it in a Site to record every
+// error Render encounters for that Site. A RecentErrors must be instantiated
+// through NewRecentErrors, its empty value is not usable. It can safely be
+// used by multiple goroutines.
+type RecentErrors struct {
+	// Redact is called with every RecentError before it's stored, and
+	// the RecentError it returns is stored instead. Use it to remove
+	// sensitive information, like personal data or secrets, from error
+	// messages. If nil, RecentErrors are stored as-is.
+	Redact func(RecentError) RecentError
+
+	// RequestID returns the ID of the request being served, to help
+	// find it in other logs. If nil, RecentErrors have no RequestID.
+	RequestID func(context.Context) string
+
+	errors []RecentError
+	next   int
+	mu     sync.Mutex
+}
+
+// NewRecentErrors returns a RecentErrors that keeps the `size` most recent
+// render errors. If size is less than 1, it's treated as 1.
+func NewRecentErrors(size int) *RecentErrors {
+	return &RecentErrors{
+		errors: make([]RecentError, 0, max(size, 1)),
+	}
+}
+
+// RecordRenderError stores the render error in the RenderResult, replacing
+// the oldest one stored if there's no room for it. RenderResults without an
+// error are ignored.
+func (r *RecentErrors) RecordRenderError(ctx context.Context, result RenderResult) {
+	if result.Err == nil {
+		return
+	}
+	recent := RecentError{
+		Key:   result.Key,
+		Error: result.Err.Error(),
+		Time:  time.Now(),
+	}
+	if r.RequestID != nil {
+		recent.RequestID = r.RequestID(ctx)
+	}
+	if r.Redact != nil {
+		recent = r.Redact(recent)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.errors) < cap(r.errors) {
+		r.errors = append(r.errors, recent)
+		return
+	}
+	r.errors[r.next] = recent
+	r.next = (r.next + 1) % len(r.errors)
+}
+
+// RecentRenderErrors returns the stored render errors, newest first.
+func (r *RecentErrors) RecentRenderErrors(_ context.Context) []RecentError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	results := make([]RecentError, 0, len(r.errors))
+	// r.next is the oldest error once the buffer is full, and 0 until
+	// then, so walking backwards from it visits newest to oldest
+	for i := range len(r.errors) {
+		pos := (r.next - 1 - i + 2*len(r.errors)) % len(r.errors)
+		results = append(results, r.errors[pos])
+	}
+	return results
+}
@@ -0,0 +1,106 @@
+package temple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+var (
+	// ErrNotRegistered is returned by Registry.Resolve when nothing is
+	// registered under the name.
+	ErrNotRegistered = errors.New("nothing registered under that name")
+
+	// ErrAlreadyRegistered is returned when registering something with a
+	// Registry under a name that's already in use.
+	ErrAlreadyRegistered = errors.New("name already registered")
+)
+
+// Factory builds a Component or Renderable for a Registry, from the data
+// passed to Resolve.
+type Factory[T Component] func(ctx context.Context, data any) (T, error)
+
+// Registry maps names to Components or Renderables, so the one to use can be
+// chosen at runtime by name. It's meant for CMS-style Sites, where the page
+// type to render, or the Components on it, are chosen by content stored in a
+// database, instead of by the handler:
+//
+//	var pages temple.Registry[temple.Renderable]
+//
+//	pages.RegisterFunc("landing", func(_ context.Context, data any) (temple.Renderable, error) {
+//		content, ok := data.(Content)
+//		if !ok {
+//			return nil, fmt.Errorf("expected Content, got %T", data)
+//		}
+//		return LandingPage{Content: content}, nil
+//	})
+//
+//	page, err := pages.Resolve(ctx, content.Template, content)
+//
+// Use Registry[temple.Renderable] for pages and Registry[temple.Component] for
+// Components. The zero value is ready to use, and it can safely be used by
+// multiple goroutines.
+type Registry[T Component] struct {
+	factories map[string]Factory[T]
+	mu        sync.RWMutex
+}
+
+// Register registers the value under the name. Resolve returns the value
+// as-is, ignoring the data it's passed. Register returns an error wrapping
+// ErrAlreadyRegistered if something is already registered under the name.
+func (r *Registry[T]) Register(name string, value T) error {
+	return r.RegisterFunc(name, func(_ context.Context, _ any) (T, error) {
+		return value, nil
+	})
+}
+
+// RegisterFunc registers the Factory under the name. Resolve calls it to
+// build the Component or Renderable. RegisterFunc returns an error wrapping
+// ErrAlreadyRegistered if something is already registered under the name.
+func (r *Registry[T]) RegisterFunc(name string, factory Factory[T]) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.factories[name]; ok {
+		return fmt.Errorf("error registering %q: %w", name, ErrAlreadyRegistered)
+	}
+	if r.factories == nil {
+		r.factories = map[string]Factory[T]{}
+	}
+	r.factories[name] = factory
+	return nil
+}
+
+// Resolve returns the Component or Renderable registered under the name,
+// built from the data if it was registered with RegisterFunc. It returns an
+// error wrapping ErrNotRegistered if nothing is registered under the name.
+func (r *Registry[T]) Resolve(ctx context.Context, name string, data any) (T, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[name]
+	r.mu.RUnlock()
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("error resolving %q: %w", name, ErrNotRegistered)
+	}
+	value, err := factory(ctx, data)
+	if err != nil {
+		var zero T
+		return zero, fmt.Errorf("error resolving %q: %w", name, err)
+	}
+	return value, nil
+}
+
+// Names returns the names registered with the Registry, sorted
+// alphabetically, for things like listing the page types editors can choose
+// from.
+func (r *Registry[T]) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
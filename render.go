@@ -7,8 +7,14 @@ import (
 	"html/template"
 	"io"
 	"io/fs"
+	"maps"
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"slices"
+	"time"
 
-	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -21,6 +27,16 @@ var (
 	// ErrTemplatePatternMatchesNoFiles is returned when a template path is
 	// a pattern, but that pattern doesn't match any files.
 	ErrTemplatePatternMatchesNoFiles = errors.New("pattern matches no files")
+
+	// ErrFuncMapConflict is returned when the WithStrictFuncMaps
+	// RenderOption is used and two Components add different functions
+	// with the same name to the FuncMap.
+	ErrFuncMapConflict = errors.New("conflicting FuncMap entries")
+
+	// ErrComponentTooDeep is returned when Components are nested within
+	// each other too deeply, usually because of a cycle between
+	// Components that can't be compared, which CycleError can't detect.
+	ErrComponentTooDeep = errors.New("components nested too deeply")
 )
 
 // Component is an interface for a UI component that can be rendered to HTML.
@@ -42,6 +58,21 @@ type ComponentUser interface {
 	UseComponents(context.Context) []Component
 }
 
+// TemplateDirProvider is an interface that Components can fulfill to supply
+// their own templates, rather than relying on the Site's TemplateDir. This
+// lets packages of Components ship the templates they need, usually using
+// embed.FS. The paths returned by the Component's Templates method will be
+// looked up in the returned fs.FS.
+//
+// All templates are parsed into the same set, so Components supplying their
+// own templates should use paths that won't collide with the Site's, like
+// paths prefixed with the name of the package.
+type TemplateDirProvider interface {
+	// TemplateDir returns an fs.FS containing all the templates needed to
+	// render the Component.
+	TemplateDir(ctx context.Context) fs.FS
+}
+
 // FuncMapExtender is an interface that Components can fulfill to add to the
 // map of functions available to them when rendering.
 type FuncMapExtender interface {
@@ -81,6 +112,10 @@ type RenderData[SiteType Site, PageType Renderable] struct {
 	// Renderable type.
 	Page PageType
 
+	// Request is the request-scoped data embedded in the context.Context
+	// passed to Render using WithRequestData, if any.
+	Request any
+
 	// EmbeddedJS is the result of calling EmbedJS on the Renderable, if
 	// the Renderable supports the JSEmbedder interface.
 	EmbeddedJS template.JS
@@ -91,18 +126,197 @@ type RenderData[SiteType Site, PageType Renderable] struct {
 
 	// LinkedJS is the result of calling LinkJS on the Renderable, if the
 	// Renderable supports the JSLinker interface.
+	//
+	// Scripts that need to be included at a precise point in the page,
+	// like an ad slot, can be rendered there with the placeResource
+	// template function, {{ placeResource "/js/ads.js" }}. Scripts placed
+	// using a string literal are left out of LinkedJS, and each script is
+	// only rendered once, however many times it's placed.
 	LinkedJS []string
 
+	// IdleJS is the URLs of the scripts linked to using the JSLoadIdle
+	// strategy, if the Renderable supports the JSResourceLinker
+	// interface. They're loaded by the script IdleJSLoader renders.
+	IdleJS []string
+
 	// LinkedCSS is the result of calling LinkCSS on the Renderable, if the
-	// Renderable supports the CSSLinker interface.
+	// Renderable supports the CSSLinker interface, along with any
+	// non-critical stylesheets from LinkCSSResources, if the Renderable
+	// supports the CSSResourceLinker interface.
+	//
+	// Like LinkedJS, stylesheets can be rendered at a precise point in the
+	// page with the placeResource template function, and those placed
+	// using a string literal are left out of LinkedCSS.
 	LinkedCSS []string
+
+	// CriticalCSS is the merged contents of every critical stylesheet
+	// returned by LinkCSSResources, if the Renderable supports the
+	// CSSResourceLinker interface.
+	CriticalCSS template.CSS
+
+	// PreloadedCSS is the URLs of every critical stylesheet returned by
+	// LinkCSSResources, if the Renderable supports the CSSResourceLinker
+	// interface. They should be preloaded, not linked, as their contents
+	// are already available in CriticalCSS.
+	PreloadedCSS []string
+
+	// ImportMap is the merged output of calling ImportMap on the
+	// Renderable and all the Components it uses, if they support the
+	// JSImportMapper interface.
+	ImportMap JSImportMap
+
+	// JSONData is the result of calling EmbedJSONData on the Renderable
+	// and all the Components it uses, if they support the
+	// JSONDataEmbedder interface.
+	JSONData []JSONData
+
+	// FetchPriorities is the fetchpriority of each URL in PreloadedCSS,
+	// LinkedCSS, and LinkedJS, set by the Site's FetchPriorityPolicy, if
+	// the Site implements FetchPriorityPolicier. URLs without a priority
+	// aren't included.
+	FetchPriorities map[string]FetchPriority
+
+	// Theme is the Theme the page is being rendered with, if the Site
+	// implements Themer. Otherwise, it's the zero Theme.
+	Theme Theme
+
+	// Indexable is false if the Renderable implements the Indexable
+	// interface and says search engines shouldn't index it.
+	Indexable bool
+
+	// Debug is information about the render, if the WithDebugInfo
+	// RenderOption was used. Otherwise, it's nil.
+	Debug *DebugInfo
+}
+
+// JSONDataTags returns a <script type="application/json"> element for each
+// entry in JSONData, with the entry's Value serialized as JSON.
+func (r RenderData[SiteType, PageType]) JSONDataTags() (template.HTML, error) {
+	return jsonDataTags(r.JSONData)
+}
+
+// ImportMapTag returns a <script type="importmap"> element containing
+// ImportMap, or nothing if ImportMap is empty. Import maps need to come before
+// any <script type="module"> elements in the document.
+func (r RenderData[SiteType, PageType]) ImportMapTag() (template.HTML, error) {
+	return r.ImportMap.tag()
+}
+
+// CriticalCSSTags returns the HTML needed to inline CriticalCSS in a <style>
+// element and preload the stylesheets in PreloadedCSS, applying them once they
+// finish loading. It's meant to be included in the <head> of the document.
+func (r RenderData[SiteType, PageType]) CriticalCSSTags() template.HTML {
+	return criticalCSSTags(r.CriticalCSS, r.PreloadedCSS, r.FetchPriorities)
+}
+
+// FetchPriority returns a fetchpriority attribute for the URL, if the Site's
+// FetchPriorityPolicy sets one for it, or nothing if it doesn't. It's meant to
+// be included in the <link> and <script> elements for LinkedCSS and LinkedJS:
+//
+//	{{ range .LinkedCSS }}<link rel="stylesheet" href="{{ . }}"{{ $.FetchPriority . }}>{{ end }}
+func (r RenderData[SiteType, PageType]) FetchPriority(url string) template.HTMLAttr {
+	return fetchPriorityAttr(r.FetchPriorities[url])
+}
+
+// IdleJSLoader returns a <script> element that loads the scripts in IdleJS
+// once the browser is idle or the user first interacts with the page, or
+// nothing if IdleJS is empty. It's meant to be included once, at the end of
+// the <body> of the document.
+func (r RenderData[SiteType, PageType]) IdleJSLoader() (template.HTML, error) {
+	return idleJSLoaderTag(r.IdleJS)
+}
+
+// RobotsMetaTag returns a <meta name="robots" content="noindex"> element if
+// the page isn't Indexable, or nothing if it is. It's meant to be included in
+// the <head> of the document.
+func (r RenderData[SiteType, PageType]) RobotsMetaTag() template.HTML {
+	return robotsMetaTag(r.Indexable)
+}
+
+// RenderOption is a way to modify the behavior of a single call to Render.
+type RenderOption func(*renderOptions)
+
+type renderOptions struct {
+	earlyHints          bool
+	preloadHeaders      bool
+	preloadLimit        int
+	streamChunkSize     int
+	strictFuncMaps      bool
+	strictTemplateNames bool
+	debug               bool
+	cssValidator        CSSValidator
+	executedTemplate    string
+	outputFilters       []OutputFilter
+	compression         *compression
+
+	// status is the status code to respond with if the page's Responder
+	// doesn't choose one, used for server error pages
+	status int
+}
+
+// WithStrictFuncMaps is a RenderOption that makes it an error for two
+// Components, or a Component and the Site, to add different functions with
+// the same name to the FuncMap, or for either to add a function with the same
+// name as one of the context funcs, which would be overridden by it. Without
+// it, the function from the Component that comes later in the Component tree
+// silently wins, and context funcs silently override the rest.
+//
+// Functions are only considered the same if they're the same top-level
+// function or method. Closures, including method values, created by the same
+// function literal can capture different values, so two of them with the
+// same name are always a conflict.
+//
+// The check only happens when templates are parsed, so if the Site is a
+// TemplateCacher, it only applies when the template isn't already cached.
+func WithStrictFuncMaps() RenderOption {
+	return func(opts *renderOptions) {
+		opts.strictFuncMaps = true
+	}
+}
+
+func buildRenderOptions(opts []RenderOption) renderOptions {
+	var res renderOptions
+	for _, opt := range opts {
+		opt(&res)
+	}
+	return res
 }
 
 // Render renders the passed Renderable to the Writer. If it can't, a server
-// error page is written instead. If the Site implements ServerErrorPager, that
-// will be rendered; if not, a simple text page indicating a server error will
-// be written.
-func Render[SiteType Site, PageType Renderable](ctx context.Context, out io.Writer, site SiteType, page PageType) {
+// error page is written instead. If the Site implements
+// ServerErrorPagerWithError or ServerErrorPager, that will be rendered; if
+// not, a simple text page indicating a server error will be written. When
+// Render is writing to an http.ResponseWriter, server error pages are sent
+// with a 500 Internal Server Error status, unless the error page is a
+// Responder that chooses a different one.
+//
+// Pages are rendered into a buffer before anything is written to the Writer,
+// so if a template fails partway through executing, the error page can be
+// written in its place, and so response headers, like the ones set by a
+// CachePolicy, are only set for pages that render successfully. WithStreaming
+// writes pages as they render instead, for pages too large to buffer.
+//
+// The behavior of Render can be modified by passing RenderOptions.
+//
+// Information about the render is returned as a RenderResult, and recorded in
+// the context.Context if it was returned from RecordRenderResult. It's also
+// recorded as OpenTelemetry metrics, using the global MeterProvider: the
+// temple.render.duration histogram, the temple.render.errors and
+// temple.render.written counters, and, if the Site is a TemplateCacher and
+// the page got far enough to need its templates, the
+// temple.template.cache.lookups counter, all with the page's Key in the
+// temple.page.key attribute.
+func Render[SiteType Site, PageType Renderable](ctx context.Context, out io.Writer, site SiteType, page PageType, opts ...RenderOption) (result RenderResult) {
+	start := time.Now()
+	result.Key = page.Key(ctx)
+	defer func() {
+		result.Duration = time.Since(start)
+		recordRenderResult(ctx, result)
+		recordRenderMetrics(ctx, result)
+		if recorder, ok := Site(site).(RenderErrorRecorder); ok && result.Err != nil {
+			recorder.RecordRenderError(ctx, result)
+		}
+	}()
 	defer func() {
 		// if the ResponseWriter can be closed, let's try to close it
 		if closer, ok := out.(io.Closer); ok {
@@ -115,20 +329,42 @@ func Render[SiteType Site, PageType Renderable](ctx context.Context, out io.Writ
 		}
 	}()
 
-	tracer := otel.GetTracerProvider().Tracer("impractical.co/temple")
 	var span trace.Span
-	ctx, span = tracer.Start(ctx, "render")
+	ctx, span = tracer().Start(ctx, "render")
 	defer span.End()
-	// try to render the page
-	err := basicRender(ctx, out, site, page)
+	// wait for our turn, if the number of pages rendering at once is
+	// limited, then try to render the page, remembering the response's
+	// headers so the server error page doesn't get the page's
+	headers := snapshotHeaders(out)
+	release, err := acquireRenderLimits(ctx, site, page, &result)
+	if err == nil {
+		err = basicRender(ctx, out, site, page, buildRenderOptions(opts), &result)
+	}
+	release()
 
 	// if there's no error, we're done here
 	if err == nil {
-		return
+		return result
+	}
+	result.Err = err
+
+	// if part of the page has already been written, it's too late to
+	// render an error page
+	var partial partialRenderError
+	if errors.As(err, &partial) {
+		logger(ctx).
+			ErrorContext(ctx, "error rendering page after output was written", "error", err)
+		span.AddEvent("error rendering span",
+			trace.WithStackTrace(true),
+			trace.WithAttributes(attribute.String("error", err.Error())),
+		)
+		return result
 	}
 
 	// if there is an error, we now need to try and render a server error
-	// page
+	// page, without any headers the page or its Components added, like
+	// its CachePolicy or cookies
+	restoreHeaders(out, headers)
 
 	// but first we're logging whatever went wrong
 	logger(ctx).
@@ -140,100 +376,384 @@ func Render[SiteType Site, PageType Renderable](ctx context.Context, out io.Writ
 	)
 
 	// now let's render the server error page
-	if pager, ok := Site(site).(ServerErrorPager); ok {
-		err = basicRender(ctx, out, site, pager.ServerErrorPage(ctx))
+	var errorPage Renderable
+	if pager, ok := Site(site).(ServerErrorPagerWithError); ok {
+		errorPage = pager.ServerErrorPageWithError(ctx, ErrorPageData{
+			Err:  err,
+			Page: page,
+			Key:  result.Key,
+		})
+	} else if pager, ok := Site(site).(ServerErrorPager); ok {
+		errorPage = pager.ServerErrorPage(ctx)
+	}
+	if errorPage != nil {
+		err = basicRender(ctx, out, site, errorPage, renderOptions{status: http.StatusInternalServerError}, &RenderResult{})
 		if err != nil {
 			// if we can't do that, everything's doomed, doomed, doomed
 			// just log it and we'll move on
 			logger(ctx).
 				ErrorContext(ctx, "error rendering server error page", "error", err)
 		}
-		return
+		return result
 	}
 
 	// there's no default server error page, write a server error message
+	writeStatus(out, http.StatusInternalServerError)
 	_, err = out.Write([]byte("Server error."))
 	if err != nil {
 		logger(ctx).
 			ErrorContext(ctx, "error writing server error message", "error", err)
 	}
+	return result
 }
 
-func basicRender[SiteType Site, PageType Renderable](ctx context.Context, output io.Writer, site SiteType, page PageType) error {
-	tmpl, err := getTemplate(ctx, site, page)
+func basicRender[SiteType Site, PageType Renderable](ctx context.Context, output io.Writer, site SiteType, page PageType, opts renderOptions, result *RenderResult) error {
+	ctx = withPublished(ctx)
+	ctx = withTheme(ctx, site)
+	timer := newDebugTimer(opts.debug)
+	components, cachedComponents, err := resolveComponents(ctx, site, page)
 	if err != nil {
 		return err
 	}
+	if cachedComponents {
+		timer.phase("get cached components")
+	} else {
+		timer.phase("resolve components")
+	}
+
+	err = checkRequirements(ctx, site, components)
+	if err != nil {
+		return err
+	}
+
+	response, err := getResponse(ctx, output, page)
+	if err != nil {
+		return err
+	}
+	if response.Redirect != "" {
+		redirect(output, response)
+		result.Redirect = response.Redirect
+		return nil
+	}
+	if response.Status == 0 {
+		response.Status = opts.status
+	}
+
+	if opts.cssValidator != nil {
+		err := validateComponentCSS(ctx, components, opts.cssValidator)
+		if err != nil {
+			return err
+		}
+	}
+
+	data, err := collectRenderData(ctx, site, page, components)
+	if err != nil {
+		return err
+	}
+	timer.phase("collect resources")
 
-	data := RenderData[SiteType, PageType]{
-		Site:        site,
-		Page:        page,
-		EmbeddedJS:  getComponentJSEmbeds(ctx, page),
-		LinkedJS:    getComponentJSLinks(ctx, page),
-		EmbeddedCSS: getComponentCSSEmbeds(ctx, page),
-		LinkedCSS:   getComponentCSSLinks(ctx, page),
+	if opts.earlyHints {
+		sendEarlyHints(ctx, output, data.preloads())
+	}
+
+	_, result.lookedUpTemplate = Site(site).(TemplateCacher)
+	tmpl, cached, err := getTemplate(ctx, site, page, components, opts)
+	if err != nil {
+		return err
+	}
+	result.CachedTemplate = cached
+	if cached {
+		timer.phase("get cached templates")
+	} else {
+		timer.phase("parse templates")
+	}
+
+	// resources placed in the body of the page shouldn't be linked to
+	// anywhere else
+	ctx = withPageResources(ctx, data.LinkedJS, data.LinkedCSS, data.FetchPriorities)
+	data.LinkedJS = removePlacedResources(tmpl, data.LinkedJS)
+	data.LinkedCSS = removePlacedResources(tmpl, data.LinkedCSS)
+
+	tmpl, err = bindContextFuncs(ctx, site, tmpl)
+	if err != nil {
+		return err
+	}
+
+	if opts.debug {
+		data.Debug = buildDebugInfo(ctx, site, page, components, data, cached, timer)
 	}
 
 	executed := page.ExecutedTemplate(ctx)
-	err = tmpl.ExecuteTemplate(output, executed, data)
+	if opts.executedTemplate != "" {
+		executed = opts.executedTemplate
+	}
+	if opts.streamChunkSize > 0 && len(opts.outputFilters) < 1 && opts.compression == nil {
+		if opts.preloadHeaders && !opts.earlyHints {
+			setPagePreloadHeaders(output, data.preloads(), opts.preloadLimit)
+		}
+		headers := getHeaders(ctx, site, components)
+		mergeHeaders(headers, cookieHeaders(response.Cookies))
+		return streamRender(ctx, output, tmpl, executed, data, page, components, headers, response.Status, opts.streamChunkSize, result)
+	}
+
+	// render into a buffer, so if the template fails partway through
+	// executing, we can still render an error page instead, and so
+	// response headers can be set once we know the page rendered
+	buf := getRenderBuffer()
+	_, span := tracer().Start(ctx, "execute template",
+		trace.WithAttributes(attribute.String("temple.template", executed)),
+	)
+	err = tmpl.ExecuteTemplate(buf, executed, data)
+	span.End()
+	defer putRenderBuffer(buf, buf.Len())
 	if err != nil {
 		return fmt.Errorf("error executing template %q for %T: %w", executed, page, err)
 	}
+	if len(opts.outputFilters) > 0 {
+		filtered, err := applyOutputFilters(ctx, opts.outputFilters, buf.Bytes())
+		if err != nil {
+			return err
+		}
+		buf.Reset()
+		buf.Write(filtered)
+	}
+	setHeaders(output, getHeaders(ctx, site, components))
+	setHeaders(output, cookieHeaders(response.Cookies))
+	setCacheHeaders(ctx, output, page)
+	setSurrogateKeyHeaders(ctx, output, components)
+	setRobotsHeaders(ctx, output, page)
+	if opts.preloadHeaders && !opts.earlyHints {
+		setPagePreloadHeaders(output, data.preloads(), opts.preloadLimit)
+	}
+	if opts.compression != nil {
+		result.BytesWritten, err = writeCompressed(output, buf, *opts.compression, response.Status)
+	} else {
+		writeStatus(output, response.Status)
+		result.BytesWritten, err = buf.WriteTo(output)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing %T: %w", page, err)
+	}
 	return nil
 }
 
-func getTemplate(ctx context.Context, site Site, page Renderable) (*template.Template, error) {
+// collectRenderData gathers the CSS, JavaScript, and data the page's
+// Components supply into the RenderData for the page.
+func collectRenderData[SiteType Site, PageType Renderable](ctx context.Context, site SiteType, page PageType, components []Component) (RenderData[SiteType, PageType], error) {
+	ctx, span := tracer().Start(ctx, "collect resources")
+	defer span.End()
+
+	criticalCSS, preloadedCSS, err := getComponentCriticalCSS(ctx, site, components)
+	if err != nil {
+		return RenderData[SiteType, PageType]{}, err
+	}
+
+	importMap, err := getComponentImportMap(ctx, components)
+	if err != nil {
+		return RenderData[SiteType, PageType]{}, err
+	}
+
+	linkedJS := getComponentJSLinks(ctx, components)
+	linkedCSS := getComponentCSSLinks(ctx, components)
+
+	return RenderData[SiteType, PageType]{
+		Site:            site,
+		Page:            page,
+		Request:         RequestData(ctx),
+		EmbeddedJS:      getComponentJSEmbeds(ctx, components),
+		LinkedJS:        linkedJS,
+		IdleJS:          getComponentIdleJS(ctx, components, linkedJS),
+		EmbeddedCSS:     getComponentCSSEmbeds(ctx, components),
+		LinkedCSS:       linkedCSS,
+		CriticalCSS:     criticalCSS,
+		PreloadedCSS:    preloadedCSS,
+		ImportMap:       importMap,
+		JSONData:        getComponentJSONData(ctx, components),
+		FetchPriorities: getFetchPriorities(ctx, site, preloadedCSS, linkedCSS, linkedJS),
+		Theme:           CurrentTheme(ctx),
+		Indexable:       IsIndexable(ctx, page),
+	}, nil
+}
+
+func getTemplate(ctx context.Context, site Site, page Renderable, components []Component, opts renderOptions) (*template.Template, bool, error) {
 	span := trace.SpanFromContext(ctx)
-	key := page.Key(ctx)
-	if cache, ok := site.(TemplateCacher); ok {
-		cached := cache.GetCachedTemplate(ctx, key)
-		if cached != nil {
-			span.AddEvent("got cached template",
-				trace.WithAttributes(attribute.String("key", key)),
-			)
+	key := themedKey(ctx, page.Key(ctx))
+	cache, ok := site.(TemplateCacher)
+	if !ok {
+		parsed, err := parsePageTemplates(ctx, site, page, components, opts)
+		return parsed, false, err
+	}
+	cached := cache.GetCachedTemplate(ctx, key)
+	if cached != nil {
+		span.AddEvent("got cached template",
+			trace.WithAttributes(attribute.String("key", key)),
+		)
+		return cached, true, nil
+	}
+	// only one goroutine parses the templates for each key at a time;
+	// the rest wait for it and use its results
+	parsed, shared, err := parseOnce(ctx, cache, key, func() (*template.Template, error) {
+		// the templates may have been cached while we were waiting
+		// to parse them
+		if cached := cache.GetCachedTemplate(ctx, key); cached != nil {
 			return cached, nil
 		}
+		parsed, err := parsePageTemplates(ctx, site, page, components, opts)
+		if err != nil {
+			return nil, err
+		}
+		cache.SetCachedTemplate(ctx, key, parsed)
+		return parsed, nil
+	})
+	if shared {
+		span.AddEvent("waited for templates parsed by another render",
+			trace.WithAttributes(attribute.String("key", key)),
+		)
 	}
-	tmplPaths := getComponentTemplatePaths(ctx, page)
+	return parsed, false, err
+}
+
+// parsePageTemplates parses the templates needed by the page and every
+// Component it uses.
+func parsePageTemplates(ctx context.Context, site Site, page Renderable, components []Component, opts renderOptions) (*template.Template, error) {
+	span := trace.SpanFromContext(ctx)
+	key := page.Key(ctx)
+	ctx, parseSpan := tracer().Start(ctx, "parse templates")
+	defer parseSpan.End()
+	tmplPaths := getComponentTemplatePaths(ctx, site, components)
 	if len(tmplPaths) < 1 {
 		return nil, fmt.Errorf("error rendering %T: %w", page, ErrNoTemplatePath)
 	}
-	funcMap := getComponentFuncMap(ctx, site, page)
-	parsed, err := parseTemplates(site.TemplateDir(ctx), funcMap, tmplPaths...)
+	ctxFuncs := contextFuncs(ctx, site)
+	componentFuncs, err := getComponentFuncMap(ctx, site, components, ctxFuncs, opts.strictFuncMaps)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing templates %v for page %T: %w", page, tmplPaths, err)
+		return nil, fmt.Errorf("error building FuncMap for page %T: %w", page, err)
 	}
-	if cache, ok := site.(TemplateCacher); ok {
-		cache.SetCachedTemplate(ctx, key, parsed)
+	funcMap := mergeFuncMaps(componentFuncs, ctxFuncs)
+	parsed, err := parseTemplates(funcMap, opts.strictTemplateNames, tmplPaths...)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing templates %v for page %T: %w", templatePathStrings(tmplPaths), page, err)
+	}
+	err = checkSlots(ctx, parsed, components)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering %T: %w", page, err)
+	}
+	err = addContextFuncsMarker(ctx, site, parsed)
+	if err != nil {
+		return nil, err
+	}
+	err = addPlacedResourcesMarker(parsed)
+	if err != nil {
+		return nil, err
 	}
 	span.AddEvent("parsed templates",
 		trace.WithAttributes(attribute.String("key", key)),
-		trace.WithAttributes(attribute.StringSlice("templates", tmplPaths)),
+		trace.WithAttributes(attribute.StringSlice("templates", templatePathStrings(tmplPaths))),
 	)
 	return parsed, nil
 }
 
-func getRecursiveComponents(ctx context.Context, component Component) []Component {
-	results := []Component{component}
+// maxComponentDepth is how deeply Components can be nested within each other
+// before getRecursiveComponents gives up, to guard against cycles it can't
+// detect.
+const maxComponentDepth = 100
+
+// getRecursiveComponents returns the Component and every Component it uses,
+// recursively, recording a span for each Component. Components that are used
+// more than once are only included the first time they're used. If a
+// Component uses itself, directly or indirectly, a CycleError is returned.
+func getRecursiveComponents(ctx context.Context, component Component) ([]Component, error) {
+	var results []Component
+	err := walkComponents(ctx, component, nil, map[Component]struct{}{}, &results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// walkComponents appends the Component and every Component it uses to
+// results, unless it's in seen. path holds the Components that led to this
+// one, for detecting cycles.
+func walkComponents(ctx context.Context, component Component, path []Component, seen map[Component]struct{}, results *[]Component) error {
+	for pos, ancestor := range path {
+		if sameComponent(ancestor, component) {
+			return CycleError{Components: append(slices.Clone(path[pos:]), component)}
+		}
+	}
+	if len(path) >= maxComponentDepth {
+		return fmt.Errorf("%w: %s", ErrComponentTooDeep, componentPath(append(slices.Clone(path), component)))
+	}
+	comparable := reflect.ValueOf(component).Comparable()
+	if comparable {
+		if _, ok := seen[component]; ok {
+			return nil
+		}
+		seen[component] = struct{}{}
+	}
+
+	ctx, span := tracer().Start(ctx, "use components",
+		trace.WithAttributes(componentTypeAttr.String(componentType(component))),
+	)
+	defer span.End()
+	*results = append(*results, component)
 
 	if uses, ok := component.(ComponentUser); ok {
-		children := uses.UseComponents(ctx)
-		for _, child := range children {
-			results = append(results, getRecursiveComponents(ctx, child)...)
+		path = append(path, component)
+		for _, child := range uses.UseComponents(ctx) {
+			err := walkComponents(ctx, child, path, seen, results)
+			if err != nil {
+				return err
+			}
 		}
 	}
+	return nil
+}
+
+// sameComponent returns true if the two Components are the same type and
+// equal. Components whose types can't be compared are never the same.
+func sameComponent(a, b Component) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	if !reflect.ValueOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// templatePath is a path to a template, along with the fs.FS it should be read
+// from, the Component that needs it, and the delimiters it should be parsed
+// with.
+type templatePath struct {
+	dir       fs.FS
+	path      string
+	component Component
+	delims    [2]string
+}
+
+func templatePathStrings(paths []templatePath) []string {
+	results := make([]string, 0, len(paths))
+	for _, path := range paths {
+		results = append(results, path.path)
+	}
 	return results
 }
 
-func getComponentTemplatePaths(ctx context.Context, component Component) []string {
-	var results []string
+func getComponentTemplatePaths(ctx context.Context, site Site, components []Component) []templatePath {
+	var results []templatePath
 	seen := map[string]struct{}{}
-	components := getRecursiveComponents(ctx, component)
 	for _, comp := range components {
+		var dir fs.FS
+		if provider, ok := comp.(TemplateDirProvider); ok {
+			dir = provider.TemplateDir(ctx)
+		} else {
+			dir = site.TemplateDir(ctx)
+		}
+		delims := templateDelims(ctx, site, comp)
 		paths := comp.Templates(ctx)
 		for _, path := range paths {
 			if _, ok := seen[path]; !ok {
-				results = append(results, path)
+				results = append(results, templatePath{dir: dir, path: path, component: comp, delims: delims})
 				seen[path] = struct{}{}
 			}
 		}
@@ -241,47 +761,114 @@ func getComponentTemplatePaths(ctx context.Context, component Component) []strin
 	return results
 }
 
-func getComponentFuncMap(ctx context.Context, site Site, component Component) template.FuncMap {
+// getComponentFuncMap merges the FuncMaps of the Site and Components. If
+// strict is true, it returns an error wrapping ErrFuncMapConflict if two of
+// them have different functions with the same name, or if any of them have a
+// function with the same name as one of ctxFuncs.
+func getComponentFuncMap(ctx context.Context, site Site, components []Component, ctxFuncs template.FuncMap, strict bool) (template.FuncMap, error) {
 	results := template.FuncMap{}
+	if includer, ok := site.(DefaultFuncsIncluder); ok && includer.IncludeDefaultFuncs(ctx) {
+		results = mergeFuncMaps(results, DefaultFuncs())
+	}
+	// keep track of where each function came from, so we can report
+	// conflicts in strict mode
+	sources := map[string]any{}
 	if fm, ok := site.(FuncMapExtender); ok {
-		results = mergeFuncMaps(results, fm.FuncMap(ctx))
+		funcs := fm.FuncMap(ctx)
+		results = mergeFuncMaps(results, funcs)
+		for name := range funcs {
+			sources[name] = site
+		}
 	}
-	components := getRecursiveComponents(ctx, component)
 	for _, comp := range components {
 		fm, ok := comp.(FuncMapExtender)
 		if !ok {
 			continue
 		}
-		results = mergeFuncMaps(results, fm.FuncMap(ctx))
+		funcs := fm.FuncMap(ctx)
+		if strict {
+			for name, fn := range funcs {
+				source, ok := sources[name]
+				if !ok || sameFunc(results[name], fn) {
+					continue
+				}
+				return nil, fmt.Errorf("%w: %q is registered by both %T and %T", ErrFuncMapConflict, name, source, comp)
+			}
+		}
+		results = mergeFuncMaps(results, funcs)
+		for name := range funcs {
+			if _, ok := sources[name]; !ok {
+				sources[name] = comp
+			}
+		}
 	}
-	return results
+	if strict {
+		for _, name := range slices.Sorted(maps.Keys(sources)) {
+			if _, ok := ctxFuncs[name]; ok {
+				return nil, fmt.Errorf("%w: %q is registered by %T, but is overridden by a context func", ErrFuncMapConflict, name, sources[name])
+			}
+		}
+	}
+	return results, nil
 }
 
-func parseTemplates(fsys fs.FS, funcs template.FuncMap, patterns ...string) (*template.Template, error) {
-	var files []string
+// sameFunc returns true if `a` and `b` are the same top-level function or
+// method. Closures created by the same function literal share their code, but
+// can capture different variables, so they're never considered the same.
+func sameFunc(a, b any) bool {
+	aVal, bVal := reflect.ValueOf(a), reflect.ValueOf(b)
+	if aVal.Kind() != reflect.Func || bVal.Kind() != reflect.Func {
+		return false
+	}
+	if aVal.Pointer() != bVal.Pointer() {
+		return false
+	}
+	fn := runtime.FuncForPC(aVal.Pointer())
+	return fn != nil && !closureName.MatchString(fn.Name())
+}
+
+// closureName matches the names the Go runtime gives closures, like
+// pkg.Func.func1 or pkg.Func.func1.2, and method values, like pkg.Type.Method-fm.
+var closureName = regexp.MustCompile(`\.func\d+(\.\d+)*$|-fm$`)
+
+// parseTemplates parses the templates matching the patterns into a single
+// template set. If strictNames is true, it returns a TemplateConflictError if
+// two files define the same template.
+func parseTemplates(funcs template.FuncMap, strictNames bool, patterns ...templatePath) (*template.Template, error) {
+	var files []templatePath
 	for _, pattern := range patterns {
-		list, err := fs.Glob(fsys, pattern)
+		list, err := fs.Glob(pattern.dir, pattern.path)
 		if err != nil {
-			return nil, fmt.Errorf("error listing files for %q: %w", pattern, err)
+			return nil, TemplateParseError{Path: pattern.path, Component: pattern.component, Err: err}
 		}
 		if len(list) < 1 {
-			return nil, fmt.Errorf("error parsing %q: %w", pattern, ErrTemplatePatternMatchesNoFiles)
+			return nil, TemplateParseError{Path: pattern.path, Component: pattern.component, Err: ErrTemplatePatternMatchesNoFiles}
+		}
+		for _, file := range list {
+			files = append(files, templatePath{dir: pattern.dir, path: file, component: pattern.component, delims: pattern.delims})
 		}
-		files = append(files, list...)
 	}
 	if len(files) < 1 {
 		return nil, ErrNoTemplatePath
 	}
 	tmpl := template.New("").Funcs(funcs)
-	for _, file := range files {
-		sub := tmpl.New(file)
-		contents, err := fs.ReadFile(fsys, file)
+	definers := map[string]templateDefiner{}
+	for _, tp := range files {
+		file := tp.path
+		sub := tmpl.New(file).Delims(tp.delims[0], tp.delims[1])
+		contents, err := fs.ReadFile(tp.dir, file)
 		if err != nil {
-			return nil, fmt.Errorf("error reading %q: %w", file, err)
+			return nil, TemplateParseError{Path: file, Component: tp.component, Err: err}
+		}
+		if strictNames {
+			err = checkTemplateConflicts(tp, string(contents), definers)
+			if err != nil {
+				return nil, err
+			}
 		}
 		_, err = sub.Parse(string(contents))
 		if err != nil {
-			return nil, fmt.Errorf("error parsing %q: %w", file, err)
+			return nil, TemplateParseError{Path: file, Component: tp.component, Err: err}
 		}
 	}
 	return tmpl, nil
@@ -0,0 +1,76 @@
+package temple_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"impractical.co/temple"
+)
+
+type BenchPage struct {
+	Items []string
+}
+
+func (BenchPage) Templates(_ context.Context) []string {
+	return []string{"bench.html.tmpl"}
+}
+
+func (BenchPage) Key(_ context.Context) string {
+	return "bench.html.tmpl"
+}
+
+func (BenchPage) ExecutedTemplate(_ context.Context) string {
+	return "bench.html.tmpl"
+}
+
+func benchSite() MySite {
+	return MySite{
+		CachedSite: temple.NewCachedSite(staticFS{
+			"bench.html.tmpl": `<!doctype html>
+<html>
+	<head><title>Benchmark</title></head>
+	<body>
+		<ul>
+		{{- range .Page.Items }}
+			<li>{{ . }}</li>
+		{{- end }}
+		</ul>
+	</body>
+</html>`,
+		}),
+	}
+}
+
+func benchPage() BenchPage {
+	items := make([]string, 500)
+	for i := range items {
+		items[i] = strings.Repeat("item ", 10)
+	}
+	return BenchPage{Items: items}
+}
+
+func BenchmarkRender(b *testing.B) {
+	ctx := context.Background()
+	site := benchSite()
+	page := benchPage()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		temple.Render(ctx, io.Discard, site, page)
+	}
+}
+
+func BenchmarkRender_parallel(b *testing.B) {
+	ctx := context.Background()
+	site := benchSite()
+	page := benchPage()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			temple.Render(ctx, io.Discard, site, page)
+		}
+	})
+}
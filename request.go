@@ -0,0 +1,29 @@
+package temple
+
+import (
+	"context"
+)
+
+type requestDataCtxKey struct{}
+
+// WithRequestData returns a context.Context with the passed data embedded in
+// it. When that context.Context is passed to Render, the data will be made
+// available to the template as .Request. It's also available as .Request to
+// templates rendered with RenderComponent and inline Components.
+//
+// CSS and JavaScript aren't rendered from templates, so there's no .Request
+// for them; the Components that supply them get the same context.Context in
+// their EmbedCSS and EmbedJS methods, and can use RequestData to read it.
+//
+// This is meant for request-scoped information that many pages need, like the
+// current user, a CSRF token, or the locale of the request, so it doesn't need
+// to be copied onto every Renderable.
+func WithRequestData(ctx context.Context, data any) context.Context {
+	return context.WithValue(ctx, requestDataCtxKey{}, data)
+}
+
+// RequestData returns the data embedded in the context.Context by
+// WithRequestData, or nil if there isn't any.
+func RequestData(ctx context.Context) any {
+	return ctx.Value(requestDataCtxKey{})
+}
@@ -0,0 +1,116 @@
+package temple_test
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"impractical.co/temple"
+)
+
+type requestUser struct {
+	Name string
+}
+
+// requestStyles embeds CSS and JavaScript that depend on the request data.
+type requestStyles struct{}
+
+func (requestStyles) Templates(_ context.Context) []string {
+	return nil
+}
+
+func (requestStyles) EmbedCSS(ctx context.Context) template.CSS {
+	user, _ := temple.RequestData(ctx).(requestUser)
+	return template.CSS(fmt.Sprintf(".user-%s { color: red; }", user.Name)) // #nosec G203
+}
+
+func (requestStyles) EmbedJS(ctx context.Context) template.JS {
+	user, _ := temple.RequestData(ctx).(requestUser)
+	return template.JS(fmt.Sprintf("const user = %q;", user.Name)) // #nosec G203
+}
+
+type requestPage struct{}
+
+func (requestPage) Templates(_ context.Context) []string {
+	return []string{"request.html.tmpl"}
+}
+
+func (requestPage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{requestStyles{}}
+}
+
+func (requestPage) Key(_ context.Context) string {
+	return "request.html.tmpl"
+}
+
+func (requestPage) ExecutedTemplate(_ context.Context) string {
+	return "request.html.tmpl"
+}
+
+func TestRequestData(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		template string
+		render   func(context.Context, *strings.Builder, *temple.CachedSite) error
+		want     string
+	}{
+		"page": {
+			template: `{{ .Request.Name }}`,
+			render: func(ctx context.Context, out *strings.Builder, site *temple.CachedSite) error {
+				return temple.Render(ctx, out, site, requestPage{}).Err
+			},
+			want: "ada",
+		},
+		"page-css": {
+			template: `{{ .EmbeddedCSS }}`,
+			render: func(ctx context.Context, out *strings.Builder, site *temple.CachedSite) error {
+				return temple.Render(ctx, out, site, requestPage{}).Err
+			},
+			want: ".user-ada { color: red; }",
+		},
+		"page-js": {
+			template: `<script>{{ .EmbeddedJS }}</script>`,
+			render: func(ctx context.Context, out *strings.Builder, site *temple.CachedSite) error {
+				return temple.Render(ctx, out, site, requestPage{}).Err
+			},
+			want: `const user = "ada";`,
+		},
+		"component": {
+			template: `{{ define "user" }}{{ .Request.Name }}: {{ .EmbeddedCSS }}{{ end }}`,
+			render: func(ctx context.Context, out *strings.Builder, site *temple.CachedSite) error {
+				return temple.RenderComponent(ctx, out, site, requestPage{}, "user", nil)
+			},
+			want: "ada: \n/* embedded CSS from temple_test.requestStyles */\n.user-ada { color: red; }",
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			site := temple.NewCachedSite(fstest.MapFS{
+				"request.html.tmpl": {Data: []byte(test.template)},
+			})
+			ctx := temple.WithRequestData(context.Background(), requestUser{Name: "ada"})
+			var out strings.Builder
+			err := test.render(ctx, &out, site)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !strings.Contains(out.String(), test.want) {
+				t.Errorf("expected output to contain %q, got %q", test.want, out.String())
+			}
+		})
+	}
+}
+
+func TestRequestDataMissing(t *testing.T) {
+	t.Parallel()
+
+	if got := temple.RequestData(context.Background()); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+}
@@ -0,0 +1,99 @@
+package temple
+
+import (
+	"context"
+)
+
+// Requirer is an interface that Components can fulfill to declare
+// capabilities they need from the page, like "jquery" or "modal-root", that
+// other Components are expected to provide. Render fails with an
+// UnmetRequirementsError if a page uses a Requirer without also using a
+// CapabilityProvider providing each of its requirements, instead of rendering
+// a page that breaks in the browser.
+type Requirer interface {
+	// Requires returns the capabilities the Component needs.
+	Requires(context.Context) []string
+}
+
+// CapabilityProvider is an interface that Components can fulfill to declare
+// the capabilities they provide to the other Components on the page, like the
+// scripts they link to or the elements their templates render. Sites can
+// fulfill it too, to provide capabilities to every page, like a script every
+// layout includes.
+type CapabilityProvider interface {
+	// Provides returns the capabilities the Component provides.
+	Provides(context.Context) []string
+}
+
+// UnmetRequirement is a capability a Component requires that nothing on the
+// page provides.
+type UnmetRequirement struct {
+	// Component is the Component that requires the capability.
+	Component Component
+
+	// Requirement is the capability that isn't provided.
+	Requirement string
+}
+
+// CheckRequirements returns an UnmetRequirementsError if the page, or any of
+// the Components it uses, requires a capability that neither the Site nor any
+// of the Components on the page provide. Render performs the same check, but
+// CheckRequirements can be used to validate pages ahead of time, like in
+// tests or when a server starts.
+func CheckRequirements(ctx context.Context, site Site, page Component) error {
+	components, err := getRecursiveComponents(ctx, page)
+	if err != nil {
+		return err
+	}
+	return checkRequirements(ctx, site, components)
+}
+
+// checkRequirements returns an UnmetRequirementsError listing every
+// requirement of the Components that isn't provided by the Site or one of the
+// Components.
+func checkRequirements(ctx context.Context, site Site, components []Component) error {
+	var requirers []Requirer
+	var requirerComponents []Component
+	for _, comp := range components {
+		if requirer, ok := comp.(Requirer); ok {
+			requirers = append(requirers, requirer)
+			requirerComponents = append(requirerComponents, comp)
+		}
+	}
+	if len(requirers) < 1 {
+		return nil
+	}
+
+	provided := map[string]struct{}{}
+	if provider, ok := site.(CapabilityProvider); ok {
+		for _, capability := range provider.Provides(ctx) {
+			provided[capability] = struct{}{}
+		}
+	}
+	for _, comp := range components {
+		provider, ok := comp.(CapabilityProvider)
+		if !ok {
+			continue
+		}
+		for _, capability := range provider.Provides(ctx) {
+			provided[capability] = struct{}{}
+		}
+	}
+
+	var unmet []UnmetRequirement
+	for pos, requirer := range requirers {
+		for _, requirement := range requirer.Requires(ctx) {
+			if _, ok := provided[requirement]; ok {
+				continue
+			}
+			unmet = append(unmet, UnmetRequirement{
+				Component:   requirerComponents[pos],
+				Requirement: requirement,
+			})
+		}
+	}
+	if len(unmet) > 0 {
+		return UnmetRequirementsError{Requirements: unmet}
+	}
+	return nil
+}
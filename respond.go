@@ -0,0 +1,111 @@
+package temple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+var (
+	// ErrInvalidRedirectStatus is returned when a Responder's Response
+	// redirects with a Status that isn't a 3xx redirection status.
+	ErrInvalidRedirectStatus = errors.New("redirect status must be 3xx")
+)
+
+// Response describes how a page wants Render to respond to the request it's
+// being rendered for, beyond writing the page. It's returned by Responders.
+type Response struct {
+	// Status is the HTTP status code to respond with. If 0, the response
+	// is 200 OK, or 302 Found if Redirect is set. If Redirect is set, it
+	// must be 0 or a 3xx status.
+	Status int
+
+	// Cookies are set on the response, whether the page is rendered or
+	// redirects.
+	Cookies []*http.Cookie
+
+	// Redirect, if set, is the URL to redirect to instead of rendering
+	// the page.
+	Redirect string
+}
+
+// Responder is an interface that Renderables can fulfill to control the HTTP
+// response they're rendered into, when Render is writing to an
+// http.ResponseWriter: setting cookies, choosing the status code, or
+// redirecting instead of being rendered. It lets decisions made while loading
+// a page's data, like that the thing it shows doesn't exist or has moved,
+// live with the page, instead of handlers having to work them out before
+// rendering it.
+//
+// Respond is called once the page's Components have been resolved, before
+// its templates are parsed or executed. If it returns an error, or a
+// Response redirecting with a status that isn't 3xx, the page fails to
+// render. If the page redirects, nothing else about it is rendered,
+// and none of the headers it or its Components would have set are set.
+// Cookies and the status code are only applied once the page has rendered
+// successfully, so a server error page isn't sent with them; when streaming,
+// they're applied before the page renders, and removed again if it fails
+// before any of it is written.
+//
+// If Render isn't writing to an http.ResponseWriter, Respond isn't called.
+type Responder interface {
+	// Respond returns how the page wants Render to respond.
+	Respond(ctx context.Context) (Response, error)
+}
+
+// getResponse returns the page's Response, if it's a Responder and `out` is
+// an http.ResponseWriter.
+func getResponse(ctx context.Context, out io.Writer, page Renderable) (Response, error) {
+	if _, ok := out.(http.ResponseWriter); !ok {
+		return Response{}, nil
+	}
+	responder, ok := page.(Responder)
+	if !ok {
+		return Response{}, nil
+	}
+	response, err := responder.Respond(ctx)
+	if err != nil {
+		return Response{}, fmt.Errorf("error getting response for %T: %w", page, err)
+	}
+	if response.Redirect != "" && response.Status != 0 && (response.Status < 300 || response.Status > 399) {
+		return Response{}, fmt.Errorf("error getting response for %T: %w, got %d", page, ErrInvalidRedirectStatus, response.Status)
+	}
+	return response, nil
+}
+
+// cookieHeaders returns a header setting each of the cookies. Invalid
+// cookies are left out.
+func cookieHeaders(cookies []*http.Cookie) http.Header {
+	headers := http.Header{}
+	for _, cookie := range cookies {
+		if val := cookie.String(); val != "" {
+			headers.Add("Set-Cookie", val)
+		}
+	}
+	return headers
+}
+
+// redirect sets the response's cookies and redirects to its Redirect URL,
+// without writing a body.
+func redirect(out io.Writer, response Response) {
+	resp := out.(http.ResponseWriter) // only called when getResponse returned a Response
+	mergeHeaders(resp.Header(), cookieHeaders(response.Cookies))
+	status := response.Status
+	if status == 0 {
+		status = http.StatusFound
+	}
+	resp.Header().Set("Location", response.Redirect)
+	resp.WriteHeader(status)
+}
+
+// writeStatus writes the status code to `out`, if it's an
+// http.ResponseWriter and the status isn't 0.
+func writeStatus(out io.Writer, status int) {
+	resp, ok := out.(http.ResponseWriter)
+	if !ok || status == 0 {
+		return
+	}
+	resp.WriteHeader(status)
+}
@@ -0,0 +1,141 @@
+package temple_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"impractical.co/temple"
+)
+
+type respondingStyles struct{}
+
+func (respondingStyles) Templates(_ context.Context) []string {
+	return nil
+}
+
+func (respondingStyles) LinkCSS(_ context.Context) []string {
+	return []string{"/styles.css"}
+}
+
+func TestResponder(t *testing.T) {
+	t.Parallel()
+
+	session := &http.Cookie{Name: "session", Value: "abc"}
+	styles := []temple.Component{respondingStyles{}}
+	cases := map[string]struct {
+		site         temple.Site
+		page         testPage
+		opts         []temple.RenderOption
+		wantStatus   int
+		wantErr      error
+		wantBody     string
+		wantCookies  []string
+		wantLocation string
+		wantLink     []string
+	}{
+		"rendered": {
+			page:        testPage{components: styles, response: temple.Response{Status: http.StatusCreated, Cookies: []*http.Cookie{session}}},
+			wantStatus:  http.StatusCreated,
+			wantBody:    "ok",
+			wantCookies: []string{"theme=dark", "session=abc"},
+		},
+		"redirect": {
+			page:         testPage{components: styles, response: temple.Response{Redirect: "/new", Status: http.StatusMovedPermanently, Cookies: []*http.Cookie{session}}},
+			wantStatus:   http.StatusMovedPermanently,
+			wantCookies:  []string{"theme=dark", "session=abc"},
+			wantLocation: "/new",
+		},
+		"redirect-default-status": {
+			page:         testPage{components: styles, response: temple.Response{Redirect: "/new"}},
+			wantStatus:   http.StatusFound,
+			wantCookies:  []string{"theme=dark"},
+			wantLocation: "/new",
+		},
+		"redirect-invalid-status": {
+			page:        testPage{components: styles, response: temple.Response{Redirect: "/new", Status: http.StatusOK, Cookies: []*http.Cookie{session}}},
+			wantStatus:  http.StatusInternalServerError,
+			wantErr:     temple.ErrInvalidRedirectStatus,
+			wantBody:    "Server error.",
+			wantCookies: []string{"theme=dark"},
+		},
+		"failed": {
+			page:        testPage{components: styles, response: temple.Response{Status: http.StatusCreated, Cookies: []*http.Cookie{session}}, fail: true},
+			wantStatus:  http.StatusInternalServerError,
+			wantBody:    "Server error.",
+			wantCookies: []string{"theme=dark"},
+		},
+		"failed-streaming": {
+			page:        testPage{components: styles, response: temple.Response{Status: http.StatusCreated, Cookies: []*http.Cookie{session}}, fail: true},
+			opts:        []temple.RenderOption{temple.WithStreaming(1024)},
+			wantStatus:  http.StatusInternalServerError,
+			wantBody:    "Server error.",
+			wantCookies: []string{"theme=dark"},
+		},
+		"failed-early-hints-redirect": {
+			site: errorPageSite{
+				CachedSite: temple.NewCachedSite(fstest.MapFS{
+					"page.html.tmpl": {Data: []byte(`{{ .Page.Check }}`)},
+				}),
+				errorPage: testPage{components: styles, response: temple.Response{Redirect: "/login"}},
+			},
+			page:         testPage{components: styles, fail: true},
+			opts:         []temple.RenderOption{temple.WithEarlyHints()},
+			wantStatus:   http.StatusFound,
+			wantCookies:  []string{"theme=dark"},
+			wantLocation: "/login",
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			site := test.site
+			if site == nil {
+				site = temple.NewCachedSite(fstest.MapFS{
+					"page.html.tmpl": {Data: []byte(`{{ .Page.Check }}`)},
+				})
+			}
+			resp := httptest.NewRecorder()
+			// cookies the handler set are kept, even if the page fails
+			http.SetCookie(resp, &http.Cookie{Name: "theme", Value: "dark"})
+			result := temple.Render(context.Background(), finalStatusRecorder{resp}, site, test.page, test.opts...)
+			if test.wantErr != nil && !errors.Is(result.Err, test.wantErr) {
+				t.Errorf("expected error %v, got %v", test.wantErr, result.Err)
+			}
+			if resp.Code != test.wantStatus {
+				t.Errorf("expected status %d, got %d", test.wantStatus, resp.Code)
+			}
+			if got := resp.Body.String(); got != test.wantBody {
+				t.Errorf("expected body %q, got %q", test.wantBody, got)
+			}
+			if got := resp.Header().Values("Set-Cookie"); !slices.Equal(got, test.wantCookies) {
+				t.Errorf("expected cookies %q, got %q", test.wantCookies, got)
+			}
+			if got := resp.Header().Get("Location"); got != test.wantLocation {
+				t.Errorf("expected Location %q, got %q", test.wantLocation, got)
+			}
+			if got := resp.Header().Values("Link"); !slices.Equal(got, test.wantLink) {
+				t.Errorf("expected Link headers %q, got %q", test.wantLink, got)
+			}
+		})
+	}
+}
+
+// finalStatusRecorder is an http.ResponseWriter that ignores informational
+// responses, like 103 Early Hints, which httptest.ResponseRecorder would
+// otherwise record as the response's status.
+type finalStatusRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (f finalStatusRecorder) WriteHeader(status int) {
+	if status >= 100 && status <= 199 {
+		return
+	}
+	f.ResponseRecorder.WriteHeader(status)
+}
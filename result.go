@@ -0,0 +1,104 @@
+package temple
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RenderResult holds information about a call to Render, for logging and
+// monitoring purposes.
+type RenderResult struct {
+	// Key is the output of the Key method of the Renderable that was
+	// rendered.
+	Key string
+
+	// CachedTemplate is true if the Renderable's templates were retrieved
+	// from the Site's TemplateCacher instead of being parsed.
+	CachedTemplate bool
+
+	// Duration is how long the call to Render took.
+	Duration time.Duration
+
+	// QueueWait is how long the page waited for the RenderLimiters of the
+	// page and Site before it started rendering. It's included in
+	// Duration.
+	QueueWait time.Duration
+
+	// BytesWritten is the number of bytes of the rendered page written to
+	// the io.Writer. It doesn't include any server error page written
+	// instead.
+	BytesWritten int64
+
+	// Redirect is the URL the Renderable's Responder redirected to
+	// instead of rendering the page, if any.
+	Redirect string
+
+	// Err is the error encountered while rendering the Renderable, if
+	// any. If Err is set, a server error page was rendered instead.
+	Err error
+
+	// lookedUpTemplate is true if the Renderable's templates were looked
+	// up in the Site's TemplateCacher, which doesn't happen if it fails
+	// or redirects before its templates are needed.
+	lookedUpTemplate bool
+}
+
+type renderResultCtxKey struct{}
+
+// renderResultRecorder holds the RenderResult recorded in a context.Context.
+// Handlers may render from more than one goroutine, so it's guarded by mu.
+type renderResultRecorder struct {
+	mu       sync.Mutex
+	result   RenderResult
+	recorded bool
+
+	// parent is the recorder of the context.Context passed to
+	// RecordRenderResult, if it had one, which is recorded to as well
+	parent *renderResultRecorder
+}
+
+// RecordRenderResult returns a context.Context that, when passed to Render,
+// will cause Render to record its RenderResult, which can then be retrieved
+// by calling RenderResultFromContext on the returned context.Context.
+//
+// If Render is called more than once with the context.Context, only the
+// RenderResult of the call that finished last is kept. It's safe to render
+// using the context.Context from multiple goroutines, and to call
+// RenderResultFromContext while they render.
+//
+// If the context.Context was already returned by RecordRenderResult, like
+// when two middlewares both record results, the RenderResult is recorded in
+// both, so neither hides it from the other.
+func RecordRenderResult(ctx context.Context) context.Context {
+	parent, _ := ctx.Value(renderResultCtxKey{}).(*renderResultRecorder)
+	return context.WithValue(ctx, renderResultCtxKey{}, &renderResultRecorder{parent: parent})
+}
+
+// RenderResultFromContext returns the RenderResult recorded by Render, if the
+// context.Context was returned by RecordRenderResult and has been passed to
+// Render. If not, it returns false.
+func RenderResultFromContext(ctx context.Context) (RenderResult, bool) {
+	rec, ok := ctx.Value(renderResultCtxKey{}).(*renderResultRecorder)
+	if !ok {
+		return RenderResult{}, false
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if !rec.recorded {
+		return RenderResult{}, false
+	}
+	return rec.result, true
+}
+
+// recordRenderResult stores the passed RenderResult in the context.Context,
+// if it was returned by RecordRenderResult.
+func recordRenderResult(ctx context.Context, result RenderResult) {
+	rec, _ := ctx.Value(renderResultCtxKey{}).(*renderResultRecorder)
+	for ; rec != nil; rec = rec.parent {
+		rec.mu.Lock()
+		rec.result = result
+		rec.recorded = true
+		rec.mu.Unlock()
+	}
+}
@@ -0,0 +1,46 @@
+package temple_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"impractical.co/temple"
+)
+
+type resultPage struct{}
+
+func (resultPage) Templates(_ context.Context) []string {
+	return []string{"result.html.tmpl"}
+}
+
+func (resultPage) Key(_ context.Context) string {
+	return "result.html.tmpl"
+}
+
+func (resultPage) ExecutedTemplate(_ context.Context) string {
+	return "result.html.tmpl"
+}
+
+func TestRecordRenderResultNested(t *testing.T) {
+	t.Parallel()
+
+	site := temple.NewCachedSite(fstest.MapFS{
+		"result.html.tmpl": {Data: []byte(`ok`)},
+	})
+	outer := temple.RecordRenderResult(context.Background())
+	inner := temple.RecordRenderResult(outer)
+	temple.Render(inner, &strings.Builder{}, site, resultPage{})
+
+	for name, ctx := range map[string]context.Context{"outer": outer, "inner": inner} {
+		result, ok := temple.RenderResultFromContext(ctx)
+		if !ok {
+			t.Errorf("expected the %s context.Context to have a RenderResult", name)
+			continue
+		}
+		if result.Key != "result.html.tmpl" {
+			t.Errorf("expected the %s RenderResult to be for %q, got %q", name, "result.html.tmpl", result.Key)
+		}
+	}
+}
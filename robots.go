@@ -0,0 +1,55 @@
+package temple
+
+import (
+	"context"
+	"html/template"
+	"io"
+	"net/http"
+)
+
+// Indexable is an interface that Renderables can fulfill to control whether
+// search engines should index them. Pages that aren't indexable get an
+// X-Robots-Tag: noindex header when Render is writing to an
+// http.ResponseWriter, and .RobotsMetaTag renders a
+// <meta name="robots" content="noindex"> element for them. Pages that don't
+// implement Indexable are presumed to be indexable.
+//
+// Anything that lists a Site's pages for search engines, like a sitemap,
+// should leave out the pages IsIndexable returns false for.
+type Indexable interface {
+	// Indexable returns false if search engines shouldn't index the
+	// page.
+	Indexable(context.Context) bool
+}
+
+// IsIndexable returns false if the page implements Indexable and says it
+// shouldn't be indexed, and true otherwise.
+func IsIndexable(ctx context.Context, page Component) bool {
+	indexable, ok := page.(Indexable)
+	if !ok {
+		return true
+	}
+	return indexable.Indexable(ctx)
+}
+
+// robotsMetaTag returns a <meta name="robots"> element telling search engines
+// not to index the page, or nothing if the page is indexable.
+func robotsMetaTag(indexable bool) template.HTML {
+	if indexable {
+		return ""
+	}
+	return `<meta name="robots" content="noindex">`
+}
+
+// setRobotsHeaders sets the X-Robots-Tag header for the page, if `out` is an
+// http.ResponseWriter and the page isn't indexable.
+func setRobotsHeaders(ctx context.Context, out io.Writer, page Renderable) {
+	resp, ok := out.(http.ResponseWriter)
+	if !ok {
+		return
+	}
+	if IsIndexable(ctx, page) {
+		return
+	}
+	resp.Header().Set("X-Robots-Tag", "noindex")
+}
@@ -0,0 +1,158 @@
+package temple
+
+import (
+	"html/template"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Sanitizer is a policy for cleaning untrusted HTML, like user-generated
+// content, so it's safe to include in a page. *bluemonday.Policy fulfills it,
+// so Sites that need a more thorough or more permissive policy than
+// BasicSanitizer can use one.
+type Sanitizer interface {
+	// Sanitize returns a copy of the HTML with anything the policy
+	// doesn't allow removed.
+	Sanitize(string) string
+}
+
+// SanitizeFunc returns a template function that cleans HTML using the passed
+// Sanitizer and marks the result as safe to include in the page. The sanitize
+// function in DefaultFuncs uses BasicSanitizer; Sites that want to use a
+// different policy can override it using a FuncMapExtender:
+//
+//	func (MySite) FuncMap(_ context.Context) template.FuncMap {
+//		return template.FuncMap{
+//			"sanitize": temple.SanitizeFunc(bluemonday.UGCPolicy()),
+//		}
+//	}
+func SanitizeFunc(policy Sanitizer) func(string) template.HTML {
+	return func(s string) template.HTML {
+		return template.HTML(policy.Sanitize(s)) // #nosec G203
+	}
+}
+
+var _ Sanitizer = BasicSanitizer{}
+
+// BasicSanitizer is a minimal Sanitizer suitable for comments and other short
+// user-generated content. It allows paragraphs, line breaks, lists, quotes,
+// code, basic text formatting, and links using the http, https, or mailto
+// schemes, or relative URLs. Links are marked rel="nofollow". All other
+// elements are removed, keeping their text, except for elements like <script>
+// and <style>, which are removed along with their contents. All attributes are
+// removed, except for the href and title attributes of links.
+type BasicSanitizer struct{}
+
+// basicSanitizerElements are the elements BasicSanitizer allows, and the
+// attributes allowed on them.
+var basicSanitizerElements = map[string][]string{
+	"a":          {"href", "title"},
+	"b":          nil,
+	"blockquote": nil,
+	"br":         nil,
+	"code":       nil,
+	"em":         nil,
+	"i":          nil,
+	"li":         nil,
+	"ol":         nil,
+	"p":          nil,
+	"pre":        nil,
+	"s":          nil,
+	"strong":     nil,
+	"u":          nil,
+	"ul":         nil,
+}
+
+// basicSanitizerDroppedElements are the elements BasicSanitizer removes along
+// with their contents.
+var basicSanitizerDroppedElements = map[string]struct{}{
+	"iframe":   {},
+	"noscript": {},
+	"object":   {},
+	"script":   {},
+	"style":    {},
+	"template": {},
+	"textarea": {},
+	"title":    {},
+}
+
+// Sanitize returns a copy of the HTML with everything BasicSanitizer doesn't
+// allow removed. Unclosed elements are closed, and closing tags without a
+// matching opening tag are removed, so the result can't affect the markup
+// around it.
+func (BasicSanitizer) Sanitize(s string) string {
+	var out strings.Builder
+	// the allowed elements that are currently open
+	var open []string
+	// how many elements whose contents are being dropped are open
+	dropping := 0
+	tokenizer := html.NewTokenizer(strings.NewReader(s))
+	for {
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			// either we've reached the end of the input, or it's
+			// malformed; either way, close anything still open
+			for i := len(open) - 1; i >= 0; i-- {
+				out.WriteString("</" + open[i] + ">")
+			}
+			return out.String()
+		case html.TextToken:
+			if dropping == 0 {
+				out.WriteString(html.EscapeString(string(tokenizer.Text())))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			if _, ok := basicSanitizerDroppedElements[token.Data]; ok {
+				if tokenType == html.StartTagToken {
+					dropping++
+				}
+				continue
+			}
+			attrs, ok := basicSanitizerElements[token.Data]
+			if dropping > 0 || !ok {
+				continue
+			}
+			out.WriteString("<" + token.Data)
+			for _, attr := range token.Attr {
+				if attr.Namespace != "" || !slices.Contains(attrs, attr.Key) {
+					continue
+				}
+				if attr.Key == "href" && !safeLinkHref(attr.Val) {
+					continue
+				}
+				out.WriteString(" " + attr.Key + `="` + html.EscapeString(attr.Val) + `"`)
+			}
+			if token.Data == "a" {
+				out.WriteString(` rel="nofollow"`)
+			}
+			out.WriteString(">")
+			if token.Data != "br" {
+				open = append(open, token.Data)
+			}
+		case html.EndTagToken:
+			token := tokenizer.Token()
+			if _, ok := basicSanitizerDroppedElements[token.Data]; ok {
+				if dropping > 0 {
+					dropping--
+				}
+				continue
+			}
+			if dropping > 0 {
+				continue
+			}
+			index := slices.Index(open, token.Data)
+			if index < 0 {
+				continue
+			}
+			// close anything opened inside the element, too
+			for i := len(open) - 1; i >= index; i-- {
+				out.WriteString("</" + open[i] + ">")
+			}
+			open = open[:index]
+		}
+		// comments and doctypes are always removed
+	}
+}
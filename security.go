@@ -0,0 +1,156 @@
+package temple
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// SecurityPolicier is an optional interface for Sites. Those fulfilling it
+// can declare the security policy that should be applied to every response
+// served by SecureHeadersMiddleware.
+type SecurityPolicier interface {
+	// SecurityPolicy returns the SecurityPolicy that should be applied to
+	// the response for the request the context.Context belongs to.
+	SecurityPolicy(ctx context.Context) SecurityPolicy
+}
+
+// SecurityPolicy describes the security-related headers that should be set on
+// responses. The zero value sets no headers.
+type SecurityPolicy struct {
+	// ContentSecurityPolicy is used to set the Content-Security-Policy
+	// header. If it's empty, the header won't be set.
+	ContentSecurityPolicy ContentSecurityPolicy
+
+	// ReferrerPolicy is used to set the Referrer-Policy header, e.g.
+	// "strict-origin-when-cross-origin". If it's empty, the header won't
+	// be set.
+	ReferrerPolicy string
+
+	// NoSniff sets the X-Content-Type-Options header to "nosniff" when
+	// true.
+	NoSniff bool
+
+	// PermissionsPolicy is used to set the Permissions-Policy header. If
+	// it's empty, the header won't be set.
+	PermissionsPolicy PermissionsPolicy
+}
+
+// headers returns the headers that should be set to apply the
+// SecurityPolicy.
+func (s SecurityPolicy) headers() http.Header {
+	headers := http.Header{}
+	if csp := s.ContentSecurityPolicy.String(); csp != "" {
+		headers.Set("Content-Security-Policy", csp)
+	}
+	if s.ReferrerPolicy != "" {
+		headers.Set("Referrer-Policy", s.ReferrerPolicy)
+	}
+	if s.NoSniff {
+		headers.Set("X-Content-Type-Options", "nosniff")
+	}
+	if pp := s.PermissionsPolicy.String(); pp != "" {
+		headers.Set("Permissions-Policy", pp)
+	}
+	return headers
+}
+
+// ContentSecurityPolicy is a builder for the value of a
+// Content-Security-Policy header. It maps directives, like "script-src", to
+// the sources allowed for them, like "'self'" or "https://example.com".
+// Sources are included in the header exactly as they're specified, so
+// keywords need to include their single quotes.
+type ContentSecurityPolicy map[string][]string
+
+// Add includes the passed sources in the sources allowed for the directive,
+// ignoring any sources that are already allowed. Directives without any
+// sources, like "upgrade-insecure-requests", can be added by not passing any
+// sources.
+func (c ContentSecurityPolicy) Add(directive string, sources ...string) ContentSecurityPolicy {
+	existing, ok := c[directive]
+	if !ok {
+		existing = []string{}
+	}
+	for _, source := range sources {
+		found := false
+		for _, e := range existing {
+			if e == source {
+				found = true
+				break
+			}
+		}
+		if !found {
+			existing = append(existing, source)
+		}
+	}
+	c[directive] = existing
+	return c
+}
+
+// String returns the ContentSecurityPolicy in the format expected by the
+// Content-Security-Policy header. Directives are sorted alphabetically, so the
+// output is stable.
+func (c ContentSecurityPolicy) String() string {
+	directives := make([]string, 0, len(c))
+	for directive := range c {
+		directives = append(directives, directive)
+	}
+	sort.Strings(directives)
+	parts := make([]string, 0, len(directives))
+	for _, directive := range directives {
+		parts = append(parts, strings.Join(append([]string{directive}, c[directive]...), " "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// PermissionsPolicy is a builder for the value of a Permissions-Policy
+// header. It maps features, like "geolocation", to the origins allowed to use
+// them. Origins can be "self", "*", or a URL like "https://example.com". A
+// feature with no origins is disabled entirely.
+type PermissionsPolicy map[string][]string
+
+// String returns the PermissionsPolicy in the format expected by the
+// Permissions-Policy header. Features are sorted alphabetically, so the output
+// is stable.
+func (p PermissionsPolicy) String() string {
+	features := make([]string, 0, len(p))
+	for feature := range p {
+		features = append(features, feature)
+	}
+	sort.Strings(features)
+	parts := make([]string, 0, len(features))
+	for _, feature := range features {
+		origins := make([]string, 0, len(p[feature]))
+		for _, origin := range p[feature] {
+			if origin != "self" && origin != "*" {
+				origin = `"` + origin + `"`
+			}
+			origins = append(origins, origin)
+		}
+		parts = append(parts, feature+"=("+strings.Join(origins, " ")+")")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SecureHeadersMiddleware returns a middleware that sets the headers described
+// by the Site's SecurityPolicy on every response served by the http.Handler it
+// wraps, if the Site implements SecurityPolicier. If it doesn't, the
+// middleware does nothing.
+//
+// The headers are set before the wrapped http.Handler is called, so it can
+// still modify or remove them.
+func SecureHeadersMiddleware(site Site) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		policier, ok := site.(SecurityPolicier)
+		if !ok {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for header, values := range policier.SecurityPolicy(r.Context()).headers() {
+				w.Header()[header] = values
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"html/template"
 	"io/fs"
+	"slices"
 	"sync"
 )
 
@@ -48,8 +49,51 @@ type ServerErrorPager interface {
 	ServerErrorPage(ctx context.Context) Renderable
 }
 
+// ErrorPageData describes a page that failed to render, for
+// ServerErrorPagerWithError.
+type ErrorPageData struct {
+	// Err is the error that stopped the page from rendering. It may
+	// contain details about the Site's internals, so it should usually
+	// only be displayed in development.
+	Err error
+
+	// Page is the Renderable that failed to render.
+	Page Renderable
+
+	// Key is the output of the Key method of Page.
+	Key string
+}
+
+// ServerErrorPagerWithError defines an interface that Sites can optionally
+// implement to render server error pages that depend on what went wrong, like
+// pages that display the error in development. If a Site implements both
+// ServerErrorPagerWithError and ServerErrorPager, ServerErrorPagerWithError
+// is used.
+type ServerErrorPagerWithError interface {
+	ServerErrorPageWithError(ctx context.Context, data ErrorPageData) Renderable
+}
+
+// DevelopmentReporter is an optional interface for Sites. Those fulfilling it
+// can report that they're being used in development, where changes to their
+// templates should show up immediately, so temple doesn't remember that a
+// page's templates were missing either.
+type DevelopmentReporter interface {
+	// InDevelopment returns true if the Site is being used in
+	// development.
+	InDevelopment(ctx context.Context) bool
+}
+
 var _ Site = &CachedSite{}
 var _ TemplateCacher = &CachedSite{}
+var _ TemplateCacheInvalidator = &CachedSite{}
+var _ TemplateCacheStatser = &CachedSite{}
+var _ DefaultFuncsIncluder = &CachedSite{}
+var _ FuncMapExtender = &CachedSite{}
+var _ DelimsProvider = &CachedSite{}
+var _ Packager = &CachedSite{}
+var _ DevelopmentReporter = &CachedSite{}
+var _ CriticalCSSCacher = &CachedSite{}
+var _ CriticalCSSCacheInvalidator = &CachedSite{}
 
 // CachedSite is an implementation of the Site interface that can be embedded
 // in other Site implementations. It fulfills the Site interface and the
@@ -62,17 +106,94 @@ type CachedSite struct {
 	templateCache   map[string]*template.Template
 	templateCacheMu sync.RWMutex
 
+	// cacheOrder holds the keys in templateCache in the order they were
+	// cached, so the oldest can be evicted when maxTemplates is reached
+	cacheOrder []string
+
+	// cache the contents of critical stylesheets, by path, to avoid
+	// reading them for every request
+	criticalCSS   map[string][]byte
+	criticalCSSMu sync.RWMutex
+
 	// templateDir is where Render will look for the templates required by
 	// Components.
 	templateDir fs.FS
+
+	// the configuration set by SiteOptions
+	maxTemplates int
+	noCache      bool
+	defaultFuncs bool
+	funcs        template.FuncMap
+	leftDelim    string
+	rightDelim   string
+	packages     []ComponentPackage
+}
+
+// SiteOption configures a CachedSite, when passed to NewCachedSite.
+type SiteOption func(*CachedSite)
+
+// WithMaxCachedTemplates is a SiteOption that limits the number of keys a
+// CachedSite caches templates for. Once the limit is reached, caching the
+// templates for a new key evicts the key that was cached first. If limit is
+// less than 1, the number of keys isn't limited, which is the default.
+func WithMaxCachedTemplates(limit int) SiteOption {
+	return func(s *CachedSite) {
+		s.maxTemplates = limit
+	}
+}
+
+// WithDevelopment is a SiteOption that stops a CachedSite from caching
+// templates or critical stylesheets, or remembering that templates are
+// missing, so they're read again on every render and changes to them show up
+// immediately. It's meant to be used in development, and shouldn't be used
+// in production.
+func WithDevelopment() SiteOption {
+	return func(s *CachedSite) {
+		s.noCache = true
+	}
 }
 
-// NewCachedSite returns a CachedSite instance that is ready to be used.
-func NewCachedSite(templates fs.FS) *CachedSite {
-	return &CachedSite{
+// WithDefaultFuncs is a SiteOption that makes the functions returned by
+// DefaultFuncs available to the CachedSite's templates, as if the Site
+// implemented DefaultFuncsIncluder.
+func WithDefaultFuncs() SiteOption {
+	return func(s *CachedSite) {
+		s.defaultFuncs = true
+	}
+}
+
+// WithFuncs is a SiteOption that makes the functions in the FuncMap available
+// to the CachedSite's templates, as if the Site implemented FuncMapExtender.
+// It can be used more than once, with later functions overriding earlier ones
+// with the same name.
+func WithFuncs(funcs template.FuncMap) SiteOption {
+	return func(s *CachedSite) {
+		s.funcs = mergeFuncMaps(s.funcs, funcs)
+	}
+}
+
+// WithDelims is a SiteOption that makes the CachedSite's templates use the
+// passed action delimiters instead of {{ and }}, as if the Site implemented
+// DelimsProvider. An empty delimiter means the default.
+func WithDelims(left, right string) SiteOption {
+	return func(s *CachedSite) {
+		s.leftDelim = left
+		s.rightDelim = right
+	}
+}
+
+// NewCachedSite returns a CachedSite instance that is ready to be used,
+// configured by any SiteOptions passed.
+func NewCachedSite(templates fs.FS, opts ...SiteOption) *CachedSite {
+	site := &CachedSite{
 		templateCache: map[string]*template.Template{},
+		criticalCSS:   map[string][]byte{},
 		templateDir:   templates,
 	}
+	for _, opt := range opts {
+		opt(site)
+	}
+	return site
 }
 
 // GetCachedTemplate returns the cached template associated with the passed
@@ -93,9 +214,111 @@ func (s *CachedSite) GetCachedTemplate(_ context.Context, key string) *template.
 //
 // It can safely be used by multiple goroutines.
 func (s *CachedSite) SetCachedTemplate(_ context.Context, key string, tmpl *template.Template) {
+	if s.noCache {
+		return
+	}
 	s.templateCacheMu.Lock()
 	defer s.templateCacheMu.Unlock()
+	if _, ok := s.templateCache[key]; !ok {
+		s.cacheOrder = append(s.cacheOrder, key)
+	}
 	s.templateCache[key] = tmpl
+	for s.maxTemplates > 0 && len(s.cacheOrder) > s.maxTemplates {
+		delete(s.templateCache, s.cacheOrder[0])
+		s.cacheOrder = s.cacheOrder[1:]
+	}
+}
+
+// InvalidateCachedTemplates removes the templates cached for each of the
+// keys, so they'll be parsed again the next time they're needed.
+//
+// It can safely be used by multiple goroutines.
+func (s *CachedSite) InvalidateCachedTemplates(_ context.Context, keys ...string) {
+	s.templateCacheMu.Lock()
+	defer s.templateCacheMu.Unlock()
+	for _, key := range keys {
+		delete(s.templateCache, key)
+	}
+	s.cacheOrder = slices.DeleteFunc(s.cacheOrder, func(key string) bool {
+		_, ok := s.templateCache[key]
+		return !ok
+	})
+}
+
+// GetCachedCriticalCSS returns the contents of the critical stylesheet cached
+// for the key, and false if there aren't any.
+//
+// It can safely be used by multiple goroutines.
+func (s *CachedSite) GetCachedCriticalCSS(_ context.Context, key string) ([]byte, bool) {
+	s.criticalCSSMu.RLock()
+	defer s.criticalCSSMu.RUnlock()
+	css, ok := s.criticalCSS[key]
+	return css, ok
+}
+
+// SetCachedCriticalCSS caches the contents of a critical stylesheet for the
+// key, unless the CachedSite was configured with WithDevelopment.
+//
+// It can safely be used by multiple goroutines.
+func (s *CachedSite) SetCachedCriticalCSS(_ context.Context, key string, css []byte) {
+	if s.noCache {
+		return
+	}
+	s.criticalCSSMu.Lock()
+	defer s.criticalCSSMu.Unlock()
+	s.criticalCSS[key] = css
+}
+
+// InvalidateCachedCriticalCSS removes the contents of the critical
+// stylesheets cached for each of the keys, so they'll be read again the next
+// time they're needed.
+//
+// It can safely be used by multiple goroutines.
+func (s *CachedSite) InvalidateCachedCriticalCSS(_ context.Context, keys ...string) {
+	s.criticalCSSMu.Lock()
+	defer s.criticalCSSMu.Unlock()
+	for _, key := range keys {
+		delete(s.criticalCSS, key)
+	}
+}
+
+// TemplateCacheStats returns statistics about the templates currently cached,
+// including an estimate of how much memory they use.
+//
+// It can safely be used by multiple goroutines.
+func (s *CachedSite) TemplateCacheStats(_ context.Context) TemplateCacheStats {
+	s.templateCacheMu.RLock()
+	defer s.templateCacheMu.RUnlock()
+	var stats TemplateCacheStats
+	for _, tmpl := range s.templateCache {
+		stats.add(tmpl)
+	}
+	return stats
+}
+
+// InDevelopment returns true if the CachedSite was configured with the
+// WithDevelopment SiteOption.
+func (s *CachedSite) InDevelopment(_ context.Context) bool {
+	return s.noCache
+}
+
+// IncludeDefaultFuncs returns true if the CachedSite was configured with the
+// WithDefaultFuncs SiteOption.
+func (s *CachedSite) IncludeDefaultFuncs(_ context.Context) bool {
+	return s.defaultFuncs
+}
+
+// FuncMap returns the functions the CachedSite was configured with using the
+// WithFuncs SiteOption.
+func (s *CachedSite) FuncMap(_ context.Context) template.FuncMap {
+	return s.funcs
+}
+
+// Delims returns the action delimiters the CachedSite was configured with
+// using the WithDelims SiteOption, or empty strings for the default
+// delimiters.
+func (s *CachedSite) Delims(_ context.Context) (string, string) {
+	return s.leftDelim, s.rightDelim
 }
 
 // TemplateDir returns an fs.FS containing all the templates needed to render a
@@ -0,0 +1,175 @@
+package temple
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ComponentRenderData is the data that is passed to a Component when
+// rendering it with RenderComponent.
+type ComponentRenderData[SiteType Site, ComponentType Component] struct {
+	// Site is an instance of the Site type, containing all the
+	// configuration and information about a Site.
+	Site SiteType
+
+	// Component is the Component being rendered.
+	Component ComponentType
+
+	// Data is the data passed to RenderComponent.
+	Data any
+
+	// Request is the request-scoped data embedded in the context.Context
+	// passed to RenderComponent using WithRequestData, if any.
+	Request any
+
+	// EmbeddedJS is the result of calling EmbedJS on the Component and
+	// all the Components it uses, if they support the JSEmbedder
+	// interface.
+	EmbeddedJS template.JS
+
+	// EmbeddedCSS is the result of calling EmbedCSS on the Component and
+	// all the Components it uses, if they support the CSSEmbedder
+	// interface.
+	EmbeddedCSS template.CSS
+
+	// LinkedJS is the result of calling LinkJS on the Component and all
+	// the Components it uses, if they support the JSLinker interface.
+	LinkedJS []string
+
+	// LinkedCSS is the result of calling LinkCSS on the Component and all
+	// the Components it uses, if they support the CSSLinker interface,
+	// along with any non-critical stylesheets from LinkCSSResources, if
+	// they support the CSSResourceLinker interface.
+	LinkedCSS []string
+
+	// CriticalCSS is the merged contents of every critical stylesheet
+	// returned by LinkCSSResources, if the Component or any of the
+	// Components it uses support the CSSResourceLinker interface.
+	CriticalCSS template.CSS
+
+	// ImportMap is the merged output of calling ImportMap on the
+	// Component and all the Components it uses, if they support the
+	// JSImportMapper interface.
+	ImportMap JSImportMap
+
+	// JSONData is the result of calling EmbedJSONData on the Component
+	// and all the Components it uses, if they support the
+	// JSONDataEmbedder interface.
+	JSONData []JSONData
+
+	// Theme is the Theme the Component is being rendered with, if the
+	// Site implements Themer. Otherwise, it's the zero Theme.
+	Theme Theme
+}
+
+// JSONDataTags returns a <script type="application/json"> element for each
+// entry in JSONData, with the entry's Value serialized as JSON.
+func (r ComponentRenderData[SiteType, ComponentType]) JSONDataTags() (template.HTML, error) {
+	return jsonDataTags(r.JSONData)
+}
+
+// ImportMapTag returns a <script type="importmap"> element containing
+// ImportMap, or nothing if ImportMap is empty.
+func (r ComponentRenderData[SiteType, ComponentType]) ImportMapTag() (template.HTML, error) {
+	return r.ImportMap.tag()
+}
+
+// RenderComponent renders the template named templateName to the Writer,
+// using the templates of the Component and every Component it uses. It's
+// meant for output that isn't a full page, like emails, HTML fragments for
+// HTMX, or the contents of web components, where a Renderable would be
+// overkill.
+//
+// The template is executed with a ComponentRenderData, holding the
+// Component, the passed data, and the resources the Component and the
+// Components it uses supply.
+//
+// Unlike Render, RenderComponent doesn't render an error page when something
+// goes wrong; errors are returned, and nothing is written to the Writer. As
+// Components have no Key, their templates are parsed on every call and never
+// cached.
+func RenderComponent[SiteType Site, ComponentType Component](ctx context.Context, out io.Writer, site SiteType, component ComponentType, templateName string, data any) error {
+	ctx, span := tracer().Start(ctx, "render component",
+		trace.WithAttributes(
+			componentTypeAttr.String(componentType(component)),
+			attribute.String("temple.template", templateName),
+		),
+	)
+	defer span.End()
+	ctx = withPublished(ctx)
+	ctx = withTheme(ctx, site)
+
+	components, err := getRecursiveComponents(ctx, component)
+	if err != nil {
+		return err
+	}
+	err = checkRequirements(ctx, site, components)
+	if err != nil {
+		return err
+	}
+
+	criticalCSS, _, err := getComponentCriticalCSS(ctx, site, components)
+	if err != nil {
+		return err
+	}
+	importMap, err := getComponentImportMap(ctx, components)
+	if err != nil {
+		return err
+	}
+	renderData := ComponentRenderData[SiteType, ComponentType]{
+		Site:        site,
+		Component:   component,
+		Data:        data,
+		Request:     RequestData(ctx),
+		EmbeddedJS:  getComponentJSEmbeds(ctx, components),
+		EmbeddedCSS: getComponentCSSEmbeds(ctx, components),
+		LinkedJS:    getComponentJSLinks(ctx, components),
+		LinkedCSS:   getComponentCSSLinks(ctx, components),
+		CriticalCSS: criticalCSS,
+		ImportMap:   importMap,
+		JSONData:    getComponentJSONData(ctx, components),
+		Theme:       CurrentTheme(ctx),
+	}
+
+	tmplPaths := getComponentTemplatePaths(ctx, site, components)
+	if len(tmplPaths) < 1 {
+		return fmt.Errorf("error rendering %T: %w", component, ErrNoTemplatePath)
+	}
+	componentFuncs, err := getComponentFuncMap(ctx, site, components, nil, false)
+	if err != nil {
+		return fmt.Errorf("error building FuncMap for %T: %w", component, err)
+	}
+	// the templates are never cached, so the context funcs can be bound
+	// to this render's context.Context when they're parsed
+	ctx = withPageResources(ctx, renderData.LinkedJS, renderData.LinkedCSS, nil)
+	tmpl, err := parseTemplates(mergeFuncMaps(componentFuncs, contextFuncs(ctx, site)), false, tmplPaths...)
+	if err != nil {
+		return fmt.Errorf("error parsing templates %v for %T: %w", templatePathStrings(tmplPaths), component, err)
+	}
+	err = addPlacedResourcesMarker(tmpl)
+	if err != nil {
+		return err
+	}
+	renderData.LinkedJS = removePlacedResources(tmpl, renderData.LinkedJS)
+	renderData.LinkedCSS = removePlacedResources(tmpl, renderData.LinkedCSS)
+	tmpl.Funcs(template.FuncMap{
+		"component": componentFunc(ctx, site, tmpl),
+	})
+
+	buf := getRenderBuffer()
+	err = tmpl.ExecuteTemplate(buf, templateName, renderData)
+	defer putRenderBuffer(buf, buf.Len())
+	if err != nil {
+		return fmt.Errorf("error executing template %q for %T: %w", templateName, component, err)
+	}
+	_, err = buf.WriteTo(out)
+	if err != nil {
+		return fmt.Errorf("error writing %T: %w", component, err)
+	}
+	return nil
+}
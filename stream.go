@@ -0,0 +1,144 @@
+package temple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultStreamChunkSize is the chunk size used by WithStreaming if it's
+// passed a chunk size less than 1.
+const defaultStreamChunkSize = 32 * 1024
+
+// WithStreaming is a RenderOption that writes the page to the io.Writer as it
+// renders, in chunks of roughly chunkSize bytes, instead of rendering the
+// whole page into memory first. If the io.Writer is an http.ResponseWriter
+// that supports flushing, each chunk is flushed to the client as it's written.
+// If chunkSize is less than 1, a default of 32KiB is used.
+//
+// This keeps the memory used by very large pages, like sitemaps or long
+// listings, bounded by the chunk size, especially when the data being
+// rendered is produced lazily as the template ranges over it. But it changes
+// how errors are handled: by default, Render only writes the page once it has
+// rendered successfully, so if rendering fails, a server error page can be
+// written instead. When streaming, once the first chunk has been written, the
+// response is committed; if the template fails after that, the error is
+// logged and recorded in the RenderResult, but the output is left truncated
+// and no server error page is rendered. Errors that occur before the first
+// chunk is written are still handled as usual.
+//
+// Response headers set by Render, like those from CachePolicy, are set before
+// the page starts rendering, so they'll apply even if the page is truncated.
+func WithStreaming(chunkSize int) RenderOption {
+	return func(opts *renderOptions) {
+		if chunkSize < 1 {
+			chunkSize = defaultStreamChunkSize
+		}
+		opts.streamChunkSize = chunkSize
+	}
+}
+
+// partialRenderError is returned when a page fails to render after some of
+// its output has already been written, meaning it's too late to render a
+// server error page instead.
+type partialRenderError struct {
+	err error
+}
+
+func (p partialRenderError) Error() string {
+	return p.err.Error()
+}
+
+func (p partialRenderError) Unwrap() error {
+	return p.err
+}
+
+// chunkedWriter buffers writes until it has at least `size` bytes, then
+// writes and flushes them to the wrapped io.Writer.
+type chunkedWriter struct {
+	out     io.Writer
+	buf     []byte
+	size    int
+	written int64
+
+	// status is the status code to write before the first chunk, if
+	// it's not 0
+	status int
+}
+
+// Write buffers b, flushing the buffer once it's full. b is always buffered,
+// and Flush keeps any bytes the wrapped io.Writer didn't accept, so b's length
+// is returned along with any error.
+func (c *chunkedWriter) Write(b []byte) (int, error) {
+	c.buf = append(c.buf, b...)
+	if len(c.buf) >= c.size {
+		if err := c.Flush(); err != nil {
+			return len(b), err
+		}
+	}
+	return len(b), nil
+}
+
+// Flush writes any buffered bytes to the wrapped io.Writer and, if it's an
+// http.ResponseWriter that supports it, flushes them to the client. If the
+// io.Writer returns an error, the bytes it didn't write stay buffered.
+func (c *chunkedWriter) Flush() error {
+	if c.status != 0 {
+		writeStatus(c.out, c.status)
+		c.status = 0
+	}
+	if len(c.buf) < 1 {
+		return nil
+	}
+	n, err := c.out.Write(c.buf)
+	c.written += int64(n)
+	c.buf = c.buf[:copy(c.buf, c.buf[n:])]
+	if err != nil {
+		return err
+	}
+	if resp, ok := c.out.(http.ResponseWriter); ok {
+		err = http.NewResponseController(resp).Flush()
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+	}
+	return nil
+}
+
+func streamRender(ctx context.Context, output io.Writer, tmpl *template.Template, executed string, data any, page Renderable, components []Component, headers http.Header, status int, chunkSize int, result *RenderResult) error {
+	setHeaders(output, headers)
+	setCacheHeaders(ctx, output, page)
+	setSurrogateKeyHeaders(ctx, output, components)
+	setRobotsHeaders(ctx, output, page)
+	writer := &chunkedWriter{out: output, size: chunkSize, buf: make([]byte, 0, chunkSize), status: status}
+	_, span := tracer().Start(ctx, "execute template",
+		trace.WithAttributes(attribute.String("temple.template", executed)),
+	)
+	err := tmpl.ExecuteTemplate(writer, executed, data)
+	span.End()
+	defer func() {
+		result.BytesWritten = writer.written
+	}()
+	if err != nil {
+		err = fmt.Errorf("error executing template %q for %T: %w", executed, page, err)
+		if writer.written > 0 {
+			return partialRenderError{err: err}
+		}
+		return err
+	}
+	err = writer.Flush()
+	if err != nil {
+		err = fmt.Errorf("error writing %T: %w", page, err)
+		if writer.written > 0 {
+			return partialRenderError{err: err}
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package temple_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"impractical.co/temple"
+)
+
+// writeRecorder records the size of each write to it, failing once it's
+// been written to failAfter times, if failAfter isn't 0. If short is set, the
+// failing write still writes half of its bytes.
+type writeRecorder struct {
+	bytes.Buffer
+	writes    []int
+	failAfter int
+	short     bool
+}
+
+func (w *writeRecorder) Write(b []byte) (int, error) {
+	if w.failAfter > 0 && len(w.writes) >= w.failAfter {
+		n := 0
+		if w.short {
+			n, _ = w.Buffer.Write(b[:len(b)/2])
+		}
+		return n, errors.New("client went away")
+	}
+	w.writes = append(w.writes, len(b))
+	return w.Buffer.Write(b)
+}
+
+func TestWithStreaming(t *testing.T) {
+	t.Parallel()
+
+	rows := make([]string, 100)
+	for i := range rows {
+		rows[i] = strings.Repeat("x", 10)
+	}
+	// each row renders to 18 bytes
+	const row = "<p>xxxxxxxxxx</p>\n"
+	const chunkSize = 64
+
+	cases := map[string]struct {
+		failAt      int
+		failAfter   int
+		short       bool
+		wantErr     bool
+		wantPartial bool
+	}{
+		"rendered": {},
+		"fails-before-first-chunk": {
+			failAt:  2,
+			wantErr: true,
+		},
+		"fails-mid-template": {
+			failAt:      50,
+			wantErr:     true,
+			wantPartial: true,
+		},
+		"fails-writing": {
+			failAfter:   3,
+			wantErr:     true,
+			wantPartial: true,
+		},
+		"fails-mid-write": {
+			failAfter:   3,
+			short:       true,
+			wantErr:     true,
+			wantPartial: true,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			site := temple.NewCachedSite(fstest.MapFS{
+				"page.html.tmpl": {Data: []byte("{{ range $i, $row := .Page.Rows }}{{ $.Page.CheckRow $i }}<p>{{ $row }}</p>\n{{ end }}")},
+			})
+			out := &writeRecorder{failAfter: test.failAfter, short: test.short}
+			result := temple.Render(context.Background(), out, site, testPage{Rows: rows, failAt: test.failAt}, temple.WithStreaming(chunkSize))
+			if (result.Err != nil) != test.wantErr {
+				t.Fatalf("expected error %v, got %v", test.wantErr, result.Err)
+			}
+
+			// every chunk but the last is at least chunkSize bytes
+			for i, size := range out.writes {
+				if size < chunkSize && i < len(out.writes)-1 {
+					t.Errorf("write %d was %d bytes, expected at least %d", i, size, chunkSize)
+				}
+			}
+			// the server error message isn't counted
+			if (!test.wantErr || test.wantPartial) && int64(out.Len()) != result.BytesWritten {
+				t.Errorf("expected BytesWritten to be %d, got %d", out.Len(), result.BytesWritten)
+			}
+
+			body := out.String()
+			switch {
+			case !test.wantErr:
+				if body != strings.Repeat(row, len(rows)) {
+					t.Errorf("expected every row, got %q", body)
+				}
+			case test.wantPartial:
+				// it's too late for an error page, so the page is
+				// left truncated
+				if body == "" || strings.Contains(body, "Server error.") || !strings.HasPrefix(strings.Repeat(row, len(rows)), body) {
+					t.Errorf("expected truncated page, got %q", body)
+				}
+			default:
+				if body != "Server error." {
+					t.Errorf("expected server error message, got %q", body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package tags_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing/fstest"
+
+	"impractical.co/temple"
+	"impractical.co/temple/tags"
+)
+
+type consentCtxKey struct{}
+
+type ArticlePage struct {
+	Tags tags.Container
+}
+
+func (ArticlePage) Templates(_ context.Context) []string {
+	return []string{"article.html.tmpl"}
+}
+
+func (p ArticlePage) UseComponents(_ context.Context) []temple.Component {
+	return []temple.Component{p.Tags}
+}
+
+func (ArticlePage) Key(_ context.Context) string {
+	return "article.html.tmpl"
+}
+
+func (ArticlePage) ExecutedTemplate(_ context.Context) string {
+	return "article.html.tmpl"
+}
+
+func Example() {
+	config, err := tags.LoadConfig(strings.NewReader(`[
+		{"name": "errors", "url": "https://errors.example.com/e.js"},
+		{"name": "analytics", "url": "https://analytics.example.com/a.js", "consent": "analytics", "strategy": "idle"},
+		{"name": "ads", "url": "https://ads.example.com/ads.js", "consent": "marketing"}
+	]`))
+	if err != nil {
+		panic(err)
+	}
+	container := tags.Container{
+		Tags: config,
+		// usually this would check a cookie or the user's settings
+		Consent: tags.ConsenterFunc(func(ctx context.Context, category string) bool {
+			consented, _ := ctx.Value(consentCtxKey{}).([]string)
+			for _, c := range consented {
+				if c == category {
+					return true
+				}
+			}
+			return false
+		}),
+	}
+
+	fmt.Println(container.AllowInPolicy(temple.ContentSecurityPolicy{}.Add("script-src", "'self'")))
+
+	templates := fstest.MapFS{
+		"article.html.tmpl": {Data: []byte(`{{ range .LinkedJS }}<script src="{{ . }}"></script>
+{{ end }}{{ .IdleJS }}`)},
+	}
+	site := temple.NewCachedSite(templates)
+
+	ctx := context.WithValue(context.Background(), consentCtxKey{}, []string{"analytics"})
+	temple.Render(ctx, os.Stdout, site, ArticlePage{Tags: container})
+
+	//Output:
+	// script-src 'self' https://errors.example.com https://analytics.example.com https://ads.example.com
+	// <script src="https://errors.example.com/e.js"></script>
+	// [https://analytics.example.com/a.js]
+}
+
+func ExampleLoadConfig() {
+	_, err := tags.LoadConfig(strings.NewReader(`[{"name": "pixel", "url": "http://pixel.example.com/p.js"}]`))
+	fmt.Println(err)
+
+	//Output:
+	// invalid tag "pixel": URL "http://pixel.example.com/p.js" must be an absolute https URL
+}
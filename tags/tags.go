@@ -0,0 +1,169 @@
+// Package tags provides a temple Component that loads the third-party
+// scripts, or tags, a Site is configured to use, like analytics or
+// advertising scripts, without a client-side tag manager.
+//
+// Tags are configured on the server, usually by loading a JSON file with
+// LoadConfig, so they can be changed without changing any templates:
+//
+//	[
+//		{"name": "analytics", "url": "https://analytics.example.com/a.js", "consent": "analytics", "strategy": "idle"},
+//		{"name": "ads", "url": "https://ads.example.com/ads.js", "consent": "marketing"}
+//	]
+//
+// A Container holding the configured Tags can then be included in the
+// UseComponents output of a page's layout. Each Tag the user has consented
+// to is linked to as a temple.JSLink, so it's made available to templates as
+// .LinkedJS or .IdleJS, depending on its Strategy, like any other script.
+// Container.AllowInPolicy adds the Tags' origins to a Site's
+// temple.ContentSecurityPolicy, so the Tags are allowed to load.
+package tags
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+
+	"impractical.co/temple"
+)
+
+var (
+	// ErrInvalidTag is returned when a Tag's configuration can't be used.
+	ErrInvalidTag = errors.New("invalid tag")
+)
+
+// Tag is a third-party script the Site loads.
+type Tag struct {
+	// Name identifies the Tag in error messages.
+	Name string `json:"name"`
+
+	// URL is the URL the script is loaded from. It must be an absolute
+	// https URL.
+	URL string `json:"url"`
+
+	// Consent is the category of consent the user needs to have given
+	// before the Tag is loaded, like "analytics" or "marketing". Tags
+	// without a Consent are always loaded.
+	Consent string `json:"consent,omitempty"`
+
+	// Strategy is when the script should be loaded.
+	Strategy temple.JSLoadStrategy `json:"strategy,omitempty"`
+}
+
+// validate returns an error if the Tag can't be used.
+func (t Tag) validate() error {
+	parsed, err := url.Parse(t.URL)
+	if err != nil {
+		return fmt.Errorf("%w %q: error parsing URL: %w", ErrInvalidTag, t.Name, err)
+	}
+	if parsed.Scheme != "https" || parsed.Host == "" {
+		return fmt.Errorf("%w %q: URL %q must be an absolute https URL", ErrInvalidTag, t.Name, t.URL)
+	}
+	switch t.Strategy {
+	case temple.JSLoadDefault, temple.JSLoadIdle:
+	default:
+		return fmt.Errorf("%w %q: unknown strategy %q", ErrInvalidTag, t.Name, t.Strategy)
+	}
+	return nil
+}
+
+// origin returns the scheme and host of the Tag's URL.
+func (t Tag) origin() string {
+	parsed, err := url.Parse(t.URL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
+
+// LoadConfig reads a JSON array of Tags, returning an error if any of them
+// can't be used.
+func LoadConfig(r io.Reader) ([]Tag, error) {
+	var tags []Tag
+	err := json.NewDecoder(r).Decode(&tags)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding tags: %w", err)
+	}
+	for _, tag := range tags {
+		err = tag.validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+// Consenter reports which categories of Tags the user making the request has
+// consented to.
+type Consenter interface {
+	// HasConsent returns true if the user making the request the
+	// context.Context belongs to has consented to Tags in the category.
+	HasConsent(ctx context.Context, category string) bool
+}
+
+// ConsenterFunc is a function that fulfills the Consenter interface.
+type ConsenterFunc func(ctx context.Context, category string) bool
+
+// HasConsent calls the ConsenterFunc.
+func (fn ConsenterFunc) HasConsent(ctx context.Context, category string) bool {
+	return fn(ctx, category)
+}
+
+var (
+	_ temple.Component        = Container{}
+	_ temple.JSResourceLinker = Container{}
+)
+
+// Container is a Component that links to the Tags the user has consented
+// to. It has no templates of its own; the Tags are loaded wherever the page
+// renders .LinkedJS and .IdleJSLoader.
+type Container struct {
+	// Tags are the Tags the Site is configured to use.
+	Tags []Tag
+
+	// Consent decides which Tags the user has consented to. If nil, only
+	// Tags without a Consent are loaded.
+	Consent Consenter
+}
+
+// Templates returns nil, as the Container doesn't need any templates.
+func (Container) Templates(_ context.Context) []string {
+	return nil
+}
+
+// LinkJSResources returns the Tags the user has consented to, loaded using
+// their Strategy. Tags that aren't valid are left out.
+func (c Container) LinkJSResources(ctx context.Context) []temple.JSLink {
+	var links []temple.JSLink
+	for _, tag := range c.Tags {
+		if tag.validate() != nil {
+			continue
+		}
+		if tag.Consent != "" && (c.Consent == nil || !c.Consent.HasConsent(ctx, tag.Consent)) {
+			continue
+		}
+		links = append(links, temple.JSLink{URL: tag.URL, Strategy: tag.Strategy})
+	}
+	return links
+}
+
+// AllowInPolicy adds the origins of every valid Tag to the script-src
+// directive of the policy, whether the user has consented to them or not, so
+// the policy doesn't change with the user's consent. The Tags themselves may
+// load more resources, which need to be allowed separately. If policy is nil,
+// a new ContentSecurityPolicy is returned.
+func (c Container) AllowInPolicy(policy temple.ContentSecurityPolicy) temple.ContentSecurityPolicy {
+	var origins []string
+	for _, tag := range c.Tags {
+		if tag.validate() != nil {
+			continue
+		}
+		origins = append(origins, tag.origin())
+	}
+	if policy == nil {
+		policy = temple.ContentSecurityPolicy{}
+	}
+	return policy.Add("script-src", origins...)
+}
@@ -0,0 +1,102 @@
+package temple
+
+import (
+	"context"
+	"io/fs"
+	"maps"
+	"slices"
+)
+
+// TemplateUser is a Component that needs a template, found by IndexTemplates.
+type TemplateUser struct {
+	// Page is the page that uses the Component.
+	Page Renderable
+
+	// Key is the output of the page's Key method, which is what its
+	// templates are cached under by TemplateCachers.
+	Key string
+
+	// Component is the Component whose Templates method returned the
+	// template, which may be the page itself.
+	Component Component
+}
+
+// TemplateIndex maps template paths to the pages and Components that use
+// them, so tools can answer questions like "what breaks if I edit
+// nav.html.tmpl?", and caches can be invalidated only for the pages a changed
+// template affects. It's built by IndexTemplates.
+type TemplateIndex struct {
+	users map[string][]TemplateUser
+}
+
+// IndexTemplates builds a TemplateIndex of every template used by the passed
+// pages and the Components they use. Template paths that are patterns are
+// expanded to the files they match, so the TemplateIndex can be searched by
+// file. Templates are indexed by their path within the fs.FS they're read
+// from, whether that's the Site's TemplateDir or a Component's.
+//
+// It returns an error if a page's Components can't be resolved, or if one of
+// their template patterns is malformed. Patterns that don't match any files
+// are skipped, as they'd fail to render anyway.
+func IndexTemplates[SiteType Site](ctx context.Context, site SiteType, pages ...Renderable) (TemplateIndex, error) {
+	index := TemplateIndex{users: map[string][]TemplateUser{}}
+	for _, page := range pages {
+		components, err := getRecursiveComponents(ctx, page)
+		if err != nil {
+			return TemplateIndex{}, err
+		}
+		key := page.Key(ctx)
+		for _, comp := range components {
+			var dir fs.FS
+			if provider, ok := comp.(TemplateDirProvider); ok {
+				dir = provider.TemplateDir(ctx)
+			} else {
+				dir = site.TemplateDir(ctx)
+			}
+			for _, pattern := range comp.Templates(ctx) {
+				files, err := fs.Glob(dir, pattern)
+				if err != nil {
+					return TemplateIndex{}, TemplateParseError{Path: pattern, Component: comp, Err: err}
+				}
+				for _, file := range files {
+					index.users[file] = append(index.users[file], TemplateUser{
+						Page:      page,
+						Key:       key,
+						Component: comp,
+					})
+				}
+			}
+		}
+	}
+	return index, nil
+}
+
+// Templates returns the path of every template in the TemplateIndex, sorted
+// alphabetically.
+func (t TemplateIndex) Templates() []string {
+	return slices.Sorted(maps.Keys(t.users))
+}
+
+// Users returns every Component that uses the template at path, along with
+// the page that uses it, in the order the pages were passed to
+// IndexTemplates.
+func (t TemplateIndex) Users(path string) []TemplateUser {
+	return slices.Clone(t.users[path])
+}
+
+// Keys returns the Keys of the pages that use the template at path, without
+// duplicates, sorted alphabetically. These are the keys whose cached
+// templates need to be invalidated when the template changes.
+func (t TemplateIndex) Keys(path string) []string {
+	seen := map[string]struct{}{}
+	var keys []string
+	for _, user := range t.users[path] {
+		if _, ok := seen[user.Key]; ok {
+			continue
+		}
+		seen[user.Key] = struct{}{}
+		keys = append(keys, user.Key)
+	}
+	slices.Sort(keys)
+	return keys
+}
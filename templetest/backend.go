@@ -0,0 +1,153 @@
+package templetest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"impractical.co/temple/cache"
+)
+
+// BackendFactory returns a new, empty instance of the cache.Backend being
+// tested. It's called at the start of each test, so tests don't share stored
+// values.
+type BackendFactory func(t *testing.T) cache.Backend
+
+// RunBackendTests runs a suite of tests against the cache.Backends returned
+// by factory, checking that they return cache.ErrNotFound for missing and
+// expired values, store copies of values rather than the slices passed to
+// them, keep keys isolated from each other, delete values without erroring on
+// missing keys, and are safe to use from multiple goroutines.
+//
+// Checking expiry takes a little over a second, so Backends that only
+// support expiring values with a granularity of seconds can be tested.
+func RunBackendTests(t *testing.T, factory BackendFactory) {
+	t.Helper()
+	ctx := context.Background()
+
+	t.Run("miss", func(t *testing.T) {
+		backend := factory(t)
+		_, err := backend.Get(ctx, "missing")
+		if !errors.Is(err, cache.ErrNotFound) {
+			t.Errorf("expected cache.ErrNotFound for a key that was never set, got %v", err)
+		}
+	})
+
+	t.Run("roundtrip", func(t *testing.T) {
+		backend := factory(t)
+		setValue(t, backend, "a", "first", 0)
+		checkValue(t, backend, "a", "first")
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		backend := factory(t)
+		setValue(t, backend, "a", "first", 0)
+		setValue(t, backend, "a", "second", 0)
+		checkValue(t, backend, "a", "second")
+	})
+
+	t.Run("isolation", func(t *testing.T) {
+		backend := factory(t)
+		setValue(t, backend, "a", "first", 0)
+		_, err := backend.Get(ctx, "b")
+		if !errors.Is(err, cache.ErrNotFound) {
+			t.Errorf("expected cache.ErrNotFound for key %q after setting key %q, got %v", "b", "a", err)
+		}
+		setValue(t, backend, "b", "second", 0)
+		checkValue(t, backend, "a", "first")
+		checkValue(t, backend, "b", "second")
+	})
+
+	t.Run("copies", func(t *testing.T) {
+		backend := factory(t)
+		value := []byte("first")
+		err := backend.Set(ctx, "a", value, 0)
+		if err != nil {
+			t.Fatalf("error setting key %q: %s", "a", err)
+		}
+		copy(value, "xxxxx")
+		got, err := backend.Get(ctx, "a")
+		if err != nil {
+			t.Fatalf("error getting key %q: %s", "a", err)
+		}
+		copy(got, "yyyyy")
+		checkValue(t, backend, "a", "first")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		backend := factory(t)
+		setValue(t, backend, "a", "first", 0)
+		setValue(t, backend, "b", "second", 0)
+		err := backend.Delete(ctx, "a")
+		if err != nil {
+			t.Errorf("error deleting key %q: %s", "a", err)
+		}
+		_, err = backend.Get(ctx, "a")
+		if !errors.Is(err, cache.ErrNotFound) {
+			t.Errorf("expected cache.ErrNotFound for deleted key %q, got %v", "a", err)
+		}
+		checkValue(t, backend, "b", "second")
+		err = backend.Delete(ctx, "missing")
+		if err != nil {
+			t.Errorf("expected no error deleting a key that was never set, got %s", err)
+		}
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		backend := factory(t)
+		setValue(t, backend, "a", "first", time.Second)
+		setValue(t, backend, "b", "second", time.Hour)
+		checkValue(t, backend, "a", "first")
+		time.Sleep(1500 * time.Millisecond)
+		_, err := backend.Get(ctx, "a")
+		if !errors.Is(err, cache.ErrNotFound) {
+			t.Errorf("expected cache.ErrNotFound for expired key %q, got %v", "a", err)
+		}
+		checkValue(t, backend, "b", "second")
+	})
+
+	t.Run("concurrency", func(t *testing.T) {
+		backend := factory(t)
+		var wg sync.WaitGroup
+		for i := range concurrency {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				key := fmt.Sprintf("key-%d", i)
+				setValue(t, backend, key, key, 0)
+				setValue(t, backend, "shared", "shared", 0)
+				checkValue(t, backend, key, key)
+				checkValue(t, backend, "shared", "shared")
+				err := backend.Delete(ctx, key)
+				if err != nil {
+					t.Errorf("error deleting key %q: %s", key, err)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func setValue(t *testing.T, backend cache.Backend, key, value string, ttl time.Duration) {
+	t.Helper()
+	err := backend.Set(context.Background(), key, []byte(value), ttl)
+	if err != nil {
+		t.Errorf("error setting key %q: %s", key, err)
+	}
+}
+
+func checkValue(t *testing.T, backend cache.Backend, key, want string) {
+	t.Helper()
+	got, err := backend.Get(context.Background(), key)
+	if err != nil {
+		t.Errorf("error getting key %q: %s", key, err)
+		return
+	}
+	if !bytes.Equal(got, []byte(want)) {
+		t.Errorf("expected %q for key %q, got %q", want, key, got)
+	}
+}
@@ -0,0 +1,121 @@
+package templetest
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode"
+
+	"impractical.co/temple"
+)
+
+// updateGolden is the flag that makes AssertGolden and AssertGoldenFile
+// write the output they're passed to the golden files, instead of comparing
+// it to them. It's named so it won't conflict with a package's own -update
+// flag.
+var updateGolden = flag.Bool("update-golden", false, "update the golden files used by templetest.AssertGolden")
+
+// RenderToString renders the page using the Site, returning the output. The
+// test fails immediately if the page can't be rendered, instead of comparing
+// the error page against what was expected.
+func RenderToString[SiteType temple.Site, PageType temple.Renderable](t testing.TB, site SiteType, page PageType, opts ...temple.RenderOption) string {
+	t.Helper()
+	var out strings.Builder
+	result := temple.Render(context.Background(), &out, site, page, opts...)
+	if result.Err != nil {
+		t.Fatalf("error rendering %T: %s", page, result.Err)
+	}
+	return out.String()
+}
+
+// AssertGolden compares got to the contents of testdata/<name>.golden, as
+// AssertGoldenFile does.
+func AssertGolden(t testing.TB, name, got string) {
+	t.Helper()
+	AssertGoldenFile(t, filepath.Join("testdata", name+".golden"), got)
+}
+
+// AssertGoldenFile compares got to the contents of the golden file at path,
+// failing the test if they're different once their whitespace has been
+// normalized with NormalizeWhitespace. If the tests are run with
+// -update-golden, got is written to the file instead, creating it if
+// necessary:
+//
+//	go test ./... -update-golden
+//
+// Golden files should be checked in, and any changes to them reviewed like
+// any other change.
+func AssertGoldenFile(t testing.TB, path, got string) {
+	t.Helper()
+	if *updateGolden {
+		err := os.MkdirAll(filepath.Dir(path), 0o750)
+		if err != nil {
+			t.Fatalf("error creating directory for golden file %s: %s", path, err)
+		}
+		err = os.WriteFile(path, []byte(got), 0o600)
+		if err != nil {
+			t.Fatalf("error updating golden file %s: %s", path, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path) // #nosec G304
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("golden file %s doesn't exist, run the tests with -update-golden to create it", path)
+	}
+	if err != nil {
+		t.Fatalf("error reading golden file %s: %s", path, err)
+	}
+	normalizedGot, normalizedWant := NormalizeWhitespace(got), NormalizeWhitespace(string(want))
+	if normalizedGot == normalizedWant {
+		return
+	}
+	t.Errorf("output doesn't match golden file %s, run the tests with -update-golden to update it if the change is expected\n%s",
+		path, describeDifference(normalizedWant, normalizedGot))
+}
+
+// NormalizeWhitespace makes insignificant differences in whitespace in HTML
+// disappear, so they don't fail golden file comparisons: runs of whitespace
+// become a single space, and whitespace between tags and at the start and end
+// is removed. It doesn't know which elements preserve whitespace, like
+// <pre>, so differences in their whitespace are ignored too.
+func NormalizeWhitespace(s string) string {
+	var out strings.Builder
+	space := false
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			space = true
+			continue
+		}
+		if space && out.Len() > 0 {
+			out.WriteByte(' ')
+		}
+		space = false
+		out.WriteRune(r)
+	}
+	return strings.ReplaceAll(out.String(), "> <", "><")
+}
+
+// describeDifference shows where got first differs from want, with some
+// context either side.
+func describeDifference(want, got string) string {
+	const context = 40
+	pos := 0
+	for pos < len(want) && pos < len(got) && want[pos] == got[pos] {
+		pos++
+	}
+	start := max(pos-context, 0)
+	excerpt := func(s string) string {
+		end := min(pos+context, len(s))
+		if start >= len(s) {
+			return ""
+		}
+		return s[start:end]
+	}
+	return fmt.Sprintf("first difference at offset %d:\nwant: …%s…\n got: …%s…", pos, excerpt(want), excerpt(got))
+}
@@ -0,0 +1,280 @@
+package templetest
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	xhtml "golang.org/x/net/html"
+)
+
+var (
+	// ErrInvalidSelector is returned when a selector can't be parsed.
+	ErrInvalidSelector = errors.New("invalid selector")
+)
+
+// QueryAll parses the HTML document and returns the elements matching the
+// selector, in document order. The test fails immediately if the document or
+// selector can't be parsed.
+//
+// Selectors are a small subset of CSS selectors: compound selectors made of
+// an optional element name followed by any number of #id, .class, [attr],
+// and [attr=value] parts, which can be separated by whitespace to match
+// descendants. For example:
+//
+//	head link[rel=stylesheet]
+//	main article.post #comments
+//	script[type="module"]
+func QueryAll(t testing.TB, doc, selector string) []*xhtml.Node {
+	t.Helper()
+	sel, err := parseSelector(selector)
+	if err != nil {
+		t.Fatalf("error parsing selector %q: %s", selector, err)
+	}
+	root, err := xhtml.Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("error parsing HTML: %s", err)
+	}
+	var matches []*xhtml.Node
+	for node := range root.Descendants() {
+		if sel.matches(node) {
+			matches = append(matches, node)
+		}
+	}
+	return matches
+}
+
+// AssertElement fails the test if no element in the HTML document matches the
+// selector. See QueryAll for the selectors that are supported.
+func AssertElement(t testing.TB, doc, selector string) {
+	t.Helper()
+	if len(QueryAll(t, doc, selector)) < 1 {
+		t.Errorf("no element matches %q", selector)
+	}
+}
+
+// AssertNoElement fails the test if any element in the HTML document matches
+// the selector. See QueryAll for the selectors that are supported.
+func AssertNoElement(t testing.TB, doc, selector string) {
+	t.Helper()
+	if matches := QueryAll(t, doc, selector); len(matches) > 0 {
+		t.Errorf("expected no elements to match %q, %d did", selector, len(matches))
+	}
+}
+
+// AssertAttr fails the test unless the first element in the HTML document
+// matching the selector has the attribute, set to want. See QueryAll for the
+// selectors that are supported.
+func AssertAttr(t testing.TB, doc, selector, attr, want string) {
+	t.Helper()
+	matches := QueryAll(t, doc, selector)
+	if len(matches) < 1 {
+		t.Errorf("no element matches %q", selector)
+		return
+	}
+	got, ok := nodeAttr(matches[0], attr)
+	if !ok {
+		t.Errorf("element matching %q has no %s attribute, expected %q", selector, attr, want)
+		return
+	}
+	if got != want {
+		t.Errorf("expected %s attribute of element matching %q to be %q, got %q", attr, selector, want, got)
+	}
+}
+
+// AssertOrder fails the test unless the want values all appear as the attr
+// attribute of elements matching the selector, in the order they're passed.
+// Other matching elements may appear before, after, or between them.
+//
+// It's for testing resource ordering guarantees, like the order stylesheets
+// are linked in, without depending on the rest of the page's output:
+//
+//	templetest.AssertOrder(t, got, "link[rel=stylesheet]", "href", "/css/base.css", "/css/theme.css")
+func AssertOrder(t testing.TB, doc, selector, attr string, want ...string) {
+	t.Helper()
+	var got []string
+	for _, node := range QueryAll(t, doc, selector) {
+		if val, ok := nodeAttr(node, attr); ok {
+			got = append(got, val)
+		}
+	}
+	pos := 0
+	for _, val := range got {
+		if pos < len(want) && val == want[pos] {
+			pos++
+		}
+	}
+	if pos < len(want) {
+		t.Errorf("expected %s attributes of elements matching %q to include %q in order, got %q", attr, selector, want, got)
+	}
+}
+
+// AssertScriptOrder fails the test unless <script> elements with each of the
+// srcs appear in the HTML document, in the order they're passed, as
+// AssertOrder does.
+func AssertScriptOrder(t testing.TB, doc string, srcs ...string) {
+	t.Helper()
+	AssertOrder(t, doc, "script[src]", "src", srcs...)
+}
+
+// nodeAttr returns the value of the attribute of the node with the key, and
+// whether it was set.
+func nodeAttr(node *xhtml.Node, key string) (string, bool) {
+	for _, attr := range node.Attr {
+		if attr.Namespace == "" && attr.Key == key {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+// selector is a parsed selector: a list of compound selectors, each of which
+// must match an ancestor of the element matching the next.
+type selector []compoundSelector
+
+// compoundSelector matches a single element.
+type compoundSelector struct {
+	tag     string
+	id      string
+	classes []string
+	attrs   []attrSelector
+}
+
+// attrSelector matches elements with an attribute, optionally set to a
+// specific value.
+type attrSelector struct {
+	key      string
+	val      string
+	hasValue bool
+}
+
+// matches returns whether the node matches the selector.
+func (sel selector) matches(node *xhtml.Node) bool {
+	if len(sel) < 1 || !sel[len(sel)-1].matches(node) {
+		return false
+	}
+	remaining := sel[:len(sel)-1]
+	for ancestor := node.Parent; ancestor != nil && len(remaining) > 0; ancestor = ancestor.Parent {
+		if remaining[len(remaining)-1].matches(ancestor) {
+			remaining = remaining[:len(remaining)-1]
+		}
+	}
+	return len(remaining) == 0
+}
+
+// matches returns whether the node matches the compound selector.
+func (sel compoundSelector) matches(node *xhtml.Node) bool {
+	if node.Type != xhtml.ElementNode {
+		return false
+	}
+	if sel.tag != "" && sel.tag != node.Data {
+		return false
+	}
+	if sel.id != "" {
+		if id, _ := nodeAttr(node, "id"); id != sel.id {
+			return false
+		}
+	}
+	if len(sel.classes) > 0 {
+		class, _ := nodeAttr(node, "class")
+		classes := strings.Fields(class)
+		for _, want := range sel.classes {
+			if !slices.Contains(classes, want) {
+				return false
+			}
+		}
+	}
+	for _, attr := range sel.attrs {
+		val, ok := nodeAttr(node, attr.key)
+		if !ok || (attr.hasValue && val != attr.val) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseSelector parses the selector syntax described on QueryAll.
+func parseSelector(s string) (selector, error) {
+	var sel selector
+	rest := strings.TrimSpace(s)
+	for rest != "" {
+		compound, remaining, err := parseCompoundSelector(rest)
+		if err != nil {
+			return nil, err
+		}
+		sel = append(sel, compound)
+		rest = strings.TrimLeft(remaining, " \t\n")
+	}
+	if len(sel) < 1 {
+		return nil, fmt.Errorf("%w: empty selector", ErrInvalidSelector)
+	}
+	return sel, nil
+}
+
+// parseCompoundSelector parses a compound selector from the start of s,
+// returning it and the rest of s.
+func parseCompoundSelector(s string) (compoundSelector, string, error) {
+	var sel compoundSelector
+	sel.tag, s = selectorName(s)
+	sel.tag = strings.ToLower(sel.tag)
+	for s != "" && !strings.ContainsRune(" \t\n", rune(s[0])) {
+		var name string
+		switch s[0] {
+		case '#':
+			name, s = selectorName(s[1:])
+			if name == "" {
+				return sel, "", fmt.Errorf("%w: # must be followed by an id", ErrInvalidSelector)
+			}
+			sel.id = name
+		case '.':
+			name, s = selectorName(s[1:])
+			if name == "" {
+				return sel, "", fmt.Errorf("%w: . must be followed by a class", ErrInvalidSelector)
+			}
+			sel.classes = append(sel.classes, name)
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return sel, "", fmt.Errorf("%w: unclosed [", ErrInvalidSelector)
+			}
+			attr, err := parseAttrSelector(s[1:end])
+			if err != nil {
+				return sel, "", err
+			}
+			sel.attrs = append(sel.attrs, attr)
+			s = s[end+1:]
+		default:
+			return sel, "", fmt.Errorf("%w: unexpected %q", ErrInvalidSelector, s[0])
+		}
+	}
+	return sel, s, nil
+}
+
+// parseAttrSelector parses the contents of an [attr] or [attr=value]
+// selector. Values may be quoted.
+func parseAttrSelector(s string) (attrSelector, error) {
+	key, val, hasValue := strings.Cut(s, "=")
+	key = strings.ToLower(strings.TrimSpace(key))
+	if key == "" {
+		return attrSelector{}, fmt.Errorf("%w: [] must contain an attribute name", ErrInvalidSelector)
+	}
+	val = strings.TrimSpace(val)
+	if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+		val = val[1 : len(val)-1]
+	}
+	return attrSelector{key: key, val: val, hasValue: hasValue}, nil
+}
+
+// selectorName returns the name at the start of s, made of letters, digits,
+// hyphens, and underscores, and the rest of s.
+func selectorName(s string) (string, string) {
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return !(r == '-' || r == '_' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'))
+	})
+	if end < 0 {
+		return s, ""
+	}
+	return s[:end], s[end:]
+}
@@ -0,0 +1,180 @@
+package templetest
+
+import (
+	"context"
+	"html/template"
+	"io/fs"
+	"sync"
+
+	"impractical.co/temple"
+)
+
+var (
+	_ temple.Site           = &Site{}
+	_ temple.TemplateCacher = &Site{}
+	_ temple.GraphCacher    = &Site{}
+)
+
+// CacheCounts records how a cache was used for a key.
+type CacheCounts struct {
+	// Gets is how many times the cache was checked for the key.
+	Gets int
+
+	// Hits is how many of those checks found a cached value.
+	Hits int
+
+	// Sets is how many times a value was cached for the key.
+	Sets int
+}
+
+// Site is a temple.Site for tests, which records how its caches and
+// templates are used so tests can make assertions about caching behavior,
+// like that a page's templates are only parsed once no matter how many
+// times it's rendered:
+//
+//	site := templetest.NewSite(templates)
+//	for range 10 {
+//		templetest.RenderToString(t, site, HomePage{})
+//	}
+//	if parses := site.Parses(HomePage{}.Key(ctx)); parses != 1 {
+//		t.Errorf("expected templates to be parsed once, got %d", parses)
+//	}
+//
+// It caches templates and ComponentGraphs in memory, using a
+// temple.CachedSite and a temple.GraphCache, and supports the same
+// SiteOptions. A Site must be instantiated through NewSite, its empty value is
+// not usable. It can safely be used by multiple goroutines.
+type Site struct {
+	*temple.CachedSite
+	*temple.GraphCache
+
+	mu        sync.Mutex
+	templates map[string]CacheCounts
+	graphs    map[string]CacheCounts
+	reads     map[string]int
+}
+
+// NewSite returns a Site serving the templates, configured by any
+// SiteOptions passed.
+func NewSite(templates fs.FS, opts ...temple.SiteOption) *Site {
+	site := &Site{
+		GraphCache: temple.NewGraphCache(),
+		templates:  map[string]CacheCounts{},
+		graphs:     map[string]CacheCounts{},
+		reads:      map[string]int{},
+	}
+	site.CachedSite = temple.NewCachedSite(countingFS{FS: templates, site: site}, opts...)
+	return site
+}
+
+// GetCachedTemplate returns the template cached for the key, as
+// temple.CachedSite does, and records the lookup.
+func (s *Site) GetCachedTemplate(ctx context.Context, key string) *template.Template {
+	tmpl := s.CachedSite.GetCachedTemplate(ctx, key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts := s.templates[key]
+	counts.Gets++
+	if tmpl != nil {
+		counts.Hits++
+	}
+	s.templates[key] = counts
+	return tmpl
+}
+
+// SetCachedTemplate caches the template for the key, as temple.CachedSite
+// does, and records that it was parsed.
+func (s *Site) SetCachedTemplate(ctx context.Context, key string, tmpl *template.Template) {
+	s.CachedSite.SetCachedTemplate(ctx, key, tmpl)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts := s.templates[key]
+	counts.Sets++
+	s.templates[key] = counts
+}
+
+// GetCachedGraph returns the ComponentGraph cached for the key, as
+// temple.GraphCache does, and records the lookup.
+func (s *Site) GetCachedGraph(ctx context.Context, key string) *temple.ComponentGraph {
+	graph := s.GraphCache.GetCachedGraph(ctx, key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts := s.graphs[key]
+	counts.Gets++
+	if graph != nil {
+		counts.Hits++
+	}
+	s.graphs[key] = counts
+	return graph
+}
+
+// SetCachedGraph caches the ComponentGraph for the key, as temple.GraphCache
+// does, and records that it was resolved.
+func (s *Site) SetCachedGraph(ctx context.Context, key string, graph *temple.ComponentGraph) {
+	s.GraphCache.SetCachedGraph(ctx, key, graph)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts := s.graphs[key]
+	counts.Sets++
+	s.graphs[key] = counts
+}
+
+// TemplateCounts returns how the template cache has been used for the key.
+// Keys are the output of the page's Key method, with the theme appended if
+// the Site implements temple.Themer.
+func (s *Site) TemplateCounts(key string) CacheCounts {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.templates[key]
+}
+
+// GraphCounts returns how the ComponentGraph cache has been used for the
+// key, which is the same as the key used for TemplateCounts.
+func (s *Site) GraphCounts(key string) CacheCounts {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.graphs[key]
+}
+
+// Parses returns how many times the templates for the key have been parsed.
+// temple caches templates after every parse, so it's the same as the Sets
+// field of TemplateCounts, even when the Site was configured with
+// temple.WithDevelopment and doesn't keep them.
+func (s *Site) Parses(key string) int {
+	return s.TemplateCounts(key).Sets
+}
+
+// Reads returns how many times the file or directory at path has been
+// opened in the templates the Site was created with. Parsing a template may
+// open its file more than once, as matching it against the Component's
+// template patterns opens it too, so tests should usually compare Reads
+// before and after something rather than expect an exact number.
+func (s *Site) Reads(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reads[path]
+}
+
+// Reset forgets everything the Site has recorded, without clearing its
+// caches, so a test can make assertions about only what happens next.
+func (s *Site) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.templates = map[string]CacheCounts{}
+	s.graphs = map[string]CacheCounts{}
+	s.reads = map[string]int{}
+}
+
+// countingFS is an fs.FS that records the paths opened in it on a Site.
+type countingFS struct {
+	fs.FS
+	site *Site
+}
+
+// Open opens the file in the wrapped fs.FS, recording the path.
+func (c countingFS) Open(name string) (fs.File, error) {
+	c.site.mu.Lock()
+	c.site.reads[name]++
+	c.site.mu.Unlock()
+	return c.FS.Open(name)
+}
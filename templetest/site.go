@@ -0,0 +1,246 @@
+package templetest
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"strings"
+	"sync"
+	"testing"
+
+	"impractical.co/temple"
+)
+
+// SiteFactory returns a new, empty instance of the Site being tested. It's
+// called at the start of each test, so tests don't share cached state.
+type SiteFactory func(t *testing.T) temple.Site
+
+// RunSiteTests runs a suite of tests against the Sites returned by factory,
+// checking that they implement the optional interfaces temple looks for
+// correctly. Each interface is only tested if the Site implements it:
+//
+//   - TemplateCachers must return nil for keys that haven't been cached,
+//     return the template most recently cached for a key, keep keys
+//     isolated from each other, and be safe to use from multiple
+//     goroutines.
+//   - TemplateCacheInvalidators must remove only the keys being
+//     invalidated.
+//   - GraphCachers and GraphCacheInvalidators are held to the same
+//     standards as their template equivalents.
+//
+// Sites that intentionally don't cache anything, like those using
+// temple.WithDevelopment, will fail the TemplateCacher tests.
+func RunSiteTests(t *testing.T, factory SiteFactory) {
+	t.Helper()
+
+	t.Run("TemplateDir", func(t *testing.T) {
+		if factory(t).TemplateDir(context.Background()) == nil {
+			t.Error("TemplateDir returned nil")
+		}
+	})
+
+	if _, ok := factory(t).(temple.TemplateCacher); ok {
+		t.Run("TemplateCacher", func(t *testing.T) {
+			runTemplateCacherTests(t, func(t *testing.T) temple.TemplateCacher {
+				cacher, ok := factory(t).(temple.TemplateCacher)
+				if !ok {
+					t.Fatal("factory returned a Site that isn't a TemplateCacher")
+				}
+				return cacher
+			})
+		})
+	}
+
+	if _, ok := factory(t).(temple.GraphCacher); ok {
+		t.Run("GraphCacher", func(t *testing.T) {
+			runGraphCacherTests(t, func(t *testing.T) temple.GraphCacher {
+				cacher, ok := factory(t).(temple.GraphCacher)
+				if !ok {
+					t.Fatal("factory returned a Site that isn't a GraphCacher")
+				}
+				return cacher
+			})
+		})
+	}
+}
+
+// testTemplate returns a template that outputs its name, so templates
+// retrieved from a cache can be told apart even if the cache doesn't preserve
+// their identity.
+func testTemplate(t *testing.T, name string) *template.Template {
+	t.Helper()
+	tmpl, err := template.New(name).Parse(name)
+	if err != nil {
+		t.Fatalf("error parsing test template %q: %s", name, err)
+	}
+	return tmpl
+}
+
+// checkTemplate reports an error if tmpl isn't a template created by
+// testTemplate with the name want.
+func checkTemplate(t *testing.T, key string, tmpl *template.Template, want string) {
+	t.Helper()
+	if tmpl == nil {
+		t.Errorf("expected template %q for key %q, got nil", want, key)
+		return
+	}
+	var out strings.Builder
+	err := tmpl.Execute(&out, nil)
+	if err != nil {
+		t.Errorf("error executing template cached for key %q: %s", key, err)
+		return
+	}
+	if out.String() != want {
+		t.Errorf("expected template %q for key %q, got %q", want, key, out.String())
+	}
+}
+
+func runTemplateCacherTests(t *testing.T, factory func(t *testing.T) temple.TemplateCacher) {
+	ctx := context.Background()
+
+	t.Run("miss", func(t *testing.T) {
+		cacher := factory(t)
+		if tmpl := cacher.GetCachedTemplate(ctx, "missing"); tmpl != nil {
+			t.Errorf("expected nil for a key that was never cached, got %v", tmpl.Name())
+		}
+	})
+
+	t.Run("roundtrip", func(t *testing.T) {
+		cacher := factory(t)
+		cacher.SetCachedTemplate(ctx, "a", testTemplate(t, "first"))
+		checkTemplate(t, "a", cacher.GetCachedTemplate(ctx, "a"), "first")
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		cacher := factory(t)
+		cacher.SetCachedTemplate(ctx, "a", testTemplate(t, "first"))
+		cacher.SetCachedTemplate(ctx, "a", testTemplate(t, "second"))
+		checkTemplate(t, "a", cacher.GetCachedTemplate(ctx, "a"), "second")
+	})
+
+	t.Run("isolation", func(t *testing.T) {
+		cacher := factory(t)
+		cacher.SetCachedTemplate(ctx, "a", testTemplate(t, "first"))
+		if tmpl := cacher.GetCachedTemplate(ctx, "b"); tmpl != nil {
+			t.Errorf("expected nil for key %q after caching key %q, got %v", "b", "a", tmpl.Name())
+		}
+		cacher.SetCachedTemplate(ctx, "b", testTemplate(t, "second"))
+		checkTemplate(t, "a", cacher.GetCachedTemplate(ctx, "a"), "first")
+		checkTemplate(t, "b", cacher.GetCachedTemplate(ctx, "b"), "second")
+	})
+
+	t.Run("concurrency", func(t *testing.T) {
+		cacher := factory(t)
+		var wg sync.WaitGroup
+		for i := range concurrency {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				key := fmt.Sprintf("key-%d", i)
+				cacher.SetCachedTemplate(ctx, key, testTemplate(t, key))
+				cacher.SetCachedTemplate(ctx, "shared", testTemplate(t, "shared"))
+				checkTemplate(t, key, cacher.GetCachedTemplate(ctx, key), key)
+				checkTemplate(t, "shared", cacher.GetCachedTemplate(ctx, "shared"), "shared")
+			}()
+		}
+		wg.Wait()
+	})
+
+	if _, ok := factory(t).(temple.TemplateCacheInvalidator); !ok {
+		return
+	}
+	t.Run("invalidate", func(t *testing.T) {
+		cacher := factory(t)
+		invalidator, ok := cacher.(temple.TemplateCacheInvalidator)
+		if !ok {
+			t.Fatal("factory returned a Site that isn't a TemplateCacheInvalidator")
+		}
+		cacher.SetCachedTemplate(ctx, "a", testTemplate(t, "first"))
+		cacher.SetCachedTemplate(ctx, "b", testTemplate(t, "second"))
+		invalidator.InvalidateCachedTemplates(ctx, "a", "missing")
+		if tmpl := cacher.GetCachedTemplate(ctx, "a"); tmpl != nil {
+			t.Errorf("expected nil for invalidated key %q, got %v", "a", tmpl.Name())
+		}
+		checkTemplate(t, "b", cacher.GetCachedTemplate(ctx, "b"), "second")
+	})
+}
+
+func runGraphCacherTests(t *testing.T, factory func(t *testing.T) temple.GraphCacher) {
+	ctx := context.Background()
+
+	t.Run("miss", func(t *testing.T) {
+		cacher := factory(t)
+		if graph := cacher.GetCachedGraph(ctx, "missing"); graph != nil {
+			t.Error("expected nil for a key that was never cached")
+		}
+	})
+
+	t.Run("isolation", func(t *testing.T) {
+		cacher := factory(t)
+		first, second := &temple.ComponentGraph{}, &temple.ComponentGraph{}
+		cacher.SetCachedGraph(ctx, "a", first)
+		if graph := cacher.GetCachedGraph(ctx, "b"); graph != nil {
+			t.Errorf("expected nil for key %q after caching key %q", "b", "a")
+		}
+		cacher.SetCachedGraph(ctx, "b", second)
+		if cacher.GetCachedGraph(ctx, "a") != first {
+			t.Errorf("expected the graph cached for key %q", "a")
+		}
+		if cacher.GetCachedGraph(ctx, "b") != second {
+			t.Errorf("expected the graph cached for key %q", "b")
+		}
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		cacher := factory(t)
+		second := &temple.ComponentGraph{}
+		cacher.SetCachedGraph(ctx, "a", &temple.ComponentGraph{})
+		cacher.SetCachedGraph(ctx, "a", second)
+		if cacher.GetCachedGraph(ctx, "a") != second {
+			t.Errorf("expected the graph most recently cached for key %q", "a")
+		}
+	})
+
+	t.Run("concurrency", func(t *testing.T) {
+		cacher := factory(t)
+		var wg sync.WaitGroup
+		for i := range concurrency {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				key := fmt.Sprintf("key-%d", i)
+				graph := &temple.ComponentGraph{}
+				cacher.SetCachedGraph(ctx, key, graph)
+				cacher.SetCachedGraph(ctx, "shared", &temple.ComponentGraph{})
+				if cacher.GetCachedGraph(ctx, key) != graph {
+					t.Errorf("expected the graph cached for key %q", key)
+				}
+				if cacher.GetCachedGraph(ctx, "shared") == nil {
+					t.Errorf("expected a graph for key %q", "shared")
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	if _, ok := factory(t).(temple.GraphCacheInvalidator); !ok {
+		return
+	}
+	t.Run("invalidate", func(t *testing.T) {
+		cacher := factory(t)
+		invalidator, ok := cacher.(temple.GraphCacheInvalidator)
+		if !ok {
+			t.Fatal("factory returned a Site that isn't a GraphCacheInvalidator")
+		}
+		second := &temple.ComponentGraph{}
+		cacher.SetCachedGraph(ctx, "a", &temple.ComponentGraph{})
+		cacher.SetCachedGraph(ctx, "b", second)
+		invalidator.InvalidateCachedGraphs(ctx, "a", "missing")
+		if cacher.GetCachedGraph(ctx, "a") != nil {
+			t.Errorf("expected nil for invalidated key %q", "a")
+		}
+		if cacher.GetCachedGraph(ctx, "b") != second {
+			t.Errorf("expected the graph cached for key %q", "b")
+		}
+	})
+}
@@ -0,0 +1,42 @@
+// Package templetest provides helpers for testing code built on temple.
+//
+// RunSiteTests and RunBackendTests are conformance suites for custom
+// implementations of temple's caching interfaces, like a TemplateCacher or
+// cache.Backend backed by a shared store. They check the semantics temple
+// relies on (what a miss looks like, that keys don't leak into each other,
+// and that the implementation is safe to use from multiple goroutines) and
+// are meant to be run from the implementation's own tests, ideally with the
+// race detector enabled:
+//
+//	func TestRedisSite(t *testing.T) {
+//		templetest.RunSiteTests(t, func(t *testing.T) temple.Site {
+//			return NewRedisSite(t, templates)
+//		})
+//	}
+//
+// RenderToString, AssertGolden, and AssertGoldenFile are for snapshot testing
+// pages: a page is rendered and compared to a golden file checked in
+// alongside the tests, ignoring insignificant differences in whitespace.
+// Running the tests with -update-golden rewrites the golden files with the
+// current output, so the changes can be reviewed in the diff:
+//
+//	func TestHomePage(t *testing.T) {
+//		got := templetest.RenderToString(t, site, HomePage{User: "Ada"})
+//		templetest.AssertGolden(t, "home_page", got)
+//	}
+//
+// AssertElement, AssertAttr, and AssertOrder make assertions about the
+// structure of rendered HTML, using a small subset of CSS selectors, so
+// guarantees like the order resources are included in can be tested without
+// matching the whole output:
+//
+//	templetest.AssertScriptOrder(t, got, "/js/runtime.js", "/js/app.js")
+//
+// Site is a temple.Site that records how its caches and templates are used,
+// for testing caching behavior, like how many times a page's templates are
+// parsed.
+package templetest
+
+// concurrency is how many goroutines the suites use when checking that
+// implementations are safe to use from multiple goroutines.
+const concurrency = 16
@@ -0,0 +1,305 @@
+package templetest_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"testing/fstest"
+
+	"impractical.co/temple"
+	"impractical.co/temple/cache"
+	"impractical.co/temple/templetest"
+)
+
+type graphCachedSite struct {
+	*temple.CachedSite
+	*temple.GraphCache
+}
+
+func TestCachedSite(t *testing.T) {
+	templetest.RunSiteTests(t, func(_ *testing.T) temple.Site {
+		return temple.NewCachedSite(fstest.MapFS{})
+	})
+}
+
+func TestGraphCache(t *testing.T) {
+	templetest.RunSiteTests(t, func(_ *testing.T) temple.Site {
+		return graphCachedSite{
+			CachedSite: temple.NewCachedSite(fstest.MapFS{}),
+			GraphCache: temple.NewGraphCache(),
+		}
+	})
+}
+
+func TestMemory(t *testing.T) {
+	templetest.RunBackendTests(t, func(_ *testing.T) cache.Backend {
+		return &cache.Memory{}
+	})
+}
+
+func TestDir(t *testing.T) {
+	templetest.RunBackendTests(t, func(t *testing.T) cache.Backend {
+		return cache.Dir{Path: t.TempDir()}
+	})
+}
+
+type goldenPage struct {
+	Name string
+}
+
+func (goldenPage) Templates(_ context.Context) []string {
+	return []string{"golden.html.tmpl"}
+}
+
+func (goldenPage) Key(_ context.Context) string {
+	return "golden.html.tmpl"
+}
+
+func (goldenPage) ExecutedTemplate(_ context.Context) string {
+	return "golden.html.tmpl"
+}
+
+// recordingTB is a testing.TB that records whether the test failed, instead
+// of failing the test it's part of.
+type recordingTB struct {
+	testing.TB
+	failed bool
+}
+
+func (*recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(_ string, _ ...any) {
+	r.failed = true
+}
+
+func (r *recordingTB) Fatalf(_ string, _ ...any) {
+	r.failed = true
+	runtime.Goexit()
+}
+
+// assertGoldenFile calls AssertGoldenFile with a recordingTB, returning
+// whether it failed.
+func assertGoldenFile(t *testing.T, path, got string) bool {
+	t.Helper()
+	return fails(t, func(tb testing.TB) {
+		templetest.AssertGoldenFile(tb, path, got)
+	})
+}
+
+func TestAssertGoldenFile(t *testing.T) {
+	site := temple.NewCachedSite(fstest.MapFS{
+		"golden.html.tmpl": {Data: []byte("<main>\n\t<h1>Hello, {{ .Page.Name }}</h1>\n</main>\n")},
+	})
+	got := templetest.RenderToString(t, site, goldenPage{Name: "Ada"})
+
+	path := filepath.Join(t.TempDir(), "golden.html")
+	if !assertGoldenFile(t, path, got) {
+		t.Error("expected a missing golden file to fail")
+	}
+	err := os.WriteFile(path, []byte("<main><h1>Hello,   Ada</h1></main>"), 0o600)
+	if err != nil {
+		t.Fatalf("error writing golden file: %s", err)
+	}
+	if assertGoldenFile(t, path, got) {
+		t.Error("expected output differing only in whitespace to match")
+	}
+	if !assertGoldenFile(t, path, templetest.RenderToString(t, site, goldenPage{Name: "Grace"})) {
+		t.Error("expected different output to fail")
+	}
+}
+
+func TestNormalizeWhitespace(t *testing.T) {
+	t.Parallel()
+	cases := map[string]string{
+		"":                              "",
+		"  \n\t ":                       "",
+		"<p>a  b</p>":                   "<p>a b</p>",
+		"\n<ul>\n\t<li>a</li>\n</ul>\n": "<ul><li>a</li></ul>",
+		"<b>a</b> <i>b</i>":             "<b>a</b><i>b</i>",
+	}
+	for input, want := range cases {
+		if got := templetest.NormalizeWhitespace(input); got != want {
+			t.Errorf("NormalizeWhitespace(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+const structuredPage = `<!DOCTYPE html>
+<html>
+<head>
+	<link rel="stylesheet" href="/css/base.css">
+	<link rel="preload" href="/fonts/body.woff2">
+	<link rel="stylesheet" href="/css/theme.css">
+	<script src="/js/runtime.js"></script>
+</head>
+<body>
+	<main class="page home">
+		<h1 id="title" data-title="Hello, world">Hello</h1>
+	</main>
+	<script src="/js/analytics.js" async></script>
+	<script src="/js/app.js" type="module"></script>
+</body>
+</html>`
+
+func TestQueryAll(t *testing.T) {
+	t.Parallel()
+	cases := map[string]int{
+		"script":                         3,
+		"script[src]":                    3,
+		"script[async]":                  1,
+		"head script":                    1,
+		"body script[type=module]":       1,
+		`link[rel="stylesheet"]`:         2,
+		"html main.home #title":          1,
+		"main.home.page h1":              1,
+		"main.other h1":                  0,
+		"head h1":                        0,
+		`h1[data-title="Hello, world"]`:  1,
+		"#missing":                       0,
+		"body main h1[data-title]#title": 1,
+		"LINK[REL=preload]":              1,
+		"body   main":                    1,
+	}
+	for selector, want := range cases {
+		if got := templetest.QueryAll(t, structuredPage, selector); len(got) != want {
+			t.Errorf("expected %d elements to match %q, got %d", want, selector, len(got))
+		}
+	}
+}
+
+// fails calls fn with a recordingTB, returning whether it failed.
+func fails(t *testing.T, fn func(tb testing.TB)) bool {
+	t.Helper()
+	tb := &recordingTB{TB: t}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(tb)
+	}()
+	<-done
+	return tb.failed
+}
+
+func TestStructuralAssertions(t *testing.T) {
+	t.Parallel()
+	cases := map[string]struct {
+		fn       func(tb testing.TB)
+		wantFail bool
+	}{
+		"element": {
+			fn: func(tb testing.TB) { templetest.AssertElement(tb, structuredPage, "main h1#title") },
+		},
+		"missing-element": {
+			fn:       func(tb testing.TB) { templetest.AssertElement(tb, structuredPage, "main h2") },
+			wantFail: true,
+		},
+		"no-element": {
+			fn: func(tb testing.TB) { templetest.AssertNoElement(tb, structuredPage, "head h1") },
+		},
+		"unexpected-element": {
+			fn:       func(tb testing.TB) { templetest.AssertNoElement(tb, structuredPage, "body h1") },
+			wantFail: true,
+		},
+		"attr": {
+			fn: func(tb testing.TB) {
+				templetest.AssertAttr(tb, structuredPage, "script[async]", "src", "/js/analytics.js")
+			},
+		},
+		"wrong-attr": {
+			fn:       func(tb testing.TB) { templetest.AssertAttr(tb, structuredPage, "script[async]", "src", "/js/app.js") },
+			wantFail: true,
+		},
+		"missing-attr": {
+			fn:       func(tb testing.TB) { templetest.AssertAttr(tb, structuredPage, "h1", "class", "title") },
+			wantFail: true,
+		},
+		"script-order": {
+			fn: func(tb testing.TB) { templetest.AssertScriptOrder(tb, structuredPage, "/js/runtime.js", "/js/app.js") },
+		},
+		"wrong-script-order": {
+			fn:       func(tb testing.TB) { templetest.AssertScriptOrder(tb, structuredPage, "/js/app.js", "/js/runtime.js") },
+			wantFail: true,
+		},
+		"missing-script": {
+			fn: func(tb testing.TB) {
+				templetest.AssertScriptOrder(tb, structuredPage, "/js/runtime.js", "/js/missing.js")
+			},
+			wantFail: true,
+		},
+		"stylesheet-order": {
+			fn: func(tb testing.TB) {
+				templetest.AssertOrder(tb, structuredPage, "link[rel=stylesheet]", "href", "/css/base.css", "/css/theme.css")
+			},
+		},
+		"invalid-selector": {
+			fn:       func(tb testing.TB) { templetest.AssertElement(tb, structuredPage, "main[class") },
+			wantFail: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := fails(t, tc.fn); got != tc.wantFail {
+				t.Errorf("expected failure to be %v, got %v", tc.wantFail, got)
+			}
+		})
+	}
+}
+
+func TestInstrumentedSite(t *testing.T) {
+	templetest.RunSiteTests(t, func(_ *testing.T) temple.Site {
+		return templetest.NewSite(fstest.MapFS{})
+	})
+}
+
+func TestSiteCounts(t *testing.T) {
+	t.Parallel()
+	site := templetest.NewSite(fstest.MapFS{
+		"golden.html.tmpl": {Data: []byte("<h1>Hello, {{ .Page.Name }}</h1>")},
+	})
+	key := goldenPage{}.Key(context.Background())
+	templetest.RenderToString(t, site, goldenPage{Name: "Ada"})
+	reads := site.Reads("golden.html.tmpl")
+	if reads < 1 {
+		t.Error("expected template file to be read")
+	}
+	for range 4 {
+		templetest.RenderToString(t, site, goldenPage{Name: "Ada"})
+	}
+	if got := site.Parses(key); got != 1 {
+		t.Errorf("expected templates to be parsed once, got %d", got)
+	}
+	if got, want := site.TemplateCounts(key), (templetest.CacheCounts{Gets: 6, Hits: 4, Sets: 1}); got != want {
+		t.Errorf("expected template cache counts %+v, got %+v", want, got)
+	}
+	if got := site.GraphCounts(key); got.Sets != 1 || got.Hits != 4 {
+		t.Errorf("expected graph to be resolved once and reused 4 times, got %+v", got)
+	}
+	if got := site.Reads("golden.html.tmpl"); got != reads {
+		t.Errorf("expected cached renders not to read the template file, reads went from %d to %d", reads, got)
+	}
+
+	site.Reset()
+	if got := site.TemplateCounts(key); got != (templetest.CacheCounts{}) {
+		t.Errorf("expected counts to be reset, got %+v", got)
+	}
+	templetest.RenderToString(t, site, goldenPage{Name: "Grace"})
+	if got := site.TemplateCounts(key); got != (templetest.CacheCounts{Gets: 1, Hits: 1}) {
+		t.Errorf("expected cached templates to survive Reset, got %+v", got)
+	}
+}
+
+func TestSiteDevelopment(t *testing.T) {
+	t.Parallel()
+	site := templetest.NewSite(fstest.MapFS{
+		"golden.html.tmpl": {Data: []byte("<h1>Hello, {{ .Page.Name }}</h1>")},
+	}, temple.WithDevelopment())
+	for range 3 {
+		templetest.RenderToString(t, site, goldenPage{Name: "Ada"})
+	}
+	if got := site.Parses("golden.html.tmpl"); got != 3 {
+		t.Errorf("expected templates to be parsed on every render, got %d", got)
+	}
+}
@@ -0,0 +1,130 @@
+package temple
+
+import (
+	"html"
+	"html/template"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// Truncate shortens `s` to at most `n` user-perceived characters, replacing
+// the end with an ellipsis if anything was removed. The ellipsis counts
+// towards the `n` characters. It never splits a character made of multiple
+// code points, like an emoji with a skin tone modifier or a letter followed
+// by a combining accent, and it prefers to cut at a word boundary if there's
+// one in the last half of the result.
+//
+// Truncate works on plain text; truncating HTML, even if it's just escaped
+// text, can split entities or leave elements unclosed. Truncate text first,
+// and let html/template escape the result.
+func Truncate(s string, n int) string {
+	if n < 1 {
+		return ""
+	}
+	if uniseg.GraphemeClusterCount(s) <= n {
+		return s
+	}
+	// find the end of the first n-1 characters, leaving room for the
+	// ellipsis
+	end := 0
+	lastSpace := -1
+	graphemes := uniseg.NewGraphemes(s)
+	for i := 0; i < n-1 && graphemes.Next(); i++ {
+		start, stop := graphemes.Positions()
+		if strings.TrimSpace(s[start:stop]) == "" {
+			lastSpace = start
+		}
+		end = stop
+	}
+	if lastSpace > end/2 {
+		end = lastSpace
+	}
+	return strings.TrimRight(s[:end], " \t\r\n") + "…"
+}
+
+// Highlight escapes `s` for inclusion in HTML, wrapping every case-insensitive
+// match of any of `terms` in a <mark> element. The terms are matched against
+// the unescaped text, so searching for "amp" won't match the "&amp;" an
+// ampersand is escaped to, and a match can't split an entity or a character
+// made of multiple code points. When terms overlap, the longest match at each
+// position wins.
+func Highlight(s string, terms ...string) template.HTML {
+	var out strings.Builder
+	boundaries := graphemeBoundaries(s)
+	// unmatched is the start of the text since the last match
+	unmatched := 0
+	for pos := 0; pos < len(s); {
+		length := matchTerms(s[pos:], terms, boundaries[pos:])
+		if length < 1 {
+			// try again at the start of the next character
+			pos++
+			for !boundaries[pos] {
+				pos++
+			}
+			continue
+		}
+		out.WriteString(html.EscapeString(s[unmatched:pos]))
+		out.WriteString("<mark>" + html.EscapeString(s[pos:pos+length]) + "</mark>")
+		pos += length
+		unmatched = pos
+	}
+	out.WriteString(html.EscapeString(s[unmatched:]))
+	return template.HTML(out.String()) // #nosec G203
+}
+
+// graphemeBoundaries returns a slice one longer than `s`, where each index is
+// true if a user-perceived character starts at that byte of `s`, or it's the
+// end of `s`.
+func graphemeBoundaries(s string) []bool {
+	boundaries := make([]bool, len(s)+1)
+	boundaries[len(s)] = true
+	state := -1
+	pos := 0
+	for rest := s; len(rest) > 0; {
+		var cluster string
+		boundaries[pos] = true
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		pos += len(cluster)
+	}
+	return boundaries
+}
+
+// matchTerms returns the length in bytes of the longest of `terms` that `s`
+// starts with, ignoring case, or 0 if it doesn't start with any of them.
+// `boundaries` are the grapheme boundaries of `s`, as returned by
+// graphemeBoundaries, so matches don't end partway through a character.
+func matchTerms(s string, terms []string, boundaries []bool) int {
+	longest := 0
+	for _, term := range terms {
+		if term == "" {
+			continue
+		}
+		// compare rune by rune, as a rune's upper and lower case
+		// forms can have different lengths in bytes
+		sPos, termPos := 0, 0
+		for termPos < len(term) && sPos < len(s) {
+			sRune, sSize := utf8.DecodeRuneInString(s[sPos:])
+			termRune, termSize := utf8.DecodeRuneInString(term[termPos:])
+			if !strings.EqualFold(string(sRune), string(termRune)) {
+				break
+			}
+			sPos += sSize
+			termPos += termSize
+		}
+		if termPos < len(term) || !boundaries[sPos] {
+			continue
+		}
+		if sPos > longest {
+			longest = sPos
+		}
+	}
+	return longest
+}
+
+// highlightQuery is the highlight template function, which highlights each
+// whitespace-separated word of `query` in `s`.
+func highlightQuery(query, s string) template.HTML {
+	return Highlight(s, strings.Fields(query)...)
+}
@@ -0,0 +1,37 @@
+package temple_test
+
+import (
+	"testing"
+
+	"impractical.co/temple"
+)
+
+func TestHighlight(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		s     string
+		terms []string
+		want  string
+	}{
+		"no-terms":      {s: "a <b>", want: "a &lt;b&gt;"},
+		"case":          {s: "Go go GO", terms: []string{"go"}, want: "<mark>Go</mark> <mark>go</mark> <mark>GO</mark>"},
+		"longest-wins":  {s: "fishing", terms: []string{"fish", "fishing"}, want: "<mark>fishing</mark>"},
+		"escaped":       {s: "a&b", terms: []string{"amp", "&"}, want: "a<mark>&amp;</mark>b"},
+		"combining":     {s: "café cafe", terms: []string{"cafe"}, want: "café <mark>cafe</mark>"},
+		"combining-mid": {s: "ée", terms: []string{"́"}, want: "ée"},
+		"emoji":         {s: "👍🏽 👍", terms: []string{"👍"}, want: "👍🏽 <mark>👍</mark>"},
+		"multibyte":     {s: "Straße STRASSE", terms: []string{"straße"}, want: "<mark>Straße</mark> STRASSE"},
+		"at-end":        {s: "the end", terms: []string{"end"}, want: "the <mark>end</mark>"},
+		"empty-term":    {s: "abc", terms: []string{""}, want: "abc"},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := string(temple.Highlight(test.s, test.terms...)); got != test.want {
+				t.Errorf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
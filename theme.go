@@ -0,0 +1,104 @@
+package temple
+
+import (
+	"context"
+)
+
+// Theme is a set of visual variations a Site can render pages with, like
+// light and dark modes or different brands' colors.
+type Theme struct {
+	// Name identifies the Theme, like "dark". Templates are cached
+	// separately for each Theme Name.
+	Name string
+
+	// Vars are values templates can use to style the page, like
+	// "accent": "#ff6600".
+	Vars map[string]string
+}
+
+// Var returns the Theme's value for the variable, or an empty string if it
+// doesn't have one.
+func (t Theme) Var(name string) string {
+	return t.Vars[name]
+}
+
+// Themer is an optional interface for Sites. Those fulfilling it can render
+// pages with different Themes. The Theme for each render is made available
+// to templates as .Theme, and to Components through CurrentTheme, so they
+// can change which CSS they embed or link to for each Theme:
+//
+//	func (Card) LinkCSS(ctx context.Context) []string {
+//		if temple.CurrentTheme(ctx).Name == "dark" {
+//			return []string{"/css/card-dark.css"}
+//		}
+//		return []string{"/css/card.css"}
+//	}
+//
+// As the Components a page uses and their templates can depend on the Theme,
+// cached templates and ComponentGraphs are keyed by the Theme's Name as
+// well as the page's Key.
+type Themer interface {
+	// Theme returns the Theme to render the request the context.Context
+	// belongs to with.
+	Theme(ctx context.Context) Theme
+
+	// Themes returns every Theme the Site can render with, so the
+	// templates cached for each of them can be invalidated by
+	// InvalidateTemplates.
+	Themes(ctx context.Context) []Theme
+}
+
+type themeCtxKey struct{}
+
+// withTheme returns a context.Context that CurrentTheme can retrieve the
+// Site's Theme from, if the Site is a Themer.
+func withTheme(ctx context.Context, site Site) context.Context {
+	themer, ok := site.(Themer)
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, themeCtxKey{}, themer.Theme(ctx))
+}
+
+// CurrentTheme returns the Theme of the render the context.Context belongs
+// to. If the Site isn't a Themer, or the context.Context doesn't belong to a
+// render, the zero Theme is returned.
+func CurrentTheme(ctx context.Context) Theme {
+	theme, _ := ctx.Value(themeCtxKey{}).(Theme)
+	return theme
+}
+
+// themedKey returns the key templates and ComponentGraphs for the page with
+// the passed Key should be cached under, in the render the context.Context
+// belongs to.
+func themedKey(ctx context.Context, key string) string {
+	return themeKey(key, CurrentTheme(ctx))
+}
+
+// themeKey returns the key templates and ComponentGraphs for the page with
+// the passed Key should be cached under when rendered with the Theme.
+func themeKey(key string, theme Theme) string {
+	if theme.Name == "" {
+		return key
+	}
+	return key + "|theme=" + theme.Name
+}
+
+// themedKeys returns the passed keys, followed by the keys they'd be cached
+// under with each of the Site's Themes, if it's a Themer.
+func themedKeys(ctx context.Context, site Site, keys []string) []string {
+	themer, ok := site.(Themer)
+	if !ok {
+		return keys
+	}
+	results := keys
+	for _, theme := range themer.Themes(ctx) {
+		if theme.Name == "" {
+			continue
+		}
+		for _, key := range keys {
+			results = append(results, themeKey(key, theme))
+		}
+	}
+	return results
+}
@@ -0,0 +1,215 @@
+package temple
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	_ Component   = ThemeVariables{}
+	_ CSSEmbedder = ThemeVariables{}
+)
+
+// ThemeVariables is a Component that embeds CSS declaring custom properties,
+// like --accent-color, from values defined in Go, so design tokens only need
+// to be defined in one place:
+//
+//	temple.ThemeVariables{Vars: Tokens{AccentColor: "#ff6600", Spacing: 4}}
+//
+// renders as
+//
+//	:root {
+//		--accent-color: #ff6600;
+//		--spacing: 4;
+//	}
+//
+// Vars can be a map with string keys, or a struct or pointer to a struct.
+// Struct fields are named by converting their names to kebab-case, unless
+// they have a css tag: `css:"brand"` names the field --brand, and `css:"-"`
+// leaves it out. Fields that are structs themselves are flattened, with their
+// fields' names prefixed by the field's name, so Colors.Primary becomes
+// --colors-primary. Map keys are sorted, so the output is stable.
+//
+// Values are formatted with fmt, and characters that could end the
+// declaration or the <style> element early, like semicolons and angle
+// brackets, are escaped, as are unbalanced quotes; quoted strings, like font
+// names, are kept. Names are limited to letters, digits, hyphens, and
+// underscores; anything else is removed.
+type ThemeVariables struct {
+	// Vars holds the custom properties' values. If nil, the Vars of the
+	// Theme being rendered are used.
+	Vars any
+
+	// Selector is the selector the custom properties are declared on,
+	// like `[data-theme="dark"]`. It's included as-is, so it mustn't come
+	// from users. If empty, ":root" is used.
+	Selector string
+}
+
+// Templates returns nil, as ThemeVariables doesn't need any templates.
+func (ThemeVariables) Templates(_ context.Context) []string {
+	return nil
+}
+
+// EmbedCSS returns a rule declaring the custom properties.
+func (t ThemeVariables) EmbedCSS(ctx context.Context) template.CSS {
+	vars := t.Vars
+	if vars == nil {
+		vars = CurrentTheme(ctx).Vars
+	}
+	var decls [][2]string
+	flattenThemeVariables(reflect.ValueOf(vars), "", &decls)
+	if len(decls) < 1 {
+		return ""
+	}
+	selector := t.Selector
+	if selector == "" {
+		selector = ":root"
+	}
+	var out strings.Builder
+	// the selector comes from the Site, not users, so it's trusted, but
+	// it still mustn't be able to end the <style> element
+	out.WriteString(strings.ReplaceAll(selector, "<", `\3c `) + " {\n")
+	for _, decl := range decls {
+		fmt.Fprintf(&out, "\t--%s: %s;\n", decl[0], decl[1])
+	}
+	out.WriteString("}\n")
+	return template.CSS(out.String()) // #nosec G203
+}
+
+// flattenThemeVariables appends a name and value for each custom property
+// described by `val` to decls, prefixing the names with prefix.
+func flattenThemeVariables(val reflect.Value, prefix string, decls *[][2]string) {
+	for val.Kind() == reflect.Pointer || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return
+		}
+		val = val.Elem()
+	}
+	switch val.Kind() {
+	case reflect.Invalid:
+		return
+	case reflect.Map:
+		if val.Type().Key().Kind() != reflect.String {
+			return
+		}
+		keys := val.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(a.String(), b.String())
+		})
+		for _, key := range keys {
+			flattenThemeVariables(val.MapIndex(key), joinThemeVariableName(prefix, cssIdent(key.String())), decls)
+		}
+	case reflect.Struct:
+		if _, ok := val.Interface().(fmt.Stringer); ok {
+			*decls = append(*decls, [2]string{prefix, escapeCSSValue(fmt.Sprint(val.Interface()))})
+			return
+		}
+		for i := range val.NumField() {
+			field := val.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := kebabCase(field.Name)
+			if tag, ok := field.Tag.Lookup("css"); ok {
+				if tag == "-" {
+					continue
+				}
+				name = cssIdent(tag)
+			}
+			flattenThemeVariables(val.Field(i), joinThemeVariableName(prefix, name), decls)
+		}
+	default:
+		if prefix == "" {
+			return
+		}
+		*decls = append(*decls, [2]string{prefix, escapeCSSValue(fmt.Sprint(val.Interface()))})
+	}
+}
+
+// joinThemeVariableName returns the name of a custom property nested within
+// prefix.
+func joinThemeVariableName(prefix, name string) string {
+	if prefix == "" || name == "" {
+		return prefix + name
+	}
+	return prefix + "-" + name
+}
+
+// kebabCase converts a Go identifier, like AccentColor or HTTPPort, to
+// kebab-case, like accent-color or http-port.
+func kebabCase(s string) string {
+	runes := []rune(s)
+	var out strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (nextLower && unicode.IsUpper(runes[i-1])) {
+				out.WriteByte('-')
+			}
+		}
+		out.WriteRune(unicode.ToLower(r))
+	}
+	return cssIdent(out.String())
+}
+
+// cssIdent removes everything but letters, digits, hyphens, and underscores
+// from s, so it can be used as part of a custom property's name.
+func cssIdent(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// escapeCSSValue escapes the characters in s that could end a declaration,
+// rule, or <style> element, start a comment, or start an escape, using CSS hex
+// escapes. Well-formed quoted strings, like the "Inter" in a font stack, are
+// kept intact, with only the characters that could end them or the <style>
+// element escaped; unbalanced quotes are escaped.
+func escapeCSSValue(s string) string {
+	var out strings.Builder
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		if r == '"' || r == '\'' {
+			if end := strings.IndexRune(s[size:], r); end >= 0 {
+				out.WriteRune(r)
+				writeCSSEscaped(&out, s[size:size+end], `<\`)
+				out.WriteRune(r)
+				s = s[size+end+size:]
+				continue
+			}
+		}
+		writeCSSEscaped(&out, s[:size], `;{}<\"'`)
+		if r == '/' && strings.HasPrefix(s[size:], "*") {
+			// escaping the * means the /* can't start a comment
+			writeCSSEscaped(&out, "*", "*")
+			size++
+		}
+		s = s[size:]
+	}
+	return out.String()
+}
+
+// writeCSSEscaped writes s to out, with the characters in special and any
+// control characters replaced by CSS hex escapes.
+func writeCSSEscaped(out *strings.Builder, s, special string) {
+	for _, r := range s {
+		if strings.ContainsRune(special, r) || unicode.IsControl(r) {
+			// the trailing space ends the escape, so a following
+			// hex digit isn't taken as part of it
+			fmt.Fprintf(out, `\%x `, r)
+			continue
+		}
+		out.WriteRune(r)
+	}
+}
@@ -0,0 +1,42 @@
+package temple_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"impractical.co/temple"
+)
+
+func TestThemeVariablesEscaping(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		value string
+		want  string
+	}{
+		"plain":             {value: "#ff6600", want: "#ff6600"},
+		"quoted-fonts":      {value: `"Inter Var", 'Noto Sans', sans-serif`, want: `"Inter Var", 'Noto Sans', sans-serif`},
+		"quoted-semicolon":  {value: `"a; b { c }"`, want: `"a; b { c }"`},
+		"quoted-style-end":  {value: `"</style>"`, want: `"\3c /style>"`},
+		"quoted-backslash":  {value: `"a\" b`, want: `"a\5c " b`},
+		"unbalanced-quote":  {value: `"Inter, sans-serif`, want: `\22 Inter, sans-serif`},
+		"mismatched-quotes": {value: `"Inter'`, want: `\22 Inter\27 `},
+		"semicolon":         {value: "red; color: blue", want: `red\3b  color: blue`},
+		"braces":            {value: "} body {", want: `\7d  body \7b `},
+		"comment":           {value: "red /* hidden", want: `red /\2a  hidden`},
+		"trailing-escape":   {value: `red\`, want: `red\5c `},
+		"newline":           {value: "a\nb", want: `a\a b`},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			css := string(temple.ThemeVariables{Vars: map[string]string{"value": test.value}}.EmbedCSS(context.Background()))
+			want := "\t--value: " + test.want + ";\n"
+			if !strings.Contains(css, want) {
+				t.Errorf("expected declaration %q, got %q", want, css)
+			}
+		})
+	}
+}
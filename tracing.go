@@ -0,0 +1,52 @@
+package temple
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// componentTypeAttr is the span attribute holding the Go type of the
+// Component a span is for.
+const componentTypeAttr = attribute.Key("temple.component.type")
+
+// tracer returns the trace.Tracer used for all of temple's spans.
+func tracer() trace.Tracer {
+	return otel.GetTracerProvider().Tracer("impractical.co/temple")
+}
+
+// componentType returns the Go type name of the Component, for use in span
+// attributes.
+func componentType(component Component) string {
+	return fmt.Sprintf("%T", component)
+}
+
+// resolveComponents returns the page and every Component it uses,
+// recursively, recording a span for the resolution of the whole tree and a
+// child span for each Component in it, covering its UseComponents call and
+// the resolution of the Components it uses. If the Site is a GraphCacher, the
+// Components are retrieved from and stored in its cache. It returns true if
+// the Components came from the cache.
+func resolveComponents(ctx context.Context, site Site, page Renderable) ([]Component, bool, error) {
+	ctx, span := tracer().Start(ctx, "resolve components",
+		trace.WithAttributes(componentTypeAttr.String(componentType(page))),
+	)
+	defer span.End()
+	if components, ok := getCachedComponents(ctx, site, page); ok {
+		span.SetAttributes(
+			attribute.Int("temple.components", len(components)),
+			attribute.Bool("temple.cached", true),
+		)
+		return components, true, nil
+	}
+	components, err := getRecursiveComponents(ctx, page)
+	if err != nil {
+		return nil, false, err
+	}
+	setCachedComponents(ctx, site, page, components)
+	span.SetAttributes(attribute.Int("temple.components", len(components)))
+	return components, false, nil
+}
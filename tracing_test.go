@@ -0,0 +1,149 @@
+package temple_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"impractical.co/temple"
+)
+
+// recordedSpan is a span recorded by spanRecorder. Its name is "name" or
+// "name(component type)" if it has a temple.component.type attribute.
+type recordedSpan struct {
+	name   string
+	parent string
+}
+
+// spanRecorder is a trace.TracerProvider that records the name and parent of
+// every span started by its Tracers.
+type spanRecorder struct {
+	noop.TracerProvider
+	mu    sync.Mutex
+	spans []recordedSpan
+}
+
+func (s *spanRecorder) Tracer(_ string, _ ...trace.TracerOption) trace.Tracer {
+	return spanTracer{recorder: s}
+}
+
+func (s *spanRecorder) get() []recordedSpan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.spans)
+}
+
+type spanTracer struct {
+	noop.Tracer
+	recorder *spanRecorder
+}
+
+func (t spanTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	config := trace.NewSpanStartConfig(opts...)
+	attrs := attribute.NewSet(config.Attributes()...)
+	if typ, ok := attrs.Value("temple.component.type"); ok {
+		name += "(" + typ.AsString() + ")"
+	}
+	var parent string
+	if span, ok := trace.SpanFromContext(ctx).(namedSpan); ok {
+		parent = span.name
+	}
+	t.recorder.mu.Lock()
+	defer t.recorder.mu.Unlock()
+	t.recorder.spans = append(t.recorder.spans, recordedSpan{name: name, parent: parent})
+	span := namedSpan{name: name}
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+type namedSpan struct {
+	noop.Span
+	name string
+}
+
+type tracedPage struct {
+	components []temple.Component
+}
+
+func (tracedPage) Templates(_ context.Context) []string {
+	return []string{"traced.html.tmpl"}
+}
+
+func (p tracedPage) UseComponents(_ context.Context) []temple.Component {
+	return p.components
+}
+
+func (tracedPage) Key(_ context.Context) string {
+	return "traced.html.tmpl"
+}
+
+func (tracedPage) ExecutedTemplate(_ context.Context) string {
+	return "traced.html.tmpl"
+}
+
+type tracedComponent struct {
+	uses []temple.Component
+}
+
+func (*tracedComponent) Templates(_ context.Context) []string {
+	return nil
+}
+
+func (c *tracedComponent) UseComponents(_ context.Context) []temple.Component {
+	return c.uses
+}
+
+type tracedLeaf struct{}
+
+func (tracedLeaf) Templates(_ context.Context) []string {
+	return nil
+}
+
+func TestRenderComponentSpans(t *testing.T) {
+	// the global TracerProvider is shared, so this can't run in parallel
+	// with other tests that set it
+	recorder := &spanRecorder{}
+	otel.SetTracerProvider(recorder)
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	site := temple.NewCachedSite(fstest.MapFS{
+		"traced.html.tmpl": {Data: []byte(`<p>traced</p>`)},
+	})
+	page := tracedPage{components: []temple.Component{
+		&tracedComponent{uses: []temple.Component{tracedLeaf{}}},
+	}}
+	result := temple.Render(context.Background(), httptest.NewRecorder(), site, page)
+	if result.Err != nil {
+		t.Fatalf("unexpected error: %s", result.Err)
+	}
+
+	const (
+		pageSpan      = "use components(temple_test.tracedPage)"
+		componentSpan = "use components(*temple_test.tracedComponent)"
+		leafSpan      = "use components(temple_test.tracedLeaf)"
+	)
+	want := []recordedSpan{
+		{name: pageSpan, parent: "resolve components(temple_test.tracedPage)"},
+		{name: componentSpan, parent: pageSpan},
+		{name: leafSpan, parent: componentSpan},
+	}
+	var got []recordedSpan
+	for _, span := range recorder.get() {
+		if span.parent == "" && span.name != "render" {
+			t.Errorf("expected span %q to have a parent", span.name)
+		}
+		if slices.Contains(want, span) {
+			got = append(got, span)
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected spans %v, got %v", want, got)
+	}
+}
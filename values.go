@@ -0,0 +1,39 @@
+package temple
+
+import (
+	"context"
+)
+
+type valuesCtxKey struct{}
+
+// WithValue returns a context.Context with the passed value stored under the
+// passed key, in such a way that templates rendered with that context.Context
+// can retrieve it using the ctxval template function:
+//
+//	{{ ctxval "theme" }}
+//
+// The ctxstring template function returns the value only if it's a string,
+// and an empty string otherwise.
+//
+// This is meant for middleware to expose simple values to deeply nested
+// Component templates without threading them through every Component.
+// Values stored by earlier calls to WithValue are still available, unless
+// they're stored under the same key, in which case the latest value wins.
+func WithValue(ctx context.Context, key string, val any) context.Context {
+	existing, _ := ctx.Value(valuesCtxKey{}).(map[string]any)
+	values := make(map[string]any, len(existing)+1)
+	for k, v := range existing {
+		values[k] = v
+	}
+	values[key] = val
+	return context.WithValue(ctx, valuesCtxKey{}, values)
+}
+
+// Value returns the value stored in the context.Context under the passed key
+// by WithValue, if it exists and is of type T. If it doesn't exist or isn't of
+// type T, the zero value of T and false are returned.
+func Value[T any](ctx context.Context, key string) (T, bool) {
+	values, _ := ctx.Value(valuesCtxKey{}).(map[string]any)
+	val, ok := values[key].(T)
+	return val, ok
+}